@@ -1,14 +1,21 @@
 package soroban
 
 import (
+	"context"
 	"errors"
 	"fmt"
 	"math"
 	"net/http"
 
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 )
 
+const (
+	ErrorAccountNotFound = "Account not found"
+)
+
 type Account struct {
 	AccountId            string            `json:"account_id"`
 	Sequence             int64             `json:"sequence,string"`
@@ -77,23 +84,21 @@ type AccountFlags struct {
 }
 
 // GetAccountEntry returns the ledger entry of the sourceAccount
-func (c Client) GetAccountEntry(publicKey string) (*xdr.AccountEntry, error) {
-	acountId := xdr.MustAddress(publicKey)
-	key := xdr.LedgerKey{
-		Account: &xdr.LedgerKeyAccount{
-			AccountId: acountId,
-		},
+func (c Client) GetAccountEntry(ctx context.Context, publicKey string) (*xdr.AccountEntry, error) {
+	key, err := Key.Account(publicKey)
+	if err != nil {
+		return nil, err
 	}
 	base64Key, err := key.MarshalBinaryBase64()
 	if err != nil {
 		return nil, err
 	}
-	res, err := c.GetLedgerEntries(base64Key)
+	res, err := c.GetLedgerEntries(ctx, base64Key)
 	if err != nil {
 		return nil, err
 	}
 	if len(res.Entries) < 1 {
-		return nil, errors.New("Account not found")
+		return nil, errors.New(ErrorAccountNotFound)
 	}
 	var ledgerEntry xdr.LedgerEntryData
 	err = xdr.SafeUnmarshalBase64(res.Entries[0].Xdr, &ledgerEntry)
@@ -104,8 +109,8 @@ func (c Client) GetAccountEntry(publicKey string) (*xdr.AccountEntry, error) {
 }
 
 // GetAccount returns a txnbuild.Account interface retrive from AccountEntry
-func (c Client) GetAccount(publicKey string) (account *Account, err error) {
-	accountEntry, err := c.GetAccountEntry(publicKey)
+func (c Client) GetAccount(ctx context.Context, publicKey string) (account *Account, err error) {
+	accountEntry, err := c.GetAccountEntry(ctx, publicKey)
 	if err != nil {
 		return nil, err
 	}
@@ -152,16 +157,16 @@ func (c Client) GetAccount(publicKey string) (account *Account, err error) {
 
 // Fund funds the publicKey recived. It only works with test networks.
 // If FriendbotURL is not set, it will get it from the network.
-func (c *Client) Fund(publicKey string) (*http.Response, error) {
+func (c *Client) Fund(ctx context.Context, publicKey string) (*http.Response, error) {
 	if c.FriendbotURL == "" {
-		network, err := c.GetNetwork()
+		network, err := c.GetNetwork(ctx)
 		if err != nil {
 			return nil, err
 		}
 		c.FriendbotURL = network.FriendbotURL
 	}
 	friendbotURL := fmt.Sprintf("%s?addr=%s", c.FriendbotURL, publicKey)
-	req, err := http.NewRequest("GET", friendbotURL, nil)
+	req, err := http.NewRequestWithContext(ctx, "GET", friendbotURL, nil)
 	if err != nil {
 		return nil, err
 	}
@@ -171,3 +176,38 @@ func (c *Client) Fund(publicKey string) (*http.Response, error) {
 	}
 	return res, nil
 }
+
+// DefaultRootFundAmount is the amount of XLM sent by FundFromRoot when
+// amount is empty, the same friendbot sends
+const DefaultRootFundAmount = "10000"
+
+// RootKeyPair returns the root account of the network, derived from its
+// passphrase. Only standalone networks leave it usable.
+func (c Client) RootKeyPair() *keypair.Full {
+	return keypair.Root(c.PassPhrase)
+}
+
+// FundFromRoot funds publicKey from the root account of the network, creating
+// the account if it does not exist. It allows funding accounts in standalone
+// networks without friendbot, amount is in XLM, DefaultRootFundAmount if empty.
+// Use the returned PendingTransaction to wait for the funding.
+func (c *Client) FundFromRoot(ctx context.Context, publicKey string, amount string) (*PendingTransaction, error) {
+	if amount == "" {
+		amount = DefaultRootFundAmount
+	}
+	root := c.RootKeyPair()
+	rootAccount, err := c.GetAccount(ctx, root.Address())
+	if err != nil {
+		return nil, err
+	}
+	var op txnbuild.Operation = &txnbuild.CreateAccount{Destination: publicKey, Amount: amount}
+	if _, err := c.GetAccountEntry(ctx, publicKey); err == nil {
+		op = &txnbuild.Payment{Destination: publicKey, Amount: amount, Asset: txnbuild.NativeAsset{}}
+	}
+	return NewTransctionBuilder().
+		Client(c).
+		SourceAccount(rootAccount).
+		Signer(root).
+		Operation(op).
+		Send(ctx)
+}
@@ -1,9 +1,11 @@
 package soroban_test
 
 import (
+	"context"
 	"testing"
 
 	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
 )
 
 func TestGetAccount(t *testing.T) {
@@ -11,9 +13,35 @@ func TestGetAccount(t *testing.T) {
 	sorobanClient.URL = LocalNetwork
 	sorobanClient.PassPhrase = LocalPassphrase
 
-	a, err := sorobanClient.GetAccountEntry("GDDFXO5LE6JLE7E4HYN7EWBDJSKJ3NV7MAC4UN7LY7BUSD6JNPUAUK4K")
+	a, err := sorobanClient.GetAccountEntry(context.Background(), "GDDFXO5LE6JLE7E4HYN7EWBDJSKJ3NV7MAC4UN7LY7BUSD6JNPUAUK4K")
 	if err != nil {
 		t.Fatal(err)
 	}
 	t.Log(a)
 }
+
+func TestFundFromRoot(t *testing.T) {
+	sorobanClient := soroban.Client{}
+	sorobanClient.URL = LocalNetwork
+	sorobanClient.PassPhrase = LocalPassphrase
+
+	pair := keypair.MustRandom()
+	pending, err := sorobanClient.FundFromRoot(context.Background(), pair.Address(), "100")
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := pending.Wait(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Status != "SUCCESS" {
+		t.Fatal(res)
+	}
+	account, err := sorobanClient.GetAccount(context.Background(), pair.Address())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if account.Balance != 100_0000000 {
+		t.Fatal(account.Balance)
+	}
+}
@@ -0,0 +1,186 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorInvalidAdmin      = "Invalid admin address"
+	ErrorAdminNotRotated   = "Admin in instance storage is not the new admin after the rotation"
+	ErrorAdminRotateFailed = "Admin rotation transaction failed"
+	ErrorSignerRemoval     = "Old signer removal failed"
+)
+
+// AdminRotation is the guided rotation of the admin of a contract: it invokes
+// the rotation function, verifies the new admin reading the instance storage
+// and optionally removes the old signer from the contract source account.
+//
+//	Example:
+//	 res, err := contract.RotateAdmin(newAdmin).
+//		AuthSigner(oldAdmin).
+//		RemoveSigner(oldAdmin.Address()).
+//		Run(ctx)
+type AdminRotation struct {
+	contract     *Contract
+	newAdmin     string
+	function     string
+	key          xdr.ScVal
+	authSigners  []*keypair.Full
+	removeSigner string
+}
+
+// AdminRotationResult is the outcome of a completed AdminRotation
+type AdminRotationResult struct {
+	// Previous is the admin read before the rotation, nil if there was none
+	Previous *xdr.ScVal
+	Rotation *GetTransactionResult
+	// SignerRemoval is nil without RemoveSigner
+	SignerRemoval *GetTransactionResult
+}
+
+// RotateAdmin returns the rotation of the admin of the contract to newAdmin,
+// an account (G...) or contract (C...) address. By default it invokes
+// set_admin(newAdmin) and reads the admin at the DataKey::Admin key, the
+// ScVal of a unit enum variant, of the instance storage.
+//
+//	Requires client, sourceAccount, keyPair, and salt or address
+func (c *Contract) RotateAdmin(newAdmin string) *AdminRotation {
+	return &AdminRotation{
+		contract: c,
+		newAdmin: newAdmin,
+		function: "set_admin",
+		key:      NewScVec(scSymbol("Admin")),
+	}
+}
+
+// Function sets the rotation function, invoked with the new admin as its
+// only param
+func (r *AdminRotation) Function(function string) *AdminRotation {
+	r.function = function
+	return r
+}
+
+// StorageKey sets the instance storage key of the admin
+func (r *AdminRotation) StorageKey(key xdr.ScVal) *AdminRotation {
+	r.key = key
+	return r
+}
+
+// AuthSigner adds the signers of the authorization of the rotation, usually
+// the current admin
+func (r *AdminRotation) AuthSigner(signers ...*keypair.Full) *AdminRotation {
+	r.authSigners = append(r.authSigners, signers...)
+	return r
+}
+
+// RemoveSigner sets the signer removed from the contract source account
+// once the rotation is verified, usually the old admin key
+func (r *AdminRotation) RemoveSigner(signer string) *AdminRotation {
+	r.removeSigner = signer
+	return r
+}
+
+// Run executes the rotation, waiting for each transaction to be final. The
+// old signer is only removed once the new admin is verified.
+func (r *AdminRotation) Run(ctx context.Context) (*AdminRotationResult, error) {
+	if err := r.contract.requireSubmitter(); err != nil {
+		return nil, err
+	}
+	newAdmin, err := scAddressFromString(r.newAdmin)
+	if err != nil {
+		return nil, err
+	}
+	if r.removeSigner != "" && !strkey.IsValidEd25519PublicKey(r.removeSigner) {
+		return nil, fmt.Errorf("%s: signer %s", ErrorInvalidAdmin, r.removeSigner)
+	}
+	result := &AdminRotationResult{}
+	storage, err := r.contract.InstanceStorage(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if previous, ok := scMapGet(storage, r.key); ok {
+		result.Previous = &previous.Val
+	}
+
+	pending, err := r.contract.Invoke().
+		Function(r.function).
+		Params(newAdmin).
+		AuthSigner(r.authSigners...).
+		Send(ctx)
+	if err != nil {
+		return nil, err
+	}
+	result.Rotation, err = pending.Wait(ctx)
+	if err != nil {
+		return result, err
+	}
+	if result.Rotation.Status != "SUCCESS" {
+		return result, fmt.Errorf("%s: %s %s", ErrorAdminRotateFailed, result.Rotation.TxHash, result.Rotation.Status)
+	}
+
+	storage, err = r.contract.InstanceStorage(ctx)
+	if err != nil {
+		return result, err
+	}
+	admin, ok := scMapGet(storage, r.key)
+	if !ok || !ScValEqual(admin.Val, newAdmin) {
+		return result, errors.New(ErrorAdminNotRotated)
+	}
+
+	if r.removeSigner == "" {
+		return result, nil
+	}
+	pending, err = NewTransctionBuilder().
+		Client(r.contract.client).
+		SourceAccount(r.contract.source).
+		Signer(r.contract.kp).
+		Operation(&txnbuild.SetOptions{Signer: &txnbuild.Signer{Address: r.removeSigner, Weight: 0}}).
+		Timeout(r.contract.timeout).
+		Pin(r.contract.pin).
+		Send(ctx)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", ErrorSignerRemoval, err)
+	}
+	result.SignerRemoval, err = pending.Wait(ctx)
+	if err != nil {
+		return result, fmt.Errorf("%s: %w", ErrorSignerRemoval, err)
+	}
+	if result.SignerRemoval.Status != "SUCCESS" {
+		return result, fmt.Errorf("%s: %s %s", ErrorSignerRemoval, result.SignerRemoval.TxHash, result.SignerRemoval.Status)
+	}
+	return result, nil
+}
+
+// scAddressFromString returns the address ScVal of an account (G...) or
+// contract (C...) strkey
+func scAddressFromString(address string) (xdr.ScVal, error) {
+	var scAddress xdr.ScAddress
+	switch {
+	case strkey.IsValidEd25519PublicKey(address):
+		accountId, err := xdr.AddressToAccountId(address)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("%s: %w", ErrorInvalidAdmin, err)
+		}
+		scAddress = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId}
+	default:
+		id, err := strkey.Decode(strkey.VersionByteContract, address)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("%s: %w", ErrorInvalidAdmin, err)
+		}
+		contractId := xdr.ContractId(id)
+		scAddress = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}
+	}
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &scAddress}, nil
+}
+
+func scSymbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
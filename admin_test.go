@@ -0,0 +1,113 @@
+package soroban_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+// adminContract returns a contract whose instance storage holds *admin at
+// DataKey::Admin, set_admin sets it unless ignore is set
+func adminContract(t *testing.T, admin *xdr.ScVal, ignore bool) (*soroban.Contract, *[]xdr.OperationType) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	sym := xdr.ScSymbol("Admin")
+	key := soroban.NewScVec(xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		storage := xdr.ScMap{{Key: key, Val: *admin}}
+		data := xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.ContractId{1}},
+				Durability: xdr.ContractDataDurabilityPersistent,
+				Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+				Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+					Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset},
+					Storage:    &storage,
+				}},
+			},
+		}
+		entry, err := xdr.MarshalBase64(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &soroban.GetLedgerEntriesResult{LatestLedger: latestLedger, Entries: []soroban.GetLedgerEntry{{Key: keys[0], Xdr: entry}}}, nil
+	}
+	var sent []xdr.OperationType
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		op := envelope.Operations()[0]
+		sent = append(sent, op.Body.Type)
+		switch op.Body.Type {
+		case xdr.OperationTypeInvokeHostFunction:
+			if !ignore {
+				*admin = op.Body.InvokeHostFunctionOp.HostFunction.InvokeContract.Args[0]
+			}
+		case xdr.OperationTypeSetOptions:
+			if signer := op.Body.SetOptionsOp.Signer; signer == nil || signer.Weight != 0 {
+				t.Error("signer not removed")
+			}
+		}
+		return &soroban.SendTransactionResult{Hash: "h", Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, Ledger: latestLedger, LatestLedger: latestLedger}, nil
+	}
+	return contract, &sent
+}
+
+func TestRotateAdmin(t *testing.T) {
+	oldAdmin, newAdmin := keypair.MustRandom(), keypair.MustRandom()
+	accountId := xdr.MustAddress(oldAdmin.Address())
+	admin := xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId}}
+	previous := admin
+	contract, sent := adminContract(t, &admin, false)
+
+	res, err := contract.RotateAdmin(newAdmin.Address()).
+		AuthSigner(oldAdmin).
+		RemoveSigner(oldAdmin.Address()).
+		Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Previous == nil || !soroban.ScValEqual(*res.Previous, previous) || res.Rotation == nil || res.SignerRemoval == nil {
+		t.Fatal(res)
+	}
+	if len(*sent) != 2 || (*sent)[0] != xdr.OperationTypeInvokeHostFunction || (*sent)[1] != xdr.OperationTypeSetOptions {
+		t.Fatal(*sent)
+	}
+	if address, _ := admin.Address.String(); address != newAdmin.Address() {
+		t.Fatal(address)
+	}
+}
+
+func TestRotateAdminNotVerified(t *testing.T) {
+	oldAdmin, newAdmin := keypair.MustRandom(), keypair.MustRandom()
+	accountId := xdr.MustAddress(oldAdmin.Address())
+	admin := xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId}}
+	contract, sent := adminContract(t, &admin, true)
+
+	_, err := contract.RotateAdmin(newAdmin.Address()).RemoveSigner(oldAdmin.Address()).Run(context.Background())
+	if err == nil || err.Error() != soroban.ErrorAdminNotRotated {
+		t.Fatal(err)
+	}
+	// the old signer is kept
+	if len(*sent) != 1 {
+		t.Fatal(*sent)
+	}
+
+	_, err = contract.RotateAdmin("GABC").Run(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorInvalidAdmin) {
+		t.Fatal(err)
+	}
+	if len(*sent) != 1 {
+		t.Fatal(*sent)
+	}
+}
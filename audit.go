@@ -0,0 +1,121 @@
+package soroban
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// AuditRecord is the record of a submitted envelope
+type AuditRecord struct {
+	Hash           string    `json:"hash"`
+	EnvelopeXdr    string    `json:"envelope_xdr"`
+	Source         string    `json:"source"`
+	Fee            int64     `json:"fee"`
+	Status         string    `json:"status"`
+	ErrorResultXdr string    `json:"error_result_xdr,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	SubmittedAt    time.Time `json:"submitted_at"`
+	RespondedAt    time.Time `json:"responded_at"`
+}
+
+// AuditSink stores AuditRecords, e.g. in a file or a database
+type AuditSink interface {
+	Record(AuditRecord) error
+}
+
+// AuditSinkFunc adapts a function to AuditSink
+type AuditSinkFunc func(AuditRecord) error
+
+// Record calls f(r)
+func (f AuditSinkFunc) Record(r AuditRecord) error {
+	return f(r)
+}
+
+// AuditError is returned by SendTransaction when the transaction was
+// submitted but its record could not be stored. The result of the
+// submission is still returned and valid, the transaction may be applied,
+// so it must be tracked rather than sent again.
+type AuditError struct {
+	Err error
+}
+
+func (e *AuditError) Error() string {
+	return "audit record failed: " + e.Err.Error()
+}
+
+func (e *AuditError) Unwrap() error {
+	return e.Err
+}
+
+// JSONLinesAuditSink writes each record as a JSON line
+type JSONLinesAuditSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONLinesAuditSink returns a sink writing to w
+func NewJSONLinesAuditSink(w io.Writer) *JSONLinesAuditSink {
+	return &JSONLinesAuditSink{w: w}
+}
+
+// NewFileAuditSink opens, or creates, the file at path in append mode and
+// returns a sink writing to it.
+func NewFileAuditSink(path string) (*JSONLinesAuditSink, *os.File, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return NewJSONLinesAuditSink(f), f, nil
+}
+
+// Record writes r as a JSON line
+func (s *JSONLinesAuditSink) Record(r AuditRecord) error {
+	b, err := json.Marshal(r)
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	_, err = s.w.Write(append(b, '\n'))
+	return err
+}
+
+func (c Client) audit(envelope xdr.TransactionEnvelope, envelopeXdr string, submittedAt time.Time, res *SendTransactionResult, sendErr error) error {
+	if c.Audit == nil {
+		return nil
+	}
+	source := envelope.SourceAccount()
+	record := AuditRecord{
+		EnvelopeXdr: envelopeXdr,
+		Source:      source.Address(),
+		Fee:         int64(envelope.Fee()),
+		SubmittedAt: submittedAt,
+		RespondedAt: time.Now(),
+	}
+	if envelope.IsFeeBump() {
+		feeAccount := envelope.FeeBumpAccount()
+		record.Source = feeAccount.Address()
+		record.Fee = envelope.FeeBumpFee()
+	}
+	if hash, err := network.HashTransactionInEnvelope(envelope, c.PassPhrase); err == nil {
+		record.Hash = hex.EncodeToString(hash[:])
+	}
+	if res != nil {
+		record.Status = res.Status
+		record.ErrorResultXdr = res.ErrorResultXdr
+	}
+	if sendErr != nil {
+		record.Error = sendErr.Error()
+	}
+	if err := c.Audit.Record(record); err != nil {
+		return &AuditError{Err: err}
+	}
+	return nil
+}
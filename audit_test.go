@@ -0,0 +1,84 @@
+package soroban_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+// auditedClient returns a client whose node answers sendTransaction with
+// status, recording in sink
+func auditedClient(t *testing.T, status string, sink soroban.AuditSink) soroban.Client {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"hash":"abc","status":%q,"latestLedger":7}}`, req.ID, status)
+	}))
+	t.Cleanup(server.Close)
+	return soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase, Audit: sink}
+}
+
+func signedTransaction(t *testing.T) (*txnbuild.Transaction, *keypair.Full) {
+	t.Helper()
+	pair := keypair.MustRandom()
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = tx.Sign(network.TestNetworkPassphrase, pair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx, pair
+}
+
+func TestAuditRecord(t *testing.T) {
+	var b bytes.Buffer
+	client := auditedClient(t, "PENDING", soroban.NewJSONLinesAuditSink(&b))
+	tx, pair := signedTransaction(t)
+	res, err := client.SendTransaction(context.Background(), tx)
+	if err != nil || res.Status != "PENDING" {
+		t.Fatal(res, err)
+	}
+	var record soroban.AuditRecord
+	if err := json.Unmarshal(b.Bytes(), &record); err != nil {
+		t.Fatal(b.String(), err)
+	}
+	hash, _ := tx.HashHex(network.TestNetworkPassphrase)
+	if record.Hash != hash || record.Source != pair.Address() || record.Fee != txnbuild.MinBaseFee || record.Status != "PENDING" {
+		t.Fatal(record)
+	}
+}
+
+func TestAuditError(t *testing.T) {
+	full := errors.New("disk full")
+	client := auditedClient(t, "PENDING", soroban.AuditSinkFunc(func(soroban.AuditRecord) error { return full }))
+	tx, _ := signedTransaction(t)
+	// the transaction was submitted, its result is returned with the error
+	res, err := client.SendTransaction(context.Background(), tx)
+	var auditErr *soroban.AuditError
+	if !errors.As(err, &auditErr) || !errors.Is(err, full) {
+		t.Fatal(err)
+	}
+	if res == nil || res.Hash != "abc" || res.Status != "PENDING" {
+		t.Fatal(res)
+	}
+}
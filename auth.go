@@ -0,0 +1,121 @@
+package soroban
+
+import (
+	"crypto/sha256"
+	"fmt"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// DefaultAuthValidityLedgers is the number of ledgers, after the simulation
+// latest ledger, the authorization signatures are valid
+const DefaultAuthValidityLedgers = 100
+
+const (
+	ErrorAuthSignerMissing = "No signer for the authorization entry"
+)
+
+// AuthEntryAddress returns the address authorizing the entry, false if the
+// entry uses the source account credentials, which need no signature.
+func AuthEntryAddress(entry xdr.SorobanAuthorizationEntry) (string, bool, error) {
+	if entry.Credentials.Type != xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+		return "", false, nil
+	}
+	address, err := entry.Credentials.Address.Address.String()
+	if err != nil {
+		return "", false, err
+	}
+	return address, true, nil
+}
+
+// SignAuthEntry signs the authorization entry with signer, the signature is
+// valid until validUntilLedger. Entries using the source account credentials
+// are returned unchanged.
+func SignAuthEntry(entry xdr.SorobanAuthorizationEntry, signer *keypair.Full, validUntilLedger uint32, networkPassphrase string) (xdr.SorobanAuthorizationEntry, error) {
+	if entry.Credentials.Type != xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+		return entry, nil
+	}
+	credentials := *entry.Credentials.Address
+	preimage := xdr.HashIdPreimage{
+		Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+		SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+			NetworkId:                 sha256.Sum256([]byte(networkPassphrase)),
+			Nonce:                     credentials.Nonce,
+			SignatureExpirationLedger: xdr.Uint32(validUntilLedger),
+			Invocation:                entry.RootInvocation,
+		},
+	}
+	b, err := preimage.MarshalBinary()
+	if err != nil {
+		return entry, err
+	}
+	payload := sha256.Sum256(b)
+	signature, err := signer.Sign(payload[:])
+	if err != nil {
+		return entry, err
+	}
+	publicKey, err := strkey.Decode(strkey.VersionByteAccountID, signer.Address())
+	if err != nil {
+		return entry, err
+	}
+	signatureMap, err := ScMapFromMap(map[string]xdr.ScVal{
+		"public_key": scBytes(publicKey),
+		"signature":  scBytes(signature),
+	})
+	if err != nil {
+		return entry, err
+	}
+	credentials.SignatureExpirationLedger = xdr.Uint32(validUntilLedger)
+	signatures := &xdr.ScVec{signatureMap}
+	credentials.Signature = xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &signatures}
+	entry.Credentials.Address = &credentials
+	return entry, nil
+}
+
+// SignAuthEntries signs each entry with the signer matching its credentials
+// address, so every node of the authorization tree can be authorized by a
+// different signer. Entries using the source account credentials need no
+// signer, any other entry without a matching signer is an error.
+func SignAuthEntries(entries []xdr.SorobanAuthorizationEntry, signers []*keypair.Full, validUntilLedger uint32, networkPassphrase string) ([]xdr.SorobanAuthorizationEntry, error) {
+	byAddress := make(map[string]*keypair.Full, len(signers))
+	for _, s := range signers {
+		byAddress[s.Address()] = s
+	}
+	signed := make([]xdr.SorobanAuthorizationEntry, 0, len(entries))
+	for _, entry := range entries {
+		address, ok, err := AuthEntryAddress(entry)
+		if err != nil {
+			return nil, err
+		}
+		if !ok {
+			signed = append(signed, entry)
+			continue
+		}
+		signer, ok := byAddress[address]
+		if !ok {
+			return nil, fmt.Errorf("%s: %s", ErrorAuthSignerMissing, address)
+		}
+		entry, err := SignAuthEntry(entry, signer, validUntilLedger, networkPassphrase)
+		if err != nil {
+			return nil, err
+		}
+		signed = append(signed, entry)
+	}
+	return signed, nil
+}
+
+func needsAuthSignatures(entries []xdr.SorobanAuthorizationEntry) bool {
+	for _, entry := range entries {
+		if entry.Credentials.Type == xdr.SorobanCredentialsTypeSorobanCredentialsAddress {
+			return true
+		}
+	}
+	return false
+}
+
+func scBytes(b []byte) xdr.ScVal {
+	bytes := xdr.ScBytes(b)
+	return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}
+}
@@ -0,0 +1,76 @@
+package soroban_test
+
+import (
+	"crypto/sha256"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+func authEntry(kp *keypair.Full, function string) xdr.SorobanAuthorizationEntry {
+	contract := xdr.ContractId{1}
+	account := xdr.MustAddress(kp.Address())
+	return xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type: xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{
+				Address: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account},
+				Nonce:   7,
+			},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type: xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contract},
+					FunctionName:    xdr.ScSymbol(function),
+				},
+			},
+		},
+	}
+}
+
+func TestSignAuthEntries(t *testing.T) {
+	user, admin := keypair.MustRandom(), keypair.MustRandom()
+	entries := []xdr.SorobanAuthorizationEntry{
+		authEntry(user, "transfer"),
+		authEntry(admin, "set_config"),
+		{Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount}},
+	}
+	signed, err := soroban.SignAuthEntries(entries, []*keypair.Full{admin, user}, 1000, network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for i, kp := range []*keypair.Full{user, admin} {
+		credentials := signed[i].Credentials.Address
+		if credentials.SignatureExpirationLedger != 1000 {
+			t.Fatal(credentials)
+		}
+		preimage := xdr.HashIdPreimage{
+			Type: xdr.EnvelopeTypeEnvelopeTypeSorobanAuthorization,
+			SorobanAuthorization: &xdr.HashIdPreimageSorobanAuthorization{
+				NetworkId:                 sha256.Sum256([]byte(network.TestNetworkPassphrase)),
+				Nonce:                     7,
+				SignatureExpirationLedger: 1000,
+				Invocation:                signed[i].RootInvocation,
+			},
+		}
+		b, _ := preimage.MarshalBinary()
+		payload := sha256.Sum256(b)
+		signature := *(**credentials.Signature.Vec)[0].Map
+		if err := kp.Verify(payload[:], *(*signature)[1].Val.Bytes); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if signed[2].Credentials.Address != nil {
+		t.Fatal(signed[2])
+	}
+
+	_, err = soroban.SignAuthEntries(entries, []*keypair.Full{user}, 1000, network.TestNetworkPassphrase)
+	if err == nil {
+		t.Fatal("signed without the admin signer")
+	}
+}
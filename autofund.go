@@ -0,0 +1,105 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorFundFailed = "Funding the source account failed"
+)
+
+// autoFund returns whether the client funds the missing or underfunded
+// source accounts, never on the public network
+func autoFund(client SorobanClient) bool {
+	return config(client).AutoFund && client.NetworkPassphrase() != network.PublicNetworkPassphrase
+}
+
+// fundSource funds the source account if the submission was rejected because
+// it is missing or underfunded, and loads its sequence number again. It
+// returns whether the transaction can be sent again, the rejection is kept
+// otherwise.
+func (t *Transaction) fundSource(ctx context.Context, res *SendTransactionResult) bool {
+	if res.Status != "ERROR" || t.envelope != nil {
+		return false
+	}
+	var result xdr.TransactionResult
+	if err := xdr.SafeUnmarshalBase64(res.ErrorResultXdr, &result); err != nil {
+		return false
+	}
+	switch result.Result.Code {
+	case xdr.TransactionResultCodeTxNoAccount, xdr.TransactionResultCodeTxInsufficientBalance:
+	default:
+		return false
+	}
+	if err := t.fund(ctx); err != nil {
+		debug(ctx, t.client, "fund", slog.String("error", err.Error()))
+		return false
+	}
+	if err := t.reloadSource(ctx); err != nil {
+		debug(ctx, t.client, "fund", slog.String("error", err.Error()))
+		return false
+	}
+	return true
+}
+
+// fund funds the source account, from the root account on the local
+// network, with friendbot otherwise, which only creates accounts
+func (t *Transaction) fund(ctx context.Context) error {
+	accountID := t.build.source.GetAccountID()
+	debug(ctx, t.client, "fund", slog.String("account", accountID))
+	if funder, ok := t.client.(rootFunder); ok && t.client.NetworkPassphrase() == LocalNetworkPassphrase {
+		pending, err := funder.FundFromRoot(ctx, accountID, "")
+		if err != nil {
+			return fmt.Errorf("%s: %w", ErrorFundFailed, err)
+		}
+		if _, err := pending.Wait(ctx); err != nil {
+			return fmt.Errorf("%s: %w", ErrorFundFailed, err)
+		}
+		return nil
+	}
+	res, err := t.client.Fund(ctx, accountID)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrorFundFailed, err)
+	}
+	defer res.Body.Close()
+	if res.StatusCode != http.StatusOK {
+		return fmt.Errorf("%s: %s", ErrorFundFailed, res.Status)
+	}
+	return nil
+}
+
+// reloadSource loads the sequence number of the source account again, after
+// it was incremented by a rejected submission or the account was created
+func (t *Transaction) reloadSource(ctx context.Context) error {
+	if t.build.sequence != 0 {
+		return nil
+	}
+	switch source := t.build.source.(type) {
+	case *LazyAccount:
+		source.Refresh()
+		return t.loadSource(ctx)
+	case *Account:
+		account, err := t.client.GetAccount(ctx, source.AccountId)
+		if err != nil {
+			return err
+		}
+		source.Sequence = account.Sequence
+	case *txnbuild.SimpleAccount:
+		account, err := t.client.GetAccount(ctx, source.AccountID)
+		if err != nil {
+			return err
+		}
+		source.Sequence = account.Sequence
+	default:
+		return errors.New("source account can not be reloaded")
+	}
+	return nil
+}
@@ -0,0 +1,96 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestAutoFund(t *testing.T) {
+	pair := keypair.MustRandom()
+	funded := false
+	var sequences []int64
+	noAccount, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxNoAccount}})
+	account, _ := xdr.MarshalBase64(xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{
+		AccountId: xdr.MustAddress(pair.Address()),
+		SeqNum:    20,
+	}})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/friendbot" {
+			funded = true
+			return
+		}
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				Transaction string `json:"transaction"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		result := `{"entries":[],"latestLedger":7}`
+		switch {
+		case req.Method == soroban.SendTransaction:
+			var envelope xdr.TransactionEnvelope
+			xdr.SafeUnmarshalBase64(req.Params.Transaction, &envelope)
+			sequences = append(sequences, envelope.SeqNum())
+			result = `{"hash":"abc","status":"PENDING","latestLedger":7}`
+			if !funded {
+				result = fmt.Sprintf(`{"hash":"abc","status":"ERROR","errorResultXdr":%q,"latestLedger":7}`, noAccount)
+			}
+		case funded:
+			result = fmt.Sprintf(`{"entries":[{"xdr":%q}],"latestLedger":7}`, account)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase, FriendbotURL: server.URL + "/friendbot", AutoFund: true}
+	client.URL = server.URL
+	send := func(source txnbuild.Account) (*soroban.PendingTransaction, error) {
+		return soroban.NewTransctionBuilder().
+			Client(client).
+			SourceAccount(source).
+			Signer(pair).
+			Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+			Send(context.Background())
+	}
+
+	// rejected, funded and sent again with the sequence number of the new account
+	pending, err := send(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if pending.Sent().Status != "PENDING" || len(sequences) != 2 || sequences[0] != 2 || sequences[1] != 21 {
+		t.Fatal(pending.Sent().Status, sequences)
+	}
+
+	// missing while loading
+	funded, sequences = false, nil
+	if _, err := send(soroban.NewLazyAccount(pair.Address())); err != nil {
+		t.Fatal(err)
+	}
+	if !funded || len(sequences) != 1 || sequences[0] != 21 {
+		t.Fatal(funded, sequences)
+	}
+
+	// never on the public network
+	funded, sequences = false, nil
+	client.PassPhrase = network.PublicNetworkPassphrase
+	pending, err = send(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if funded || pending.Sent().Status != "ERROR" || len(sequences) != 1 {
+		t.Fatal(funded, sequences)
+	}
+}
@@ -0,0 +1,143 @@
+package soroban
+
+import (
+	"context"
+	"time"
+)
+
+// TransactionHandler processes a transaction of Backfill, an error stops it
+type TransactionHandler func(ctx context.Context, tx TransactionInfo) error
+
+// Backfill processes the transactions of a historical ledger range with a
+// handler, at a controlled rate so it does not starve the live work sharing
+// the RPC server. With Follow, once it catches up with the latest ledger it
+// becomes the live tail, from the same cursor, so no transaction is skipped or
+// processed twice.
+//
+//	Example:
+//	 last, err := client.Backfill(1000, 0, handler).
+//		LedgersPerMinute(600).
+//		CallsPerMinute(60).
+//		Follow(5 * time.Second).
+//		Run(ctx)
+type Backfill struct {
+	client           Client
+	start            int64
+	end              int64
+	handler          TransactionHandler
+	limit            uint
+	cursor           string
+	ledgersPerMinute int
+	callsPerMinute   int
+	follow           time.Duration
+}
+
+// Backfill returns a Backfill of the transactions from startLedger to
+// endLedger, both included. With endLedger 0 it runs up to the latest ledger.
+func (c Client) Backfill(startLedger, endLedger int64, handler TransactionHandler) *Backfill {
+	return &Backfill{client: c, start: startLedger, end: endLedger, handler: handler}
+}
+
+// LedgersPerMinute sets the maximum number of ledgers processed per minute
+func (b *Backfill) LedgersPerMinute(n int) *Backfill {
+	b.ledgersPerMinute = n
+	return b
+}
+
+// CallsPerMinute sets the budget of getTransactions calls per minute
+func (b *Backfill) CallsPerMinute(n int) *Backfill {
+	b.callsPerMinute = n
+	return b
+}
+
+// Limit sets the number of transactions requested per call
+func (b *Backfill) Limit(limit uint) *Backfill {
+	b.limit = limit
+	return b
+}
+
+// Cursor resumes the backfill after cursor, the paging token returned by a
+// previous Run, instead of the start ledger
+func (b *Backfill) Cursor(cursor string) *Backfill {
+	b.cursor = cursor
+	return b
+}
+
+// Follow keeps processing the new transactions once the backfill catches up
+// with the latest ledger, polling every interval, until ctx is done. The rate
+// limits do not apply to the live tail.
+func (b *Backfill) Follow(interval time.Duration) *Backfill {
+	b.follow = interval
+	return b
+}
+
+// Run processes the range, returning the paging token of the last processed
+// transaction, to resume with Cursor, and the error that stopped it, if any.
+// A followed backfill runs until ctx is done.
+func (b *Backfill) Run(ctx context.Context) (string, error) {
+	last := b.cursor
+	cursor := b.cursor
+	began := time.Now()
+	calls := int64(0)
+	live := false
+	for {
+		if !live {
+			if err := pace(ctx, began, calls, b.callsPerMinute); err != nil {
+				return last, err
+			}
+		}
+		res, err := b.client.GetTransactions(ctx, b.start, &TransactionsOptions{Cursor: cursor, Limit: b.limit})
+		if err != nil {
+			return last, err
+		}
+		calls++
+		for _, tx := range res.Transactions {
+			if b.end != 0 && tx.Ledger > b.end {
+				return last, nil
+			}
+			if !live {
+				if err := pace(ctx, began, tx.Ledger-b.start, b.ledgersPerMinute); err != nil {
+					return last, err
+				}
+			}
+			if err := b.handler(ctx, tx); err != nil {
+				return last, err
+			}
+			last = tx.PagingToken()
+		}
+		caughtUp := len(res.Transactions) == 0 || res.Cursor == "" || res.Cursor == cursor
+		if res.Cursor != "" {
+			cursor = res.Cursor
+		}
+		if !caughtUp {
+			continue
+		}
+		if b.follow == 0 || (b.end != 0 && res.LatestLedger >= b.end) {
+			return last, nil
+		}
+		live = true
+		select {
+		case <-ctx.Done():
+			return last, ctx.Err()
+		case <-time.After(b.follow):
+		}
+	}
+}
+
+// pace waits until n units at perMinute fit in the time since began, no
+// limit if perMinute is 0
+func pace(ctx context.Context, began time.Time, n int64, perMinute int) error {
+	if perMinute <= 0 || n <= 0 {
+		return nil
+	}
+	wait := time.Until(began.Add(time.Duration(n) * time.Minute / time.Duration(perMinute)))
+	if wait <= 0 {
+		return nil
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(wait):
+		return nil
+	}
+}
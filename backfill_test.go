@@ -0,0 +1,126 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/network"
+)
+
+// transactionsServer serves getTransactions of one transaction per ledger
+// from 10 to latest, the cursor is the ledger of the last one returned
+type transactionsServer struct {
+	mu     sync.Mutex
+	latest int64
+}
+
+func (s *transactionsServer) client(t *testing.T) soroban.Client {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Params struct {
+				StartLedger int64 `json:"startLedger"`
+				Pagination  struct {
+					Cursor string `json:"cursor"`
+					Limit  int64  `json:"limit"`
+				} `json:"pagination"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		from := req.Params.StartLedger
+		cursor := req.Params.Pagination.Cursor
+		if cursor != "" {
+			fmt.Sscan(cursor, &from)
+			from++
+		}
+		limit := req.Params.Pagination.Limit
+		if limit == 0 {
+			limit = 10
+		}
+		var txs []string
+		for ledger := from; ledger <= s.latest && int64(len(txs)) < limit; ledger++ {
+			txs = append(txs, fmt.Sprintf(`{"status":"SUCCESS","txHash":"%d","ledger":%d,"applicationOrder":1}`, ledger, ledger))
+			cursor = fmt.Sprint(ledger)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":%d,"cursor":"%s","transactions":[%s]}}`,
+			req.ID, s.latest, cursor, strings.Join(txs, ","))
+	}))
+	t.Cleanup(server.Close)
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	return client
+}
+
+func TestBackfill(t *testing.T) {
+	server := &transactionsServer{latest: 20}
+	client := server.client(t)
+
+	var hashes []string
+	began := time.Now()
+	last, err := client.Backfill(10, 13, func(ctx context.Context, tx soroban.TransactionInfo) error {
+		hashes = append(hashes, tx.TxHash)
+		return nil
+	}).Limit(2).LedgersPerMinute(1200).Run(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(hashes, ",") != "10,11,12,13" {
+		t.Fatal(hashes)
+	}
+	// 3 ledgers after the start at 50ms each
+	if elapsed := time.Since(began); elapsed < 150*time.Millisecond {
+		t.Fatal("not rate limited", elapsed)
+	}
+	if last != (soroban.TOID{Ledger: 13, ApplicationOrder: 1}).PagingToken() {
+		t.Fatal(last)
+	}
+
+	stop := errors.New("stop")
+	hashes = nil
+	_, err = client.Backfill(10, 0, func(ctx context.Context, tx soroban.TransactionInfo) error {
+		hashes = append(hashes, tx.TxHash)
+		return stop
+	}).Run(context.Background())
+	if err != stop || len(hashes) != 1 {
+		t.Fatal(err, hashes)
+	}
+}
+
+func TestBackfillFollow(t *testing.T) {
+	server := &transactionsServer{latest: 11}
+	client := server.client(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var hashes []string
+	_, err := client.Backfill(10, 0, func(ctx context.Context, tx soroban.TransactionInfo) error {
+		hashes = append(hashes, tx.TxHash)
+		if tx.Ledger == 11 {
+			// a new ledger closes once the backfill caught up
+			server.mu.Lock()
+			server.latest = 12
+			server.mu.Unlock()
+		}
+		if tx.Ledger == 12 {
+			cancel()
+		}
+		return nil
+	}).Limit(10).Follow(10 * time.Millisecond).Run(ctx)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatal(err)
+	}
+	if strings.Join(hashes, ",") != "10,11,12" {
+		t.Fatal(hashes)
+	}
+}
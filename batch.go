@@ -0,0 +1,246 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	DefaultBatchChunkSize = 100
+
+	ErrorBatchItemExceedsLimits = "Batch item exceeds the resource limits on its own"
+)
+
+// BatchLimits are the resources a chunk of a Batch is sized below, a chunk
+// whose simulated resources exceed them is split in half
+type BatchLimits struct {
+	Instructions  uint32
+	DiskReadBytes uint32
+	WriteBytes    uint32
+	// ReadEntries and WriteEntries limit the keys of the footprint
+	ReadEntries  int
+	WriteEntries int
+}
+
+// DefaultBatchLimits are below the per transaction limits of mainnet, leaving
+// a margin for the simulation underestimating
+var DefaultBatchLimits = BatchLimits{
+	Instructions:  80_000_000,
+	DiskReadBytes: 160_000,
+	WriteBytes:    100_000,
+	ReadEntries:   80,
+	WriteEntries:  40,
+}
+
+type (
+	// Batch invokes a function of a contract over a large collection of items,
+	// e.g. an airdrop to many addresses, split in chunks of invocations sized
+	// below the resource limits using the simulation of each chunk.
+	Batch struct {
+		contract  *Contract
+		function  string
+		items     []xdr.ScVal
+		args      func(chunk []xdr.ScVal) []xdr.ScVal
+		chunkSize int
+		limits    BatchLimits
+		parallel  bool
+	}
+
+	// BatchChunk is an invocation of a Batch, with the items in
+	// [Start, End)
+	BatchChunk struct {
+		Start   int
+		End     int
+		Pending *PendingTransaction
+		Result  *GetTransactionResult
+		Err     error
+		// AuditErr is the *AuditError of a chunk submitted without its
+		// audit record, the chunk is still waited on
+		AuditErr error
+	}
+
+	// BatchResult are the chunks a Batch was split into, in order
+	BatchResult struct {
+		Chunks []BatchChunk
+	}
+)
+
+// Batch starts a Batch invoking function over items. By default each chunk
+// is invoked with the items of the chunk as a single vec param.
+//
+//	Requires client, sourceAccount, keyPair, salt
+func (c *Contract) Batch(function string, items []xdr.ScVal) *Batch {
+	return &Batch{
+		contract:  c,
+		function:  function,
+		items:     items,
+		args:      func(chunk []xdr.ScVal) []xdr.ScVal { return []xdr.ScVal{NewScVec(chunk...)} },
+		chunkSize: DefaultBatchChunkSize,
+		limits:    DefaultBatchLimits,
+	}
+}
+
+// Args sets how the params of a chunk invocation are built from its items
+func (b *Batch) Args(args func(chunk []xdr.ScVal) []xdr.ScVal) *Batch {
+	b.args = args
+	return b
+}
+
+// ChunkSize sets the maximum items of a chunk, DefaultBatchChunkSize by default
+func (b *Batch) ChunkSize(size int) *Batch {
+	b.chunkSize = size
+	return b
+}
+
+// Limits sets the resources a chunk is sized below, DefaultBatchLimits by
+// default
+func (b *Batch) Limits(limits BatchLimits) *Batch {
+	b.limits = limits
+	return b
+}
+
+// Parallel submits every chunk without waiting for the previous one to be
+// finalized, waiting for all of them at the end. The chunks are still
+// submitted in order, as they share the source account.
+func (b *Batch) Parallel() *Batch {
+	b.parallel = true
+	return b
+}
+
+// Send invokes the batch. A chunk that fails to simulate, or whose simulated
+// resources exceed the limits, is split in half, isolating the failing items
+// in chunks of their own, and the size doubles again, up to ChunkSize, after
+// every chunk. Like an invocation, each chunk waits for the Contract.Fence,
+// held until it is finalized, and counts in the Contract.Quota once
+// simulated. It only returns an error if ctx is done, the errors of each
+// chunk are reported in the BatchResult.
+func (b *Batch) Send(ctx context.Context) (*BatchResult, error) {
+	result := &BatchResult{}
+	chunkSize := max(b.chunkSize, 1)
+	size := chunkSize
+	for start := 0; start < len(b.items); {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		end := min(start+size, len(b.items))
+		invoker := &invokeBuilder{
+			contract: b.contract,
+			build:    &invokeBuild{function: b.function, prams: b.args(b.items[start:end])},
+		}
+		release, err := invoker.fence(ctx)
+		var transaction *Transaction
+		if err == nil {
+			transaction, err = b.simulate(ctx, invoker.build)
+		}
+		if err != nil && end-start > 1 && ctx.Err() == nil {
+			release.after(nil, nil)
+			size = (end - start) / 2
+			continue
+		}
+		if err == nil {
+			err = b.contract.quota.take(b.function)
+		}
+		chunk := BatchChunk{Start: start, End: end, Err: err}
+		if err == nil {
+			chunk.send(release.after(transaction.Send(ctx)))
+		} else {
+			release.after(nil, nil)
+		}
+		if !b.parallel && chunk.Pending != nil {
+			chunk.wait(ctx)
+		}
+		result.Chunks = append(result.Chunks, chunk)
+		start = end
+		size = min(size*2, chunkSize)
+	}
+	if b.parallel {
+		var wg sync.WaitGroup
+		for i := range result.Chunks {
+			if result.Chunks[i].Pending == nil {
+				continue
+			}
+			wg.Add(1)
+			go func(chunk *BatchChunk) {
+				defer wg.Done()
+				chunk.wait(ctx)
+			}(&result.Chunks[i])
+		}
+		wg.Wait()
+	}
+	return result, ctx.Err()
+}
+
+// simulate simulates the chunk of build, returning an error if it exceeds
+// the limits
+func (b *Batch) simulate(ctx context.Context, build *invokeBuild) (*Transaction, error) {
+	transaction, err := b.contract.invokeTransaction(build)
+	if err != nil {
+		return nil, err
+	}
+	res, err := transaction.Simulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !b.limits.fits(res.Data.Resources) {
+		return nil, errors.New(ErrorBatchItemExceedsLimits)
+	}
+	return transaction, nil
+}
+
+func (l BatchLimits) fits(r xdr.SorobanResources) bool {
+	footprint := r.Footprint
+	return (l.Instructions == 0 || uint32(r.Instructions) <= l.Instructions) &&
+		(l.DiskReadBytes == 0 || uint32(r.DiskReadBytes) <= l.DiskReadBytes) &&
+		(l.WriteBytes == 0 || uint32(r.WriteBytes) <= l.WriteBytes) &&
+		(l.ReadEntries == 0 || len(footprint.ReadOnly)+len(footprint.ReadWrite) <= l.ReadEntries) &&
+		(l.WriteEntries == 0 || len(footprint.ReadWrite) <= l.WriteEntries)
+}
+
+// send records the result of sending the chunk, a pending transaction with
+// an error is submitted, the error is an *AuditError
+func (c *BatchChunk) send(pending *PendingTransaction, err error) {
+	c.Pending = pending
+	if pending == nil {
+		c.Err = err
+		return
+	}
+	c.AuditErr = err
+}
+
+func (c *BatchChunk) wait(ctx context.Context) {
+	res, err := c.Pending.Wait(ctx)
+	c.Result = res
+	switch {
+	case err != nil:
+		c.Err = err
+	case res.Status != "SUCCESS":
+		c.Err = fmt.Errorf("%s: %s %s", ErrorTransactionRejected, res.TxHash, res.Status)
+	}
+}
+
+// Failed returns the chunks that failed to be simulated, submitted or
+// finalized
+func (r *BatchResult) Failed() []BatchChunk {
+	var failed []BatchChunk
+	for _, chunk := range r.Chunks {
+		if chunk.Err != nil {
+			failed = append(failed, chunk)
+		}
+	}
+	return failed
+}
+
+// Succeeded returns the number of items invoked successfully
+func (r *BatchResult) Succeeded() int {
+	n := 0
+	for _, chunk := range r.Chunks {
+		if chunk.Err == nil {
+			n += chunk.End - chunk.Start
+		}
+	}
+	return n
+}
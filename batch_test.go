@@ -0,0 +1,115 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestBatch(t *testing.T) {
+	contract, fake := fakeContract()
+	contract.Address(contractAddress(1))
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			return nil, err
+		}
+		args := envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.Args
+		items := **args[0].Vec
+		for _, item := range items {
+			if *item.U32 == 7 {
+				return &soroban.SimulateTransactionResult{Error: "HostError: item 7"}, nil
+			}
+		}
+		data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Instructions: xdr.Uint32(10_000_000 * len(items))},
+		})
+		return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 100}, nil
+	}
+
+	var items []xdr.ScVal
+	for i := range 10 {
+		u := xdr.Uint32(i)
+		items = append(items, xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u})
+	}
+	res, err := contract.Batch("airdrop", items).
+		ChunkSize(4).
+		Limits(soroban.BatchLimits{Instructions: 25_000_000}).
+		Parallel().
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := res.Failed()
+	if res.Succeeded() != 9 || len(failed) != 1 || failed[0].Start != 7 || failed[0].End != 8 {
+		t.Fatal(res.Chunks)
+	}
+	for _, chunk := range res.Chunks {
+		if chunk.End-chunk.Start > 2 {
+			t.Fatal("chunk above the limits", chunk)
+		}
+	}
+}
+
+func TestBatchChunkSizeRecovers(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			return nil, err
+		}
+		args := envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.Args
+		for _, item := range **args[0].Vec {
+			if *item.U32 == 1 {
+				return &soroban.SimulateTransactionResult{Error: "HostError: item 1"}, nil
+			}
+		}
+		data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+		return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+	}
+
+	var items []xdr.ScVal
+	for i := range 12 {
+		u := xdr.Uint32(i)
+		items = append(items, xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u})
+	}
+	res, err := contract.Batch("airdrop", items).ChunkSize(4).Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the chunks after the failing item are back to the chunk size
+	var sizes []int
+	for _, chunk := range res.Chunks {
+		sizes = append(sizes, chunk.End-chunk.Start)
+	}
+	if fmt.Sprint(sizes) != "[1 1 2 4 4]" || res.Succeeded() != 11 {
+		t.Fatal(sizes, res.Chunks)
+	}
+}
+
+func TestBatchAuditError(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	full := errors.New("disk full")
+	// submitted, but not audited
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, &soroban.AuditError{Err: full}
+	}
+	res, err := contract.Batch("airdrop", []xdr.ScVal{u32(1), u32(2)}).ChunkSize(1).Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Succeeded() != 2 || len(res.Chunks) != 2 {
+		t.Fatal(res.Chunks)
+	}
+	for _, chunk := range res.Chunks {
+		if chunk.Result == nil || chunk.Result.Status != "SUCCESS" || !errors.Is(chunk.AuditErr, full) {
+			t.Fatal(chunk)
+		}
+	}
+}
@@ -0,0 +1,161 @@
+package soroban
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorScValOverflow  = "Integer overflows the type"
+	ErrorInvalidDecimal = "Invalid decimal"
+)
+
+// ScValToBigInt returns the integer of val, of any integer type, i128, u128,
+// i256 and u256 included
+func ScValToBigInt(val xdr.ScVal) (*big.Int, error) {
+	switch val.Type {
+	case xdr.ScValTypeScvI32:
+		return big.NewInt(int64(*val.I32)), nil
+	case xdr.ScValTypeScvI64:
+		return big.NewInt(int64(*val.I64)), nil
+	case xdr.ScValTypeScvU32:
+		return new(big.Int).SetUint64(uint64(*val.U32)), nil
+	case xdr.ScValTypeScvU64:
+		return new(big.Int).SetUint64(uint64(*val.U64)), nil
+	case xdr.ScValTypeScvI128:
+		return fromWords(true, uint64(val.I128.Hi), uint64(val.I128.Lo)), nil
+	case xdr.ScValTypeScvU128:
+		return fromWords(false, uint64(val.U128.Hi), uint64(val.U128.Lo)), nil
+	case xdr.ScValTypeScvI256:
+		p := val.I256
+		return fromWords(true, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)), nil
+	case xdr.ScValTypeScvU256:
+		p := val.U256
+		return fromWords(false, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)), nil
+	}
+	return nil, fmt.Errorf("%s: %s is not an integer", ErrorScValTypeMismatch, val.Type)
+}
+
+// ScValToInt64 returns the integer of val, of any integer type, failing with
+// ErrorScValOverflow if it does not fit in an int64
+func ScValToInt64(val xdr.ScVal) (int64, error) {
+	n, err := ScValToBigInt(val)
+	if err != nil {
+		return 0, err
+	}
+	if !n.IsInt64() {
+		return 0, fmt.Errorf("%s: %s into int64", ErrorScValOverflow, n)
+	}
+	return n.Int64(), nil
+}
+
+// BigIntToI128 returns n as an i128, failing with ErrorScValOverflow if it
+// does not fit
+func BigIntToI128(n *big.Int) (xdr.ScVal, error) {
+	words, err := toWords(n, true, 2)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	parts := xdr.Int128Parts{Hi: xdr.Int64(words[0]), Lo: xdr.Uint64(words[1])}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &parts}, nil
+}
+
+// BigIntToU128 returns n as an u128, failing with ErrorScValOverflow if it
+// is negative or does not fit
+func BigIntToU128(n *big.Int) (xdr.ScVal, error) {
+	words, err := toWords(n, false, 2)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	parts := xdr.UInt128Parts{Hi: xdr.Uint64(words[0]), Lo: xdr.Uint64(words[1])}
+	return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &parts}, nil
+}
+
+// ScValToDecimal returns the integer of val as a decimal string with scale
+// digits after the point, e.g. 12345 with scale 2 is "123.45", the amounts
+// of tokens with 7 decimals have scale 7
+func ScValToDecimal(val xdr.ScVal, scale int) (string, error) {
+	n, err := ScValToBigInt(val)
+	if err != nil {
+		return "", err
+	}
+	return FormatDecimal(n, scale), nil
+}
+
+// FormatDecimal returns n as a decimal string with scale digits after the
+// point
+func FormatDecimal(n *big.Int, scale int) string {
+	digits := new(big.Int).Abs(n).String()
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+	}
+	if scale <= 0 {
+		return sign + digits
+	}
+	if len(digits) <= scale {
+		digits = strings.Repeat("0", scale-len(digits)+1) + digits
+	}
+	point := len(digits) - scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
+// ParseDecimal returns the integer of the decimal string s with scale digits
+// after the point, e.g. "123.45" with scale 2 is 12345. It fails with
+// ErrorInvalidDecimal if s has more digits after the point.
+func ParseDecimal(s string, scale int) (*big.Int, error) {
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > scale {
+		return nil, fmt.Errorf("%s: %q has more than %d decimals", ErrorInvalidDecimal, s, scale)
+	}
+	n, ok := new(big.Int).SetString(whole+fraction+strings.Repeat("0", scale-len(fraction)), 10)
+	if !ok || whole == "" || whole == "-" || strings.HasPrefix(fraction, "-") || strings.HasPrefix(fraction, "+") {
+		return nil, fmt.Errorf("%s: %q", ErrorInvalidDecimal, s)
+	}
+	return n, nil
+}
+
+// fromWords returns the integer of the big endian words, in two's complement
+// if signed
+func fromWords(signed bool, words ...uint64) *big.Int {
+	n := new(big.Int)
+	for _, w := range words {
+		n.Lsh(n, 64).Or(n, new(big.Int).SetUint64(w))
+	}
+	if signed && words[0]>>63 == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(64*len(words))))
+	}
+	return n
+}
+
+// toWords returns n as count big endian words, in two's complement if
+// signed
+func toWords(n *big.Int, signed bool, count int) ([]uint64, error) {
+	if n == nil {
+		return nil, errors.New(ErrorScValOverflow + ": nil")
+	}
+	bits := 64 * count
+	m := new(big.Int).Set(n)
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		if m.Cmp(limit) >= 0 || m.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("%s: %s into i%d", ErrorScValOverflow, n, bits)
+		}
+		if m.Sign() < 0 {
+			m.Add(m, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+		}
+	} else if m.Sign() < 0 || m.BitLen() > bits {
+		return nil, fmt.Errorf("%s: %s into u%d", ErrorScValOverflow, n, bits)
+	}
+	words := make([]uint64, count)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := count - 1; i >= 0; i-- {
+		words[i] = new(big.Int).And(m, mask).Uint64()
+		m.Rsh(m, 64)
+	}
+	return words, nil
+}
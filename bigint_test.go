@@ -0,0 +1,113 @@
+package soroban_test
+
+import (
+	"math"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestBigIntI128(t *testing.T) {
+	maxI128 := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 127), big.NewInt(1))
+	minI128 := new(big.Int).Neg(new(big.Int).Lsh(big.NewInt(1), 127))
+	for _, n := range []*big.Int{big.NewInt(0), big.NewInt(-1), big.NewInt(math.MinInt64), new(big.Int).Lsh(big.NewInt(3), 70), maxI128, minI128} {
+		val, err := soroban.BigIntToI128(n)
+		if err != nil {
+			t.Fatal(n, err)
+		}
+		back, err := soroban.ScValToBigInt(val)
+		if err != nil || back.Cmp(n) != 0 {
+			t.Fatal(n, back, err)
+		}
+	}
+	if val, _ := soroban.BigIntToI128(big.NewInt(-1)); val.I128.Hi != -1 || val.I128.Lo != math.MaxUint64 {
+		t.Fatal(val.I128)
+	}
+	overflow := new(big.Int).Add(maxI128, big.NewInt(1))
+	if _, err := soroban.BigIntToI128(overflow); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValOverflow) {
+		t.Fatal(err)
+	}
+	if _, err := soroban.BigIntToU128(big.NewInt(-1)); err == nil {
+		t.Fatal("negative u128")
+	}
+	val, err := soroban.BigIntToU128(overflow)
+	if err != nil || val.U128.Hi != 1<<63 || val.U128.Lo != 0 {
+		t.Fatal(val, err)
+	}
+
+	if _, err := soroban.ScValToInt64(val); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValOverflow) {
+		t.Fatal(err)
+	}
+	small, _ := soroban.BigIntToI128(big.NewInt(-42))
+	if n, err := soroban.ScValToInt64(small); err != nil || n != -42 {
+		t.Fatal(n, err)
+	}
+	i256 := xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &xdr.Int256Parts{HiHi: -1, HiLo: math.MaxUint64, LoHi: math.MaxUint64, LoLo: math.MaxUint64 - 1}}
+	if n, err := soroban.ScValToBigInt(i256); err != nil || n.Int64() != -2 {
+		t.Fatal(n, err)
+	}
+}
+
+func TestBigIntFromScVal(t *testing.T) {
+	amount, _ := soroban.BigIntToI128(new(big.Int).Lsh(big.NewInt(1), 100))
+	var n big.Int
+	if err := soroban.FromScVal(amount, &n); err != nil || n.BitLen() != 101 {
+		t.Fatal(n.String(), err)
+	}
+	val, err := soroban.ToScVal(&n)
+	if err != nil || val.Type != xdr.ScValTypeScvI128 || !soroban.ScValEqual(val, amount) {
+		t.Fatal(val, err)
+	}
+
+	var i int64
+	if err := soroban.FromScVal(amount, &i); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValOverflow) {
+		t.Fatal(err)
+	}
+	small, _ := soroban.BigIntToI128(big.NewInt(300))
+	if err := soroban.FromScVal(small, &i); err != nil || i != 300 {
+		t.Fatal(i, err)
+	}
+	var b int8
+	if err := soroban.FromScVal(small, &b); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValOverflow) {
+		t.Fatal(err)
+	}
+	unsigned, _ := soroban.BigIntToU128(big.NewInt(300))
+	var u uint64
+	if err := soroban.FromScVal(unsigned, &u); err != nil || u != 300 {
+		t.Fatal(u, err)
+	}
+}
+
+func TestDecimal(t *testing.T) {
+	for _, c := range []struct {
+		n     int64
+		scale int
+		s     string
+	}{
+		{12345, 2, "123.45"},
+		{-5, 3, "-0.005"},
+		{10000000, 7, "1.0000000"},
+		{42, 0, "42"},
+	} {
+		val, _ := soroban.BigIntToI128(big.NewInt(c.n))
+		s, err := soroban.ScValToDecimal(val, c.scale)
+		if err != nil || s != c.s {
+			t.Fatal(c, s, err)
+		}
+		n, err := soroban.ParseDecimal(c.s, c.scale)
+		if err != nil || n.Int64() != c.n {
+			t.Fatal(c, n, err)
+		}
+	}
+	if n, err := soroban.ParseDecimal("1.5", 7); err != nil || n.Int64() != 15000000 {
+		t.Fatal(n, err)
+	}
+	for _, s := range []string{"1.234", "abc", "", ".5", "1.-5"} {
+		if _, err := soroban.ParseDecimal(s, 2); err == nil || !strings.Contains(err.Error(), soroban.ErrorInvalidDecimal) {
+			t.Fatal(s, err)
+		}
+	}
+}
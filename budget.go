@@ -0,0 +1,30 @@
+package soroban
+
+import (
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+// ErrRetryBudgetExhausted is returned when a retry, a restore or a
+// resubmission is not attempted because the Client RetryBudget is empty, and
+// joined to the error of an RPC call not retried for the same reason
+var ErrRetryBudgetExhausted = rpc.ErrRetryBudgetExhausted
+
+// NewRetryBudget returns a budget, to be used as the Client RetryBudget, of
+// perMinute retries per minute, up to burst at once. It is shared by the RPC
+// retries of the Client RetryPolicy, the restores of RestoreAndSend and the
+// resubmissions of OutboxWorker.
+//
+//	client.RetryBudget = soroban.NewRetryBudget(60, 10)
+func NewRetryBudget(perMinute int, burst int) *rpc.RetryBudget {
+	return rpc.NewRetryBudget(perMinute, burst)
+}
+
+// spendRetry takes a token of the retry budget of client, if it is Configured
+// with one, returning ErrRetryBudgetExhausted if there is none left
+func spendRetry(client SorobanClient) error {
+	budget := config(client).RetryBudget
+	if budget == nil || budget.Allow() {
+		return nil
+	}
+	return ErrRetryBudgetExhausted
+}
@@ -1,10 +1,16 @@
 package soroban
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
 
 	"github.com/sebamiro/soroban/internal/rpc"
 	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
 )
 
 // Client wrapper of rpc.Client
@@ -12,6 +18,47 @@ type Client struct {
 	rpc.Client
 	PassPhrase   string
 	FriendbotURL string
+	// ProtocolVersion of the network, set by AutoConfigure
+	ProtocolVersion int64
+	// Labels used when rendering addresses, optional
+	Labels *AddressBook
+	// Audit records every submitted envelope, optional
+	Audit AuditSink
+	// DefaultTimeout of the built transactions, if 0 DefaultTimeout is used
+	DefaultTimeout time.Duration
+	// ConfirmationDepth is the number of ledgers the data of watchers
+	// (WatchLedgerEntries, PendingTransaction) must be behind the latest
+	// ledger before it is delivered, 0 delivers it as soon as it is seen
+	ConfirmationDepth uint32
+	// DeliverProvisional makes WatchLedgerEntries emit changes not yet
+	// confirmed, flagged as Provisional
+	DeliverProvisional bool
+	// WaitOptions is the polling of the PendingTransactions, the zero value
+	// queries getTransaction once per closed ledger
+	WaitOptions WaitOptions
+	// MaxFee is the ceiling of the total fee of the sent transactions, see
+	// Transaction.MaxFee, 0 means no ceiling
+	MaxFee int64
+	// Logger receives the logs of the client and of the transactions and
+	// contracts using it: every stage of the transactions sent with
+	// Transaction.Send (assembled, submitted, confirmed or failed) at info
+	// level, or warn if failed, and the RPC calls, the simulations, the fees
+	// and the polling of the transactions at debug level, optional
+	Logger *slog.Logger
+	// CheckEnvelopes runs CheckEnvelope on the envelopes sent with
+	// Transaction.Send, see Transaction.ExpectSigners
+	CheckEnvelopes bool
+	// Metrics receives the measurements of the client, optional
+	Metrics Metrics
+	// AutoFund funds the source account of a transaction, and sends it
+	// again once, when it is rejected because the account is missing or
+	// underfunded. The local network funds it from the root account, other
+	// networks with friendbot, which only creates accounts. It is ignored on
+	// the public network.
+	AutoFund bool
+	// FeeEscalation resubmits the transactions rejected with
+	// TRY_AGAIN_LATER with a higher inclusion fee, optional
+	FeeEscalation *FeeEscalation
 }
 
 // Methods
@@ -22,6 +69,9 @@ const (
 	GetHealth           = "getHealth"
 	GetNetwork          = "getNetwork"
 	GetLedgerEntries    = "getLedgerEntries"
+	GetLatestLedger     = "getLatestLedger"
+	GetEvents           = "getEvents"
+	GetTransactions     = "getTransactions"
 )
 
 type transaction struct {
@@ -40,21 +90,42 @@ type SendTransactionResult struct {
 
 // SendTransaction sends a signed transaction and returns its result.
 // Returns an error if unmarshal, http call, etc; fail, NOT if the transaction faild.
+// If Audit is set the submission is recorded, if that fails an *AuditError is returned
+// along with the result, which is still valid.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/sendTransaction
-func (c Client) SendTransaction(tx *txnbuild.Transaction) (*SendTransactionResult, error) {
+func (c Client) SendTransaction(ctx context.Context, tx *txnbuild.Transaction) (*SendTransactionResult, error) {
 	base64, err := tx.Base64()
 	if err != nil {
 		return nil, err
 	}
+	return c.SendTransactionXDR(ctx, base64)
+}
+
+// SendTransactionXDR sends a signed transaction envelope, encoded as base64 XDR,
+// and returns its result. It allows submitting envelopes signed elsewhere, use
+// Track to wait for its final result.
+// It behaves like SendTransaction.
+func (c Client) SendTransactionXDR(ctx context.Context, envelopeXdr string) (*SendTransactionResult, error) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+	if err != nil {
+		return nil, err
+	}
 	var sendTransactionResult SendTransactionResult
-	err = c.CallResult(SendTransaction, &sendTransactionResult, transaction{base64})
+	submittedAt := time.Now()
+	err = c.CallResult(ctx, SendTransaction, &sendTransactionResult, transaction{envelopeXdr})
 	if err != nil {
+		if auditErr := c.audit(envelope, envelopeXdr, submittedAt, nil, err); auditErr != nil {
+			return nil, errors.Join(err, auditErr)
+		}
 		return nil, err
 	}
-	return &sendTransactionResult, nil
+	return &sendTransactionResult, c.audit(envelope, envelopeXdr, submittedAt, &sendTransactionResult, nil)
 }
 
 // SimulateTransactionResult as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/simulateTransaction
+// The base64 XDR fields are decoded by SimulateTransaction into Data,
+// Results[].Value, Results[].AuthEntries and RestorePreamble.Data.
 type SimulateTransactionResult struct {
 	Error           string   `json:"error,omitempty"`
 	TransactionData string   `json:"transactionData"`
@@ -62,43 +133,183 @@ type SimulateTransactionResult struct {
 	LatestLedger    int64    `json:"latestLedger"`
 	Events          []string `json:"events"`
 
-	Results []struct {
-		Auth []string `json:"auth"`
-		XDR  string   `json:"xdr"`
-	} `json:"results"`
+	Results []SimulateHostFunctionResult `json:"results"`
 
 	RestorePreamble struct {
 		MinResourceFee  int64  `json:"minResourceFee,string"`
 		TransactionData string `json:"transactionData"`
+
+		Data xdr.SorobanTransactionData `json:"-"`
 	} `json:"restorePreamble"`
 
-	StateChange struct {
-		Type   int    `json:"type"`
-		Key    string `json:"key"`
-		Before string `json:"before"`
-		After  string `json:"after"`
-	} `json:"stateChange"`
+	// StateChanges are the ledger entries the invocation would create,
+	// update or delete, as base64 XDR
+	StateChanges []SimulateStateChange `json:"stateChanges"`
+
+	// Data is the decoded TransactionData
+	Data xdr.SorobanTransactionData `json:"-"`
+
+	decoded bool
+}
+
+// SimulateHostFunctionResult is the result of the simulated host function
+type SimulateHostFunctionResult struct {
+	Auth []string `json:"auth"`
+	XDR  string   `json:"xdr"`
+
+	// Value is the decoded XDR, the return value
+	Value xdr.ScVal `json:"-"`
+	// AuthEntries are the decoded Auth
+	AuthEntries []xdr.SorobanAuthorizationEntry `json:"-"`
+}
+
+// Decode decodes the base64 XDR fields into the typed ones, including the
+// StateChanges, the empty ones are skipped. It is done by SimulateTransaction, results built elsewhere,
+// e.g. by a fake client, are decoded once on the first call.
+func (r *SimulateTransactionResult) Decode() error {
+	if r.decoded {
+		return nil
+	}
+	if r.TransactionData != "" {
+		if err := xdr.SafeUnmarshalBase64(r.TransactionData, &r.Data); err != nil {
+			return err
+		}
+	}
+	if r.RestorePreamble.TransactionData != "" {
+		if err := xdr.SafeUnmarshalBase64(r.RestorePreamble.TransactionData, &r.RestorePreamble.Data); err != nil {
+			return err
+		}
+	}
+	for i := range r.Results {
+		result := &r.Results[i]
+		if result.XDR != "" {
+			if err := xdr.SafeUnmarshalBase64(result.XDR, &result.Value); err != nil {
+				return err
+			}
+		}
+		result.AuthEntries = make([]xdr.SorobanAuthorizationEntry, len(result.Auth))
+		for j, auth := range result.Auth {
+			if err := xdr.SafeUnmarshalBase64(auth, &result.AuthEntries[j]); err != nil {
+				return err
+			}
+		}
+	}
+	for i := range r.StateChanges {
+		if err := r.StateChanges[i].decode(); err != nil {
+			return err
+		}
+	}
+	r.decoded = true
+	return nil
+}
+
+// SimulateStateChange is a change of a ledger entry by a simulated invocation
+type SimulateStateChange struct {
+	// Type is created, updated or deleted
+	Type   string `json:"type"`
+	Key    string `json:"key"`
+	Before string `json:"before"`
+	After  string `json:"after"`
+
+	// LedgerKey is the decoded Key
+	LedgerKey xdr.LedgerKey `json:"-"`
+	// BeforeEntry and AfterEntry are the decoded Before and After, nil when
+	// the entry is created or deleted
+	BeforeEntry *xdr.LedgerEntry `json:"-"`
+	AfterEntry  *xdr.LedgerEntry `json:"-"`
+}
+
+func (c *SimulateStateChange) decode() error {
+	if err := xdr.SafeUnmarshalBase64(c.Key, &c.LedgerKey); err != nil {
+		return err
+	}
+	for _, entry := range []struct {
+		xdr string
+		dst **xdr.LedgerEntry
+	}{{c.Before, &c.BeforeEntry}, {c.After, &c.AfterEntry}} {
+		if entry.xdr == "" {
+			continue
+		}
+		*entry.dst = &xdr.LedgerEntry{}
+		if err := xdr.SafeUnmarshalBase64(entry.xdr, *entry.dst); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // SimulateTransaction simulates a transaction and returns its result.
 // Returns an error if unmarshal, http call, etc; fail, NOT if the transaction faild.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/simulateTransaction
-func (c Client) SimulateTransaction(tx *txnbuild.Transaction) (*SimulateTransactionResult, error) {
+func (c Client) SimulateTransaction(ctx context.Context, tx *txnbuild.Transaction) (*SimulateTransactionResult, error) {
 	base64, err := tx.Base64()
 	if err != nil {
 		return nil, err
 	}
+	return c.SimulateTransactionXDR(ctx, base64)
+}
+
+// SimulateTransactionXDR simulates a transaction envelope encoded as base64 XDR.
+// It behaves like SimulateTransaction.
+func (c Client) SimulateTransactionXDR(ctx context.Context, envelopeXdr string) (*SimulateTransactionResult, error) {
+	return c.SimulateTransactionWithOptions(ctx, envelopeXdr, SimulateOptions{})
+}
+
+// ResourceConfig configures the resources of a simulation
+type ResourceConfig struct {
+	// InstructionLeeway is added to the simulated instructions, headroom for
+	// invocations whose cost changes between simulation and submission
+	InstructionLeeway uint64 `json:"instructionLeeway"`
+}
+
+// Auth modes of a simulation: enforce checks the authorization entries of
+// the transaction, record returns the ones required, record_allow_nonroot
+// also records the ones of non root invocations. The RPC default is enforce
+// if the transaction has entries, record otherwise.
+const (
+	AuthModeEnforce            = "enforce"
+	AuthModeRecord             = "record"
+	AuthModeRecordAllowNonroot = "record_allow_nonroot"
+)
+
+// SimulateOptions are the optional parameters of simulateTransaction
+type SimulateOptions struct {
+	ResourceConfig *ResourceConfig
+	// AuthMode is one of AuthModeEnforce, AuthModeRecord or
+	// AuthModeRecordAllowNonroot, the RPC default if empty
+	AuthMode string
+}
+
+type simulateTransaction struct {
+	Transaction    string          `json:"transaction"`
+	ResourceConfig *ResourceConfig `json:"resourceConfig,omitempty"`
+	AuthMode       string          `json:"authMode,omitempty"`
+}
+
+// SimulateTransactionWithOptions simulates a transaction envelope encoded as
+// base64 XDR with opts. It behaves like SimulateTransaction.
+//
+//	Example:
+//	 res, err := client.SimulateTransactionWithOptions(ctx, envelopeXdr, soroban.SimulateOptions{
+//		ResourceConfig: &soroban.ResourceConfig{InstructionLeeway: 1_000_000},
+//	 })
+func (c Client) SimulateTransactionWithOptions(ctx context.Context, envelopeXdr string, opts SimulateOptions) (*SimulateTransactionResult, error) {
 	var simulateTransactionResult SimulateTransactionResult
-	err = c.CallResult(SimulateTransaction, &simulateTransactionResult, transaction{base64})
+	params := simulateTransaction{Transaction: envelopeXdr, ResourceConfig: opts.ResourceConfig, AuthMode: opts.AuthMode}
+	err := c.CallResult(ctx, SimulateTransaction, &simulateTransactionResult, params)
 	if err != nil {
 		return nil, err
 	}
+	if err := simulateTransactionResult.Decode(); err != nil {
+		return nil, err
+	}
 	return &simulateTransactionResult, nil
 }
 
 // GetTransactionResult as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getTransaction
 type GetTransactionResult struct {
 	Status                string `json:"status"`
+	TxHash                string `json:"txHash"`
 	LatestLedger          int64  `json:"latestLedger"`
 	LatestLedgerCloseTime string `json:"latestLedgerCloseTime"`
 	OldestLedger          int64  `json:"oldestLedger"`
@@ -110,14 +321,16 @@ type GetTransactionResult struct {
 	EnvelopeXdr           string `json:"envelopeXdr"`
 	ResultXdr             string `json:"resultXdr"`
 	ResultMetaXdr         string `json:"resultMetaXdr"`
+	// DiagnosticEventsXdr are returned by the nodes emitting them
+	DiagnosticEventsXdr []string `json:"diagnosticEventsXdr,omitempty"`
 }
 
 // GetTransaction provides details about the specified transaction.
 // Returns an error if unmarshal, http call, etc; fail, NOT if the transaction faild.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getTransaction
-func (c Client) GetTransaction(hash string) (*GetTransactionResult, error) {
+func (c Client) GetTransaction(ctx context.Context, hash string) (*GetTransactionResult, error) {
 	var getTransactionResult GetTransactionResult
-	err := c.CallResult(GetTransaction, &getTransactionResult, struct {
+	err := c.CallResult(ctx, GetTransaction, &getTransactionResult, struct {
 		Hash string `json:"hash"`
 	}{hash})
 	if err != nil {
@@ -137,9 +350,9 @@ type GetHealthResult struct {
 // GetHealth provides details about the health of the network.
 // Returns an error if unmarshal, http call, etc; fail, NOT if the transaction faild.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getHealth
-func (c Client) GetHealth() (*GetHealthResult, error) {
+func (c Client) GetHealth(ctx context.Context) (*GetHealthResult, error) {
 	var getHealthResult GetHealthResult
-	err := c.CallResult(GetHealth, &getHealthResult)
+	err := c.CallResult(ctx, GetHealth, &getHealthResult)
 	if err != nil {
 		return nil, err
 	}
@@ -160,9 +373,9 @@ type GetLedgerEntry struct {
 
 // GetLedgerEntries provides details about the health of the network.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getLedgerEntries
-func (c Client) GetLedgerEntries(keys ...string) (*GetLedgerEntriesResult, error) {
+func (c Client) GetLedgerEntries(ctx context.Context, keys ...string) (*GetLedgerEntriesResult, error) {
 	var getLedgerEntriesResult GetLedgerEntriesResult
-	err := c.CallResult(GetLedgerEntries, &getLedgerEntriesResult, struct {
+	err := c.CallResult(ctx, GetLedgerEntries, &getLedgerEntriesResult, struct {
 		Keys []string `json:"keys"`
 	}{keys})
 	if err != nil {
@@ -181,25 +394,197 @@ type GetNetworkResult struct {
 // GetNetwork provides details about the the network.
 // Returns an error if unmarshal, http call, etc; fail, NOT if the transaction faild.
 // Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getNetwork
-func (c Client) GetNetwork() (*GetNetworkResult, error) {
+func (c Client) GetNetwork(ctx context.Context) (*GetNetworkResult, error) {
 	var getNetworkResult GetNetworkResult
-	err := c.CallResult(GetNetwork, &getNetworkResult)
+	err := c.CallResult(ctx, GetNetwork, &getNetworkResult)
 	if err != nil {
 		return nil, err
 	}
 	return &getNetworkResult, nil
 }
 
+// GetLatestLedgerResult as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getLatestLedger
+type GetLatestLedgerResult struct {
+	Id              string `json:"id"`
+	ProtocolVersion int64  `json:"protocolVersion"`
+	Sequence        int64  `json:"sequence"`
+}
+
+// GetLatestLedger provides details about the latest ledger closed by the network.
+// Returns an error if unmarshal, http call, etc; fail.
+// Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getLatestLedger
+func (c Client) GetLatestLedger(ctx context.Context) (*GetLatestLedgerResult, error) {
+	var getLatestLedgerResult GetLatestLedgerResult
+	err := c.CallResult(ctx, GetLatestLedger, &getLatestLedgerResult)
+	if err != nil {
+		return nil, err
+	}
+	return &getLatestLedgerResult, nil
+}
+
+// RPCError is the error object of a JSON-RPC response, returned by CallResult
+// and the methods of Client when the server answers with an error, to match
+// with errors.As and switch on its Code
+type RPCError = rpc.RPCError
+
+// JSON-RPC 2.0 error codes of RPCError, the server defines its own from
+// -32000 to -32099
+const (
+	CodeParseError     = rpc.CodeParseError
+	CodeInvalidRequest = rpc.CodeInvalidRequest
+	CodeMethodNotFound = rpc.CodeMethodNotFound
+	CodeInvalidParams  = rpc.CodeInvalidParams
+	CodeInternalError  = rpc.CodeInternalError
+)
+
+// RetryPolicy retries the calls of a Client failing with a transient error,
+// set it as Client.Retry
+//
+//	Example:
+//	 client.Retry = &soroban.RetryPolicy{MaxAttempts: 3, Backoff: 500 * time.Millisecond}
+type RetryPolicy = rpc.RetryPolicy
+
+// StatusError is returned when the server answers with a status other than
+// 200, after the retries
+type StatusError = rpc.StatusError
+
+// Middleware wraps the calls of a Client, set it in Client.Middleware to log,
+// authenticate, measure or mutate the requests
+type Middleware = rpc.Middleware
+
+// RoundTrip sends a JSON-RPC request, the next step of a Middleware
+type RoundTrip = rpc.RoundTrip
+
+// RPCRequest is the JSON-RPC request seen by a Middleware
+type RPCRequest = rpc.Request
+
+// RPCResponse is the JSON-RPC response seen by a Middleware
+type RPCResponse = rpc.Response
+
+// Call executes a call, with params if any, and returns the raw response.
+// The call is measured by Metrics and logged to Logger, see CallResult.
+func (c Client) Call(ctx context.Context, method string, params ...interface{}) (*RPCResponse, error) {
+	start := time.Now()
+	// the id of the request is logged for the correlation with the server
+	// logs
+	resp, id, err := c.CallID(ctx, method, params...)
+	if c.Metrics != nil {
+		c.Metrics.RPCCall(method, time.Since(start), err)
+	}
+	if c.Logger != nil {
+		attrs := []slog.Attr{slog.String("method", method), slog.String("id", id), slog.Duration("duration", time.Since(start))}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		c.Logger.LogAttrs(ctx, slog.LevelDebug, "rpc call", attrs...)
+	}
+	return resp, err
+}
+
 // CallResult executes a call, with params if any, and saves the result into
-// the interface passed as param.
-func (c Client) CallResult(method string, result interface{}, params ...interface{}) error {
-	resp, err := c.Call(method, params...)
+// the interface passed as param. The call is canceled when ctx is done. When
+// the server answers with an error it is returned as an *RPCError.
+func (c Client) CallResult(ctx context.Context, method string, result interface{}, params ...interface{}) error {
+	resp, err := c.Call(ctx, method, params...)
 	if err != nil {
 		return err
 	}
+	if resp.Result == nil {
+		return fmt.Errorf("%s: no result", method)
+	}
 	err = json.Unmarshal(*resp.Result, result)
 	if err != nil {
 		return err
 	}
 	return nil
 }
+
+// WebSocketTransport returns a transport, to be used as the Client HTTP, that
+// sends the JSON-RPC calls over a WebSocket connection to url (ws:// or wss://).
+// The Client URL is ignored by the transport but still required to build requests.
+func WebSocketTransport(url string) *rpc.WebSocket {
+	return rpc.NewWebSocket(url)
+}
+
+// UnixSocketTransport returns a transport, to be used as the Client HTTP, that
+// connects to the unix domain socket at path. Setting the Client URL to
+// unix:///path/to/socket does the same with the default transport.
+func UnixSocketTransport(path string) rpc.HTTP {
+	return rpc.NewUnixHTTP(path)
+}
+
+// DialerTransport returns a transport, to be used as the Client HTTP, that
+// opens its connections with dial.
+func DialerTransport(dial rpc.DialFunc) rpc.HTTP {
+	return rpc.NewDialerHTTP(dial)
+}
+
+// TransportConfig configures the TLS, the proxy and the connections of the
+// transport returned by HTTPTransport
+//
+//	Example:
+//	 client.HTTP, err = soroban.HTTPTransport(soroban.TransportConfig{
+//		CAFiles: []string{"/etc/ssl/internal-ca.pem"},
+//		Proxy:   "http://proxy.internal:3128",
+//	 })
+type TransportConfig = rpc.TransportConfig
+
+// HTTPTransport returns a transport, to be used as the Client HTTP,
+// configured with config
+func HTTPTransport(config TransportConfig) (rpc.HTTP, error) {
+	return rpc.NewTransportHTTP(config)
+}
+
+// MaxInFlight returns a limiter, to be used as the Client InFlight, capping
+// the concurrent RPC calls to max, which must be positive. It can be shared
+// by many clients.
+func MaxInFlight(max int) *rpc.Semaphore {
+	return rpc.NewSemaphore(max)
+}
+
+// RequestSigner signs the requests of a Client, set it as Client.Signer, or
+// the payloads of a Notifier
+type RequestSigner = rpc.RequestSigner
+
+// HMACSigner is the RequestSigner returned by HMACRequestSigner
+type HMACSigner = rpc.HMACSigner
+
+// JWTSigner is the RequestSigner returned by JWTRequestSigner
+type JWTSigner = rpc.JWTSigner
+
+// HMACRequestSigner returns a signer adding an HMAC-SHA256 signature of every
+// request in header, X-Signature if empty. secret is called on every request.
+//
+//	client.Signer = soroban.HMACRequestSigner("", func() ([]byte, error) { return secret, nil })
+func HMACRequestSigner(header string, secret func() ([]byte, error)) HMACSigner {
+	return HMACSigner{Header: header, Secret: secret}
+}
+
+// JWTRequestSigner returns a signer adding a short lived JWT, signed with the
+// key returned by key ([]byte for HS256, ed25519.PrivateKey for EdDSA), to
+// header, Authorization if empty.
+func JWTRequestSigner(header string, issuer string, key func() (any, error)) JWTSigner {
+	return JWTSigner{Header: header, Issuer: issuer, Key: key}
+}
+
+// BearerToken returns a token source, to be used as the Client Token, that
+// caches the token returned by fetch until it expires or the server answers
+// 401 Unauthorized.
+//
+//	client.Token = soroban.BearerToken(func(ctx context.Context) (string, time.Time, error) {
+//		return provider.NewToken(ctx)
+//	})
+func BearerToken(fetch func(ctx context.Context) (string, time.Time, error)) rpc.TokenFunc {
+	return rpc.CachedToken(fetch)
+}
+
+// DecimalRequestID is a Client StringID sending the request ids as strings,
+// "1" instead of 1
+func DecimalRequestID(n uint64) string {
+	return rpc.DecimalID(n)
+}
+
+// UUIDRequestID is a Client StringID sending random UUIDs as the request ids
+func UUIDRequestID(n uint64) string {
+	return rpc.UUID(n)
+}
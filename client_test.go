@@ -1,6 +1,12 @@
 package soroban_test
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
 	"testing"
 
 	"github.com/sebamiro/soroban"
@@ -23,7 +29,7 @@ func TestSimulateTransaction(t *testing.T) {
 	contractid := []byte("CAOCKSQN7D2XXP3XEYYPB3F6SGMYUNTBYSDCCML6QJYJ75H2KNZ3I23Z")
 	contractIDAddress := xdr.ScAddress{
 		Type:       xdr.ScAddressTypeScAddressTypeContract,
-		ContractId: (*xdr.Hash)(contractid),
+		ContractId: (*xdr.ContractId)(contractid),
 	}
 
 	world := xdr.ScString("world")
@@ -69,7 +75,7 @@ func TestSimulateTransaction(t *testing.T) {
 		t.Fatal(err)
 	}
 
-	r, err := client.SendTransaction(tx)
+	r, err := client.SendTransaction(context.Background(), tx)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -88,7 +94,7 @@ func TestSimulateTransaction(t *testing.T) {
 }
 
 func TestGetHealth(t *testing.T) {
-	r, err := client.GetHealth()
+	r, err := client.GetHealth(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -96,3 +102,82 @@ func TestGetHealth(t *testing.T) {
 		t.Fatal(err)
 	}
 }
+
+func TestCallResultRPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32601,"message":"method not found"}}`, req.ID)
+	}))
+	defer server.Close()
+	client := soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase}
+
+	_, err := client.GetHealth(context.Background())
+	var rpcErr *soroban.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != soroban.CodeMethodNotFound {
+		t.Fatal(err)
+	}
+}
+
+func TestSimulateTransactionDecode(t *testing.T) {
+	data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 42})
+	value, _ := xdr.MarshalBase64(sym("hello"))
+	auth, _ := xdr.MarshalBase64(xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{Function: xdr.SorobanAuthorizedFunction{
+			Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+			ContractFn: &xdr.InvokeContractArgs{ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.ContractId{1}}, FunctionName: "hello"},
+		}},
+	})
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeTtl, Ttl: &xdr.LedgerKeyTtl{KeyHash: xdr.Hash{1}}}
+	keyXdr, _ := key.MarshalBinaryBase64()
+	entry := func(liveUntil uint32) string {
+		b64, _ := xdr.MarshalBase64(xdr.LedgerEntry{Data: xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeTtl,
+			Ttl:  &xdr.TtlEntry{KeyHash: xdr.Hash{1}, LiveUntilLedgerSeq: xdr.Uint32(liveUntil)},
+		}})
+		return b64
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"transactionData":%q,"minResourceFee":"42","results":[{"xdr":%q,"auth":[%q]}],`+
+			`"stateChanges":[{"type":"updated","key":%q,"before":%q,"after":%q},{"type":"created","key":%q,"after":%q}],"latestLedger":7}}`,
+			req.ID, data, value, auth, keyXdr, entry(10), entry(20), keyXdr, entry(30))
+	}))
+	defer server.Close()
+
+	client := soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase}
+	res, err := client.SimulateTransactionXDR(context.Background(), "AAAA")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Data.ResourceFee != 42 || res.TransactionData != data {
+		t.Fatal(res.Data)
+	}
+	result := res.Results[0]
+	if *result.Value.Sym != "hello" || len(result.AuthEntries) != 1 || result.AuthEntries[0].RootInvocation.Function.ContractFn.FunctionName != "hello" {
+		t.Fatal(result)
+	}
+	if ret, err := res.ReturnValue(); err != nil || *ret.Sym != "hello" {
+		t.Fatal(ret, err)
+	}
+	if len(res.StateChanges) != 2 {
+		t.Fatal(res.StateChanges)
+	}
+	updated, created := res.StateChanges[0], res.StateChanges[1]
+	if updated.LedgerKey.Ttl.KeyHash != (xdr.Hash{1}) || updated.BeforeEntry.Data.Ttl.LiveUntilLedgerSeq != 10 ||
+		updated.AfterEntry.Data.Ttl.LiveUntilLedgerSeq != 20 {
+		t.Fatal(updated)
+	}
+	if created.Type != "created" || created.BeforeEntry != nil || created.AfterEntry.Data.Ttl.LiveUntilLedgerSeq != 30 {
+		t.Fatal(created)
+	}
+
+	invalid := &soroban.SimulateTransactionResult{TransactionData: "invalid"}
+	if err := invalid.Decode(); err == nil {
+		t.Fatal("invalid transaction data decoded")
+	}
+}
@@ -0,0 +1,340 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// exporter polls the watched contracts and renders their metrics in the
+// Prometheus text format
+type exporter struct {
+	client    soroban.Client
+	contracts []string
+	addresses map[string]xdr.ScAddress
+
+	mu sync.Mutex
+	// instanceTTL and codeTTL are the ledgers left, by contract
+	instanceTTL map[string]int64
+	codeTTL     map[string]int64
+	// invocations are by contract and status, fees (stroops) by contract
+	invocations map[[2]string]int64
+	fees        map[string]int64
+	// events are by contract and first topic
+	events map[[2]string]int64
+	errors int64
+}
+
+func newExporter(client soroban.Client, contracts []string) (*exporter, error) {
+	e := &exporter{
+		client:      client,
+		addresses:   make(map[string]xdr.ScAddress),
+		instanceTTL: make(map[string]int64),
+		codeTTL:     make(map[string]int64),
+		invocations: make(map[[2]string]int64),
+		fees:        make(map[string]int64),
+		events:      make(map[[2]string]int64),
+	}
+	for _, contract := range contracts {
+		contract = strings.TrimSpace(contract)
+		if contract == "" {
+			continue
+		}
+		id, err := strkey.Decode(strkey.VersionByteContract, contract)
+		if err != nil {
+			return nil, fmt.Errorf("contract %s: %w", contract, err)
+		}
+		contractId := xdr.ContractId(id)
+		e.addresses[contract] = xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}
+		e.contracts = append(e.contracts, contract)
+	}
+	if len(e.contracts) == 0 {
+		return nil, fmt.Errorf("no contract to watch")
+	}
+	return e, nil
+}
+
+// run polls the ttls and events every interval, and follows the transactions
+// invoking the contracts, until ctx is done
+func (e *exporter) run(ctx context.Context, interval time.Duration) {
+	latest, err := e.client.GetLatestLedger(ctx)
+	for err != nil {
+		e.failed(err)
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(interval):
+		}
+		latest, err = e.client.GetLatestLedger(ctx)
+	}
+	go e.followTransactions(ctx, latest.Sequence, interval)
+
+	var cursors []string
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := e.pollTTL(ctx); err != nil {
+			e.failed(err)
+		}
+		next, err := e.pollEvents(ctx, latest.Sequence, cursors)
+		if err != nil {
+			e.failed(err)
+		}
+		cursors = next
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (e *exporter) failed(err error) {
+	log.Print(err)
+	e.mu.Lock()
+	e.errors++
+	e.mu.Unlock()
+}
+
+// pollTTL reads the instance of every contract, and then the code of their
+// wasm, recording the ledgers left of each
+func (e *exporter) pollTTL(ctx context.Context) error {
+	keys := make([]string, 0, len(e.contracts))
+	for _, contract := range e.contracts {
+		key, err := soroban.Key.ContractData(e.addresses[contract], xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, soroban.Persistent)
+		if err != nil {
+			return err
+		}
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, k)
+	}
+	res, err := e.client.GetLedgerEntries(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	instances := make(map[string]soroban.GetLedgerEntry, len(res.Entries))
+	for _, entry := range res.Entries {
+		instances[entry.Key] = entry
+	}
+
+	codeKeys := []string{}
+	codeOf := make(map[string][]string)
+	e.mu.Lock()
+	for i, contract := range e.contracts {
+		entry, ok := instances[keys[i]]
+		if !ok {
+			e.instanceTTL[contract] = 0
+			continue
+		}
+		e.instanceTTL[contract] = max(entry.LiveUntilLedgerSeq-res.LatestLedger+1, 0)
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(entry.Xdr, &data); err != nil || data.ContractData == nil || data.ContractData.Val.Instance == nil {
+			continue
+		}
+		executable := data.ContractData.Val.Instance.Executable
+		if executable.Type != xdr.ContractExecutableTypeContractExecutableWasm {
+			continue
+		}
+		codeKey, err := soroban.Key.ContractCode(*executable.WasmHash)
+		if err != nil {
+			continue
+		}
+		k, err := codeKey.MarshalBinaryBase64()
+		if err != nil {
+			continue
+		}
+		if _, ok := codeOf[k]; !ok {
+			codeKeys = append(codeKeys, k)
+		}
+		codeOf[k] = append(codeOf[k], contract)
+	}
+	e.mu.Unlock()
+	if len(codeKeys) == 0 {
+		return nil
+	}
+
+	res, err = e.client.GetLedgerEntries(ctx, codeKeys...)
+	if err != nil {
+		return err
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, contracts := range codeOf {
+		for _, contract := range contracts {
+			e.codeTTL[contract] = 0
+		}
+	}
+	for _, entry := range res.Entries {
+		for _, contract := range codeOf[entry.Key] {
+			e.codeTTL[contract] = max(entry.LiveUntilLedgerSeq-res.LatestLedger+1, 0)
+		}
+	}
+	return nil
+}
+
+// pollEvents counts the events of the contracts after cursors, one per
+// getEvents request, or from startLedger, returning the cursors to continue
+// from
+func (e *exporter) pollEvents(ctx context.Context, startLedger int64, cursors []string) ([]string, error) {
+	// the RPC accepts up to 5 contract IDs per filter, and 5 filters per
+	// request
+	var groups [][]soroban.EventFilter
+	for group := range slices.Chunk(e.contracts, 5*5) {
+		var filters []soroban.EventFilter
+		for ids := range slices.Chunk(group, 5) {
+			filters = append(filters, soroban.EventFilter{Type: soroban.EventTypeContract, ContractIds: ids})
+		}
+		groups = append(groups, filters)
+	}
+	next := make([]string, len(groups))
+	copy(next, cursors)
+	var errs []error
+	for i, filters := range groups {
+		req := soroban.GetEventsRequest{Filters: filters}
+		if next[i] == "" {
+			req.StartLedger = startLedger
+		} else {
+			req.Pagination = &soroban.EventsPagination{Cursor: next[i]}
+		}
+		res, err := e.client.GetEvents(ctx, req)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		e.mu.Lock()
+		for _, event := range res.Events {
+			e.events[[2]string{event.ContractId, eventTopic(event)}]++
+		}
+		e.mu.Unlock()
+		if res.Cursor != "" {
+			next[i] = res.Cursor
+		}
+	}
+	return next, errors.Join(errs...)
+}
+
+// eventTopic returns the first topic of the event if it is a symbol or a
+// string, usually its name
+func eventTopic(event soroban.Event) string {
+	topics, err := event.Topics()
+	if err != nil || len(topics) == 0 {
+		return "other"
+	}
+	switch topics[0].Type {
+	case xdr.ScValTypeScvSymbol:
+		return string(*topics[0].Sym)
+	case xdr.ScValTypeScvString:
+		return string(*topics[0].Str)
+	}
+	return "other"
+}
+
+// followTransactions counts the invocations of the contracts, and their
+// fees, from startLedger, resuming after errors
+func (e *exporter) followTransactions(ctx context.Context, startLedger int64, interval time.Duration) {
+	last := ""
+	for ctx.Err() == nil {
+		var err error
+		last, err = e.client.Backfill(startLedger, 0, e.countInvocations).
+			Cursor(last).
+			Follow(interval).
+			Run(ctx)
+		if err != nil && ctx.Err() == nil {
+			e.failed(err)
+			select {
+			case <-ctx.Done():
+			case <-time.After(interval):
+			}
+		}
+	}
+}
+
+func (e *exporter) countInvocations(ctx context.Context, tx soroban.TransactionInfo) error {
+	details, err := tx.Result().Decode(e.client.PassPhrase)
+	if err != nil {
+		// not an invocation to count, the exporter keeps going
+		e.failed(err)
+		return nil
+	}
+	envelope := details.Envelope
+	if details.FeeBump && details.InnerEnvelope != nil {
+		envelope = *details.InnerEnvelope
+	}
+	status := "failed"
+	if details.Successful() {
+		status = "success"
+	}
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	for _, op := range envelope.Operations() {
+		if op.Body.Type != xdr.OperationTypeInvokeHostFunction ||
+			op.Body.InvokeHostFunctionOp.HostFunction.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			continue
+		}
+		contract, err := op.Body.InvokeHostFunctionOp.HostFunction.InvokeContract.ContractAddress.String()
+		if err != nil {
+			continue
+		}
+		if _, ok := e.addresses[contract]; !ok {
+			continue
+		}
+		e.invocations[[2]string{contract, status}]++
+		e.fees[contract] += int64(details.Result.FeeCharged)
+	}
+	return nil
+}
+
+// ServeHTTP renders the metrics in the Prometheus text format
+func (e *exporter) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+	e.write(w)
+}
+
+func (e *exporter) write(w io.Writer) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	gauge := func(name, help string, values map[string]int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s gauge\n", name, help, name)
+		for _, contract := range e.contracts {
+			if v, ok := values[contract]; ok {
+				fmt.Fprintf(w, "%s{contract=%q} %d\n", name, contract, v)
+			}
+		}
+	}
+	counter := func(name, help, label string, values map[[2]string]int64) {
+		fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		keys := make([][2]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		slices.SortFunc(keys, func(a, b [2]string) int {
+			return strings.Compare(a[0]+"\x00"+a[1], b[0]+"\x00"+b[1])
+		})
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s{contract=%q,%s=%q} %d\n", name, k[0], label, k[1], values[k])
+		}
+	}
+	gauge("soroban_contract_instance_ttl_ledgers", "Ledgers left before the contract instance is archived.", e.instanceTTL)
+	gauge("soroban_contract_code_ttl_ledgers", "Ledgers left before the contract wasm code is archived.", e.codeTTL)
+	counter("soroban_contract_invocations_total", "Transactions invoking the contract, by status.", "status", e.invocations)
+	fmt.Fprintf(w, "# HELP soroban_contract_fees_stroops_total Fees charged to the transactions invoking the contract.\n# TYPE soroban_contract_fees_stroops_total counter\n")
+	for _, contract := range e.contracts {
+		fmt.Fprintf(w, "soroban_contract_fees_stroops_total{contract=%q} %d\n", contract, e.fees[contract])
+	}
+	counter("soroban_contract_events_total", "Events emitted by the contract, by first topic.", "topic", e.events)
+	fmt.Fprintf(w, "# HELP soroban_exporter_errors_total Failed polls of the exporter.\n# TYPE soroban_exporter_errors_total counter\nsoroban_exporter_errors_total %d\n", e.errors)
+}
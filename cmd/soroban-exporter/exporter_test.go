@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func testContract(t *testing.T, b byte) string {
+	id := make([]byte, 32)
+	id[0] = b
+	contract, err := strkey.Encode(strkey.VersionByteContract, id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return contract
+}
+
+func TestNewExporter(t *testing.T) {
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	if _, err := newExporter(client, []string{"", " "}); err == nil {
+		t.Fatal("expected no contract to watch")
+	}
+	if _, err := newExporter(client, []string{"GABC"}); err == nil {
+		t.Fatal("expected invalid contract")
+	}
+	contract := testContract(t, 1)
+	e, err := newExporter(client, []string{" " + contract})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(e.contracts) != 1 || e.contracts[0] != contract {
+		t.Fatal(e.contracts)
+	}
+}
+
+func TestEventTopic(t *testing.T) {
+	sym := xdr.ScSymbol("transfer")
+	topic, err := soroban.NewTopicFilter(&xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := eventTopic(soroban.Event{Topic: topic}); name != "transfer" {
+		t.Fatal(name)
+	}
+	u := xdr.Uint32(1)
+	topic, err = soroban.NewTopicFilter(&xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name := eventTopic(soroban.Event{Topic: topic}); name != "other" {
+		t.Fatal(name)
+	}
+	if name := eventTopic(soroban.Event{}); name != "other" {
+		t.Fatal(name)
+	}
+}
+
+func invocation(t *testing.T, contract string, code xdr.TransactionResultCode) soroban.TransactionInfo {
+	id, err := strkey.Decode(strkey.VersionByteContract, contract)
+	if err != nil {
+		t.Fatal(err)
+	}
+	contractId := xdr.ContractId(id)
+	source := xdr.MustMuxedAddress(keypair.MustRandom().Address())
+	envelope := xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{HostFunction: xdr.HostFunction{
+					Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+					InvokeContract: &xdr.InvokeContractArgs{
+						ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId},
+						FunctionName:    "hello",
+					},
+				}},
+			}}},
+		}},
+	}
+	envelopeXdr, err := xdr.MarshalBase64(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := []xdr.OperationResult{}
+	result := xdr.TransactionResult{FeeCharged: 100, Result: xdr.TransactionResultResult{Code: code, Results: &results}}
+	resultXdr, err := xdr.MarshalBase64(result)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return soroban.TransactionInfo{Status: "SUCCESS", EnvelopeXdr: envelopeXdr, ResultXdr: resultXdr}
+}
+
+func TestExporterMetrics(t *testing.T) {
+	watched, other := testContract(t, 1), testContract(t, 2)
+	e, err := newExporter(soroban.Client{PassPhrase: network.TestNetworkPassphrase}, []string{watched})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, tx := range []soroban.TransactionInfo{
+		invocation(t, watched, xdr.TransactionResultCodeTxSuccess),
+		invocation(t, watched, xdr.TransactionResultCodeTxSuccess),
+		invocation(t, watched, xdr.TransactionResultCodeTxFailed),
+		invocation(t, other, xdr.TransactionResultCodeTxSuccess),
+	} {
+		if err := e.countInvocations(ctx, tx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e.instanceTTL[watched] = 42
+	e.events[[2]string{watched, "transfer"}] = 3
+
+	var out strings.Builder
+	e.write(&out)
+	for _, line := range []string{
+		`soroban_contract_instance_ttl_ledgers{contract="` + watched + `"} 42`,
+		`soroban_contract_invocations_total{contract="` + watched + `",status="failed"} 1`,
+		`soroban_contract_invocations_total{contract="` + watched + `",status="success"} 2`,
+		`soroban_contract_fees_stroops_total{contract="` + watched + `"} 300`,
+		`soroban_contract_events_total{contract="` + watched + `",topic="transfer"} 3`,
+		`soroban_exporter_errors_total 0`,
+	} {
+		if !strings.Contains(out.String(), line+"\n") {
+			t.Fatalf("missing %q in\n%s", line, out.String())
+		}
+	}
+	if strings.Contains(out.String(), other) || strings.Contains(out.String(), "soroban_contract_code_ttl_ledgers{") {
+		t.Fatal(out.String())
+	}
+}
+
+func TestPollEventsGroups(t *testing.T) {
+	var requests []soroban.GetEventsRequest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64                   `json:"id"`
+			Params soroban.GetEventsRequest `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req.Params)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"events":[],"cursor":"c%d","latestLedger":10}}`, req.ID, len(requests))
+	}))
+	defer server.Close()
+
+	var contracts []string
+	for i := range 30 {
+		contracts = append(contracts, testContract(t, byte(i+1)))
+	}
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	e, err := newExporter(client, contracts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cursors, err := e.pollEvents(context.Background(), 5, nil)
+	if err != nil || len(cursors) != 2 || cursors[0] != "c1" || cursors[1] != "c2" {
+		t.Fatal(cursors, err)
+	}
+	// 5 filters of 5 contracts per request
+	if len(requests) != 2 || len(requests[0].Filters) != 5 || len(requests[1].Filters) != 1 ||
+		len(requests[0].Filters[4].ContractIds) != 5 || requests[1].Filters[0].ContractIds[4] != contracts[29] || requests[0].StartLedger != 5 {
+		t.Fatal(requests)
+	}
+
+	if _, err := e.pollEvents(context.Background(), 5, cursors); err != nil {
+		t.Fatal(err)
+	}
+	if requests[2].Pagination.Cursor != "c1" || requests[3].Pagination.Cursor != "c2" || requests[3].StartLedger != 0 {
+		t.Fatal(requests[2:])
+	}
+}
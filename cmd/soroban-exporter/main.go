@@ -0,0 +1,60 @@
+// Command soroban-exporter exposes Prometheus metrics of Soroban contracts:
+// the ttl left of their instance and code, their invocations and failures,
+// the fees spent invoking them and the events they emit.
+//
+//	Usage:
+//	 soroban-exporter -rpc https://soroban-testnet.stellar.org -contract C...,C... -listen :9180
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"strings"
+	"time"
+
+	"github.com/sebamiro/soroban"
+)
+
+func main() {
+	rpcURL := flag.String("rpc", "http://localhost:8000/rpc", "URL of the Soroban RPC server")
+	passphrase := flag.String("passphrase", "", "network passphrase, read from the server if empty")
+	contracts := flag.String("contract", "", "comma separated contract IDs (C...) to watch")
+	listen := flag.String("listen", ":9180", "address serving /metrics")
+	interval := flag.Duration("interval", 15*time.Second, "interval between polls")
+	flag.Parse()
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	client := soroban.Client{PassPhrase: *passphrase}
+	client.URL = *rpcURL
+	if client.PassPhrase == "" {
+		network, err := client.GetNetwork(ctx)
+		if err != nil {
+			log.Fatal(err)
+		}
+		client.PassPhrase = network.Passphrase
+	}
+
+	e, err := newExporter(client, strings.Split(*contracts, ","))
+	if err != nil {
+		log.Fatal(err)
+	}
+	go e.run(ctx, *interval)
+
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", e)
+	server := &http.Server{Addr: *listen, Handler: mux}
+	go func() {
+		<-ctx.Done()
+		server.Shutdown(context.Background())
+	}()
+	log.Printf("serving metrics of %d contracts on %s/metrics", len(e.contracts), *listen)
+	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,43 @@
+// Command soroban-testgen writes a Go test skeleton of a compiled contract,
+// see sorobantest.GenerateTest.
+//
+//	Usage:
+//	 soroban-testgen -wasm testdata/token.wasm -package token_test -name Token -o token_test.go
+package main
+
+import (
+	"flag"
+	"log"
+	"os"
+
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/sebamiro/soroban/spec"
+)
+
+func main() {
+	wasmPath := flag.String("wasm", "", "path of the compiled contract, read by the test from its package directory")
+	pkg := flag.String("package", "contract_test", "package of the test file")
+	name := flag.String("name", "Contract", "name of the contract, the test is TestName")
+	out := flag.String("o", "", "output file, stdout if empty")
+	flag.Parse()
+
+	wasm, err := os.ReadFile(*wasmPath)
+	if err != nil {
+		log.Fatal(err)
+	}
+	s, err := spec.FromWasm(wasm)
+	if err != nil {
+		log.Fatal(err)
+	}
+	src, err := sorobantest.GenerateTest(s, sorobantest.Scaffold{Package: *pkg, Name: *name, WasmPath: *wasmPath})
+	if err != nil {
+		log.Fatal(err)
+	}
+	if *out == "" {
+		os.Stdout.Write(src)
+		return
+	}
+	if err := os.WriteFile(*out, src, 0o644); err != nil {
+		log.Fatal(err)
+	}
+}
@@ -0,0 +1,314 @@
+package soroban
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+const (
+	ErrorInvalidConfig = "Invalid configuration"
+)
+
+// LocalNetworkPassphrase is the passphrase of a standalone network, e.g. the
+// stellar/quickstart image
+const LocalNetworkPassphrase = "Standalone Network ; February 2017"
+
+// Environment variables read by ConfigFromEnv, KeystoreEnv and CAFilesEnv are
+// lists of paths separated by os.PathListSeparator
+const (
+	RPCURLEnv             = "SOROBAN_RPC_URL"
+	NetworkEnv            = "SOROBAN_NETWORK"
+	FriendbotURLEnv       = "SOROBAN_FRIENDBOT_URL"
+	RPCTimeoutEnv         = "SOROBAN_RPC_TIMEOUT"
+	TransactionTimeoutEnv = "SOROBAN_TRANSACTION_TIMEOUT"
+	MaxFeeEnv             = "SOROBAN_MAX_FEE"
+	RetryAttemptsEnv      = "SOROBAN_RETRY_ATTEMPTS"
+	RetryBackoffEnv       = "SOROBAN_RETRY_BACKOFF"
+	KeystoreEnv           = "SOROBAN_KEYSTORE"
+	ProxyEnv              = "SOROBAN_PROXY"
+	CAFilesEnv            = "SOROBAN_CA_FILES"
+	CertFileEnv           = "SOROBAN_CERT_FILE"
+	KeyFileEnv            = "SOROBAN_KEY_FILE"
+)
+
+// networks by name, accepted by Config Network besides the passphrases
+var networks = map[string]string{
+	"testnet":   network.TestNetworkPassphrase,
+	"pubnet":    network.PublicNetworkPassphrase,
+	"mainnet":   network.PublicNetworkPassphrase,
+	"futurenet": network.FutureNetworkPassphrase,
+	"local":     LocalNetworkPassphrase,
+}
+
+// Config of a Client, loaded with ConfigFromEnv or ConfigFromFile so every
+// service deploys it the same way.
+//
+//	Example:
+//	 config, err := soroban.ConfigFromEnv()
+//	 client, err := soroban.NewClient(config)
+type Config struct {
+	// RPCURL of the server, http, https or unix
+	RPCURL string `json:"rpcUrl"`
+	// Network is testnet, pubnet (or mainnet), futurenet, local or a
+	// network passphrase
+	Network      string `json:"network"`
+	FriendbotURL string `json:"friendbotUrl,omitempty"`
+	// RPCTimeout of every call, 0 means no timeout
+	RPCTimeout time.Duration `json:"-"`
+	// TransactionTimeout is the Client DefaultTimeout
+	TransactionTimeout time.Duration `json:"-"`
+	// MaxFee is the Client MaxFee, 0 means no ceiling
+	MaxFee int64 `json:"maxFee,omitempty"`
+	// RetryAttempts of the calls failing with a transient error, with
+	// RetryBackoff between them, 0 or 1 means no retries
+	RetryAttempts int           `json:"retryAttempts,omitempty"`
+	RetryBackoff  time.Duration `json:"-"`
+	// Keystore are the paths of files with a secret seed each, see Signers
+	Keystore []string `json:"keystore,omitempty"`
+	// Proxy, CAFiles, CertFile and KeyFile configure the transport, see
+	// TransportConfig
+	Proxy    string   `json:"proxy,omitempty"`
+	CAFiles  []string `json:"caFiles,omitempty"`
+	CertFile string   `json:"certFile,omitempty"`
+	KeyFile  string   `json:"keyFile,omitempty"`
+}
+
+// ConfigFromEnv loads a validated Config from the environment variables
+// SOROBAN_*, see RPCURLEnv
+func ConfigFromEnv() (Config, error) {
+	config := Config{
+		RPCURL:       os.Getenv(RPCURLEnv),
+		Network:      os.Getenv(NetworkEnv),
+		FriendbotURL: os.Getenv(FriendbotURLEnv),
+		Proxy:        os.Getenv(ProxyEnv),
+		CertFile:     os.Getenv(CertFileEnv),
+		KeyFile:      os.Getenv(KeyFileEnv),
+	}
+	var errs []error
+	parse := func(env string, parse func(string) error) {
+		if value := os.Getenv(env); value != "" {
+			if err := parse(value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", env, err))
+			}
+		}
+	}
+	parseDuration := func(d *time.Duration) func(string) error {
+		return func(s string) (err error) {
+			*d, err = time.ParseDuration(s)
+			return err
+		}
+	}
+	parse(RPCTimeoutEnv, parseDuration(&config.RPCTimeout))
+	parse(TransactionTimeoutEnv, parseDuration(&config.TransactionTimeout))
+	parse(RetryBackoffEnv, parseDuration(&config.RetryBackoff))
+	parse(MaxFeeEnv, func(s string) (err error) {
+		config.MaxFee, err = strconv.ParseInt(s, 10, 64)
+		return err
+	})
+	parse(RetryAttemptsEnv, func(s string) (err error) {
+		config.RetryAttempts, err = strconv.Atoi(s)
+		return err
+	})
+	parse(KeystoreEnv, func(s string) error {
+		config.Keystore = filepath.SplitList(s)
+		return nil
+	})
+	parse(CAFilesEnv, func(s string) error {
+		config.CAFiles = filepath.SplitList(s)
+		return nil
+	})
+	if len(errs) > 0 {
+		return Config{}, fmt.Errorf("%s: %w", ErrorInvalidConfig, errors.Join(errs...))
+	}
+	return config, config.Validate()
+}
+
+// ConfigFromFile loads a validated Config from a JSON file, durations are
+// strings like "30s"
+//
+//	{
+//	 "rpcUrl": "https://soroban-testnet.stellar.org",
+//	 "network": "testnet",
+//	 "rpcTimeout": "10s",
+//	 "transactionTimeout": "1m",
+//	 "maxFee": 1000000,
+//	 "retryAttempts": 3,
+//	 "retryBackoff": "500ms",
+//	 "keystore": ["/run/secrets/source"],
+//	 "proxy": "http://proxy.internal:3128",
+//	 "caFiles": ["internal-ca.pem"]
+//	}
+//
+// Relative keystore, CA and certificate paths are relative to the file.
+func ConfigFromFile(path string) (Config, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return Config{}, err
+	}
+	var config Config
+	if err := json.Unmarshal(b, &config); err != nil {
+		return Config{}, fmt.Errorf("%s: %s: %w", ErrorInvalidConfig, path, err)
+	}
+	relative := func(file *string) {
+		if *file != "" && !filepath.IsAbs(*file) {
+			*file = filepath.Join(filepath.Dir(path), *file)
+		}
+	}
+	for i := range config.Keystore {
+		relative(&config.Keystore[i])
+	}
+	for i := range config.CAFiles {
+		relative(&config.CAFiles[i])
+	}
+	relative(&config.CertFile)
+	relative(&config.KeyFile)
+	return config, config.Validate()
+}
+
+// UnmarshalJSON reads the durations of c as strings
+func (c *Config) UnmarshalJSON(b []byte) error {
+	type plain Config
+	var config struct {
+		plain
+		RPCTimeout         string `json:"rpcTimeout"`
+		TransactionTimeout string `json:"transactionTimeout"`
+		RetryBackoff       string `json:"retryBackoff"`
+	}
+	if err := json.Unmarshal(b, &config); err != nil {
+		return err
+	}
+	*c = Config(config.plain)
+	for _, d := range []struct {
+		name  string
+		value string
+		dst   *time.Duration
+	}{
+		{"rpcTimeout", config.RPCTimeout, &c.RPCTimeout},
+		{"transactionTimeout", config.TransactionTimeout, &c.TransactionTimeout},
+		{"retryBackoff", config.RetryBackoff, &c.RetryBackoff},
+	} {
+		if d.value == "" {
+			continue
+		}
+		var err error
+		if *d.dst, err = time.ParseDuration(d.value); err != nil {
+			return fmt.Errorf("%s: %w", d.name, err)
+		}
+	}
+	return nil
+}
+
+// Passphrase returns the network passphrase of Network
+func (c Config) Passphrase() string {
+	if passphrase, ok := networks[strings.ToLower(c.Network)]; ok {
+		return passphrase
+	}
+	return c.Network
+}
+
+// Validate returns every problem of c, wrapped in ErrorInvalidConfig
+func (c Config) Validate() error {
+	var errs []error
+	if u, err := url.Parse(c.RPCURL); c.RPCURL == "" || err != nil {
+		errs = append(errs, fmt.Errorf("rpcUrl is required: %q", c.RPCURL))
+	} else if u.Scheme != "http" && u.Scheme != "https" && u.Scheme != "unix" {
+		errs = append(errs, fmt.Errorf("rpcUrl scheme is not http, https or unix: %q", c.RPCURL))
+	}
+	if c.Network == "" {
+		errs = append(errs, errors.New("network is required"))
+	}
+	if c.FriendbotURL != "" {
+		if _, err := url.ParseRequestURI(c.FriendbotURL); err != nil {
+			errs = append(errs, fmt.Errorf("friendbotUrl: %w", err))
+		}
+	}
+	if c.RPCTimeout < 0 || c.TransactionTimeout < 0 || c.RetryBackoff < 0 {
+		errs = append(errs, errors.New("timeouts must not be negative"))
+	}
+	if c.MaxFee < 0 {
+		errs = append(errs, fmt.Errorf("maxFee must not be negative: %d", c.MaxFee))
+	}
+	if c.RetryAttempts < 0 {
+		errs = append(errs, fmt.Errorf("retryAttempts must not be negative: %d", c.RetryAttempts))
+	}
+	for _, path := range c.Keystore {
+		if _, err := os.Stat(path); err != nil {
+			errs = append(errs, fmt.Errorf("keystore: %w", err))
+		}
+	}
+	if c.Proxy != "" {
+		if u, err := url.Parse(c.Proxy); err != nil || u.Host == "" {
+			errs = append(errs, fmt.Errorf("proxy is not a URL: %q", c.Proxy))
+		}
+	}
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		errs = append(errs, errors.New("certFile and keyFile are required together"))
+	}
+	for _, path := range append(slices.Clone(c.CAFiles), c.CertFile, c.KeyFile) {
+		if _, err := os.Stat(path); path != "" && err != nil {
+			errs = append(errs, fmt.Errorf("tls: %w", err))
+		}
+	}
+	if len(errs) > 0 {
+		return fmt.Errorf("%s: %w", ErrorInvalidConfig, errors.Join(errs...))
+	}
+	return nil
+}
+
+// Signers reads the secret seeds of the Keystore files
+func (c Config) Signers() ([]*keypair.Full, error) {
+	signers := make([]*keypair.Full, len(c.Keystore))
+	for i, path := range c.Keystore {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		signers[i], err = keypair.ParseFull(strings.TrimSpace(string(b)))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %s: %w", ErrorInvalidConfig, path, err)
+		}
+	}
+	return signers, nil
+}
+
+// NewClient returns a Client configured with config, after validating it
+func NewClient(config Config) (*Client, error) {
+	if err := config.Validate(); err != nil {
+		return nil, err
+	}
+	client := &Client{
+		Client:         rpc.Client{URL: config.RPCURL, Timeout: config.RPCTimeout},
+		PassPhrase:     config.Passphrase(),
+		FriendbotURL:   config.FriendbotURL,
+		DefaultTimeout: config.TransactionTimeout,
+		MaxFee:         config.MaxFee,
+	}
+	if config.RetryAttempts > 1 {
+		client.Retry = &RetryPolicy{MaxAttempts: config.RetryAttempts, Backoff: config.RetryBackoff}
+	}
+	if config.Proxy != "" || len(config.CAFiles) > 0 || config.CertFile != "" {
+		var err error
+		client.HTTP, err = HTTPTransport(TransportConfig{
+			Proxy:    config.Proxy,
+			CAFiles:  config.CAFiles,
+			CertFile: config.CertFile,
+			KeyFile:  config.KeyFile,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrorInvalidConfig, err)
+		}
+	}
+	return client, nil
+}
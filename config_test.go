@@ -0,0 +1,111 @@
+package soroban_test
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+)
+
+func TestConfigFromEnv(t *testing.T) {
+	seed := filepath.Join(t.TempDir(), "source")
+	source := keypair.MustRandom()
+	os.WriteFile(seed, []byte(source.Seed()+"\n"), 0o600)
+
+	t.Setenv(soroban.RPCURLEnv, "https://soroban-testnet.stellar.org")
+	t.Setenv(soroban.NetworkEnv, "testnet")
+	t.Setenv(soroban.RPCTimeoutEnv, "10s")
+	t.Setenv(soroban.TransactionTimeoutEnv, "1m")
+	t.Setenv(soroban.MaxFeeEnv, "1000000")
+	t.Setenv(soroban.RetryAttemptsEnv, "3")
+	t.Setenv(soroban.RetryBackoffEnv, "500ms")
+	t.Setenv(soroban.KeystoreEnv, seed)
+	config, err := soroban.ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := soroban.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if client.URL != "https://soroban-testnet.stellar.org" || client.PassPhrase != network.TestNetworkPassphrase ||
+		client.Timeout != 10*time.Second || client.DefaultTimeout != time.Minute || client.MaxFee != 1000000 ||
+		client.Retry == nil || client.Retry.MaxAttempts != 3 || client.Retry.Backoff != 500*time.Millisecond {
+		t.Fatal(client)
+	}
+	signers, err := config.Signers()
+	if err != nil || len(signers) != 1 || signers[0].Address() != source.Address() {
+		t.Fatal(signers, err)
+	}
+
+	t.Setenv(soroban.RetryAttemptsEnv, "three")
+	if _, err := soroban.ConfigFromEnv(); err == nil || !strings.Contains(err.Error(), soroban.RetryAttemptsEnv) {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigFromFile(t *testing.T) {
+	dir := t.TempDir()
+	os.WriteFile(filepath.Join(dir, "source"), []byte(keypair.MustRandom().Seed()), 0o600)
+	path := filepath.Join(dir, "soroban.json")
+	os.WriteFile(path, []byte(`{
+		"rpcUrl": "http://localhost:8000/soroban/rpc",
+		"network": "local",
+		"rpcTimeout": "5s",
+		"keystore": ["source"]
+	}`), 0o600)
+	config, err := soroban.ConfigFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Passphrase() != soroban.LocalNetworkPassphrase || config.RPCTimeout != 5*time.Second || config.Keystore[0] != filepath.Join(dir, "source") {
+		t.Fatal(config)
+	}
+
+	os.WriteFile(path, []byte(`{"rpcUrl": "ftp://localhost", "maxFee": -1, "keystore": ["missing"]}`), 0o600)
+	_, err = soroban.ConfigFromFile(path)
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorInvalidConfig) {
+		t.Fatal(err)
+	}
+	for _, problem := range []string{"rpcUrl", "network", "maxFee", "keystore"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Fatal(problem, err)
+		}
+	}
+
+	os.WriteFile(path, []byte(`{"rpcTimeout": "soon"}`), 0o600)
+	if _, err := soroban.ConfigFromFile(path); err == nil || !strings.Contains(err.Error(), "rpcTimeout") {
+		t.Fatal(err)
+	}
+}
+
+func TestConfigTransport(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "soroban.json")
+	os.WriteFile(path, []byte(`{
+		"rpcUrl": "https://rpc.internal",
+		"network": "testnet",
+		"proxy": "http://proxy.internal:3128",
+		"caFiles": ["ca.pem"],
+		"certFile": "client.pem"
+	}`), 0o600)
+	_, err := soroban.ConfigFromFile(path)
+	for _, problem := range []string{"certFile and keyFile", filepath.Join(dir, "ca.pem")} {
+		if err == nil || !strings.Contains(err.Error(), problem) {
+			t.Fatal(problem, err)
+		}
+	}
+
+	client, err := soroban.NewClient(soroban.Config{RPCURL: "https://rpc.internal", Network: "testnet", Proxy: "socks5://proxy.internal:1080"})
+	if err != nil || client.HTTP == nil {
+		t.Fatal(client, err)
+	}
+	if _, err := soroban.NewClient(soroban.Config{RPCURL: "https://rpc.internal", Network: "testnet", Proxy: "proxy"}); err == nil {
+		t.Fatal("expected a proxy error")
+	}
+}
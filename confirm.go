@@ -0,0 +1,96 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionFailedError is returned by SendAndConfirm when the transaction
+// was applied but FAILED, with its decoded result codes
+type TransactionFailedError struct {
+	Hash string
+	// Code of the transaction, of the inner transaction for fee bumps
+	Code xdr.TransactionResultCode
+	// OperationCodes are the result codes of the operations, e.g.
+	// InvokeHostFunctionResultCodeInvokeHostFunctionTrapped
+	OperationCodes []string
+	// DiagnosticEvents of the transaction, if the node emits them,
+	// summarized in the message
+	DiagnosticEvents []DiagnosticEvent
+	Result           *GetTransactionResult
+}
+
+func (e *TransactionFailedError) Error() string {
+	msg := fmt.Sprintf("transaction %s failed: %s", e.Hash, e.Code)
+	if len(e.OperationCodes) > 0 {
+		msg += " (" + strings.Join(e.OperationCodes, ", ") + ")"
+	}
+	return withEvents(msg, e.DiagnosticEvents)
+}
+
+// SendAndConfirm sends the transaction and waits until it is SUCCESS or
+// FAILED. A FAILED transaction returns its result along with a
+// *TransactionFailedError. A transaction submitted without its audit record
+// is confirmed too, returning its result along with the *AuditError.
+//
+//	Example:
+//	 res, err := tx.SendAndConfirm(ctx)
+//	 var failed *soroban.TransactionFailedError
+//	 if errors.As(err, &failed) {
+func (t *Transaction) SendAndConfirm(ctx context.Context) (*GetTransactionResult, error) {
+	// a pending transaction with an *AuditError is submitted, it is still
+	// confirmed
+	pending, auditErr := t.Send(ctx)
+	if pending == nil {
+		return nil, auditErr
+	}
+	res, err := pending.Wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status != "FAILED" {
+		return res, auditErr
+	}
+	if auditErr != nil {
+		return res, errors.Join(failedError(res), auditErr)
+	}
+	return res, failedError(res)
+}
+
+// failedError decodes the result codes of the failed transaction res
+func failedError(res *GetTransactionResult) error {
+	failed := &TransactionFailedError{Hash: res.TxHash, Result: res}
+	result, err := res.Result()
+	if err != nil {
+		return failed
+	}
+	failed.Code, failed.OperationCodes = resultCodes(*result)
+	failed.DiagnosticEvents, _ = DecodeDiagnosticEvents(res.DiagnosticEventsXdr)
+	if meta, err := res.Meta(); err == nil && len(failed.DiagnosticEvents) == 0 {
+		events, _ := MetaDiagnosticEvents(*meta)
+		for _, event := range events {
+			failed.DiagnosticEvents = append(failed.DiagnosticEvents, NewDiagnosticEvent(event))
+		}
+	}
+	return failed
+}
+
+// operationCode returns the name of the result code of op
+func operationCode(op xdr.OperationResult) string {
+	if op.Code != xdr.OperationResultCodeOpInner || op.Tr == nil {
+		return op.Code.String()
+	}
+	switch op.Tr.Type {
+	case xdr.OperationTypeInvokeHostFunction:
+		return op.Tr.InvokeHostFunctionResult.Code.String()
+	case xdr.OperationTypeExtendFootprintTtl:
+		return op.Tr.ExtendFootprintTtlResult.Code.String()
+	case xdr.OperationTypeRestoreFootprint:
+		return op.Tr.RestoreFootprintResult.Code.String()
+	}
+	return op.Tr.Type.String()
+}
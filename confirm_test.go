@@ -0,0 +1,73 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSendAndConfirm(t *testing.T) {
+	status := "SUCCESS"
+	failed, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxFailed,
+		Results: &[]xdr.OperationResult{{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:                     xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionTrapped},
+			},
+		}},
+	}})
+	var auditErr error
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, auditErr
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{TxHash: hash, Status: status, ResultXdr: failed}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	send := func() (*soroban.GetTransactionResult, error) {
+		return soroban.NewTransctionBuilder().
+			Client(fake).
+			SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+			Signer(pair).
+			Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+			SendAndConfirm(context.Background())
+	}
+
+	res, err := send()
+	if err != nil || res.Status != "SUCCESS" {
+		t.Fatal(res, err)
+	}
+
+	status = "FAILED"
+	res, err = send()
+	var failedErr *soroban.TransactionFailedError
+	if !errors.As(err, &failedErr) || res == nil || failedErr.Hash != "abc" || failedErr.Code != xdr.TransactionResultCodeTxFailed ||
+		len(failedErr.OperationCodes) != 1 || failedErr.OperationCodes[0] != "InvokeHostFunctionResultCodeInvokeHostFunctionTrapped" {
+		t.Fatal(res, err)
+	}
+
+	// submitted, but not audited, the transaction is still confirmed
+	full := errors.New("disk full")
+	auditErr = &soroban.AuditError{Err: full}
+	res, err = send()
+	if !errors.As(err, &failedErr) || !errors.Is(err, full) || res == nil {
+		t.Fatal(res, err)
+	}
+	status = "SUCCESS"
+	res, err = send()
+	if !errors.Is(err, full) || res == nil || res.Status != "SUCCESS" {
+		t.Fatal(res, err)
+	}
+}
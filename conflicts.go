@@ -0,0 +1,115 @@
+package soroban
+
+import (
+	"context"
+	"sync"
+)
+
+// footprintTracker tracks the read-write footprints of the transactions of an
+// InvocationSession not yet finalized
+type footprintTracker struct {
+	mu       sync.Mutex
+	inFlight map[*PendingTransaction]map[string]bool
+}
+
+func newFootprintTracker() *footprintTracker {
+	return &footprintTracker{inFlight: make(map[*PendingTransaction]map[string]bool)}
+}
+
+// OrderConflicts makes the session wait, before sending an invocation whose
+// simulated read-write footprint overlaps the one of a previous invocation not
+// yet finalized, until that one is, and simulate it again. Conflicting
+// invocations are then in different ledgers, instead of failing on the
+// entries changed by the first one, while the others are still sent without
+// waiting. It has to be set before queuing invocations.
+func (s *InvocationSession) OrderConflicts() *InvocationSession {
+	s.footprints = newFootprintTracker()
+	return s
+}
+
+// conflicts returns the in-flight transactions writing any of the keys
+func (f *footprintTracker) conflicts(keys map[string]bool) []*PendingTransaction {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var pending []*PendingTransaction
+	for p, written := range f.inFlight {
+		select {
+		case <-p.done:
+			delete(f.inFlight, p)
+			continue
+		default:
+		}
+		for key := range keys {
+			if written[key] {
+				pending = append(pending, p)
+				break
+			}
+		}
+	}
+	return pending
+}
+
+// wait waits for the in-flight transactions conflicting with the simulated
+// invocation, returning if there was any
+func (f *footprintTracker) wait(ctx context.Context, res *SimulateTransactionResult) (bool, error) {
+	keys, err := readWriteKeys(res)
+	if err != nil {
+		return false, err
+	}
+	conflicts := f.conflicts(keys)
+	for _, p := range conflicts {
+		select {
+		case <-p.Done():
+		case <-ctx.Done():
+			return false, ctx.Err()
+		}
+	}
+	return len(conflicts) > 0, nil
+}
+
+// track adds the footprint of the sent invocation until it is finalized
+func (f *footprintTracker) track(res *SimulateTransactionResult, pending *PendingTransaction) {
+	if f == nil || pending.Sent().Status == "ERROR" || pending.Sent().Status == "TRY_AGAIN_LATER" {
+		return
+	}
+	keys, err := readWriteKeys(res)
+	if err != nil || len(keys) == 0 {
+		return
+	}
+	f.mu.Lock()
+	f.inFlight[pending] = keys
+	f.mu.Unlock()
+	go func() {
+		<-pending.Done()
+		f.mu.Lock()
+		delete(f.inFlight, pending)
+		f.mu.Unlock()
+	}()
+}
+
+func readWriteKeys(res *SimulateTransactionResult) (map[string]bool, error) {
+	if err := res.Decode(); err != nil {
+		return nil, err
+	}
+	return ledgerKeySet(res.Data.Resources.Footprint.ReadWrite)
+}
+
+// simulateInvoke builds and simulates the invocation of build. With a
+// footprintTracker, if it writes the keys of an in-flight transaction it
+// waits for those to be finalized and simulates it again.
+func (c *Contract) simulateInvoke(ctx context.Context, build *invokeBuild) (*Transaction, *SimulateTransactionResult, error) {
+	for {
+		transaction, err := c.invokeTransaction(build)
+		if err != nil {
+			return nil, nil, err
+		}
+		res, err := transaction.Simulate(ctx)
+		if err != nil || build.footprints == nil {
+			return transaction, res, err
+		}
+		waited, err := build.footprints.wait(ctx, res)
+		if err != nil || !waited {
+			return transaction, res, err
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSessionOrderConflicts(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	balance, _ := soroban.ToScVal("balance")
+	key, err := contract.GetDataKey(balance, soroban.Persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadWrite: []xdr.LedgerKey{key}}},
+		})
+		return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+	}
+	finalize := make(chan struct{})
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		<-finalize
+		return &soroban.GetTransactionResult{Status: "SUCCESS"}, nil
+	}
+
+	session := contract.Session(context.Background()).OrderConflicts()
+	defer session.Close()
+	first := session.Invoke("deposit")
+	second := session.Invoke("withdraw")
+	if _, err := first.Result(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-second.Done():
+		t.Fatal("conflicting invocation sent before the first was finalized")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(finalize)
+	if _, err := second.Result(); err != nil {
+		t.Fatal(err)
+	}
+	if n := countCalls(fake, soroban.SendTransaction); n != 2 {
+		t.Fatal(n, fake.Calls)
+	}
+	// the second one is simulated again once the first is finalized
+	if n := countCalls(fake, soroban.SimulateTransaction); n != 3 {
+		t.Fatal(n, fake.Calls)
+	}
+}
+
+func TestInvokeAuditError(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING", LatestLedger: latestLedger}, &soroban.AuditError{Err: errors.New("disk full")}
+	}
+	// the invocation is submitted, its handle is returned with the error
+	pending, err := contract.Invoke().Function("hello").Send(context.Background())
+	var auditErr *soroban.AuditError
+	if !errors.As(err, &auditErr) || pending == nil || pending.Hash() != "abc" {
+		t.Fatal(pending, err)
+	}
+}
@@ -1,10 +1,14 @@
 package soroban
 
 import (
+	"context"
 	"crypto/sha256"
 	"errors"
+	"fmt"
+	"log/slog"
 	"time"
 
+	"github.com/sebamiro/soroban/spec"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
@@ -15,10 +19,22 @@ type (
 		wasm     []byte
 		wasmHash [32]byte
 		salt     [32]byte
-		client   *Client
+		client   SorobanClient
 		source   txnbuild.Account
 		kp       *keypair.Full
 		address  *xdr.ScAddress
+		timeout  time.Duration
+		pin      Pin
+		// stateKeys are the persistent data keys exported by ExportState
+		stateKeys []xdr.ScVal
+		// fenced serializes the invocations of the instance, see Fence
+		fenced bool
+		// quota caps the invocations, see Quota
+		quota *Quota
+		// wasmCache skips the reads of the code, see WasmCache
+		wasmCache        WasmCache
+		wasmCacheMaxAge  time.Duration
+		refreshWasmCache bool
 	}
 
 	invokeBuilder struct {
@@ -29,6 +45,23 @@ type (
 	invokeBuild struct {
 		function string
 		prams    []xdr.ScVal
+		// err is the first error while adding params, returned on Send
+		err error
+		// resources, instructions and resourceFee override the simulated
+		// values when set
+		resources    *xdr.SorobanResources
+		instructions uint32
+		resourceFee  int64
+		// narrowFootprint moves the keys not written to read-only
+		narrowFootprint bool
+		authSigners     []*keypair.Full
+		maxFee          int64
+		// opSource is the source of the operation, the contract source if empty
+		opSource string
+		// fenceKeys are the ledger keys the invocation is serialized on
+		fenceKeys []xdr.LedgerKey
+		// footprints orders the invocations of a session, see OrderConflicts
+		footprints *footprintTracker
 	}
 )
 
@@ -43,6 +76,9 @@ const (
 	ErrorContractNeedsRestore     = "Contract has no ttl, requires a restore"
 	ErrorContractDataNeedsRestore = "Contract data has no ttl, requires a restore"
 	ErrorInvokeRequiresFunction   = "Function is required"
+	ErrorRequiredWasmForSpec      = "Wasm is required to read the spec"
+	ErrorInvalidOpSource          = "Invalid op source account"
+	ErrorOpSourceSignerMissing    = "Op source signer is required, add it with AuthSigner"
 )
 
 // NewContract returns a Contract builder that can install, deploy and invoke
@@ -80,7 +116,7 @@ func (c *Contract) Salt(salt string) *Contract {
 }
 
 // Client sets the client to use to connect to the network
-func (c *Contract) Client(client *Client) *Contract {
+func (c *Contract) Client(client SorobanClient) *Contract {
 	c.client = client
 	return c
 }
@@ -103,6 +139,23 @@ func (c *Contract) Address(address xdr.ScAddress) *Contract {
 	return c
 }
 
+// Spec returns the interface of the contract read from its wasm
+//
+//	Requires wasm
+func (c *Contract) Spec() (*spec.Spec, error) {
+	if len(c.wasm) == 0 {
+		return nil, errors.New(ErrorRequiredWasmForSpec)
+	}
+	return spec.FromWasm(c.wasm)
+}
+
+// Timeout sets the validity of the transactions sent by the Contract,
+// overriding the Client DefaultTimeout
+func (c *Contract) Timeout(timeout time.Duration) *Contract {
+	c.timeout = timeout
+	return c
+}
+
 func (c *Contract) getContractIdPreimage() (xdr.ContractIdPreimage, error) {
 	sourceAccountID, err := xdr.AddressToAccountId(c.source.GetAccountID())
 	if err != nil {
@@ -142,7 +195,7 @@ func (c *Contract) GetAddress() (*xdr.ScAddress, error) {
 		return nil, err
 	}
 	contractId := &xdr.HashIdPreimageContractId{
-		NetworkId:          sha256.Sum256([]byte(c.client.PassPhrase)),
+		NetworkId:          sha256.Sum256([]byte(c.client.NetworkPassphrase())),
 		ContractIdPreimage: contractIdPreimage,
 	}
 	preImage := xdr.HashIdPreimage{
@@ -153,7 +206,7 @@ func (c *Contract) GetAddress() (*xdr.ScAddress, error) {
 	if err != nil {
 		return nil, err
 	}
-	contractHash := xdr.Hash(sha256.Sum256(xdrPreImageBytes))
+	contractHash := xdr.ContractId(sha256.Sum256(xdrPreImageBytes))
 	c.address = &xdr.ScAddress{
 		Type:       xdr.ScAddressTypeScAddressTypeContract,
 		ContractId: &contractHash,
@@ -165,16 +218,7 @@ func (c *Contract) GetAddress() (*xdr.ScAddress, error) {
 //
 //	Requires wasm or wasmHash
 func (c *Contract) GetCodeKey() (xdr.LedgerKey, error) {
-	if len(c.wasmHash) == 0 {
-		return xdr.LedgerKey{}, errors.New(ErrorRequiredWasmHash)
-	}
-	ledgerKey := xdr.LedgerKey{
-		Type: xdr.LedgerEntryTypeContractCode,
-		ContractCode: &xdr.LedgerKeyContractCode{
-			Hash: c.wasmHash,
-		},
-	}
-	return ledgerKey, nil
+	return Key.ContractCode(c.wasmHash)
 }
 
 // GetFootprint returns LedgerKey of ContractData aka contract instance
@@ -184,28 +228,14 @@ func (c *Contract) GetFootprint() (xdr.LedgerKey, error) {
 	if len(c.wasmHash) == 0 {
 		return xdr.LedgerKey{}, errors.New(ErrorRequiredWasmHash)
 	}
-	contractAddress, err := c.GetAddress()
-	if err != nil {
-		return xdr.LedgerKey{}, nil
-	}
-	ledgerKey := xdr.LedgerKey{
-		Type: xdr.LedgerEntryTypeContractData,
-		ContractData: &xdr.LedgerKeyContractData{
-			Contract: *contractAddress,
-			Key: xdr.ScVal{
-				Type: xdr.ScValTypeScvLedgerKeyContractInstance,
-			},
-			Durability: xdr.ContractDataDurabilityPersistent,
-		},
-	}
-	return ledgerKey, nil
+	return c.GetDataKey(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent)
 }
 
 // IsCodeAlive returns if the contract code ttl is > 0 (liveUntilLedger >= current ledger),
 // and the ledger entry of the ContractCode.
 //
 //	Requires wasm or wasmHash, Client
-func (c *Contract) IsCodeAlive() (bool, *GetLedgerEntriesResult, error) {
+func (c *Contract) IsCodeAlive(ctx context.Context) (bool, *GetLedgerEntriesResult, error) {
 	if c.client == nil {
 		return false, nil, errors.New(ErrorRequiredClient)
 	}
@@ -217,10 +247,13 @@ func (c *Contract) IsCodeAlive() (bool, *GetLedgerEntriesResult, error) {
 	if err != nil {
 		return false, nil, err
 	}
-	res, err := c.client.GetLedgerEntries(base64)
+	res, err := c.client.GetLedgerEntries(ctx, base64)
 	if err != nil {
 		return false, nil, err
 	}
+	if len(res.Entries) == 0 {
+		return false, res, nil
+	}
 	return res.Entries[0].LiveUntilLedgerSeq >= res.LatestLedger, res, nil
 }
 
@@ -228,7 +261,7 @@ func (c *Contract) IsCodeAlive() (bool, *GetLedgerEntriesResult, error) {
 // and the ledger entry of the ContractData.
 //
 //	Requires wasm or wasmHash, SourceAddress, Client, Salt
-func (c *Contract) IsInstanceAlive() (bool, *GetLedgerEntriesResult, error) {
+func (c *Contract) IsInstanceAlive(ctx context.Context) (bool, *GetLedgerEntriesResult, error) {
 	ledgerKey, err := c.GetFootprint()
 	if err != nil {
 		return false, nil, err
@@ -237,7 +270,7 @@ func (c *Contract) IsInstanceAlive() (bool, *GetLedgerEntriesResult, error) {
 	if err != nil {
 		return false, nil, err
 	}
-	res, err := c.client.GetLedgerEntries(base64)
+	res, err := c.client.GetLedgerEntries(ctx, base64)
 	if err != nil {
 		return false, nil, err
 	}
@@ -250,22 +283,50 @@ func (c *Contract) IsInstanceAlive() (bool, *GetLedgerEntriesResult, error) {
 // IsAlive checks if contract code and instance are alive
 //
 //	Requires wasm or wasmHash, SourceAddress, Client, Salt
-func (c *Contract) IsAlive() (bool, error) {
-	code, _, err := c.IsCodeAlive()
+func (c *Contract) IsAlive(ctx context.Context) (bool, error) {
+	liveUntil, latestLedger, err := c.liveUntil(ctx)
 	if err != nil {
 		return false, err
 	}
-	instance, _, err := c.IsInstanceAlive()
+	return liveUntil != 0 && liveUntil >= latestLedger, nil
+}
+
+// liveUntil fetches the code and instance entries in a single call, returning
+// the ledger both are alive until, 0 if any is missing, and the latest ledger
+func (c *Contract) liveUntil(ctx context.Context) (int64, int64, error) {
+	if c.client == nil {
+		return 0, 0, errors.New(ErrorRequiredClient)
+	}
+	codeKey, err := c.GetCodeKey()
 	if err != nil {
-		return false, err
+		return 0, 0, err
+	}
+	instanceKey, err := c.GetFootprint()
+	if err != nil {
+		return 0, 0, err
+	}
+	codeXdr, err := codeKey.MarshalBinaryBase64()
+	if err != nil {
+		return 0, 0, err
+	}
+	instanceXdr, err := instanceKey.MarshalBinaryBase64()
+	if err != nil {
+		return 0, 0, err
+	}
+	res, err := c.client.GetLedgerEntries(ctx, codeXdr, instanceXdr)
+	if err != nil {
+		return 0, 0, err
+	}
+	if len(res.Entries) != 2 {
+		return 0, res.LatestLedger, nil
 	}
-	return code && instance, nil
+	return min(res.Entries[0].LiveUntilLedgerSeq, res.Entries[1].LiveUntilLedgerSeq), res.LatestLedger, nil
 }
 
 // Install sends the transaction to install the compiled contract wasm file
-// The result status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
-// It will NOT check if it was accepted, it will need to be check
-// using RPC call to getTransaction with the transaction hash
+// The sent status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
 //
 //	Requires wasm, client, sourceAccount, keyPair
 //
@@ -276,8 +337,8 @@ func (c *Contract) IsAlive() (bool, error) {
 //		Salt(salt).
 //		SourceAccount(account).
 //		KeyPair(pair).
-//		Install()
-func (c *Contract) Install() (*SendTransactionResult, error) {
+//		Install(ctx)
+func (c *Contract) Install(ctx context.Context) (*PendingTransaction, error) {
 	switch {
 	case c.client == nil:
 		return nil, errors.New(ErrorRequiredClient)
@@ -293,14 +354,14 @@ func (c *Contract) Install() (*SendTransactionResult, error) {
 		},
 		SourceAccount: c.source.GetAccountID(),
 	}
-	return c.simulateSubmitHostFunction(installOp)
+	return c.simulateSubmitHostFunction(ctx, installOp)
 }
 
 // Deploy sends the transaction to create a new instance of the compiled contract wasm file.
 // It will return an error if the wasm code is not installed or has no time to live left.
-// The result status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR.
-// It will NOT check if it was accepted, it will need to be check
-// using RPC call to getTransaction with the transaction hash
+// The sent status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR.
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
 //
 //	Requires wasm, client, sourceAccount, keyPair
 //
@@ -311,8 +372,8 @@ func (c *Contract) Install() (*SendTransactionResult, error) {
 //		Salt(salt).
 //		SourceAccount(account).
 //		KeyPair(pair).
-//		Deploy()
-func (c *Contract) Deploy() (*SendTransactionResult, error) {
+//		Deploy(ctx)
+func (c *Contract) Deploy(ctx context.Context) (*PendingTransaction, error) {
 	switch {
 	case c.client == nil:
 		return nil, errors.New(ErrorRequiredClient)
@@ -321,7 +382,7 @@ func (c *Contract) Deploy() (*SendTransactionResult, error) {
 	case c.kp == nil:
 		return nil, errors.New(ErrorRequiredKeyPair)
 	}
-	isCodeAlive, _, err := c.IsCodeAlive()
+	isCodeAlive, err := c.codeAlive(ctx)
 	if err != nil {
 		return nil, err
 	}
@@ -347,7 +408,7 @@ func (c *Contract) Deploy() (*SendTransactionResult, error) {
 		},
 		SourceAccount: c.source.GetAccountID(),
 	}
-	return c.simulateSubmitHostFunction(createOp)
+	return c.simulateSubmitHostFunction(ctx, createOp)
 }
 
 // Invoke inits the building of an invoketion transaction of a function.
@@ -358,7 +419,7 @@ func (c *Contract) Deploy() (*SendTransactionResult, error) {
 //		Invoke().
 //		Function("hello").
 //		Symbol("world").
-//		Send()
+//		Send(ctx)
 func (c *Contract) Invoke() *invokeBuilder {
 	return &invokeBuilder{
 		contract: c,
@@ -374,6 +435,25 @@ func (c *invokeBuilder) Function(function string) *invokeBuilder {
 	return c
 }
 
+// Reset clears the params, keeping the function and the other settings, so
+// the builder can be reused for another invocation
+func (c *invokeBuilder) Reset() *invokeBuilder {
+	c.build.prams = make([]xdr.ScVal, 0)
+	c.build.err = nil
+	return c
+}
+
+// WithArgs replaces the params with args
+//
+//	Example:
+//	 transfer := contract.Invoke().Function("transfer")
+//	 for _, to := range recipients {
+//		res, err := transfer.WithArgs(from, to, amount).Send(ctx)
+//	 }
+func (c *invokeBuilder) WithArgs(args ...xdr.ScVal) *invokeBuilder {
+	return c.Reset().Params(args...)
+}
+
 // Params appends a list of xdr.ScVal to the params
 func (c *invokeBuilder) Params(params ...xdr.ScVal) *invokeBuilder {
 	c.build.prams = append(c.build.prams, params...)
@@ -422,53 +502,166 @@ func (c *invokeBuilder) Symbol(s string) *invokeBuilder {
 	return c
 }
 
+// Value appends v converted with ToScVal to the params, using the converters
+// registered with RegisterScValType. Conversion errors make Send fail.
+func (c *invokeBuilder) Value(v any) *invokeBuilder {
+	val, err := ToScVal(v)
+	if err != nil {
+		if c.build.err == nil {
+			c.build.err = err
+		}
+		return c
+	}
+	c.build.prams = append(c.build.prams, val)
+	return c
+}
+
+// Map appends a map xdr.ScVal to the params, with the entries sorted canonically.
+// Duplicated keys make Send fail.
+func (c *invokeBuilder) Map(entries ...xdr.ScMapEntry) *invokeBuilder {
+	m, err := NewScMap(entries...)
+	if err != nil {
+		if c.build.err == nil {
+			c.build.err = err
+		}
+		return c
+	}
+	c.build.prams = append(c.build.prams, m)
+	return c
+}
+
+// Instructions sets the cpu instructions of the invocation, overriding the
+// simulated ones, for hot paths simulation underestimates. When above the
+// simulated instructions the resource fee is increased in proportion, the
+// unused resource fee is refunded.
+func (c *invokeBuilder) Instructions(instructions uint32) *invokeBuilder {
+	c.build.instructions = instructions
+	return c
+}
+
+// Resources sets the resources of the invocation (footprint, instructions,
+// read and write bytes), replacing the simulated ones. Instructions, if set,
+// still overrides the instructions.
+func (c *invokeBuilder) Resources(resources xdr.SorobanResources) *invokeBuilder {
+	c.build.resources = &resources
+	return c
+}
+
+// ResourceFee sets the resource fee of the invocation, replacing the
+// simulated, and the proportionally increased, one.
+func (c *invokeBuilder) ResourceFee(fee int64) *invokeBuilder {
+	c.build.resourceFee = fee
+	return c
+}
+
+// AuthSigner adds signers for the authorization entries of the invocation,
+// each entry is signed by the signer matching its address. It allows sub
+// invocations to be authorized by different signers, e.g. a user for a
+// transfer and an admin for a config change.
+func (c *invokeBuilder) AuthSigner(signers ...*keypair.Full) *invokeBuilder {
+	c.build.authSigners = append(c.build.authSigners, signers...)
+	return c
+}
+
+// OpSource sets the source account of the invocation operation, accountID
+// (G... or M...), different from the transaction source, e.g. a channel
+// account paying the fees of a user invocation. The authorization of the op
+// source uses its source account credentials, valid through its envelope
+// signature, so its key pair has to be added with AuthSigner.
+func (c *invokeBuilder) OpSource(accountID string) *invokeBuilder {
+	if _, err := xdr.AddressToMuxedAccount(accountID); err != nil {
+		if c.build.err == nil {
+			c.build.err = fmt.Errorf("%s: %w", ErrorInvalidOpSource, err)
+		}
+		return c
+	}
+	c.build.opSource = accountID
+	return c
+}
+
+// MaxFee sets the ceiling of the total fee of the invocation, Send fails with
+// ErrorFeeCeilingExceeded before signing if the simulated fee exceeds it, see
+// Transaction.MaxFee
+func (c *invokeBuilder) MaxFee(fee int64) *invokeBuilder {
+	c.build.maxFee = fee
+	return c
+}
+
+// NarrowFootprint moves the read-write keys of the simulated footprint the
+// invocation does not write to read-only, and drops duplicated keys, reducing
+// the fees of read heavy invocations. The footprint is validated against the
+// simulated state changes before sending.
+func (c *invokeBuilder) NarrowFootprint() *invokeBuilder {
+	c.build.narrowFootprint = true
+	return c
+}
+
 // Send sends the transaction to invoke the contract function with the parameters set.
-// It will return an error if the wasm code is not installed or has no time to live left.
-// It will return an error if the contract instance has no time to live left.
-// The result status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
-// It will NOT check if it was accepted, it will need to be check
-// using RPC call to getTransaction with the transaction hash
+// It will return ErrorContractNeedsRestore if the wasm code or the contract
+// instance have no time to live left, ErrorContractDataNeedsRestore if the
+// data read by the invocation has none.
+// The sent status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result.
+// It costs two calls, simulateTransaction, which reports the archived
+// entries, and sendTransaction.
+// A fenced invocation first waits for the ones holding its fences, see
+// Contract.Fence. It fails with ErrorQuotaExceeded over the Contract.Quota.
+//
+//	Requires wasm, client, sourceAccount, keyPair, salt, function
+func (c *invokeBuilder) Send(ctx context.Context) (*PendingTransaction, error) {
+	return c.send(ctx, false)
+}
+
+// RestoreAndSend if the contract code, instance or data have no ttl left, it
+// will restore them before sending the transaction, in one restore. A failed
+// restore returns its *TransactionFailedError without invoking.
+// The sent status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
 //
 //	Requires wasm, client, sourceAccount, keyPair, salt, function
-func (c *invokeBuilder) Send() (*SendTransactionResult, error) {
+func (c *invokeBuilder) RestoreAndSend(ctx context.Context) (*PendingTransaction, error) {
+	return c.send(ctx, true)
+}
+
+func (c *invokeBuilder) send(ctx context.Context, restore bool) (*PendingTransaction, error) {
 	if c.build.function == "" {
 		return nil, errors.New(ErrorInvokeRequiresFunction)
 	}
-	isAlive, err := c.contract.IsAlive()
-	if err != nil {
+	if c.build.err != nil {
+		return nil, c.build.err
+	}
+	if err := c.contract.quota.take(c.build.function); err != nil {
 		return nil, err
 	}
-	if !isAlive {
-		return nil, errors.New(ErrorContractNeedsRestore)
+	release, err := c.fence(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return c.contract.invoke(c.build, false)
+	return release.after(c.contract.invoke(ctx, c.build, restore))
 }
 
-// RestoreAndSend if the contract has no ttl left, it will retore it before sending the transaction.
-// The result status can be PENDING, DUPLICATE, TRY_AGAIN_LATER, ERROR
-// It will NOT check if it was accepted, it will need to be check
-// using RPC call to getTransaction with the transaction hash
+// Simulate simulates the invocation without sending it, e.g. to read the
+// value returned by a read-only function, see
+// SimulateTransactionResult.ReturnValue. The liveness of the contract is not
+// checked.
 //
-//	Requires wasm, client, sourceAccount, keyPair, salt, function
-func (c *invokeBuilder) RestoreAndSend() (*SendTransactionResult, error) {
+//	Requires client, sourceAccount, function
+func (c *invokeBuilder) Simulate(ctx context.Context) (*SimulateTransactionResult, error) {
 	if c.build.function == "" {
 		return nil, errors.New(ErrorInvokeRequiresFunction)
 	}
-	isAlive, err := c.contract.IsAlive()
-	if err != nil {
-		return nil, err
+	if c.build.err != nil {
+		return nil, c.build.err
 	}
-	if !isAlive {
-		res, err := c.contract.Restore()
-		if err != nil {
-			return nil, err
-		}
-		c.contract.client.waitCompletedTransaction(res.Hash)
-	}
-	return c.contract.invoke(c.build, true)
+	_, res, err := c.contract.simulateInvoke(ctx, c.build)
+	return res, err
 }
 
-func (c *Contract) invoke(build *invokeBuild, restore bool) (*SendTransactionResult, error) {
+// invokeTransaction builds the unsimulated transaction invoking the function
+// of build
+func (c *Contract) invokeTransaction(build *invokeBuild) (*Transaction, error) {
 	contractAddress, err := c.GetAddress()
 	if err != nil {
 		return nil, err
@@ -484,61 +677,180 @@ func (c *Contract) invoke(build *invokeBuild, restore bool) (*SendTransactionRes
 		},
 		SourceAccount: c.source.GetAccountID(),
 	}
-	transaction := NewTransctionBuilder().
+	signers := []*keypair.Full{c.kp}
+	if build.opSource != "" && build.opSource != c.source.GetAccountID() {
+		signer, err := build.opSourceSigner()
+		if err != nil {
+			return nil, err
+		}
+		invokeHostFunctionOp.SourceAccount = build.opSource
+		signers = append(signers, signer)
+	}
+	return NewTransctionBuilder().
 		Client(c.client).
 		SourceAccount(c.source).
-		Signer(c.kp).
+		Signer(signers...).
 		Operation(&invokeHostFunctionOp).
-		TimeBounds(txnbuild.NewTimeout(30))
-	res, err := transaction.Simulate()
+		Timeout(c.timeout).
+		Pin(c.pin).
+		AuthSigner(build.authSigners...).
+		MaxFee(build.maxFee), nil
+}
+
+// opSourceSigner returns the AuthSigner of the op source
+func (b *invokeBuild) opSourceSigner() (*keypair.Full, error) {
+	muxed, err := xdr.AddressToMuxedAccount(b.opSource)
 	if err != nil {
 		return nil, err
 	}
+	address := muxed.ToAccountId().Address()
+	for _, signer := range b.authSigners {
+		if signer.Address() == address {
+			return signer, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %s", ErrorOpSourceSignerMissing, address)
+}
+
+func (c *Contract) invoke(ctx context.Context, build *invokeBuild, restore bool) (*PendingTransaction, error) {
+	transaction, res, err := c.simulateInvoke(ctx, build)
+	if err != nil {
+		return nil, err
+	}
+	// auditErr is the *AuditError of a submitted restore, returned along
+	// with the invocation
+	var auditErr error
 	if res.RestorePreamble.MinResourceFee != 0 {
 		if !restore {
-			return nil, errors.New(ErrorContractDataNeedsRestore)
+			return nil, c.needsRestore(res.RestorePreamble.Data)
 		}
-		var transactionData xdr.SorobanTransactionData
-		err := xdr.SafeUnmarshalBase64(res.TransactionData, &transactionData)
-		if err != nil {
+		if err := spendRetry(c.client); err != nil {
 			return nil, err
 		}
+		debug(ctx, c.client, "restore", slog.String("function", build.function), slog.Int64("resourceFee", res.RestorePreamble.MinResourceFee))
+		data := res.RestorePreamble.Data
+		data.ResourceFee = xdr.Int64(res.RestorePreamble.MinResourceFee)
 		t := NewTransctionBuilder().
 			Client(c.client).
 			SourceAccount(c.source).
 			Signer(c.kp).
 			Operation(&txnbuild.RestoreFootprint{SourceAccount: c.source.GetAccountID()}).
-			TimeBounds(txnbuild.NewTimeout(30)).
-			SorobanData(transactionData).
-			BaseFee(res.RestorePreamble.MinResourceFee + txnbuild.MinBaseFee)
-		res, err := t.Send()
+			Timeout(c.timeout).
+			Pin(c.pin).
+			SorobanData(data)
+		restore, err := t.Send(ctx)
+		restored(c.client, restore)
+		if restore == nil {
+			return nil, err
+		}
+		auditErr = err
+		result, err := waitForTransaction(ctx, c.client, restore.Hash(), WaitOptions{})
 		if err != nil {
 			return nil, err
 		}
-		c.client.waitCompletedTransaction(res.Hash)
+		if result.Status != "SUCCESS" {
+			return nil, failedError(result)
+		}
+	}
+	if build.overridesResources() || build.narrowFootprint {
+		transactionData := res.Data
+		build.applyResources(&transactionData, res.MinResourceFee)
+		if build.narrowFootprint {
+			written, err := res.WrittenKeys()
+			if err != nil {
+				return nil, err
+			}
+			footprint, err := NarrowFootprint(transactionData.Resources.Footprint, written)
+			if err != nil {
+				return nil, err
+			}
+			if err := ValidateFootprint(footprint, written); err != nil {
+				return nil, err
+			}
+			transactionData.Resources.Footprint = footprint
+		}
+		transaction.SorobanData(transactionData)
+	}
+	// a pending transaction with an error is submitted, the error is an
+	// *AuditError
+	pending, err := transaction.Send(ctx)
+	if pending == nil {
+		return nil, err
 	}
-	return transaction.Send()
+	build.footprints.track(res, pending)
+	if err == nil {
+		err = auditErr
+	}
+	return pending, err
 }
 
-func (c *Contract) simulateSubmitHostFunction(op txnbuild.InvokeHostFunction) (*SendTransactionResult, error) {
+// needsRestore returns ErrorContractNeedsRestore if the code or the instance
+// of the contract are in the footprint of the restore data, archived,
+// ErrorContractDataNeedsRestore otherwise
+func (c *Contract) needsRestore(data xdr.SorobanTransactionData) error {
+	var keys []xdr.LedgerKey
+	if c.wasmHash != ([32]byte{}) {
+		if key, err := c.GetCodeKey(); err == nil {
+			keys = append(keys, key)
+		}
+	}
+	if key, err := c.GetDataKey(xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent); err == nil {
+		keys = append(keys, key)
+	}
+	for _, archived := range data.Resources.Footprint.ReadWrite {
+		for _, key := range keys {
+			if archived.Equals(key) {
+				return errors.New(ErrorContractNeedsRestore)
+			}
+		}
+	}
+	return errors.New(ErrorContractDataNeedsRestore)
+}
+
+func (b *invokeBuild) overridesResources() bool {
+	return b.resources != nil || b.instructions != 0 || b.resourceFee != 0
+}
+
+// applyResources overrides the simulated resources of data and sets its
+// resource fee
+func (b *invokeBuild) applyResources(data *xdr.SorobanTransactionData, minResourceFee int64) {
+	simulated := data.Resources.Instructions
+	if b.resources != nil {
+		data.Resources = *b.resources
+	}
+	if b.instructions != 0 {
+		data.Resources.Instructions = xdr.Uint32(b.instructions)
+	}
+	fee := minResourceFee
+	if instructions := data.Resources.Instructions; simulated != 0 && instructions > simulated {
+		fee = minResourceFee * int64(instructions) / int64(simulated)
+	}
+	if b.resourceFee != 0 {
+		fee = b.resourceFee
+	}
+	data.ResourceFee = xdr.Int64(fee)
+}
+
+func (c *Contract) simulateSubmitHostFunction(ctx context.Context, op txnbuild.InvokeHostFunction) (*PendingTransaction, error) {
 	transaction := NewTransctionBuilder().
 		Client(c.client).
 		SourceAccount(c.source).
 		Signer(c.kp).
 		Operation(&op).
-		TimeBounds(txnbuild.NewTimeout(30))
-	_, err := transaction.Simulate()
+		Timeout(c.timeout).
+		Pin(c.pin)
+	_, err := transaction.Simulate(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return transaction.Send()
+	return transaction.Send(ctx)
 }
 
 // Restore restores the contract wasm code and instace if neededd
 // Docs: https://developers.stellar.org/docs/learn/encyclopedia/storage/state-archival
 //
 //	Requires wasm, client, sourceAccount, keyPair, salt, function
-func (c *Contract) Restore() (*SendTransactionResult, error) {
+func (c *Contract) Restore(ctx context.Context) (*PendingTransaction, error) {
 	var readWrite []xdr.LedgerKey
 	codeKey, err := c.GetCodeKey()
 	if err != nil {
@@ -555,7 +867,8 @@ func (c *Contract) Restore() (*SendTransactionResult, error) {
 		SourceAccount(c.source).
 		Signer(c.kp).
 		Operation(&txnbuild.RestoreFootprint{SourceAccount: c.source.GetAccountID()}).
-		TimeBounds(txnbuild.NewTimeout(30)).
+		Timeout(c.timeout).
+		Pin(c.pin).
 		SorobanData(xdr.SorobanTransactionData{
 			Resources: xdr.SorobanResources{
 				Footprint: xdr.LedgerFootprint{
@@ -563,23 +876,11 @@ func (c *Contract) Restore() (*SendTransactionResult, error) {
 				},
 			},
 		})
-	_, err = transaction.Simulate()
+	_, err = transaction.Simulate(ctx)
 	if err != nil {
 		return nil, err
 	}
-	return transaction.Send()
-}
-
-func (c *Client) waitCompletedTransaction(hash string) (*GetTransactionResult, error) {
-	for i := 0; i < 5; i++ {
-		res, err := c.GetTransaction(hash)
-		if err != nil {
-			return nil, err
-		}
-		if res.Status != "NOT_FOUND" {
-			return res, nil
-		}
-		time.Sleep(time.Duration(i) * 2 * time.Second)
-	}
-	return nil, nil
+	pending, err := transaction.Send(ctx)
+	restored(c.client, pending)
+	return pending, err
 }
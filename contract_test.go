@@ -1,11 +1,14 @@
 package soroban_test
 
 import (
+	"context"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/network"
 	"github.com/stellar/go/xdr"
@@ -25,7 +28,7 @@ const (
 
 func WaitCompletedTransaction(c soroban.Client, hash string, maxAttempts int) (*soroban.GetTransactionResult, error) {
 	for i := 0; i < maxAttempts; i++ {
-		res, err := c.GetTransaction(hash)
+		res, err := c.GetTransaction(context.Background(), hash)
 		if err != nil {
 			return nil, err
 		}
@@ -53,8 +56,8 @@ func TestMain(t *testing.M) {
 
 func TestInstallContract(t *testing.T) {
 	pair, _ := keypair.Random()
-	sorobanClient.Fund(pair.Address())
-	account, err := sorobanClient.GetAccount(pair.Address())
+	sorobanClient.Fund(context.Background(), pair.Address())
+	account, err := sorobanClient.GetAccount(context.Background(), pair.Address())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -65,12 +68,12 @@ func TestInstallContract(t *testing.T) {
 		Salt("a1").
 		SourceAccount(account).
 		KeyPair(pair).
-		Install()
+		Install(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
 
-	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash, 10)
+	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -81,11 +84,11 @@ func TestInstallContract(t *testing.T) {
 
 func TestCreateContract(t *testing.T) {
 	pair, _ := keypair.Random()
-	_, err := sorobanClient.Fund(pair.Address())
+	_, err := sorobanClient.Fund(context.Background(), pair.Address())
 	if err != nil {
 		t.Fatal(err)
 	}
-	account, err := sorobanClient.GetAccount(pair.Address())
+	account, err := sorobanClient.GetAccount(context.Background(), pair.Address())
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -96,11 +99,11 @@ func TestCreateContract(t *testing.T) {
 		Salt("a1").
 		SourceAccount(account).
 		KeyPair(pair).
-		Deploy()
+		Deploy(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
-	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash, 10)
+	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -115,8 +118,8 @@ var account *soroban.Account
 
 func TestDeployContract(t *testing.T) {
 	pair, _ = keypair.Random()
-	sorobanClient.Fund(pair.Address())
-	account, _ = sorobanClient.GetAccount(pair.Address())
+	sorobanClient.Fund(context.Background(), pair.Address())
+	account, _ = sorobanClient.GetAccount(context.Background(), pair.Address())
 
 	contract := soroban.NewContract().
 		Wasm(contractWasm).
@@ -125,19 +128,19 @@ func TestDeployContract(t *testing.T) {
 		SourceAccount(account).
 		KeyPair(pair)
 
-	res, err := contract.Install()
-	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash, 10)
+	res, err := contract.Install(context.Background())
+	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
 	if completed.Status != "SUCCESS" {
 		t.Fatal(completed)
 	}
-	res, err = contract.Deploy()
+	res, err = contract.Deploy(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
-	completed, err = WaitCompletedTransaction(sorobanClient, res.Hash, 10)
+	completed, err = WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -157,11 +160,11 @@ func TestInvokeContractFunction(t *testing.T) {
 	res, err := contract.Invoke().
 		Function("hello").
 		Symbol("World").
-		RestoreAndSend()
+		RestoreAndSend(context.Background())
 	if err != nil {
 		t.Fatal(err)
 	}
-	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash, 10)
+	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
 	if err != nil {
 		t.Fatal(err)
 	}
@@ -173,10 +176,116 @@ func TestInvokeContractFunction(t *testing.T) {
 	xdr.SafeUnmarshalBase64(completed.ResultXdr, &resultXdr)
 	resultXdr.Result.MustResults()[0].Tr.MustInvokeHostFunctionResult().MustSuccess()
 
-	var transactionMeta xdr.TransactionMeta
-	xdr.SafeUnmarshalBase64(completed.ResultMetaXdr, &transactionMeta)
-
-	if *(*transactionMeta.V3.SorobanMeta.ReturnValue.MustVec())[1].Sym != xdr.ScSymbol("World") {
+	returnValue, err := completed.ReturnValue()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *(*returnValue.MustVec())[1].Sym != xdr.ScSymbol("World") {
 		t.Fatal("Missmatch result")
 	}
 }
+
+func TestInvokeContractInstructions(t *testing.T) {
+	contract := soroban.NewContract().
+		Wasm(contractWasm).
+		Client(&sorobanClient).
+		Salt("TestDeployContract").
+		SourceAccount(account).
+		KeyPair(pair)
+
+	res, err := contract.Invoke().
+		Function("hello").
+		Symbol("World").
+		Instructions(10_000_000).
+		RestoreAndSend(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	completed, err := WaitCompletedTransaction(sorobanClient, res.Hash(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if completed.Status != "SUCCESS" {
+		t.Fatal(completed)
+	}
+	envelope, err := completed.Envelope()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, ok := envelope.V1.Tx.Ext.GetSorobanData()
+	if !ok || data.Resources.Instructions != 10_000_000 {
+		t.Fatal(data)
+	}
+}
+
+func TestInvokeBuilderReuse(t *testing.T) {
+	var args []xdr.ScVec
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+			args = append(args, envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.Args)
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+			return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Status: "PENDING", LatestLedger: 11}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	contract := soroban.NewContract().
+		Client(fake).
+		WasmHash([32]byte{1}).
+		Salt("reuse").
+		SourceAccount(&soroban.Account{AccountId: pair.Address(), Sequence: 1}).
+		KeyPair(pair)
+
+	hello := contract.Invoke().Function("hello")
+	for _, name := range []string{"a", "b", "c"} {
+		if _, err := hello.WithArgs().Symbol(name).Send(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the liveness is reported by the simulations
+	for _, call := range fake.Calls {
+		if call == soroban.GetLedgerEntries {
+			t.Fatal("liveness checked", fake.Calls)
+		}
+	}
+	if len(args) != 3 || len(args[2]) != 1 || *args[2][0].Sym != "c" {
+		t.Fatal(args)
+	}
+}
+
+func TestInvokeOpSource(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	var sent xdr.TransactionEnvelope
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		xdr.SafeUnmarshalBase64(envelopeXdr, &sent)
+		return &soroban.SendTransactionResult{Status: "PENDING", LatestLedger: 11}, nil
+	}
+	user := keypair.MustRandom()
+
+	_, err := contract.Invoke().Function("hello").OpSource(user.Address()).Send(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorOpSourceSignerMissing) {
+		t.Fatal(err)
+	}
+	_, err = contract.Invoke().Function("hello").OpSource("GBAD").Send(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorInvalidOpSource) {
+		t.Fatal(err)
+	}
+
+	_, err = contract.Invoke().Function("hello").OpSource(user.Address()).AuthSigner(user).Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	op := sent.Operations()[0]
+	if op.SourceAccount == nil || op.SourceAccount.Address() != user.Address() {
+		t.Fatal(op.SourceAccount)
+	}
+	if sent.SourceAccount().ToAccountId().Address() == user.Address() || len(sent.Signatures()) != 2 {
+		t.Fatal(sent.SourceAccount(), len(sent.Signatures()))
+	}
+}
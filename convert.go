@@ -0,0 +1,367 @@
+package soroban
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"reflect"
+	"strings"
+	"sync"
+	"unicode"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorScValUnsupportedType = "Unsupported type for ScVal"
+	ErrorScValTypeMismatch    = "ScVal type does not match"
+	ErrorScValNotPointer      = "ScVal can only be decoded into a non nil pointer"
+)
+
+type scValConverter struct {
+	to   func(v reflect.Value) (xdr.ScVal, error)
+	from func(val xdr.ScVal, out reflect.Value) error
+}
+
+var (
+	convertersMu sync.RWMutex
+	converters   = map[reflect.Type]scValConverter{}
+)
+
+var (
+	scValType  = reflect.TypeOf(xdr.ScVal{})
+	bigIntType = reflect.TypeFor[big.Int]()
+)
+
+// RegisterScValType registers the conversion of T to and from ScVal, e.g.
+// decimals as i128 or UUIDs as BytesN<16>. Registered types are converted by
+// ToScVal, FromScVal and invokeBuilder.Value before the built in conversions,
+// also when nested in structs, slices and maps. Registering a type again
+// replaces its converter.
+func RegisterScValType[T any](to func(T) (xdr.ScVal, error), from func(xdr.ScVal) (T, error)) {
+	t := reflect.TypeFor[T]()
+	convertersMu.Lock()
+	defer convertersMu.Unlock()
+	converters[t] = scValConverter{
+		to: func(v reflect.Value) (xdr.ScVal, error) {
+			return to(v.Interface().(T))
+		},
+		from: func(val xdr.ScVal, out reflect.Value) error {
+			v, err := from(val)
+			if err != nil {
+				return err
+			}
+			out.Set(reflect.ValueOf(&v).Elem())
+			return nil
+		},
+	}
+}
+
+func converter(t reflect.Type) (scValConverter, bool) {
+	convertersMu.RLock()
+	defer convertersMu.RUnlock()
+	c, ok := converters[t]
+	return c, ok
+}
+
+// ToScVal converts v to ScVal:
+//   - registered types with their converter
+//   - xdr.ScVal as is
+//   - bool, ints and uints as bool, i32 (int8, int16, int32), i64 (int,
+//     int64), u32 (uint8, uint16, uint32) and u64 (uint, uint64)
+//   - big.Int as i128
+//   - string as string
+//   - []byte and [N]byte as bytes
+//   - slices and arrays as vec
+//   - maps as map
+//   - structs as map with symbol keys, the snake case field name or the
+//     name of the `scval` tag, "-" skips the field
+//   - nil pointers as void
+func ToScVal(v any) (xdr.ScVal, error) {
+	if v == nil {
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	}
+	return toScVal(reflect.ValueOf(v))
+}
+
+func toScVal(v reflect.Value) (xdr.ScVal, error) {
+	if c, ok := converter(v.Type()); ok {
+		return c.to(v)
+	}
+	if v.Type() == scValType {
+		return v.Interface().(xdr.ScVal), nil
+	}
+	if v.Type() == bigIntType {
+		n := v.Interface().(big.Int)
+		return BigIntToI128(&n)
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		b := v.Bool()
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case reflect.Int8, reflect.Int16, reflect.Int32:
+		i := xdr.Int32(v.Int())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &i}, nil
+	case reflect.Int, reflect.Int64:
+		i := xdr.Int64(v.Int())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &i}, nil
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32:
+		u := xdr.Uint32(v.Uint())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}, nil
+	case reflect.Uint, reflect.Uint64:
+		u := xdr.Uint64(v.Uint())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u}, nil
+	case reflect.String:
+		s := xdr.ScString(v.String())
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &s}, nil
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return toScVal(v.Elem())
+	case reflect.Slice, reflect.Array:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			b := make(xdr.ScBytes, v.Len())
+			reflect.Copy(reflect.ValueOf(b), v)
+			return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}, nil
+		}
+		values := make([]xdr.ScVal, v.Len())
+		for i := range values {
+			val, err := toScVal(v.Index(i))
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			values[i] = val
+		}
+		return NewScVec(values...), nil
+	case reflect.Map:
+		entries := make([]xdr.ScMapEntry, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			key, err := toScVal(iter.Key())
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			val, err := toScVal(iter.Value())
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("[%v]: %w", iter.Key(), err)
+			}
+			entries = append(entries, xdr.ScMapEntry{Key: key, Val: val})
+		}
+		return NewScMap(entries...)
+	case reflect.Struct:
+		fields := make(map[string]xdr.ScVal)
+		for i := range v.NumField() {
+			name, ok := fieldName(v.Type().Field(i))
+			if !ok {
+				continue
+			}
+			val, err := toScVal(v.Field(i))
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("%s: %w", name, err)
+			}
+			fields[name] = val
+		}
+		return ScMapFromMap(fields)
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorScValUnsupportedType, v.Type())
+}
+
+// FromScVal decodes val into out, a non nil pointer, with the conversions of
+// ToScVal. Vec and map are also decoded into slices and maps of xdr.ScVal,
+// and void into nil pointers. Any integer is decoded into a big.Int, and
+// i128 and u128 into ints and uints, failing with ErrorScValOverflow if they
+// do not fit.
+func FromScVal(val xdr.ScVal, out any) error {
+	v := reflect.ValueOf(out)
+	if v.Kind() != reflect.Pointer || v.IsNil() {
+		return errors.New(ErrorScValNotPointer)
+	}
+	return fromScVal(val, v.Elem())
+}
+
+// ScValAs decodes val into a T with FromScVal
+//
+//	Example:
+//	 balances, err := soroban.ScValAs[map[string]big.Int](val)
+func ScValAs[T any](val xdr.ScVal) (T, error) {
+	var v T
+	err := FromScVal(val, &v)
+	return v, err
+}
+
+func fromScVal(val xdr.ScVal, out reflect.Value) error {
+	if c, ok := converter(out.Type()); ok {
+		return c.from(val, out)
+	}
+	if out.Type() == scValType {
+		out.Set(reflect.ValueOf(val))
+		return nil
+	}
+	if out.Type() == bigIntType {
+		n, err := ScValToBigInt(val)
+		if err != nil {
+			return err
+		}
+		out.Set(reflect.ValueOf(*n))
+		return nil
+	}
+	mismatch := func() error {
+		return fmt.Errorf("%s: %s into %s", ErrorScValTypeMismatch, val.Type, out.Type())
+	}
+	switch out.Kind() {
+	case reflect.Bool:
+		if val.Type != xdr.ScValTypeScvBool {
+			return mismatch()
+		}
+		out.SetBool(*val.B)
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int, reflect.Int64:
+		switch val.Type {
+		case xdr.ScValTypeScvI32:
+			out.SetInt(int64(*val.I32))
+		case xdr.ScValTypeScvI64:
+			out.SetInt(int64(*val.I64))
+		case xdr.ScValTypeScvI128:
+			n, err := ScValToInt64(val)
+			if err == nil && out.OverflowInt(n) {
+				err = fmt.Errorf("%s: %d into %s", ErrorScValOverflow, n, out.Type())
+			}
+			if err != nil {
+				return err
+			}
+			out.SetInt(n)
+		default:
+			return mismatch()
+		}
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint, reflect.Uint64:
+		switch val.Type {
+		case xdr.ScValTypeScvU32:
+			out.SetUint(uint64(*val.U32))
+		case xdr.ScValTypeScvU64:
+			out.SetUint(uint64(*val.U64))
+		case xdr.ScValTypeScvU128:
+			n, _ := ScValToBigInt(val)
+			if !n.IsUint64() || out.OverflowUint(n.Uint64()) {
+				return fmt.Errorf("%s: %s into %s", ErrorScValOverflow, n, out.Type())
+			}
+			out.SetUint(n.Uint64())
+		default:
+			return mismatch()
+		}
+	case reflect.String:
+		switch val.Type {
+		case xdr.ScValTypeScvString:
+			out.SetString(string(*val.Str))
+		case xdr.ScValTypeScvSymbol:
+			out.SetString(string(*val.Sym))
+		default:
+			return mismatch()
+		}
+	case reflect.Pointer:
+		if val.Type == xdr.ScValTypeScvVoid {
+			out.SetZero()
+			return nil
+		}
+		elem := reflect.New(out.Type().Elem())
+		if err := fromScVal(val, elem.Elem()); err != nil {
+			return err
+		}
+		out.Set(elem)
+	case reflect.Slice, reflect.Array:
+		if out.Type().Elem().Kind() == reflect.Uint8 {
+			if val.Type != xdr.ScValTypeScvBytes {
+				return mismatch()
+			}
+			if out.Kind() == reflect.Slice {
+				out.Set(reflect.MakeSlice(out.Type(), len(*val.Bytes), len(*val.Bytes)))
+			} else if out.Len() != len(*val.Bytes) {
+				return mismatch()
+			}
+			reflect.Copy(out, reflect.ValueOf([]byte(*val.Bytes)))
+			return nil
+		}
+		if val.Type != xdr.ScValTypeScvVec {
+			return mismatch()
+		}
+		items := scVec(val)
+		if out.Kind() == reflect.Slice {
+			out.Set(reflect.MakeSlice(out.Type(), len(items), len(items)))
+		} else if out.Len() != len(items) {
+			return mismatch()
+		}
+		for i, item := range items {
+			if err := fromScVal(item, out.Index(i)); err != nil {
+				return fmt.Errorf("[%d]: %w", i, err)
+			}
+		}
+	case reflect.Map:
+		if val.Type != xdr.ScValTypeScvMap {
+			return mismatch()
+		}
+		m := reflect.MakeMap(out.Type())
+		for _, e := range scMap(val) {
+			key := reflect.New(out.Type().Key()).Elem()
+			if err := fromScVal(e.Key, key); err != nil {
+				return err
+			}
+			elem := reflect.New(out.Type().Elem()).Elem()
+			if err := fromScVal(e.Val, elem); err != nil {
+				return fmt.Errorf("[%v]: %w", key, err)
+			}
+			m.SetMapIndex(key, elem)
+		}
+		out.Set(m)
+	case reflect.Struct:
+		if val.Type != xdr.ScValTypeScvMap {
+			return mismatch()
+		}
+		m := scMap(val)
+		for i := range out.NumField() {
+			name, ok := fieldName(out.Type().Field(i))
+			if !ok {
+				continue
+			}
+			sym := xdr.ScSymbol(name)
+			e, found := scMapGet(m, xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym})
+			if !found {
+				continue
+			}
+			if err := fromScVal(e.Val, out.Field(i)); err != nil {
+				return fmt.Errorf("%s: %w", name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("%s: %s", ErrorScValUnsupportedType, out.Type())
+	}
+	return nil
+}
+
+// fieldName returns the symbol of a struct field, false if it is skipped
+func fieldName(field reflect.StructField) (string, bool) {
+	if !field.IsExported() || field.Anonymous {
+		return "", false
+	}
+	tag := field.Tag.Get("scval")
+	if tag == "-" {
+		return "", false
+	}
+	if tag != "" {
+		return tag, true
+	}
+	return snakeCase(field.Name), true
+}
+
+func snakeCase(name string) string {
+	var b strings.Builder
+	runes := []rune(name)
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
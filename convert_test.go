@@ -0,0 +1,71 @@
+package soroban_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+type uuid [16]byte
+
+type airdrop struct {
+	ID        uuid
+	Recipient string `scval:"to"`
+	Amount    int64
+	Tags      []string
+	Memo      *string
+	Balances  map[string]uint32
+	Ignored   bool `scval:"-"`
+}
+
+func TestScValConversion(t *testing.T) {
+	soroban.RegisterScValType(
+		func(u uuid) (xdr.ScVal, error) {
+			return soroban.ToScVal(u[:])
+		},
+		func(val xdr.ScVal) (uuid, error) {
+			var b [16]byte
+			err := soroban.FromScVal(val, &b)
+			return uuid(b), err
+		},
+	)
+	in := airdrop{
+		ID:        uuid{1, 2, 3},
+		Recipient: "alice",
+		Amount:    -5,
+		Tags:      []string{"a", "b"},
+		Balances:  map[string]uint32{"x": 1, "y": 2},
+		Ignored:   true,
+	}
+	val, err := soroban.ToScVal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var keys []string
+	for _, e := range **val.Map {
+		keys = append(keys, string(*e.Key.Sym))
+	}
+	if strings.Join(keys, ",") != "amount,balances,id,memo,tags,to" {
+		t.Fatal(keys)
+	}
+
+	var out airdrop
+	if err := soroban.FromScVal(val, &out); err != nil {
+		t.Fatal(err)
+	}
+	in.Ignored = false
+	if out.ID != in.ID || out.Recipient != in.Recipient || out.Amount != in.Amount ||
+		len(out.Tags) != 2 || out.Memo != nil || out.Balances["y"] != 2 || out.Ignored {
+		t.Fatal(out)
+	}
+
+	var wrong struct{ Amount string }
+	if err := soroban.FromScVal(val, &wrong); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValTypeMismatch) {
+		t.Fatal(err)
+	}
+	if _, err := soroban.ToScVal(func() {}); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValUnsupportedType) {
+		t.Fatal(err)
+	}
+}
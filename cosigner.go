@@ -0,0 +1,161 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorCoSignerFailed       = "Co-signer failed"
+	ErrorCoSignerBadSignature = "Co-signer signature is not valid"
+)
+
+// TransactionSigner signs transactions, e.g. a RemoteSigner. Add it to a transaction
+// with CoSigner.
+type TransactionSigner interface {
+	// Sign returns the signature of the transaction hash, envelopeXdr is
+	// the transaction, for signers applying policies
+	Sign(ctx context.Context, hash [32]byte, envelopeXdr string) (xdr.DecoratedSignature, error)
+}
+
+// KeypairSigner is a TransactionSigner signing with a keypair
+type KeypairSigner struct {
+	*keypair.Full
+}
+
+// Sign implements TransactionSigner
+func (s KeypairSigner) Sign(ctx context.Context, hash [32]byte, envelopeXdr string) (xdr.DecoratedSignature, error) {
+	return s.SignDecorated(hash[:])
+}
+
+// RemoteSigner is the client of a co-signing service, a TransactionSigner. The
+// service receives a POST of a JSON object with the hex hash of the
+// transaction, its envelope and the network passphrase, and answers with the
+// decorated signature as base64 XDR:
+//
+//	{"hash": "9f86...", "envelopeXdr": "AAAAAgAAAA...", "networkPassphrase": "Test SDF Network ; September 2015"}
+//	{"signature": "nIe7zQAAAEC..."}
+//
+// Any status but 200 is an error, with the body as message. The signature is
+// verified with PublicKey.
+//
+//	Example:
+//	 tx.CoSigner(soroban.NewRemoteSigner("https://cosigner.internal/sign", cosignerPublicKey, network.TestNetworkPassphrase))
+type RemoteSigner struct {
+	URL string
+	// PublicKey (G...) of the co-signer
+	PublicKey         string
+	NetworkPassphrase string
+	// Header is added to the requests, e.g. Authorization, optional
+	Header http.Header
+	// HTTPClient of the requests, http.DefaultClient if nil
+	HTTPClient *http.Client
+}
+
+var _ TransactionSigner = (*RemoteSigner)(nil)
+
+// NewRemoteSigner returns the RemoteSigner of the service at url
+func NewRemoteSigner(url, publicKey, networkPassphrase string) *RemoteSigner {
+	return &RemoteSigner{URL: url, PublicKey: publicKey, NetworkPassphrase: networkPassphrase}
+}
+
+type remoteSignRequest struct {
+	Hash              string `json:"hash"`
+	EnvelopeXdr       string `json:"envelopeXdr"`
+	NetworkPassphrase string `json:"networkPassphrase"`
+}
+
+type remoteSignResponse struct {
+	Signature string `json:"signature"`
+}
+
+// Sign implements TransactionSigner
+func (s *RemoteSigner) Sign(ctx context.Context, hash [32]byte, envelopeXdr string) (xdr.DecoratedSignature, error) {
+	var sig xdr.DecoratedSignature
+	body, err := json.Marshal(remoteSignRequest{hex.EncodeToString(hash[:]), envelopeXdr, s.NetworkPassphrase})
+	if err != nil {
+		return sig, err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewReader(body))
+	if err != nil {
+		return sig, err
+	}
+	for key, values := range s.Header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Content-Type", "application/json")
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	res, err := client.Do(req)
+	if err != nil {
+		return sig, fmt.Errorf("%s: %w", ErrorCoSignerFailed, err)
+	}
+	defer res.Body.Close()
+	b, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return sig, fmt.Errorf("%s: %w", ErrorCoSignerFailed, err)
+	}
+	if res.StatusCode != http.StatusOK {
+		return sig, fmt.Errorf("%s: %s: %s", ErrorCoSignerFailed, res.Status, strings.TrimSpace(string(b)))
+	}
+	var signed remoteSignResponse
+	if err := json.Unmarshal(b, &signed); err != nil {
+		return sig, fmt.Errorf("%s: %w", ErrorCoSignerFailed, err)
+	}
+	if err := xdr.SafeUnmarshalBase64(signed.Signature, &sig); err != nil {
+		return sig, fmt.Errorf("%s: %w", ErrorCoSignerFailed, err)
+	}
+	pair, err := keypair.ParseAddress(s.PublicKey)
+	if err != nil {
+		return sig, err
+	}
+	if sig.Hint != pair.Hint() || pair.Verify(hash[:], sig.Signature) != nil {
+		return sig, fmt.Errorf("%s: %s", ErrorCoSignerBadSignature, s.PublicKey)
+	}
+	return sig, nil
+}
+
+// CoSigner adds signers asked for their signature when the transaction is
+// sent, after the Signers, e.g. a RemoteSigner
+func (t *Transaction) CoSigner(signers ...TransactionSigner) *Transaction {
+	t.build.coSigners = append(t.build.coSigners, signers...)
+	return t
+}
+
+// addCoSignatures adds the signatures of the CoSigners to tx
+func (t *Transaction) addCoSignatures(ctx context.Context, tx *txnbuild.Transaction) (*txnbuild.Transaction, error) {
+	if len(t.build.coSigners) == 0 {
+		return tx, nil
+	}
+	hash, err := tx.Hash(t.client.NetworkPassphrase())
+	if err != nil {
+		return nil, err
+	}
+	envelopeXdr, err := tx.Base64()
+	if err != nil {
+		return nil, err
+	}
+	var signatures []xdr.DecoratedSignature
+	for _, signer := range t.build.coSigners {
+		sig, err := signer.Sign(ctx, hash, envelopeXdr)
+		if err != nil {
+			return nil, err
+		}
+		if !hasSignature(tx.Signatures(), sig) && !hasSignature(signatures, sig) {
+			signatures = append(signatures, sig)
+		}
+	}
+	return tx.AddSignatureDecorated(signatures...)
+}
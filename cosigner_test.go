@@ -0,0 +1,87 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestRemoteSigner(t *testing.T) {
+	cosigner := keypair.MustRandom()
+	signWith := cosigner
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Hash              string `json:"hash"`
+			EnvelopeXdr       string `json:"envelopeXdr"`
+			NetworkPassphrase string `json:"networkPassphrase"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// the policy only signs bump sequence operations
+		var envelope xdr.TransactionEnvelope
+		xdr.SafeUnmarshalBase64(req.EnvelopeXdr, &envelope)
+		if r.Header.Get("Authorization") != "Bearer token" || envelope.Operations()[0].Body.Type != xdr.OperationTypeBumpSequence {
+			http.Error(w, "policy denied", http.StatusForbidden)
+			return
+		}
+		hash, _ := hex.DecodeString(req.Hash)
+		sig, _ := signWith.SignDecorated(hash)
+		b, _ := xdr.MarshalBase64(sig)
+		fmt.Fprintf(w, `{"signature":%q}`, b)
+	}))
+	defer server.Close()
+
+	var sent xdr.TransactionEnvelope
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			xdr.SafeUnmarshalBase64(envelopeXdr, &sent)
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+	}
+	remote := soroban.NewRemoteSigner(server.URL, cosigner.Address(), network.TestNetworkPassphrase)
+	remote.Header = http.Header{"Authorization": {"Bearer token"}}
+	pair := keypair.MustRandom()
+	send := func(op txnbuild.Operation) error {
+		_, err := soroban.NewTransctionBuilder().
+			Client(fake).
+			SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+			Signer(pair).
+			CoSigner(remote).
+			Operation(op).
+			Send(context.Background())
+		return err
+	}
+
+	if err := send(&txnbuild.BumpSequence{BumpTo: 10}); err != nil {
+		t.Fatal(err)
+	}
+	if len(sent.Signatures()) != 2 || sent.Signatures()[1].Hint != cosigner.Hint() {
+		t.Fatal(sent.Signatures())
+	}
+
+	err := send(&txnbuild.SetOptions{})
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorCoSignerFailed) || !strings.Contains(err.Error(), "policy denied") {
+		t.Fatal(err)
+	}
+
+	signWith = keypair.MustRandom()
+	err = send(&txnbuild.BumpSequence{BumpTo: 10})
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorCoSignerBadSignature) {
+		t.Fatal(err)
+	}
+	if fake.Count(soroban.SendTransaction) != 1 {
+		t.Fatal(fake.Calls)
+	}
+}
@@ -0,0 +1,248 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorContractDataNotFound       = "Contract data not found"
+	ErrorTemporaryDataNotRestorable = "Temporary contract data can not be restored, once expired it is deleted"
+)
+
+// Durabilities of contract data. Persistent entries are archived when their
+// ttl runs out and can be restored, temporary entries are deleted.
+const (
+	Persistent = xdr.ContractDataDurabilityPersistent
+	Temporary  = xdr.ContractDataDurabilityTemporary
+)
+
+// ContractData is an entry of contract data
+type ContractData struct {
+	Key                xdr.ScVal
+	Val                xdr.ScVal
+	Durability         xdr.ContractDataDurability
+	LiveUntilLedgerSeq int64
+	LatestLedger       int64
+}
+
+// Alive returns if the entry ttl has not run out
+func (d *ContractData) Alive() bool {
+	return d.LiveUntilLedgerSeq >= d.LatestLedger
+}
+
+// GetDataKey returns the LedgerKey of the contract data key with durability
+//
+//	Requires address or sourceAccount and salt
+func (c *Contract) GetDataKey(key xdr.ScVal, durability xdr.ContractDataDurability) (xdr.LedgerKey, error) {
+	address, err := c.GetAddress()
+	if err != nil {
+		return xdr.LedgerKey{}, err
+	}
+	return contractDataKey(*address, key, durability), nil
+}
+
+// GetData returns the contract data key with durability, or
+// ErrorContractDataNotFound if there is none. A temporary entry is not found
+// once its ttl runs out, a persistent one is returned not Alive until restored.
+//
+//	Requires client, and address or sourceAccount and salt
+func (c *Contract) GetData(ctx context.Context, key xdr.ScVal, durability xdr.ContractDataDurability) (*ContractData, error) {
+	if c.client == nil {
+		return nil, errors.New(ErrorRequiredClient)
+	}
+	ledgerKey, err := c.GetDataKey(key, durability)
+	if err != nil {
+		return nil, err
+	}
+	ledgerKeyXdr, err := ledgerKey.MarshalBinaryBase64()
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.GetLedgerEntries(ctx, ledgerKeyXdr)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Entries) == 0 {
+		return nil, errors.New(ErrorContractDataNotFound)
+	}
+	var entry xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(res.Entries[0].Xdr, &entry); err != nil {
+		return nil, err
+	}
+	return &ContractData{
+		Key:                key,
+		Val:                entry.ContractData.Val,
+		Durability:         durability,
+		LiveUntilLedgerSeq: res.Entries[0].LiveUntilLedgerSeq,
+		LatestLedger:       res.LatestLedger,
+	}, nil
+}
+
+// InstanceStorage returns the instance storage of the contract, the data
+// living with its instance, or ErrorContractInstanceNotFound
+//
+//	Requires client, and sourceAccount and salt, or address
+func (c *Contract) InstanceStorage(ctx context.Context) (xdr.ScMap, error) {
+	if c.client == nil {
+		return nil, errors.New(ErrorRequiredClient)
+	}
+	instanceKey, err := c.GetFootprint()
+	if err != nil {
+		return nil, err
+	}
+	instanceKeyXdr, err := instanceKey.MarshalBinaryBase64()
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.client.GetLedgerEntries(ctx, instanceKeyXdr)
+	if err != nil {
+		return nil, err
+	}
+	if len(res.Entries) == 0 {
+		return nil, errors.New(ErrorContractInstanceNotFound)
+	}
+	var entry xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(res.Entries[0].Xdr, &entry); err != nil {
+		return nil, err
+	}
+	if entry.ContractData == nil || entry.ContractData.Val.Instance == nil {
+		return nil, errors.New(ErrorContractInstanceNotFound)
+	}
+	if entry.ContractData.Val.Instance.Storage == nil {
+		return xdr.ScMap{}, nil
+	}
+	return *entry.ContractData.Val.Instance.Storage, nil
+}
+
+// RestoreData sends the transaction to restore the contract data keys with
+// durability. Temporary data can not be restored, once expired it is gone,
+// ErrorTemporaryDataNotRestorable is returned.
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
+//
+//	Requires client, sourceAccount, keyPair, and address or salt
+func (c *Contract) RestoreData(ctx context.Context, durability xdr.ContractDataDurability, keys ...xdr.ScVal) (*PendingTransaction, error) {
+	if durability == Temporary {
+		return nil, errors.New(ErrorTemporaryDataNotRestorable)
+	}
+	ledgerKeys, err := c.dataKeys(keys, durability)
+	if err != nil {
+		return nil, err
+	}
+	transaction := NewTransctionBuilder().
+		Client(c.client).
+		SourceAccount(c.source).
+		Signer(c.kp).
+		Operation(&txnbuild.RestoreFootprint{SourceAccount: c.source.GetAccountID()}).
+		Timeout(c.timeout).
+		Pin(c.pin).
+		SorobanData(xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{
+				Footprint: xdr.LedgerFootprint{ReadWrite: ledgerKeys},
+			},
+		})
+	if _, err := transaction.Simulate(ctx); err != nil {
+		return nil, err
+	}
+	return transaction.Send(ctx)
+}
+
+// ExtendData sends the transaction to extend the ttl of the contract data
+// keys with durability to extendTo ledgers from the current one.
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
+//
+//	Requires client, sourceAccount, keyPair, and address or salt
+func (c *Contract) ExtendData(ctx context.Context, durability xdr.ContractDataDurability, extendTo uint32, keys ...xdr.ScVal) (*PendingTransaction, error) {
+	ledgerKeys, err := c.dataKeys(keys, durability)
+	if err != nil {
+		return nil, err
+	}
+	return c.extend(ctx, extendTo, ledgerKeys)
+}
+
+// Extend sends the transaction to extend the ttl of the contract code and
+// instance to extendTo ledgers from the current one.
+// It will NOT wait for it to be accepted, use the returned PendingTransaction
+// to wait for the final result
+//
+//	Requires wasm or wasmHash, client, sourceAccount, keyPair, and address or salt
+func (c *Contract) Extend(ctx context.Context, extendTo uint32) (*PendingTransaction, error) {
+	if err := c.requireSubmitter(); err != nil {
+		return nil, err
+	}
+	codeKey, err := c.GetCodeKey()
+	if err != nil {
+		return nil, err
+	}
+	instanceKey, err := c.GetFootprint()
+	if err != nil {
+		return nil, err
+	}
+	return c.extend(ctx, extendTo, []xdr.LedgerKey{codeKey, instanceKey})
+}
+
+func (c *Contract) extend(ctx context.Context, extendTo uint32, keys []xdr.LedgerKey) (*PendingTransaction, error) {
+	transaction := NewTransctionBuilder().
+		Client(c.client).
+		SourceAccount(c.source).
+		Signer(c.kp).
+		Operation(&txnbuild.ExtendFootprintTtl{ExtendTo: extendTo, SourceAccount: c.source.GetAccountID()}).
+		Timeout(c.timeout).
+		Pin(c.pin).
+		SorobanData(xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{
+				Footprint: xdr.LedgerFootprint{ReadOnly: keys},
+			},
+		})
+	if _, err := transaction.Simulate(ctx); err != nil {
+		return nil, err
+	}
+	return transaction.Send(ctx)
+}
+
+// dataKeys checks the requirements of a footprint operation on contract data
+// and returns the ledger keys of keys
+func (c *Contract) dataKeys(keys []xdr.ScVal, durability xdr.ContractDataDurability) ([]xdr.LedgerKey, error) {
+	if err := c.requireSubmitter(); err != nil {
+		return nil, err
+	}
+	ledgerKeys := make([]xdr.LedgerKey, 0, len(keys))
+	for _, key := range keys {
+		ledgerKey, err := c.GetDataKey(key, durability)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key.String(), err)
+		}
+		ledgerKeys = append(ledgerKeys, ledgerKey)
+	}
+	return ledgerKeys, nil
+}
+
+// requireSubmitter checks the contract can submit transactions
+func (c *Contract) requireSubmitter() error {
+	switch {
+	case c.client == nil:
+		return errors.New(ErrorRequiredClient)
+	case c.source == nil:
+		return errors.New(ErrorRequiredSource)
+	case c.kp == nil:
+		return errors.New(ErrorRequiredKeyPair)
+	}
+	return nil
+}
+
+func contractDataKey(contract xdr.ScAddress, key xdr.ScVal, durability xdr.ContractDataDurability) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.LedgerKeyContractData{
+			Contract:   contract,
+			Key:        key,
+			Durability: durability,
+		},
+	}
+}
@@ -0,0 +1,69 @@
+package soroban_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestContractData(t *testing.T) {
+	address := contractAddress(3)
+	key := sym("counter")
+	count := xdr.Uint32(5)
+	var footprint xdr.LedgerFootprint
+	contract, fake := fakeContract()
+	contract.Address(address)
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		var ledgerKey xdr.LedgerKey
+		xdr.SafeUnmarshalBase64(keys[0], &ledgerKey)
+		res := &soroban.GetLedgerEntriesResult{LatestLedger: 50}
+		if ledgerKey.ContractData.Durability != soroban.Temporary {
+			return res, nil
+		}
+		entry, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+			Type: xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{
+				Contract:   address,
+				Key:        key,
+				Durability: soroban.Temporary,
+				Val:        xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &count},
+			},
+		})
+		res.Entries = append(res.Entries, soroban.GetLedgerEntry{Xdr: entry, LiveUntilLedgerSeq: 60})
+		return res, nil
+	}
+	simulate := fake.SimulateTransactionFunc
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		footprint = simulatedFootprint(envelopeXdr)
+		return simulate(envelopeXdr)
+	}
+
+	data, err := contract.GetData(context.Background(), key, soroban.Temporary)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *data.Val.U32 != 5 || !data.Alive() {
+		t.Fatal(data)
+	}
+	if _, err := contract.GetData(context.Background(), key, soroban.Persistent); err == nil || err.Error() != soroban.ErrorContractDataNotFound {
+		t.Fatal(err)
+	}
+
+	if _, err := contract.RestoreData(context.Background(), soroban.Temporary, key); err == nil || err.Error() != soroban.ErrorTemporaryDataNotRestorable {
+		t.Fatal(err)
+	}
+	if _, err := contract.ExtendData(context.Background(), soroban.Temporary, 1000, key); err != nil {
+		t.Fatal(err)
+	}
+	if len(footprint.ReadOnly) != 1 || footprint.ReadOnly[0].ContractData.Durability != soroban.Temporary {
+		t.Fatal(footprint)
+	}
+	if _, err := contract.RestoreData(context.Background(), soroban.Persistent, key); err != nil {
+		t.Fatal(err)
+	}
+	if len(footprint.ReadWrite) != 1 || footprint.ReadWrite[0].ContractData.Durability != soroban.Persistent {
+		t.Fatal(footprint)
+	}
+}
@@ -0,0 +1,134 @@
+package soroban
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorSimulationFailed = "Simulation failed"
+)
+
+// DiagnosticEvent is a decoded diagnostic event, emitted by the host while
+// executing or simulating a transaction, e.g. fn_call, fn_return or error
+type DiagnosticEvent struct {
+	InSuccessfulContractCall bool
+	// Type is contract, system or diagnostic
+	Type string
+	// ContractID (C...) emitting the event, empty for host events
+	ContractID string
+	Topics     []xdr.ScVal
+	Data       xdr.ScVal
+}
+
+// NewDiagnosticEvent decodes event
+func NewDiagnosticEvent(event xdr.DiagnosticEvent) DiagnosticEvent {
+	decoded := DiagnosticEvent{InSuccessfulContractCall: event.InSuccessfulContractCall}
+	switch event.Event.Type {
+	case xdr.ContractEventTypeContract:
+		decoded.Type = "contract"
+	case xdr.ContractEventTypeSystem:
+		decoded.Type = "system"
+	default:
+		decoded.Type = "diagnostic"
+	}
+	if event.Event.ContractId != nil {
+		decoded.ContractID = strkey.MustEncode(strkey.VersionByteContract, event.Event.ContractId[:])
+	}
+	if body, ok := event.Event.Body.GetV0(); ok {
+		decoded.Topics = body.Topics
+		decoded.Data = body.Data
+	}
+	return decoded
+}
+
+// DecodeDiagnosticEvents decodes the diagnostic events, as base64 XDR, of
+// sendTransaction, getTransaction or simulateTransaction
+func DecodeDiagnosticEvents(eventsXdr []string) ([]DiagnosticEvent, error) {
+	events := make([]DiagnosticEvent, 0, len(eventsXdr))
+	for _, eventXdr := range eventsXdr {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(eventXdr, &event); err != nil {
+			return nil, err
+		}
+		events = append(events, NewDiagnosticEvent(event))
+	}
+	return events, nil
+}
+
+// IsError returns if the event reports an error, its first topic is the
+// symbol error
+func (e DiagnosticEvent) IsError() bool {
+	return len(e.Topics) > 0 && e.Topics[0].Type == xdr.ScValTypeScvSymbol && *e.Topics[0].Sym == "error"
+}
+
+// String formats the event in one line
+//
+//	diagnostic CA...: [error, Error(Contract, #3)] "balance is not sufficient"
+func (e DiagnosticEvent) String() string {
+	return e.Format(nil)
+}
+
+// Format is like String, replacing the contract and the addresses of the
+// topics and data with their labels.
+//
+//	contract token: [transfer, alice, bob] 100
+func (e DiagnosticEvent) Format(labels *AddressBook) string {
+	topics := make([]string, len(e.Topics))
+	for i, topic := range e.Topics {
+		topics[i] = labels.FormatScVal(topic)
+	}
+	source := e.Type
+	if e.ContractID != "" {
+		source += " " + labels.Label(e.ContractID)
+	}
+	return fmt.Sprintf("%s: [%s] %s", source, strings.Join(topics, ", "), labels.FormatScVal(e.Data))
+}
+
+// FormatDiagnosticEvents returns the error events, one per line, or every
+// event if none is an error
+func FormatDiagnosticEvents(events []DiagnosticEvent) string {
+	var errorEvents []DiagnosticEvent
+	for _, event := range events {
+		if event.IsError() {
+			errorEvents = append(errorEvents, event)
+		}
+	}
+	if len(errorEvents) > 0 {
+		events = errorEvents
+	}
+	lines := make([]string, len(events))
+	for i, event := range events {
+		lines[i] = event.String()
+	}
+	return strings.Join(lines, "\n")
+}
+
+// SimulationError is returned by Transaction.Simulate when the simulation
+// fails, with the decoded diagnostic events
+type SimulationError struct {
+	Message string
+	Events  []DiagnosticEvent
+	Result  *SimulateTransactionResult
+}
+
+func (e *SimulationError) Error() string {
+	return withEvents(fmt.Sprintf("%s: %s", ErrorSimulationFailed, e.Message), e.Events)
+}
+
+// simulationError returns the *SimulationError of res
+func simulationError(res *SimulateTransactionResult) error {
+	events, _ := DecodeDiagnosticEvents(res.Events)
+	return &SimulationError{Message: res.Error, Events: events, Result: res}
+}
+
+// withEvents appends the summary of the events to the message of an error
+func withEvents(msg string, events []DiagnosticEvent) string {
+	if summary := FormatDiagnosticEvents(events); summary != "" {
+		msg += "\n" + summary
+	}
+	return msg
+}
@@ -0,0 +1,68 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func diagnosticEvent(t *testing.T, contract *xdr.ContractId, data xdr.ScVal, topics ...xdr.ScVal) string {
+	t.Helper()
+	b, err := xdr.MarshalBase64(xdr.DiagnosticEvent{Event: xdr.ContractEvent{
+		ContractId: contract,
+		Type:       xdr.ContractEventTypeDiagnostic,
+		Body:       xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{Topics: topics, Data: data}},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestSimulationError(t *testing.T) {
+	contract := xdr.ContractId{1}
+	message := xdr.ScString("balance is not sufficient")
+	events := []string{
+		diagnosticEvent(t, nil, sym("transfer"), sym("fn_call")),
+		diagnosticEvent(t, &contract, xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &message}, sym("error")),
+	}
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(string) (*soroban.SimulateTransactionResult, error) {
+			return &soroban.SimulateTransactionResult{Error: "HostError: Error(Contract, #3)", Events: events}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Simulate(context.Background())
+	var simulationErr *soroban.SimulationError
+	if !errors.As(err, &simulationErr) || len(simulationErr.Events) != 2 || simulationErr.Events[1].ContractID[0] != 'C' ||
+		!simulationErr.Events[1].IsError() || simulationErr.Events[0].IsError() {
+		t.Fatal(err)
+	}
+	lines := strings.Split(err.Error(), "\n")
+	if len(lines) != 2 || lines[0] != soroban.ErrorSimulationFailed+": HostError: Error(Contract, #3)" ||
+		lines[1] != `diagnostic `+simulationErr.Events[1].ContractID+`: [error] "balance is not sufficient"` {
+		t.Fatal(err)
+	}
+}
+
+func TestRejectedDiagnosticEvents(t *testing.T) {
+	sent := &soroban.SendTransactionResult{Status: "ERROR", DiagnosticEventsXdr: []string{diagnosticEvent(t, nil, sym("x"), sym("fn_call"))}}
+	var rejected *soroban.TransactionRejectedError
+	if !errors.As(sent.Err(), &rejected) || len(rejected.DiagnosticEvents) != 1 ||
+		!strings.HasSuffix(sent.Err().Error(), "\ndiagnostic: [fn_call] x") {
+		t.Fatal(sent.Err())
+	}
+}
@@ -0,0 +1,90 @@
+package soroban
+
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// DefaultFeeEscalationFactor multiplies the inclusion fee of the resubmissions
+// when FeeEscalation Factor is not set
+const DefaultFeeEscalationFactor = 2
+
+// FeeEscalation resubmits the transactions rejected with TRY_AGAIN_LATER, a
+// full transaction queue, increasing their inclusion fee by Factor on every
+// attempt up to MaxInclusionFee. The MaxFee ceiling is still checked, and
+// every resubmission spends the Client RetryBudget. Only transactions built
+// by Send are resubmitted, not loaded envelopes.
+//
+//	Example:
+//	 client.FeeEscalation = &soroban.FeeEscalation{MaxInclusionFee: 10000, Backoff: time.Second}
+type FeeEscalation struct {
+	// MaxInclusionFee is the cap of the escalated inclusion fee
+	MaxInclusionFee int64
+	// Factor of the increase, DefaultFeeEscalationFactor if 0
+	Factor float64
+	// Backoff before every resubmission, optional
+	Backoff time.Duration
+}
+
+// FeeEscalation sets the escalation of the inclusion fee when the
+// transaction is rejected with TRY_AGAIN_LATER, it overrides the Client
+// FeeEscalation
+func (t *Transaction) FeeEscalation(e FeeEscalation) *Transaction {
+	t.build.feeEscalation = &e
+	return t
+}
+
+func (t *Transaction) feeEscalation() *FeeEscalation {
+	if t.build.feeEscalation != nil {
+		return t.build.feeEscalation
+	}
+	return config(t.client).FeeEscalation
+}
+
+// nextInclusionFee returns the escalated inclusion fee, false once the cap is
+// reached
+func (t *Transaction) nextInclusionFee() (int64, bool) {
+	e := t.feeEscalation()
+	if e == nil {
+		return 0, false
+	}
+	factor := e.Factor
+	if factor <= 1 {
+		factor = DefaultFeeEscalationFactor
+	}
+	fee := min(int64(float64(t.build.inclusionFee)*factor), e.MaxInclusionFee)
+	return fee, fee > t.build.inclusionFee
+}
+
+// escalate resubmits the transaction with the same sequence number and the
+// escalated inclusion fee, it returns false if it is not escalated
+func (t *Transaction) escalate(ctx context.Context, sequence int64, fund bool) (*PendingTransaction, bool, error) {
+	fee, ok := t.nextInclusionFee()
+	if !ok || t.envelope != nil {
+		return nil, false, nil
+	}
+	inclusionFee, pinned := t.build.inclusionFee, t.build.sequence
+	t.build.inclusionFee, t.build.sequence = fee, sequence
+	defer func() { t.build.sequence = pinned }()
+	tx, err := t.newTx()
+	if err == nil {
+		err = t.checkMaxFee(tx)
+	}
+	if err != nil {
+		t.build.inclusionFee = inclusionFee
+		return nil, false, nil
+	}
+	if err := spendRetry(t.client); err != nil {
+		t.build.inclusionFee = inclusionFee
+		return nil, true, err
+	}
+	debug(ctx, t.client, "fee escalation", slog.Int64("inclusionFee", fee), slog.Int64("sequence", sequence))
+	select {
+	case <-ctx.Done():
+		return nil, true, ctx.Err()
+	case <-time.After(t.feeEscalation().Backoff):
+	}
+	pending, err := t.send(ctx, fund)
+	return pending, true, err
+}
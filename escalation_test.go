@@ -0,0 +1,85 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestFeeEscalation(t *testing.T) {
+	var fees, sequences []int64
+	rejections := 2
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+			fees = append(fees, int64(envelope.Fee()))
+			sequences = append(sequences, envelope.SeqNum())
+			if len(fees) <= rejections {
+				return &soroban.SendTransactionResult{Hash: "abc", Status: "TRY_AGAIN_LATER"}, nil
+			}
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}
+	send := func(e soroban.FeeEscalation) *soroban.PendingTransaction {
+		t.Helper()
+		pending, err := soroban.NewTransctionBuilder().
+			Client(fake).
+			SourceAccount(account).
+			Signer(pair).
+			Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+			FeeEscalation(e).
+			Send(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return pending
+	}
+
+	pending := send(soroban.FeeEscalation{MaxInclusionFee: 300})
+	if pending.Sent().Status != "PENDING" || len(fees) != 3 || fees[0] != 100 || fees[1] != 200 || fees[2] != 300 ||
+		sequences[0] != 2 || sequences[1] != 2 || sequences[2] != 2 || account.Sequence != 2 {
+		t.Fatal(fees, sequences, account.Sequence)
+	}
+
+	// the cap is reached
+	fees, sequences, rejections = nil, nil, 5
+	pending = send(soroban.FeeEscalation{MaxInclusionFee: 250, Factor: 1.5})
+	if pending.Sent().Status != "TRY_AGAIN_LATER" || len(fees) != 4 || fees[1] != 150 || fees[2] != 225 || fees[3] != 250 {
+		t.Fatal(fees)
+	}
+}
+
+func TestFeeEscalationRetryBudget(t *testing.T) {
+	options := &soroban.Client{}
+	options.RetryBudget = soroban.NewRetryBudget(1, 1)
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		Options:    options,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "TRY_AGAIN_LATER"}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	// the budget allows one resubmission
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		FeeEscalation(soroban.FeeEscalation{MaxInclusionFee: 1000}).
+		Send(context.Background())
+	if !errors.Is(err, soroban.ErrRetryBudgetExhausted) || fake.Count(soroban.SendTransaction) != 2 {
+		t.Fatal(err, fake.Calls)
+	}
+}
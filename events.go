@@ -0,0 +1,147 @@
+package soroban
+
+import (
+	"context"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Event types of EventFilter
+const (
+	EventTypeContract   = "contract"
+	EventTypeSystem     = "system"
+	EventTypeDiagnostic = "diagnostic"
+)
+
+// Topic segments of an EventFilter matching any topic: TopicWildcard matches
+// exactly one segment, TopicWildcardRest zero or more trailing segments.
+const (
+	TopicWildcard     = "*"
+	TopicWildcardRest = "**"
+)
+
+// GetEventsRequest as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getEvents
+// StartLedger is omitted with a Pagination.Cursor, the cursor of a previous
+// result continues after its last event.
+type GetEventsRequest struct {
+	StartLedger int64             `json:"startLedger,omitempty"`
+	EndLedger   int64             `json:"endLedger,omitempty"`
+	Filters     []EventFilter     `json:"filters"`
+	Pagination  *EventsPagination `json:"pagination,omitempty"`
+}
+
+// EventFilter matches the events of Type, any if empty, emitted by any of
+// ContractIds (strkey C... addresses) whose topics match any of Topics
+type EventFilter struct {
+	Type        string        `json:"type,omitempty"`
+	ContractIds []string      `json:"contractIds,omitempty"`
+	Topics      []TopicFilter `json:"topics,omitempty"`
+}
+
+// TopicFilter is a list of segments, base64 XDR ScVal or a wildcard, see
+// NewTopicFilter
+type TopicFilter []string
+
+// EventsPagination sets the cursor to continue from and the maximum number
+// of events returned
+type EventsPagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+// GetEventsResult as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getEvents
+type GetEventsResult struct {
+	Events                []Event `json:"events"`
+	Cursor                string  `json:"cursor"`
+	LatestLedger          int64   `json:"latestLedger"`
+	LatestLedgerCloseTime string  `json:"latestLedgerCloseTime"`
+	OldestLedger          int64   `json:"oldestLedger"`
+	OldestLedgerCloseTime string  `json:"oldestLedgerCloseTime"`
+}
+
+// Event is an event of GetEventsResult, Topic and Value are base64 XDR ScVal
+type Event struct {
+	Type                     string   `json:"type"`
+	Ledger                   int64    `json:"ledger"`
+	LedgerClosedAt           string   `json:"ledgerClosedAt"`
+	ContractId               string   `json:"contractId"`
+	Id                       string   `json:"id"`
+	OperationIndex           int64    `json:"opIndex"`
+	TransactionIndex         int64    `json:"txIndex"`
+	TxHash                   string   `json:"txHash"`
+	InSuccessfulContractCall bool     `json:"inSuccessfulContractCall"`
+	Topic                    []string `json:"topic"`
+	Value                    string   `json:"value"`
+}
+
+// NewTopicFilter returns the TopicFilter matching the topic segments, a nil
+// segment matches any value
+func NewTopicFilter(segments ...*xdr.ScVal) (TopicFilter, error) {
+	filter := make(TopicFilter, len(segments))
+	for i, segment := range segments {
+		if segment == nil {
+			filter[i] = TopicWildcard
+			continue
+		}
+		s, err := xdr.MarshalBase64(*segment)
+		if err != nil {
+			return nil, err
+		}
+		filter[i] = s
+	}
+	return filter, nil
+}
+
+// Topics decodes the topic of the event
+func (e Event) Topics() ([]xdr.ScVal, error) {
+	topics := make([]xdr.ScVal, len(e.Topic))
+	for i, t := range e.Topic {
+		if err := xdr.SafeUnmarshalBase64(t, &topics[i]); err != nil {
+			return nil, err
+		}
+	}
+	return topics, nil
+}
+
+// ScVal decodes the value of the event
+func (e Event) ScVal() (xdr.ScVal, error) {
+	var value xdr.ScVal
+	err := xdr.SafeUnmarshalBase64(e.Value, &value)
+	return value, err
+}
+
+// Format decodes the event and formats it in one line like
+// DiagnosticEvent.Format, using labels for its addresses.
+func (e Event) Format(labels *AddressBook) (string, error) {
+	topics, err := e.Topics()
+	if err != nil {
+		return "", err
+	}
+	value, err := e.ScVal()
+	if err != nil {
+		return "", err
+	}
+	return DiagnosticEvent{
+		InSuccessfulContractCall: e.InSuccessfulContractCall,
+		Type:                     e.Type,
+		ContractID:               e.ContractId,
+		Topics:                   topics,
+		Data:                     value,
+	}.Format(labels), nil
+}
+
+// GetEvents returns the events matching the filters of req, from its
+// StartLedger or pagination cursor.
+// Returns an error if unmarshal, http call, etc; fail.
+// Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getEvents
+func (c Client) GetEvents(ctx context.Context, req GetEventsRequest) (*GetEventsResult, error) {
+	if req.Filters == nil {
+		req.Filters = []EventFilter{}
+	}
+	var getEventsResult GetEventsResult
+	err := c.CallResult(ctx, GetEvents, &getEventsResult, req)
+	if err != nil {
+		return nil, err
+	}
+	return &getEventsResult, nil
+}
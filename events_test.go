@@ -0,0 +1,73 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetEvents(t *testing.T) {
+	value, _ := soroban.ToScVal(uint32(7))
+	topic, _ := soroban.ToScVal("transfer")
+	valueXdr, _ := xdr.MarshalBase64(value)
+	topicXdr, _ := xdr.MarshalBase64(topic)
+
+	var params json.RawMessage
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != soroban.GetEvents {
+			t.Error(req.Method)
+		}
+		params = req.Params
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":20,"cursor":"0000000042-0000000001","events":[`+
+			`{"type":"contract","ledger":12,"contractId":"CA","id":"0000000042-0000000001","topic":["%s"],"value":"%s","inSuccessfulContractCall":true}]}}`,
+			req.ID, topicXdr, valueXdr)
+	}))
+	defer server.Close()
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+
+	filter, err := soroban.NewTopicFilter(&topic, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := client.GetEvents(context.Background(), soroban.GetEventsRequest{
+		StartLedger: 10,
+		Filters: []soroban.EventFilter{{
+			Type:        soroban.EventTypeContract,
+			ContractIds: []string{"CA"},
+			Topics:      []soroban.TopicFilter{filter},
+		}},
+		Pagination: &soroban.EventsPagination{Limit: 5},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := fmt.Sprintf(`{"startLedger":10,"filters":[{"type":"contract","contractIds":["CA"],"topics":[["%s","*"]]}],"pagination":{"limit":5}}`, topicXdr)
+	if string(params) != want {
+		t.Fatal(string(params))
+	}
+	if res.Cursor != "0000000042-0000000001" || res.LatestLedger != 20 || len(res.Events) != 1 {
+		t.Fatal(res)
+	}
+	topics, err := res.Events[0].Topics()
+	if err != nil || len(topics) != 1 || !soroban.ScValEqual(topics[0], topic) {
+		t.Fatal(topics, err)
+	}
+	got, err := res.Events[0].ScVal()
+	if err != nil || !soroban.ScValEqual(got, value) {
+		t.Fatal(got, err)
+	}
+}
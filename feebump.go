@@ -0,0 +1,105 @@
+package soroban
+
+import (
+	"context"
+	"encoding/hex"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionDetails is the decoded result of getTransaction.
+// For fee bump transactions the inner (user) transaction is exposed
+// separately from the outer (sponsor) one.
+type TransactionDetails struct {
+	Hash     string
+	FeeBump  bool
+	Envelope xdr.TransactionEnvelope
+	Result   xdr.TransactionResult
+
+	// Only set if FeeBump is true
+	InnerHash     string
+	InnerEnvelope *xdr.TransactionEnvelope
+	InnerResult   *xdr.InnerTransactionResult
+}
+
+// Successful returns if the transaction succeeded, for fee bumps it
+// checks the inner transaction.
+func (d TransactionDetails) Successful() bool {
+	if d.FeeBump {
+		return d.InnerResult != nil && d.InnerResult.Result.Code == xdr.TransactionResultCodeTxSuccess
+	}
+	return d.Result.Successful()
+}
+
+// OperationResults returns the operation results, for fee bumps the ones
+// of the inner transaction.
+func (d TransactionDetails) OperationResults() ([]xdr.OperationResult, bool) {
+	return OperationResults(d.Result)
+}
+
+// Envelope decodes the EnvelopeXdr of the transaction.
+func (r GetTransactionResult) Envelope() (*xdr.TransactionEnvelope, error) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(r.EnvelopeXdr, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	return &envelope, nil
+}
+
+// Decode decodes envelope and result of the transaction. The passphrase is
+// needed to compute the hashes of the envelopes.
+func (r GetTransactionResult) Decode(passphrase string) (*TransactionDetails, error) {
+	envelope, err := r.Envelope()
+	if err != nil {
+		return nil, err
+	}
+	result, err := r.Result()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := network.HashTransactionInEnvelope(*envelope, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	details := &TransactionDetails{
+		Hash:     hex.EncodeToString(hash[:]),
+		FeeBump:  envelope.IsFeeBump(),
+		Envelope: *envelope,
+		Result:   *result,
+	}
+	if !details.FeeBump {
+		return details, nil
+	}
+	details.InnerEnvelope = &xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1:   envelope.FeeBump.Tx.InnerTx.V1,
+	}
+	innerHash, err := network.HashTransactionInEnvelope(*details.InnerEnvelope, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	details.InnerHash = hex.EncodeToString(innerHash[:])
+	if pair, ok := result.Result.GetInnerResultPair(); ok {
+		details.InnerResult = &pair.Result
+	}
+	return details, nil
+}
+
+// GetTransactionDetails calls getTransaction and decodes its envelope and result.
+// If the transaction is not found, the returned details are nil.
+func (c Client) GetTransactionDetails(ctx context.Context, hash string) (*GetTransactionResult, *TransactionDetails, error) {
+	res, err := c.GetTransaction(ctx, hash)
+	if err != nil {
+		return nil, nil, err
+	}
+	if res.Status == "NOT_FOUND" {
+		return res, nil, nil
+	}
+	details, err := res.Decode(c.PassPhrase)
+	if err != nil {
+		return res, nil, err
+	}
+	return res, details, nil
+}
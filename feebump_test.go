@@ -0,0 +1,70 @@
+package soroban_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestDecodeFeeBump(t *testing.T) {
+	inner, _ := signedTransaction(t)
+	sponsor := keypair.MustRandom()
+	bump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      inner,
+		FeeAccount: sponsor.Address(),
+		BaseFee:    txnbuild.MinBaseFee * 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bump, _ = bump.Sign(network.TestNetworkPassphrase, sponsor)
+	envelopeXdr, _ := bump.Base64()
+	opResults := []xdr.OperationResult{{Code: xdr.OperationResultCodeOpInner, Tr: &xdr.OperationResultTr{
+		Type:          xdr.OperationTypeBumpSequence,
+		BumpSeqResult: &xdr.BumpSequenceResult{Code: xdr.BumpSequenceResultCodeBumpSequenceSuccess},
+	}}}
+	resultXdr, _ := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 200,
+		Result: xdr.TransactionResultResult{
+			Code: xdr.TransactionResultCodeTxFeeBumpInnerSuccess,
+			InnerResultPair: &xdr.InnerTransactionResultPair{Result: xdr.InnerTransactionResult{
+				FeeCharged: 100,
+				Result:     xdr.InnerTransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &opResults},
+			}},
+		},
+	})
+
+	details, err := soroban.GetTransactionResult{EnvelopeXdr: envelopeXdr, ResultXdr: resultXdr}.Decode(network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, _ := bump.HashHex(network.TestNetworkPassphrase)
+	innerHash, _ := inner.HashHex(network.TestNetworkPassphrase)
+	if !details.FeeBump || details.Hash != hash || details.InnerHash != innerHash || details.InnerEnvelope.SeqNum() != inner.SequenceNumber() {
+		t.Fatal(details)
+	}
+	if !details.Successful() || details.InnerResult.FeeCharged != 100 {
+		t.Fatal(details.InnerResult)
+	}
+	if results, ok := details.OperationResults(); !ok || len(results) != 1 || results[0].Tr.Type != xdr.OperationTypeBumpSequence {
+		t.Fatal(results)
+	}
+
+	// a transaction that is not a fee bump
+	envelopeXdr, _ = inner.Base64()
+	resultXdr, _ = xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxFailed, Results: &opResults},
+	})
+	details, err = soroban.GetTransactionResult{EnvelopeXdr: envelopeXdr, ResultXdr: resultXdr}.Decode(network.TestNetworkPassphrase)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if details.FeeBump || details.Hash != innerHash || details.InnerEnvelope != nil || details.Successful() {
+		t.Fatal(details)
+	}
+}
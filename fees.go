@@ -0,0 +1,110 @@
+package soroban
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorFeesNoEnvelope = "Transaction result has no envelope"
+)
+
+// TransactionFees are the fees of an applied transaction, what was actually
+// charged instead of the maximum of the envelope
+type TransactionFees struct {
+	// MaxFee is the fee of the envelope, the outer one of a fee bump
+	MaxFee int64
+	// ResourceFee is the resource fee declared in the soroban data
+	ResourceFee int64
+	// NonRefundableResourceFee, RefundableResourceFee and RentFee are the
+	// resource fees charged, RentFee is part of RefundableResourceFee
+	NonRefundableResourceFee int64
+	RefundableResourceFee    int64
+	RentFee                  int64
+	// Refunded is the part of ResourceFee not charged
+	Refunded int64
+	// Charged is the net fee paid, after the refund
+	Charged int64
+}
+
+// MetaResourceFees returns the resource fees charged to the transaction, it
+// works with V3 and V4 (protocol 23) meta. A classic transaction has none.
+func MetaResourceFees(meta xdr.TransactionMeta) (nonRefundable, refundable, rent int64, err error) {
+	var ext xdr.SorobanTransactionMetaExt
+	switch meta.V {
+	case 0, 1, 2:
+		return 0, 0, 0, nil
+	case 3:
+		if meta.V3.SorobanMeta == nil {
+			return 0, 0, 0, nil
+		}
+		ext = meta.V3.SorobanMeta.Ext
+	case 4:
+		if meta.V4.SorobanMeta == nil {
+			return 0, 0, 0, nil
+		}
+		ext = meta.V4.SorobanMeta.Ext
+	default:
+		return 0, 0, 0, fmt.Errorf("%s: %d", ErrorMetaUnsupportedVersion, meta.V)
+	}
+	if ext.V1 == nil {
+		return 0, 0, 0, nil
+	}
+	return int64(ext.V1.TotalNonRefundableResourceFeeCharged), int64(ext.V1.TotalRefundableResourceFeeCharged), int64(ext.V1.RentFeeCharged), nil
+}
+
+// Fees decodes the envelope, result and meta of the transaction and returns
+// its fees, for reconciling the actual charges.
+//
+//	Example:
+//	 res, err := pending.Wait(ctx)
+//	 fees, err := res.Fees()
+//	 fmt.Println(fees.Charged, fees.Refunded)
+func (r GetTransactionResult) Fees() (*TransactionFees, error) {
+	if r.EnvelopeXdr == "" {
+		return nil, errors.New(ErrorFeesNoEnvelope)
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(r.EnvelopeXdr, &envelope); err != nil {
+		return nil, err
+	}
+	result, err := r.Result()
+	if err != nil {
+		return nil, err
+	}
+	fees := &TransactionFees{MaxFee: int64(envelope.Fee()), Charged: int64(result.FeeCharged)}
+	if envelope.IsFeeBump() {
+		fees.MaxFee = envelope.FeeBumpFee()
+	}
+	if data := envelopeSorobanData(envelope); data != nil {
+		fees.ResourceFee = int64(data.ResourceFee)
+	}
+	if r.ResultMetaXdr != "" {
+		meta, err := r.Meta()
+		if err != nil {
+			return nil, err
+		}
+		fees.NonRefundableResourceFee, fees.RefundableResourceFee, fees.RentFee, err = MetaResourceFees(*meta)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if fees.ResourceFee > 0 {
+		fees.Refunded = max(fees.ResourceFee-fees.NonRefundableResourceFee-fees.RefundableResourceFee, 0)
+	}
+	return fees, nil
+}
+
+// envelopeSorobanData returns the soroban data of the envelope, the inner one
+// of a fee bump, nil for a classic transaction
+func envelopeSorobanData(envelope xdr.TransactionEnvelope) *xdr.SorobanTransactionData {
+	switch envelope.Type {
+	case xdr.EnvelopeTypeEnvelopeTypeTx:
+		return envelope.V1.Tx.Ext.SorobanData
+	case xdr.EnvelopeTypeEnvelopeTypeTxFeeBump:
+		return envelope.FeeBump.Tx.InnerTx.V1.Tx.Ext.SorobanData
+	}
+	return nil
+}
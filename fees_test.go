@@ -0,0 +1,52 @@
+package soroban_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestFees(t *testing.T) {
+	source := xdr.MustMuxedAddress("GBRPYHIL2CI3FNQ4BXLFMNDLFJUNPU2HY3ZMFSHONUCEOASW7QC7OX2H")
+	envelope, _ := xdr.MarshalBase64(xdr.TransactionEnvelope{
+		Type: xdr.EnvelopeTypeEnvelopeTypeTx,
+		V1: &xdr.TransactionV1Envelope{Tx: xdr.Transaction{
+			SourceAccount: source,
+			Fee:           10100,
+			Ext:           xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{ResourceFee: 10000}},
+		}},
+	})
+	result, _ := xdr.MarshalBase64(xdr.TransactionResult{
+		FeeCharged: 6100,
+		Result:     xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{}},
+	})
+	meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{
+		SorobanMeta: &xdr.SorobanTransactionMetaV2{Ext: xdr.SorobanTransactionMetaExt{V: 1, V1: &xdr.SorobanTransactionMetaExtV1{
+			TotalNonRefundableResourceFeeCharged: 4000,
+			TotalRefundableResourceFeeCharged:    2000,
+			RentFeeCharged:                       500,
+		}}},
+	}})
+	res := soroban.GetTransactionResult{Status: "SUCCESS", EnvelopeXdr: envelope, ResultXdr: result, ResultMetaXdr: meta}
+	fees, err := res.Fees()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := soroban.TransactionFees{
+		MaxFee:                   10100,
+		ResourceFee:              10000,
+		NonRefundableResourceFee: 4000,
+		RefundableResourceFee:    2000,
+		RentFee:                  500,
+		Refunded:                 4000,
+		Charged:                  6100,
+	}
+	if *fees != expected {
+		t.Fatal(fees)
+	}
+
+	if _, err := (soroban.GetTransactionResult{ResultXdr: result}).Fees(); err == nil || err.Error() != soroban.ErrorFeesNoEnvelope {
+		t.Fatal(err)
+	}
+}
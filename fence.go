@@ -0,0 +1,137 @@
+package soroban
+
+import (
+	"context"
+	"slices"
+	"sync"
+
+	"github.com/stellar/go/xdr"
+)
+
+// fences are the in-process locks of ledger keys, shared by every Contract
+// with the same address
+var fences = struct {
+	mu    sync.Mutex
+	locks map[string]*fenceLock
+}{locks: make(map[string]*fenceLock)}
+
+type fenceLock struct {
+	ch   chan struct{}
+	refs int
+}
+
+// fenceRelease releases the fences held by an invocation
+type fenceRelease func()
+
+// Fence serializes, within the process, the invocations of the contract
+// instance: each Send waits until the transaction of the previous one is
+// finalized, or rejected, before simulating. It prevents footprint conflicts
+// of goroutines mutating one contract at the same time, at the cost of one
+// invocation per ledger. Contracts with the same address share the fence.
+// Use invokeBuilder.Fence to only serialize invocations touching the same
+// data keys.
+//
+//	Requires address or sourceAccount and salt
+func (c *Contract) Fence() *Contract {
+	c.fenced = true
+	return c
+}
+
+// Fence serializes the invocation with the others of the process fenced on
+// any of the contract data keys with durability, see Contract.Fence.
+func (c *invokeBuilder) Fence(durability xdr.ContractDataDurability, keys ...xdr.ScVal) *invokeBuilder {
+	for _, key := range keys {
+		ledgerKey, err := c.contract.GetDataKey(key, durability)
+		if err != nil {
+			if c.build.err == nil {
+				c.build.err = err
+			}
+			return c
+		}
+		c.build.fenceKeys = append(c.build.fenceKeys, ledgerKey)
+	}
+	return c
+}
+
+// fence acquires the fences of the invocation, in order, waiting for the
+// invocations holding them or until ctx is done. It returns a nil release if
+// the invocation is not fenced.
+func (c *invokeBuilder) fence(ctx context.Context) (fenceRelease, error) {
+	ledgerKeys := c.build.fenceKeys
+	if c.contract.fenced {
+		instanceKey, err := c.contract.GetFootprint()
+		if err != nil {
+			return nil, err
+		}
+		ledgerKeys = append([]xdr.LedgerKey{instanceKey}, ledgerKeys...)
+	}
+	keys := make([]string, 0, len(ledgerKeys))
+	for _, ledgerKey := range ledgerKeys {
+		key, err := ledgerKey.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	// a fixed order, and no duplicates, so two invocations can not deadlock
+	slices.Sort(keys)
+	keys = slices.Compact(keys)
+	if len(keys) == 0 {
+		return nil, nil
+	}
+
+	locks := make([]*fenceLock, len(keys))
+	fences.mu.Lock()
+	for i, key := range keys {
+		lock, ok := fences.locks[key]
+		if !ok {
+			lock = &fenceLock{ch: make(chan struct{}, 1)}
+			fences.locks[key] = lock
+		}
+		lock.refs++
+		locks[i] = lock
+	}
+	fences.mu.Unlock()
+
+	held := 0
+	release := func() {
+		for i := held - 1; i >= 0; i-- {
+			<-locks[i].ch
+		}
+		fences.mu.Lock()
+		defer fences.mu.Unlock()
+		for i, key := range keys {
+			if locks[i].refs--; locks[i].refs == 0 {
+				delete(fences.locks, key)
+			}
+		}
+	}
+	for _, lock := range locks {
+		select {
+		case lock.ch <- struct{}{}:
+			held++
+		case <-ctx.Done():
+			release()
+			return nil, ctx.Err()
+		}
+	}
+	return release, nil
+}
+
+// after releases the fences once the transaction sent is finalized, right
+// away if it failed to be sent. A nil release holds no fences.
+func (release fenceRelease) after(res *PendingTransaction, err error) (*PendingTransaction, error) {
+	if release == nil {
+		return res, err
+	}
+	// a result with an error is submitted, the error is an *AuditError
+	if res == nil || res.Sent().Status == "ERROR" || res.Sent().Status == "TRY_AGAIN_LATER" {
+		release()
+		return res, err
+	}
+	go func() {
+		<-res.Done()
+		release()
+	}()
+	return res, err
+}
@@ -0,0 +1,99 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestFence(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	finalize := make(chan struct{})
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		<-finalize
+		return &soroban.GetTransactionResult{Status: "SUCCESS"}, nil
+	}
+	contract.Fence()
+
+	first, err := contract.Invoke().Function("hello").Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := contract.Invoke().Function("hello").Send(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("not fenced", err)
+	}
+
+	// keys are fenced independently of the instance
+	a := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: new(xdr.Uint32)}
+	if _, err := contract.Invoke().Function("set").Fence(soroban.Persistent, a).Send(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("not fenced on the instance", err)
+	}
+
+	close(finalize)
+	if _, err := first.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel = context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if _, err := contract.Invoke().Function("hello").Send(ctx); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestBatchFence(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	finalize := make(chan struct{})
+	defer close(finalize)
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		<-finalize
+		return &soroban.GetTransactionResult{Status: "SUCCESS"}, nil
+	}
+	contract.Fence()
+	if _, err := contract.Invoke().Function("hello").Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := contract.Batch("airdrop", []xdr.ScVal{u32(1)}).Send(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("not fenced", err)
+	}
+	if n := fake.Count(soroban.SimulateTransaction); n != 1 {
+		t.Fatal(fake.Calls)
+	}
+}
+
+func TestFenceKeys(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	finalize := make(chan struct{})
+	defer close(finalize)
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		<-finalize
+		return &soroban.GetTransactionResult{Status: "SUCCESS"}, nil
+	}
+	a, _ := soroban.ToScVal("a")
+	b, _ := soroban.ToScVal("b")
+
+	if _, err := contract.Invoke().Function("set").Fence(soroban.Persistent, a).Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := contract.Invoke().Function("set").Fence(soroban.Persistent, b).Send(ctx); err != nil {
+		t.Fatal("other key fenced", err)
+	}
+	if _, err := contract.Invoke().Function("set").Fence(soroban.Temporary, a).Send(ctx); err != nil {
+		t.Fatal("other durability fenced", err)
+	}
+	if _, err := contract.Invoke().Function("set").Fence(soroban.Persistent, b, a).Send(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal("not fenced", err)
+	}
+}
@@ -0,0 +1,69 @@
+package soroban_test
+
+import (
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// fakeContract returns a contract signed by a random source account. Its
+// fake client simulates empty transaction data, accepts every transaction
+// and finds it successful, set the other funcs as the test needs.
+func fakeContract() (*soroban.Contract, *sorobantest.FakeClient) {
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+			return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	contract := soroban.NewContract().
+		Client(fake).
+		SourceAccount(&soroban.Account{AccountId: pair.Address(), Sequence: 1}).
+		KeyPair(pair)
+	return contract, fake
+}
+
+// livenessContract is a fakeContract, deployed from a wasm hash and salt,
+// whose code and instance live until ledger 1000. Every transaction sent
+// closes a ledger.
+func livenessContract(latestLedger *int64) (*soroban.Contract, *sorobantest.FakeClient) {
+	contract, fake := fakeContract()
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		return &soroban.GetLedgerEntriesResult{
+			LatestLedger: *latestLedger,
+			Entries:      []soroban.GetLedgerEntry{{LiveUntilLedgerSeq: 1000}, {LiveUntilLedgerSeq: 1000}},
+		}, nil
+	}
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		*latestLedger++
+		return &soroban.SendTransactionResult{Status: "PENDING", LatestLedger: *latestLedger}, nil
+	}
+	return contract.WasmHash([32]byte{1}).Salt("liveness"), fake
+}
+
+func countCalls(fake *sorobantest.FakeClient, method string) int {
+	return fake.Count(method)
+}
+
+// contractAddress returns the address of the contract with id
+func contractAddress(id byte) xdr.ScAddress {
+	contractId := xdr.ContractId{id}
+	return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}
+}
+
+// simulatedFootprint decodes the footprint of a simulated envelope
+func simulatedFootprint(envelopeXdr string) xdr.LedgerFootprint {
+	var envelope xdr.TransactionEnvelope
+	xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+	return envelope.V1.Tx.Ext.SorobanData.Resources.Footprint
+}
@@ -0,0 +1,116 @@
+package soroban
+
+import (
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorFootprintKeyNotWritable = "Footprint key written by the invocation is not read-write"
+	ErrorFootprintKeyMissing     = "Footprint key written by the invocation is missing"
+	ErrorFootprintKeyDuplicated  = "Footprint key is duplicated"
+)
+
+// WrittenKeys returns the ledger keys the simulated invocation creates,
+// updates or deletes
+func (r SimulateTransactionResult) WrittenKeys() ([]xdr.LedgerKey, error) {
+	keys := make([]xdr.LedgerKey, 0, len(r.StateChanges))
+	for _, change := range r.StateChanges {
+		if r.decoded {
+			keys = append(keys, change.LedgerKey)
+			continue
+		}
+		var key xdr.LedgerKey
+		if err := xdr.SafeUnmarshalBase64(change.Key, &key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// NarrowFootprint returns the footprint with the read-write keys not in
+// written moved to read-only, and duplicated keys dropped. Read-only
+// entries are cheaper, reducing the fees of read heavy invocations.
+func NarrowFootprint(footprint xdr.LedgerFootprint, written []xdr.LedgerKey) (xdr.LedgerFootprint, error) {
+	writtenSet, err := ledgerKeySet(written)
+	if err != nil {
+		return footprint, err
+	}
+	seen := map[string]bool{}
+	narrowed := xdr.LedgerFootprint{}
+	for _, key := range footprint.ReadWrite {
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return footprint, err
+		}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		if writtenSet[k] {
+			narrowed.ReadWrite = append(narrowed.ReadWrite, key)
+		} else {
+			narrowed.ReadOnly = append(narrowed.ReadOnly, key)
+		}
+	}
+	for _, key := range footprint.ReadOnly {
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return footprint, err
+		}
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		narrowed.ReadOnly = append(narrowed.ReadOnly, key)
+	}
+	return narrowed, nil
+}
+
+// ValidateFootprint checks every written key is read-write in the footprint,
+// and no key is repeated, so a narrowed footprint does not fail on submit.
+func ValidateFootprint(footprint xdr.LedgerFootprint, written []xdr.LedgerKey) error {
+	seen := map[string]bool{}
+	readWrite := map[string]bool{}
+	for i, keys := range [][]xdr.LedgerKey{footprint.ReadWrite, footprint.ReadOnly} {
+		for _, key := range keys {
+			k, err := key.MarshalBinaryBase64()
+			if err != nil {
+				return err
+			}
+			if seen[k] {
+				return fmt.Errorf("%s: %s", ErrorFootprintKeyDuplicated, k)
+			}
+			seen[k] = true
+			readWrite[k] = i == 0
+		}
+	}
+	for _, key := range written {
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return err
+		}
+		isReadWrite, ok := readWrite[k]
+		if !ok {
+			return fmt.Errorf("%s: %s", ErrorFootprintKeyMissing, k)
+		}
+		if !isReadWrite {
+			return fmt.Errorf("%s: %s", ErrorFootprintKeyNotWritable, k)
+		}
+	}
+	return nil
+}
+
+func ledgerKeySet(keys []xdr.LedgerKey) (map[string]bool, error) {
+	set := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		set[k] = true
+	}
+	return set, nil
+}
@@ -0,0 +1,50 @@
+package soroban_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func codeKey(b byte) xdr.LedgerKey {
+	return xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{b}},
+	}
+}
+
+func TestNarrowFootprint(t *testing.T) {
+	footprint := xdr.LedgerFootprint{
+		ReadWrite: []xdr.LedgerKey{codeKey(1), codeKey(2), codeKey(2)},
+		ReadOnly:  []xdr.LedgerKey{codeKey(3), codeKey(1)},
+	}
+	written := []xdr.LedgerKey{codeKey(1)}
+	narrowed, err := soroban.NarrowFootprint(footprint, written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(narrowed.ReadWrite) != 1 || narrowed.ReadWrite[0].ContractCode.Hash[0] != 1 {
+		t.Fatal(narrowed.ReadWrite)
+	}
+	if len(narrowed.ReadOnly) != 2 || narrowed.ReadOnly[0].ContractCode.Hash[0] != 2 || narrowed.ReadOnly[1].ContractCode.Hash[0] != 3 {
+		t.Fatal(narrowed.ReadOnly)
+	}
+	if err := soroban.ValidateFootprint(narrowed, written); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestValidateFootprint(t *testing.T) {
+	written := []xdr.LedgerKey{codeKey(1)}
+	cases := []xdr.LedgerFootprint{
+		{ReadOnly: []xdr.LedgerKey{codeKey(1)}},
+		{ReadWrite: []xdr.LedgerKey{codeKey(2)}},
+		{ReadWrite: []xdr.LedgerKey{codeKey(1)}, ReadOnly: []xdr.LedgerKey{codeKey(1)}},
+	}
+	for _, footprint := range cases {
+		if err := soroban.ValidateFootprint(footprint, written); err == nil {
+			t.Fatal(footprint)
+		}
+	}
+}
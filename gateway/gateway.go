@@ -0,0 +1,156 @@
+// Package gateway serves the functions of a deployed contract as HTTP JSON
+// endpoints, from its spec, a REST facade for prototypes and internal tools.
+//
+//	GET  /            the interface of the contract, see spec.Interface
+//	POST /{function}  the arguments by name as a JSON object, see
+//	                  spec.ValueFromJSON, returns {"result": ...}
+//
+// The functions are simulated, only the ones enabled with Submit are sent as
+// transactions, signed with the key pair of the contract, unless the request
+// has the query ?simulate=true.
+//
+//	Example:
+//	 gw := gateway.New(contract, s).Submit("transfer")
+//	 http.Handle("/token/", http.StripPrefix("/token", gw))
+package gateway
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxBodySize of the requests
+const MaxBodySize = 1 << 20
+
+// Gateway is the http.Handler of the functions of a contract
+type Gateway struct {
+	contract *soroban.Contract
+	spec     *spec.Spec
+	submit   map[string]bool
+	mux      *http.ServeMux
+}
+
+// Response is the body of the responses of the functions
+type Response struct {
+	// Result is the JSON of the value returned, null for a function without
+	// outputs
+	Result json.RawMessage `json:"result"`
+	// MinResourceFee and LatestLedger of the simulation, of simulated calls
+	MinResourceFee int64 `json:"minResourceFee,omitempty"`
+	LatestLedger   int64 `json:"latestLedger,omitempty"`
+	// Hash and Status of the transaction, of submitted calls
+	Hash   string `json:"hash,omitempty"`
+	Status string `json:"status,omitempty"`
+}
+
+// New returns the gateway of the functions of contract described by s, e.g.
+// from contract.Spec()
+func New(contract *soroban.Contract, s *spec.Spec) *Gateway {
+	g := &Gateway{contract: contract, spec: s, submit: map[string]bool{}, mux: http.NewServeMux()}
+	g.mux.HandleFunc("GET /{$}", g.serveInterface)
+	g.mux.HandleFunc("POST /{function}", g.serveFunction)
+	return g
+}
+
+// Submit enables sending the functions as transactions, instead of only
+// simulating them
+func (g *Gateway) Submit(functions ...string) *Gateway {
+	for _, f := range functions {
+		g.submit[f] = true
+	}
+	return g
+}
+
+// ServeHTTP implements http.Handler
+func (g *Gateway) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	g.mux.ServeHTTP(w, r)
+}
+
+func (g *Gateway) serveInterface(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, g.spec.Interface())
+}
+
+func (g *Gateway) serveFunction(w http.ResponseWriter, r *http.Request) {
+	name := r.PathValue("function")
+	f, err := g.spec.Function(name)
+	if err != nil {
+		writeError(w, http.StatusNotFound, err)
+		return
+	}
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, MaxBodySize))
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	args, err := g.spec.ArgsFromJSON(name, body)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, err)
+		return
+	}
+	invoke := g.contract.Invoke().Function(name).WithArgs(args...)
+	simulate, _ := strconv.ParseBool(r.URL.Query().Get("simulate"))
+
+	var res Response
+	var val *xdr.ScVal
+	if g.submit[name] && !simulate {
+		pending, err := invoke.Send(r.Context())
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		final, err := pending.Wait(r.Context())
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		res.Hash, res.Status = pending.Hash(), final.Status
+		if final.Status != "SUCCESS" {
+			writeJSON(w, http.StatusUnprocessableEntity, res)
+			return
+		}
+		val, err = final.ReturnValue()
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+	} else {
+		sim, err := invoke.Simulate(r.Context())
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+		res.MinResourceFee, res.LatestLedger = sim.MinResourceFee, sim.LatestLedger
+		val, err = sim.ReturnValue()
+		if err != nil {
+			writeError(w, http.StatusBadGateway, err)
+			return
+		}
+	}
+
+	res.Result = json.RawMessage("null")
+	if len(f.Outputs) > 0 {
+		res.Result, err = g.spec.ValueToJSON(f.Outputs[0], *val)
+		if err != nil {
+			writeError(w, http.StatusBadGateway, fmt.Errorf("result: %w", err))
+			return
+		}
+	}
+	writeJSON(w, http.StatusOK, res)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,105 @@
+package gateway_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/gateway"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+func function(name string, input xdr.ScSpecType, output xdr.ScSpecType) xdr.ScSpecEntry {
+	return xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+		Name:    xdr.ScSymbol(name),
+		Inputs:  []xdr.ScSpecFunctionInputV0{{Name: "to", Type: xdr.ScSpecTypeDef{Type: input}}},
+		Outputs: []xdr.ScSpecTypeDef{{Type: output}},
+	}}
+}
+
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func TestGateway(t *testing.T) {
+	s := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		function("hello", xdr.ScSpecTypeScSpecTypeSymbol, xdr.ScSpecTypeScSpecTypeSymbol),
+		function("count", xdr.ScSpecTypeScSpecTypeU32, xdr.ScSpecTypeScSpecTypeU64),
+	}}
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+			ret, _ := xdr.MarshalBase64(symbol("World"))
+			var res soroban.SimulateTransactionResult
+			err := json.Unmarshal([]byte(`{"transactionData":"`+data+`","minResourceFee":"100","latestLedger":7,"results":[{"xdr":"`+ret+`"}]}`), &res)
+			return &res, err
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "h", Status: "PENDING", LatestLedger: 7}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			n := xdr.Uint64(42)
+			ret := xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &n}
+			meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &ret}}})
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, ResultMetaXdr: meta}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	contract := soroban.NewContract().
+		Client(fake).
+		WasmHash([32]byte{1}).
+		SourceAccount(&soroban.Account{AccountId: pair.Address(), Sequence: 1}).
+		KeyPair(pair)
+	server := httptest.NewServer(gateway.New(contract, s).Submit("count"))
+	defer server.Close()
+
+	post := func(path, body string) (int, map[string]any) {
+		resp, err := http.Post(server.URL+path, "application/json", strings.NewReader(body))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+		var res map[string]any
+		if err := json.NewDecoder(resp.Body).Decode(&res); err != nil {
+			t.Fatal(err)
+		}
+		return resp.StatusCode, res
+	}
+
+	resp, err := http.Get(server.URL + "/")
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Fatal(resp, err)
+	}
+	resp.Body.Close()
+
+	if code, res := post("/hello", `{"to":"Dev"}`); code != http.StatusOK || res["result"] != "World" || res["latestLedger"] != 7.0 {
+		t.Fatal(code, res)
+	}
+	if fake.Count(soroban.SendTransaction) != 0 {
+		t.Fatal("simulated function sent")
+	}
+	if code, res := post("/count", `{"to":1}`); code != http.StatusOK || res["result"] != "42" || res["hash"] != "h" || res["status"] != "SUCCESS" {
+		t.Fatal(code, res)
+	}
+	if fake.Count(soroban.SendTransaction) != 1 {
+		t.Fatal("submitted function not sent")
+	}
+	if code, res := post("/count?simulate=true", `{"to":1}`); code != http.StatusOK || fake.Count(soroban.SendTransaction) != 1 {
+		t.Fatal(code, res)
+	}
+	if code, _ := post("/missing", `{}`); code != http.StatusNotFound {
+		t.Fatal(code)
+	}
+	if code, res := post("/hello", `{"to":1}`); code != http.StatusBadRequest || res["error"] == nil {
+		t.Fatal(code, res)
+	}
+}
@@ -0,0 +1,133 @@
+package soroban
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorEnvelopeTooLarge    = "Envelope exceeds the maximum size"
+	ErrorTooManySignatures   = "Envelope has more than 20 signatures"
+	ErrorDuplicateSignature  = "Duplicate signature"
+	ErrorExtraneousSignature = "Signature not valid for any expected signer on the network"
+	ErrorInvalidEnvelope     = "Invalid envelope"
+)
+
+// MaxEnvelopeSize is the maximum size in bytes of a transaction envelope
+// accepted by CheckEnvelope, the tx_max_size_bytes network setting
+var MaxEnvelopeSize = 132096
+
+// maxSignatures of an envelope, fixed by the protocol
+const maxSignatures = 20
+
+// CheckEnvelope checks the hygiene of a signed envelope before it is
+// submitted: its size, that no signature is repeated and that every
+// signature is valid, for the transaction hash on the network of
+// networkPassphrase, for one of the expected signers. The source accounts of
+// the transaction and its operations are always expected, expected adds the
+// other signers (G... or X...). For fee bumps both the inner and the outer
+// signatures are checked. It catches the mistakes of multi-process signing
+// flows, like a signature for another network or for a previous version of
+// the transaction.
+func CheckEnvelope(envelopeXdr string, networkPassphrase string, expected ...string) error {
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+		return fmt.Errorf("%s: %w", ErrorInvalidEnvelope, err)
+	}
+	b, err := envelope.MarshalBinary()
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrorInvalidEnvelope, err)
+	}
+	var errs []error
+	if len(b) > MaxEnvelopeSize {
+		errs = append(errs, fmt.Errorf("%s: %d > %d bytes", ErrorEnvelopeTooLarge, len(b), MaxEnvelopeSize))
+	}
+
+	signers := map[string]int32{}
+	for _, signer := range expected {
+		signers[signer] = 1
+	}
+	for _, op := range envelope.Operations() {
+		if op.SourceAccount != nil {
+			signers[op.SourceAccount.ToAccountId().Address()] = 1
+		}
+	}
+	if envelope.IsFeeBump() {
+		inner := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: envelope.FeeBump.Tx.InnerTx.V1}
+		innerSigners := map[string]int32{inner.SourceAccount().ToAccountId().Address(): 1}
+		for signer := range signers {
+			innerSigners[signer] = 1
+		}
+		hash, err := network.HashTransactionInEnvelope(inner, networkPassphrase)
+		if err != nil {
+			return fmt.Errorf("%s: %w", ErrorInvalidEnvelope, err)
+		}
+		errs = append(errs, checkSignatures("inner ", hash, inner.Signatures(), innerSigners)...)
+		signers[envelope.FeeBumpAccount().ToAccountId().Address()] = 1
+	} else {
+		signers[envelope.SourceAccount().ToAccountId().Address()] = 1
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, networkPassphrase)
+	if err != nil {
+		return fmt.Errorf("%s: %w", ErrorInvalidEnvelope, err)
+	}
+	signatures := envelope.Signatures()
+	if envelope.IsFeeBump() {
+		signatures = envelope.FeeBumpSignatures()
+	}
+	errs = append(errs, checkSignatures("", hash, signatures, signers)...)
+	return errors.Join(errs...)
+}
+
+// checkSignatures returns the problems of signatures of hash, prefix
+// qualifies the signatures in the messages
+func checkSignatures(prefix string, hash [32]byte, signatures []xdr.DecoratedSignature, signers map[string]int32) []error {
+	var errs []error
+	if len(signatures) > maxSignatures {
+		errs = append(errs, fmt.Errorf("%s: %s%d", ErrorTooManySignatures, prefix, len(signatures)))
+	}
+	for i, sig := range signatures {
+		for _, previous := range signatures[:i] {
+			if previous.Hint == sig.Hint && bytes.Equal(previous.Signature, sig.Signature) {
+				errs = append(errs, fmt.Errorf("%s: %ssignature %d", ErrorDuplicateSignature, prefix, i))
+				break
+			}
+		}
+		if _, ok := matchSignature(hash, sig, signers); !ok {
+			errs = append(errs, fmt.Errorf("%s: %ssignature %d", ErrorExtraneousSignature, prefix, i))
+		}
+	}
+	return errs
+}
+
+// ExpectSigners checks the envelope with CheckEnvelope before Send, with
+// signers expected besides the Signers and the source accounts, e.g. the
+// ones whose signatures were added elsewhere with LoadEnvelope
+func (t *Transaction) ExpectSigners(signers ...string) *Transaction {
+	t.build.checkEnvelope = true
+	t.build.expectedSigners = append(t.build.expectedSigners, signers...)
+	return t
+}
+
+// checkEnvelope runs CheckEnvelope on tx if enabled by ExpectSigners or the
+// Client CheckEnvelopes
+func (t *Transaction) checkEnvelope(tx *txnbuild.Transaction) error {
+	if !t.build.checkEnvelope && !config(t.client).CheckEnvelopes {
+		return nil
+	}
+	envelopeXdr, err := tx.Base64()
+	if err != nil {
+		return err
+	}
+	expected := slices.Clone(t.build.expectedSigners)
+	for _, signer := range t.build.signers {
+		expected = append(expected, signer.Address())
+	}
+	return CheckEnvelope(envelopeXdr, t.client.NetworkPassphrase(), expected...)
+}
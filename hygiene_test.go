@@ -0,0 +1,110 @@
+package soroban_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func hygieneTx(t *testing.T, source *keypair.Full) *txnbuild.Transaction {
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount:        &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1},
+		IncrementSequenceNum: true,
+		Operations:           []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		BaseFee:              txnbuild.MinBaseFee,
+		Preconditions:        txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tx
+}
+
+func TestCheckEnvelope(t *testing.T) {
+	source, alice := keypair.MustRandom(), keypair.MustRandom()
+	check := func(tx *txnbuild.Transaction, expected ...string) error {
+		envelope, err := tx.Base64()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return soroban.CheckEnvelope(envelope, network.TestNetworkPassphrase, expected...)
+	}
+
+	signed, _ := hygieneTx(t, source).Sign(network.TestNetworkPassphrase, source)
+	if err := check(signed); err != nil {
+		t.Fatal(err)
+	}
+
+	withAlice, _ := signed.Sign(network.TestNetworkPassphrase, alice)
+	if err := check(withAlice); err == nil || !strings.Contains(err.Error(), soroban.ErrorExtraneousSignature) {
+		t.Fatal(err)
+	}
+	if err := check(withAlice, alice.Address()); err != nil {
+		t.Fatal(err)
+	}
+
+	duplicated, _ := signed.Sign(network.TestNetworkPassphrase, source)
+	if err := check(duplicated); err == nil || !strings.Contains(err.Error(), soroban.ErrorDuplicateSignature) {
+		t.Fatal(err)
+	}
+
+	// signed for another network
+	public, _ := hygieneTx(t, source).Sign(network.PublicNetworkPassphrase, source)
+	if err := check(public); err == nil || !strings.Contains(err.Error(), soroban.ErrorExtraneousSignature) {
+		t.Fatal(err)
+	}
+
+	feeSource := keypair.MustRandom()
+	bump, err := txnbuild.NewFeeBumpTransaction(txnbuild.FeeBumpTransactionParams{
+		Inner:      signed,
+		FeeAccount: feeSource.Address(),
+		BaseFee:    txnbuild.MinBaseFee * 2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	bump, _ = bump.Sign(network.TestNetworkPassphrase, feeSource)
+	bumpXdr, _ := bump.Base64()
+	if err := soroban.CheckEnvelope(bumpXdr, network.TestNetworkPassphrase); err != nil {
+		t.Fatal(err)
+	}
+
+	defer func(size int) { soroban.MaxEnvelopeSize = size }(soroban.MaxEnvelopeSize)
+	soroban.MaxEnvelopeSize = 10
+	if err := check(signed); err == nil || !strings.Contains(err.Error(), soroban.ErrorEnvelopeTooLarge) {
+		t.Fatal(err)
+	}
+}
+
+func TestSendExpectSigners(t *testing.T) {
+	fake := &sorobantest.FakeClient{Passphrase: network.TestNetworkPassphrase}
+	source, alice := keypair.MustRandom(), keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}
+
+	// alice signed on another network
+	other := soroban.NewTransctionBuilder().
+		Client(&sorobantest.FakeClient{Passphrase: network.PublicNetworkPassphrase}).
+		SourceAccount(account).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+	envelope, err := other.SignPartial(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tx := soroban.NewTransctionBuilder().Client(fake).Signer(source).ExpectSigners(alice.Address())
+	if err := tx.LoadEnvelope(envelope); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Send(context.Background()); err == nil || !strings.Contains(err.Error(), soroban.ErrorExtraneousSignature) {
+		t.Fatal(err)
+	}
+	if fake.Count(soroban.SendTransaction) != 0 {
+		t.Fatal(fake.Calls)
+	}
+}
@@ -0,0 +1,140 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"sync"
+)
+
+// WasmInstall is the install of a unique wasm by InstallWasms
+type WasmInstall struct {
+	WasmHash [32]byte
+	// Contracts are the indexes of the contracts sharing the wasm
+	Contracts []int
+	// Live is true when the code was live, nothing was sent
+	Live    bool
+	Pending *PendingTransaction
+	Err     error
+}
+
+// InstallWasms installs the wasm of the contracts, each unique binary
+// exactly once. The code keys are read in one call and the live ones are not
+// uploaded again, neither read when the WasmCache of the contract has them.
+// The installs are sent in order, since they may share the source account,
+// and waited in parallel. The client, source account and key pair of the
+// first contract with the wasm are used. The installs are returned in the
+// order of the contracts; the error is the first of them, if any.
+//
+//	Example:
+//	 installs, err := soroban.InstallWasms(ctx, token, pool, otherPool)
+//	 for _, c := range []*soroban.Contract{token, pool, otherPool} {
+//		pending, err := c.Deploy(ctx)
+//	 }
+func InstallWasms(ctx context.Context, contracts ...*Contract) ([]WasmInstall, error) {
+	var installs []WasmInstall
+	owners := map[[32]byte]int{}
+	for i, c := range contracts {
+		if c.client == nil {
+			return nil, errors.New(ErrorRequiredClient)
+		}
+		j, ok := owners[c.wasmHash]
+		if !ok {
+			j = len(installs)
+			owners[c.wasmHash] = j
+			installs = append(installs, WasmInstall{WasmHash: c.wasmHash})
+		}
+		installs[j].Contracts = append(installs[j].Contracts, i)
+	}
+	if len(installs) == 0 {
+		return nil, nil
+	}
+	for i := range installs {
+		live, err := contracts[installs[i].Contracts[0]].cachedCodeAlive(ctx)
+		if err != nil {
+			return nil, err
+		}
+		installs[i].Live = live
+	}
+	if err := checkCodeLive(ctx, contracts, installs); err != nil {
+		return nil, err
+	}
+	var wg sync.WaitGroup
+	for i := range installs {
+		install := &installs[i]
+		if install.Live {
+			continue
+		}
+		c := installer(contracts, install.Contracts)
+		if c == nil {
+			install.Err = errors.New(ErrorRequiredWasm)
+			continue
+		}
+		install.Pending, install.Err = c.Install(ctx)
+		if install.Err != nil {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, install.Err = install.Pending.Wait(ctx)
+		}()
+	}
+	wg.Wait()
+	for _, install := range installs {
+		if install.Err != nil {
+			return installs, install.Err
+		}
+	}
+	return installs, nil
+}
+
+// checkCodeLive reads the code keys of the installs not live yet in one
+// call, marking and caching the live ones
+func checkCodeLive(ctx context.Context, contracts []*Contract, installs []WasmInstall) error {
+	var keys []string
+	indexes := map[string]int{}
+	for i, install := range installs {
+		if install.Live {
+			continue
+		}
+		key, err := contracts[install.Contracts[0]].GetCodeKey()
+		if err != nil {
+			return err
+		}
+		keyXdr, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return err
+		}
+		keys = append(keys, keyXdr)
+		indexes[keyXdr] = i
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+	res, err := contracts[installs[0].Contracts[0]].client.GetLedgerEntries(ctx, keys...)
+	if err != nil {
+		return err
+	}
+	for _, entry := range res.Entries {
+		i, ok := indexes[entry.Key]
+		if !ok || entry.LiveUntilLedgerSeq < res.LatestLedger {
+			continue
+		}
+		installs[i].Live = true
+		c := contracts[installs[i].Contracts[0]]
+		if err := c.cacheCode(ctx, entry.LiveUntilLedgerSeq, res.LatestLedger); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// installer returns the first of the contracts with the wasm set
+func installer(contracts []*Contract, indexes []int) *Contract {
+	for _, i := range indexes {
+		if len(contracts[i].wasm) > 0 {
+			return contracts[i]
+		}
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package soroban_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"sync"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestInstallWasms(t *testing.T) {
+	live := sha256.Sum256([]byte("live"))
+	liveKey := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: live}}
+	liveKeyXdr, _ := liveKey.MarshalBinaryBase64()
+	var mu sync.Mutex
+	var uploads []string
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetLedgerEntriesFunc: func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+			if len(keys) != 3 {
+				t.Fatal(keys)
+			}
+			return &soroban.GetLedgerEntriesResult{
+				LatestLedger: 10,
+				Entries:      []soroban.GetLedgerEntry{{Key: liveKeyXdr, LiveUntilLedgerSeq: 20}},
+			}, nil
+		},
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+			return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			wasm := *envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.Wasm
+			mu.Lock()
+			defer mu.Unlock()
+			uploads = append(uploads, string(wasm))
+			return &soroban.SendTransactionResult{Hash: string(wasm), Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}
+	contract := func(wasm string) *soroban.Contract {
+		return soroban.NewContract().Client(fake).SourceAccount(account).KeyPair(pair).Wasm([]byte(wasm))
+	}
+	contracts := []*soroban.Contract{contract("token"), contract("pool"), contract("pool"), contract("live"), contract("token")}
+	installs, err := soroban.InstallWasms(context.Background(), contracts...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installs) != 3 || len(uploads) != 2 || uploads[0] != "token" || uploads[1] != "pool" || account.Sequence != 3 {
+		t.Fatal(installs, uploads, account.Sequence)
+	}
+	if !installs[2].Live || installs[2].Pending != nil || installs[2].WasmHash != live {
+		t.Fatal(installs[2])
+	}
+	if len(installs[0].Contracts) != 2 || installs[0].Contracts[1] != 4 || installs[1].Pending.Hash() != "pool" {
+		t.Fatal(installs)
+	}
+
+	missing := soroban.NewContract().Client(fake).SourceAccount(account).KeyPair(pair).WasmHash([32]byte{1})
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		return &soroban.GetLedgerEntriesResult{LatestLedger: 10}, nil
+	}
+	installs, err = soroban.InstallWasms(context.Background(), missing)
+	if err == nil || err.Error() != soroban.ErrorRequiredWasm || installs[0].Err == nil {
+		t.Fatal(err)
+	}
+}
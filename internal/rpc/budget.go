@@ -0,0 +1,47 @@
+package rpc
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// ErrRetryBudgetExhausted is joined to the error of a call not retried
+// because the RetryBudget of the client is exhausted
+var ErrRetryBudgetExhausted = errors.New("rpc, retry budget exhausted")
+
+// RetryBudget is a token bucket of retries, shared by everything retrying on
+// behalf of a client, so a degraded dependency does not trigger a retry
+// storm. It refills PerMinute tokens per minute up to Burst.
+type RetryBudget struct {
+	perMinute int
+	burst     int
+
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// NewRetryBudget returns a full budget of perMinute retries per minute, up
+// to burst at once, perMinute if burst is 0
+func NewRetryBudget(perMinute int, burst int) *RetryBudget {
+	if burst <= 0 {
+		burst = perMinute
+	}
+	return &RetryBudget{perMinute: perMinute, burst: burst, tokens: float64(burst), last: time.Now()}
+}
+
+// Allow takes a token, returning false if there is none left
+func (b *RetryBudget) Allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	now := time.Now()
+	b.tokens += now.Sub(b.last).Minutes() * float64(b.perMinute)
+	b.tokens = min(b.tokens, float64(b.burst))
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
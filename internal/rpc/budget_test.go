@@ -0,0 +1,50 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestRetryBudget(t *testing.T) {
+	budget := rpc.NewRetryBudget(60, 2)
+	if !budget.Allow() || !budget.Allow() || budget.Allow() {
+		t.Fatal("burst not capped")
+	}
+	// one token per second
+	time.Sleep(1100 * time.Millisecond)
+	if !budget.Allow() || budget.Allow() {
+		t.Fatal("not refilled")
+	}
+}
+
+func TestRetryBudgetSharedByCalls(t *testing.T) {
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := rpc.Client{
+		URL:         server.URL,
+		Retry:       &rpc.RetryPolicy{MaxAttempts: 5, Backoff: time.Millisecond},
+		RetryBudget: rpc.NewRetryBudget(1, 3),
+	}
+	_, err := client.Call(context.Background(), "getHealth")
+	if !errors.Is(err, rpc.ErrRetryBudgetExhausted) || calls.Load() != 4 {
+		t.Fatal(err, calls.Load())
+	}
+	// the next call is not retried
+	_, err = client.Call(context.Background(), "getHealth")
+	var status *rpc.StatusError
+	if !errors.Is(err, rpc.ErrRetryBudgetExhausted) || !errors.As(err, &status) || calls.Load() != 5 {
+		t.Fatal(err, calls.Load())
+	}
+}
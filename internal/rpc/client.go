@@ -2,19 +2,66 @@ package rpc
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
 	"sync/atomic"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // Client implements remote calls to http server
 type Client struct {
 	HTTP HTTP
-	URL  string
+	// URL of the server, unix:///path/to/socket?path=/rpc URLs connect to
+	// a unix domain socket.
+	URL string
+	// Timeout of every call, 0 means no timeout
+	Timeout time.Duration
+	// Timeouts overrides Timeout for the given methods
+	Timeouts map[string]time.Duration
+	// InFlight caps the concurrent calls, optional
+	InFlight *Semaphore
+	// Signer signs every request, e.g. for authenticated gateways, optional
+	Signer RequestSigner
+	// Retry retries the calls failing with a transient error, optional
+	Retry *RetryPolicy
+	// RetryBudget caps the retries of Retry, and of the other retries made
+	// on behalf of the client, optional
+	RetryBudget *RetryBudget
+	// Headers are added to every request, e.g. the API key of a provider
+	Headers http.Header
+	// Token returns the bearer token of the Authorization header, see
+	// TokenFunc, optional
+	Token TokenFunc
+	// Middleware wraps every call, the first one is the outermost
+	Middleware []Middleware
+	// TracerProvider of the spans of the calls, the global one of
+	// OpenTelemetry if nil
+	TracerProvider trace.TracerProvider
+	// StringID returns the string id of the n-th request, for the providers
+	// requiring string ids, e.g. DecimalID or UUID, optional
+	StringID func(n uint64) string
+}
+
+// ErrIDMismatch is returned when the id of a response is not the one of its
+// request, e.g. a proxy mixing up the responses of concurrent calls
+var ErrIDMismatch = errors.New("rpc, response id does not match the request")
 
-	id uint64
+// lastID is the id of the last request, shared by every Client so the ids
+// are unique in the process
+var lastID atomic.Uint64
+
+func (c Client) timeout(method string) time.Duration {
+	if t, ok := c.Timeouts[method]; ok {
+		return t
+	}
+	return c.Timeout
 }
 
 func (c Client) http() HTTP {
@@ -24,44 +71,144 @@ func (c Client) http() HTTP {
 	return c.HTTP
 }
 
-// Call remote server with given method and arguments
-func (c Client) Call(method string, args ...interface{}) (*Response, error) {
-	var b []byte
-	var err error
+// Call remote server with given method and arguments, the request is
+// canceled when ctx is done. The request goes through the Middleware, then
+// transient failures are retried with Retry. Every call is traced in a span
+// "rpc <method>", propagated to the server in the request headers.
+//
+// Every request has a unique id, in the span and the Middleware for log
+// correlation, and the response must have the same id, or ErrIDMismatch is
+// returned.
+func (c Client) Call(ctx context.Context, method string, args ...interface{}) (*Response, error) {
+	r, _, err := c.CallID(ctx, method, args...)
+	return r, err
+}
 
+// CallID is Call, also returning the id of the request, for the correlation
+// of the logs with the ones of the server
+func (c Client) CallID(ctx context.Context, method string, args ...interface{}) (r *Response, id string, err error) {
+	req := &Request{Version: "2.0", Method: method, ID: lastID.Add(1)}
+	if c.StringID != nil {
+		req.StringID = c.StringID(req.ID)
+	}
+	ctx, span := c.startSpan(ctx, req)
+	defer func() { EndSpan(span, err) }()
 	switch {
-	case len(args) == 0:
-		b, err = json.Marshal(Request{Version: "2.0", Method: method, ID: atomic.AddUint64(&c.id, 1)})
 	case len(args) == 1:
-		b, err = json.Marshal(Request{Version: "2.0", Method: method, Params: args[0], ID: atomic.AddUint64(&c.id, 1)})
-	default:
-		b, err = json.Marshal(Request{Version: "2.0", Method: method, Params: args, ID: atomic.AddUint64(&c.id, 1)})
+		req.Params = args[0]
+	case len(args) > 1:
+		req.Params = args
+	}
+	roundTrip := c.roundTrip
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		roundTrip = c.Middleware[i](roundTrip)
 	}
+	r, err = roundTrip(ctx, req)
+	return r, req.IDString(), err
+}
+
+// roundTrip sends req, retrying it with Retry
+func (c Client) roundTrip(ctx context.Context, req *Request) (*Response, error) {
+	b, err := json.Marshal(req)
 	if err != nil {
 		return nil, err
 	}
+	if c.Retry == nil {
+		return c.call(ctx, req, b)
+	}
+	for attempt := 1; ; attempt++ {
+		r, err := c.call(ctx, req, b)
+		if err == nil || attempt >= c.Retry.MaxAttempts || !c.Retry.retryable(ctx, err) {
+			return r, err
+		}
+		if c.RetryBudget != nil && !c.RetryBudget.Allow() {
+			return nil, errors.Join(err, ErrRetryBudgetExhausted)
+		}
+		if err := c.Retry.wait(ctx, attempt, err); err != nil {
+			return nil, err
+		}
+	}
+}
 
-	req, err := http.NewRequest("POST", c.URL, bytes.NewBuffer(b))
+// call sends req, marshaled in b, once with the timeout of its method
+func (c Client) call(ctx context.Context, req *Request, b []byte) (*Response, error) {
+	target, client := c.URL, c.http()
+	if socket, httpURL, ok := unixTarget(c.URL); ok {
+		target = httpURL
+		if c.HTTP == nil {
+			client = unixHTTP(socket)
+		}
+	}
+	if timeout := c.timeout(req.Method); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if c.InFlight != nil {
+		if err := c.InFlight.Acquire(ctx); err != nil {
+			return nil, errors.Join(errors.New("rpc, waiting in-flight slot:"), err)
+		}
+		defer c.InFlight.Release()
+	}
+	r, err := c.do(ctx, client, target, req, b, false)
+	var status *StatusError
+	if c.Token != nil && errors.As(err, &status) && status.Code == http.StatusUnauthorized {
+		// the token may have expired, it is refreshed once
+		r, err = c.do(ctx, client, target, req, b, true)
+	}
+	return r, err
+}
+
+// do sends req, marshaled in b, to target, refresh is passed to Token
+func (c Client) do(ctx context.Context, client HTTP, target string, rpcReq *Request, b []byte, refresh bool) (*Response, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewBuffer(b))
 	if err != nil {
 		return nil, errors.Join(errors.New("rpc, request creation:"), err)
 	}
+	for name, values := range c.Headers {
+		for _, value := range values {
+			req.Header.Add(name, value)
+		}
+	}
 	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+	if c.Token != nil {
+		token, err := c.Token(ctx, refresh)
+		if err != nil {
+			return nil, errors.Join(errors.New("rpc, bearer token:"), err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	if c.Signer != nil {
+		if err := c.Signer.SignRequest(req, b); err != nil {
+			return nil, err
+		}
+	}
 
-	resp, err := c.http().Do(req)
+	resp, err := client.Do(req)
 	if err != nil {
 		return nil, errors.Join(errors.New("rpc, request execution:"), err)
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != 200 {
-		return nil, fmt.Errorf("bad status %s for %s", resp.Status, method)
+		return nil, &StatusError{
+			Code:       resp.StatusCode,
+			Status:     resp.Status,
+			Method:     rpcReq.Method,
+			RetryAfter: retryAfter(resp.Header.Get("Retry-After")),
+		}
 	}
 
 	r := Response{}
 	if err = json.NewDecoder(resp.Body).Decode(&r); err != nil {
 		return nil, errors.Join(errors.New("rpc, response json unmarshaling:"), err)
 	}
+	// an error about an unreadable request has a null id
+	if !r.Matches(rpcReq) && (r.Error == nil || r.ID != 0 || r.StringID != "") {
+		return nil, fmt.Errorf("%w: %s id %q, response id %q", ErrIDMismatch, rpcReq.Method, rpcReq.IDString(), r.IDString())
+	}
 	if r.Error != nil {
-		return nil, fmt.Errorf("%s", r.Error)
+		return nil, r.Error
 	}
 	return &r, nil
 }
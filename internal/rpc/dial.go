@@ -0,0 +1,62 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// UnixScheme is the scheme of URLs pointing to an RPC server listening on a
+// unix domain socket, e.g. unix:///var/run/soroban-rpc.sock?path=/rpc
+// The HTTP path defaults to "/", and the Host header is set to "localhost".
+const UnixScheme = "unix"
+
+// DialFunc opens a connection, as net.Dialer.DialContext
+type DialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+// NewDialerHTTP returns an HTTP using dial to open its connections, useful
+// to reach co-located RPC servers without TCP.
+func NewDialerHTTP(dial DialFunc) HTTP {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = dial
+	return &http.Client{Transport: transport}
+}
+
+// NewUnixHTTP returns an HTTP connecting to the unix domain socket at path,
+// whatever the host of the requests is.
+func NewUnixHTTP(path string) HTTP {
+	return NewDialerHTTP(func(ctx context.Context, _, _ string) (net.Conn, error) {
+		var d net.Dialer
+		return d.DialContext(ctx, "unix", path)
+	})
+}
+
+var unixClients sync.Map
+
+// unixTarget translates a unix:// URL into the socket path and the HTTP URL
+// to request over it.
+func unixTarget(rawURL string) (socket string, httpURL string, ok bool) {
+	if !strings.HasPrefix(rawURL, UnixScheme+"://") {
+		return "", "", false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", "", false
+	}
+	path := u.Query().Get("path")
+	if path == "" {
+		path = "/"
+	}
+	return u.Path, "http://localhost" + path, true
+}
+
+func unixHTTP(socket string) HTTP {
+	if c, ok := unixClients.Load(socket); ok {
+		return c.(HTTP)
+	}
+	c, _ := unixClients.LoadOrStore(socket, NewUnixHTTP(socket))
+	return c.(HTTP)
+}
@@ -0,0 +1,63 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestUnixSocketURL(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "rpc.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skip(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rpc" || r.Host != "localhost" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, requestID(r.Body))
+	})}
+	go server.Serve(l)
+	defer server.Close()
+
+	client := rpc.Client{URL: "unix://" + socket + "?path=/rpc"}
+	res, err := client.Call(context.Background(), "getHealth")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(*res.Result) != `{"status":"healthy"}` {
+		t.Fatal(string(*res.Result))
+	}
+}
+
+func TestMethodTimeout(t *testing.T) {
+	socket := filepath.Join(t.TempDir(), "rpc.sock")
+	l, err := net.Listen("unix", socket)
+	if err != nil {
+		t.Skip(err)
+	}
+	server := &http.Server{Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	})}
+	go server.Serve(l)
+	defer server.Close()
+
+	client := rpc.Client{
+		URL:      "unix://" + socket,
+		Timeout:  time.Minute,
+		Timeouts: map[string]time.Duration{"getHealth": 10 * time.Millisecond},
+	}
+	_, err = client.Call(context.Background(), "getHealth")
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
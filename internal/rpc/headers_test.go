@@ -0,0 +1,55 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestHeadersAndToken(t *testing.T) {
+	valid := "token-1"
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Api-Key") != "key" || r.Header.Get("Content-Type") != "application/json; charset=utf-8" {
+			t.Error(r.Header)
+		}
+		if r.Header.Get("Authorization") != "Bearer "+valid {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, requestID(r.Body))
+	}))
+	defer server.Close()
+
+	fetches := 0
+	client := rpc.Client{
+		URL:     server.URL,
+		Headers: http.Header{"x-api-key": {"key"}, "Content-Type": {"text/plain"}},
+		Token: rpc.CachedToken(func(ctx context.Context) (string, time.Time, error) {
+			fetches++
+			return fmt.Sprintf("token-%d", fetches), time.Time{}, nil
+		}),
+	}
+	for range 2 {
+		if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if fetches != 1 {
+		t.Fatal(fetches)
+	}
+
+	// the server rotated the token, it is refreshed once
+	valid = "token-2"
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil || fetches != 2 {
+		t.Fatal(err, fetches)
+	}
+	valid = "none"
+	if _, err := client.Call(context.Background(), "getHealth"); err == nil || fetches != 3 {
+		t.Fatal(err, fetches)
+	}
+}
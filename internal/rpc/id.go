@@ -0,0 +1,124 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// DecimalID is a Client StringID sending n as a string, "1" instead of 1
+func DecimalID(n uint64) string {
+	return strconv.FormatUint(n, 10)
+}
+
+// UUID is a Client StringID sending a random UUID (v4) instead of n
+func UUID(n uint64) string {
+	var b [16]byte
+	rand.Read(b[:])
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
+
+// wireID reads a JSON-RPC id, a number or a string, a numeric string is read
+// as the number too
+type wireID struct {
+	n uint64
+	s string
+}
+
+func (id *wireID) UnmarshalJSON(b []byte) error {
+	switch {
+	case string(b) == "null":
+		return nil
+	case len(b) > 0 && b[0] == '"':
+		if err := json.Unmarshal(b, &id.s); err != nil {
+			return err
+		}
+		id.n, _ = strconv.ParseUint(id.s, 10, 64)
+		return nil
+	}
+	return json.Unmarshal(b, &id.n)
+}
+
+// MarshalJSON sends StringID as the id, if set
+func (r Request) MarshalJSON() ([]byte, error) {
+	type plain Request
+	var id any = r.ID
+	if r.StringID != "" {
+		id = r.StringID
+	}
+	return json.Marshal(struct {
+		plain
+		ID any `json:"id"`
+	}{plain(r), id})
+}
+
+// UnmarshalJSON reads a number or a string id, see Response.Matches
+func (r *Request) UnmarshalJSON(b []byte) error {
+	type plain Request
+	var req struct {
+		plain
+		ID wireID `json:"id"`
+	}
+	if err := json.Unmarshal(b, &req); err != nil {
+		return err
+	}
+	*r = Request(req.plain)
+	r.ID, r.StringID = req.ID.n, req.ID.s
+	return nil
+}
+
+// MarshalJSON sends StringID as the id, if set
+func (r Response) MarshalJSON() ([]byte, error) {
+	type plain Response
+	var id any = r.ID
+	if r.StringID != "" {
+		id = r.StringID
+	}
+	return json.Marshal(struct {
+		plain
+		ID any `json:"id"`
+	}{plain(r), id})
+}
+
+// UnmarshalJSON reads a number or a string id, see Matches
+func (r *Response) UnmarshalJSON(b []byte) error {
+	type plain Response
+	var res struct {
+		plain
+		ID wireID `json:"id"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return err
+	}
+	*r = Response(res.plain)
+	r.ID, r.StringID = res.ID.n, res.ID.s
+	return nil
+}
+
+// Matches returns if r is the response of req. The providers echoing a
+// number id as a string, or the reverse, still match.
+func (r *Response) Matches(req *Request) bool {
+	if req.StringID != "" {
+		return r.StringID == req.StringID || (r.StringID == "" && DecimalID(r.ID) == req.StringID)
+	}
+	return r.ID == req.ID && (r.StringID == "" || r.StringID == DecimalID(req.ID))
+}
+
+// IDString returns the id of r as sent, StringID or ID, for log correlation
+func (r *Request) IDString() string {
+	if r.StringID != "" {
+		return r.StringID
+	}
+	return DecimalID(r.ID)
+}
+
+// IDString returns the id of r as received, StringID or ID
+func (r *Response) IDString() string {
+	if r.StringID != "" {
+		return r.StringID
+	}
+	return DecimalID(r.ID)
+}
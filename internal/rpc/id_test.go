@@ -0,0 +1,150 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestStringID(t *testing.T) {
+	var ids []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		var raw map[string]any
+		json.Unmarshal(body, &raw)
+		ids = append(ids, raw["id"])
+		var req rpc.Request
+		json.Unmarshal(body, &req)
+		if req.StringID == "" || req.StringID == rpc.DecimalID(req.ID) {
+			// a numeric id is echoed as a number
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"ok"}`, req.ID)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%q,"result":"ok"}`, req.StringID)
+	}))
+	defer server.Close()
+
+	for _, c := range []struct {
+		stringID func(uint64) string
+		id       string
+	}{
+		{nil, `^[0-9]+$`},
+		{rpc.DecimalID, `^[0-9]+$`},
+		{rpc.UUID, `^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`},
+	} {
+		ids = nil
+		client := rpc.Client{URL: server.URL, StringID: c.stringID}
+		_, id, err := client.CallID(context.Background(), "getHealth")
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, isString := ids[0].(string)
+		if isString != (c.stringID != nil) || !regexp.MustCompile(c.id).MatchString(fmt.Sprint(ids[0])) {
+			t.Fatalf("%T %v", ids[0], ids[0])
+		}
+		if id != fmt.Sprint(ids[0]) {
+			t.Fatal(id, ids[0])
+		}
+	}
+}
+
+func TestResponseMatches(t *testing.T) {
+	for _, c := range []struct {
+		req     rpc.Request
+		body    string
+		matches bool
+	}{
+		{rpc.Request{ID: 7}, `{"id":7}`, true},
+		{rpc.Request{ID: 7}, `{"id":"7"}`, true},
+		{rpc.Request{ID: 7}, `{"id":8}`, false},
+		{rpc.Request{ID: 7}, `{"id":"x"}`, false},
+		{rpc.Request{ID: 7, StringID: "7"}, `{"id":7}`, true},
+		{rpc.Request{ID: 7, StringID: "a-b"}, `{"id":"a-b"}`, true},
+		{rpc.Request{ID: 7, StringID: "a-b"}, `{"id":7}`, false},
+	} {
+		var res rpc.Response
+		if err := json.Unmarshal([]byte(c.body), &res); err != nil {
+			t.Fatal(err)
+		}
+		if res.Matches(&c.req) != c.matches {
+			t.Fatal(c.req, c.body)
+		}
+	}
+
+	b, err := json.Marshal(rpc.Request{Version: "2.0", Method: "m", ID: 1, StringID: "a"})
+	if err != nil || string(b) != `{"jsonrpc":"2.0","method":"m","id":"a"}` {
+		t.Fatal(string(b), err)
+	}
+	var req rpc.Request
+	if err := json.Unmarshal(b, &req); err != nil || req.StringID != "a" || req.Method != "m" {
+		t.Fatal(req, err)
+	}
+}
+
+func TestConcurrentIDs(t *testing.T) {
+	var mu sync.Mutex
+	seen := map[uint64]bool{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := requestID(r.Body)
+		mu.Lock()
+		if seen[id] {
+			t.Errorf("id %d reused", id)
+		}
+		seen[id] = true
+		mu.Unlock()
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"ok"}`, id)
+	}))
+	defer server.Close()
+
+	// copies of a Client share the counter
+	client := rpc.Client{URL: server.URL}
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func(client rpc.Client) {
+			defer wg.Done()
+			if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+				t.Error(err)
+			}
+		}(client)
+	}
+	wg.Wait()
+	if len(seen) != 20 {
+		t.Fatal(len(seen))
+	}
+}
+
+func TestResponseIDMismatch(t *testing.T) {
+	var body func(id uint64) string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, body(requestID(r.Body)))
+	}))
+	defer server.Close()
+
+	client := rpc.Client{URL: server.URL}
+	body = func(id uint64) string { return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":"ok"}`, id) }
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+		t.Fatal(err)
+	}
+	body = func(id uint64) string { return fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"result":"ok"}`, id+1) }
+	if _, err := client.Call(context.Background(), "getHealth"); !errors.Is(err, rpc.ErrIDMismatch) {
+		t.Fatal(err)
+	}
+	// the error of an unreadable request has a null id
+	body = func(uint64) string {
+		return `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"parse error"}}`
+	}
+	var rpcErr *rpc.RPCError
+	if _, err := client.Call(context.Background(), "getHealth"); !errors.As(err, &rpcErr) || rpcErr.Code != rpc.CodeParseError {
+		t.Fatal(err)
+	}
+}
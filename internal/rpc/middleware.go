@@ -0,0 +1,19 @@
+package rpc
+
+import "context"
+
+// RoundTrip sends a JSON-RPC request and returns its response
+type RoundTrip func(ctx context.Context, req *Request) (*Response, error)
+
+// Middleware wraps the RoundTrip of a Client, e.g. to log, authenticate,
+// measure or mutate the requests. It sees each call once, the retries of
+// the RetryPolicy happen inside next.
+//
+//	logging := func(next rpc.RoundTrip) rpc.RoundTrip {
+//		return func(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+//			res, err := next(ctx, req)
+//			log.Print(req.Method, err)
+//			return res, err
+//		}
+//	}
+type Middleware func(next RoundTrip) RoundTrip
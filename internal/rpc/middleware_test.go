@@ -0,0 +1,61 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestMiddleware(t *testing.T) {
+	var methods []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Error(err)
+		}
+		methods = append(methods, req.Method)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, req.ID)
+	}))
+	defer server.Close()
+
+	var order []string
+	trace := func(name string) rpc.Middleware {
+		return func(next rpc.RoundTrip) rpc.RoundTrip {
+			return func(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+				order = append(order, name)
+				return next(ctx, req)
+			}
+		}
+	}
+	rename := func(next rpc.RoundTrip) rpc.RoundTrip {
+		return func(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+			req.Method = "getHealth"
+			return next(ctx, req)
+		}
+	}
+	client := rpc.Client{URL: server.URL, Middleware: []rpc.Middleware{trace("outer"), trace("inner"), rename}}
+	res, err := client.Call(context.Background(), "getStatus")
+	if err != nil || res.Result == nil {
+		t.Fatal(res, err)
+	}
+	if len(order) != 2 || order[0] != "outer" || order[1] != "inner" || len(methods) != 1 || methods[0] != "getHealth" {
+		t.Fatal(order, methods)
+	}
+
+	// a middleware can answer without calling the server
+	refused := errors.New("refused")
+	client.Middleware = []rpc.Middleware{func(next rpc.RoundTrip) rpc.RoundTrip {
+		return func(ctx context.Context, req *rpc.Request) (*rpc.Response, error) {
+			return nil, refused
+		}
+	}}
+	if _, err := client.Call(context.Background(), "getHealth"); !errors.Is(err, refused) || len(methods) != 1 {
+		t.Fatal(err, methods)
+	}
+}
@@ -0,0 +1,101 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+)
+
+// DefaultRetryStatusCodes are the transient HTTP statuses retried by a
+// RetryPolicy without StatusCodes
+var DefaultRetryStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// StatusError is returned by Call when the server answers with a status
+// other than 200
+type StatusError struct {
+	Code   int
+	Status string
+	Method string
+	// RetryAfter is the delay asked by the Retry-After header, if any
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("bad status %s for %s", e.Status, e.Method)
+}
+
+// RetryPolicy retries the calls failing with a transient error: one of
+// StatusCodes, a timeout of the attempt or a network error. JSON-RPC errors
+// are never retried.
+type RetryPolicy struct {
+	// MaxAttempts of a call, including the first one
+	MaxAttempts int
+	// Backoff before the first retry, doubling after each one up to
+	// MaxBackoff, if set. A longer Retry-After of the server is honored.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+	// StatusCodes retried, DefaultRetryStatusCodes if nil
+	StatusCodes []int
+	// Retryable overrides the decision for the errors that are not a
+	// StatusError, optional
+	Retryable func(error) bool
+}
+
+// retryable returns if err, of an attempt whose own deadline may have
+// expired, can be retried while ctx is not done
+func (p *RetryPolicy) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		codes := p.StatusCodes
+		if codes == nil {
+			codes = DefaultRetryStatusCodes
+		}
+		return slices.Contains(codes, status.Code)
+	}
+	if p.Retryable != nil {
+		return p.Retryable(err)
+	}
+	var netErr net.Error
+	return errors.Is(err, context.DeadlineExceeded) || errors.As(err, &netErr)
+}
+
+// wait sleeps before the retry following attempt, 1 for the first retry
+func (p *RetryPolicy) wait(ctx context.Context, attempt int, err error) error {
+	backoff := p.Backoff << (attempt - 1)
+	if p.MaxBackoff > 0 && (backoff > p.MaxBackoff || backoff <= 0) {
+		backoff = p.MaxBackoff
+	}
+	var status *StatusError
+	if errors.As(err, &status) && status.RetryAfter > backoff {
+		backoff = status.RetryAfter
+	}
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(backoff):
+		return nil
+	}
+}
+
+// retryAfter parses a Retry-After header in seconds, the HTTP date form is
+// ignored
+func retryAfter(header string) time.Duration {
+	seconds, err := strconv.Atoi(header)
+	if err != nil || seconds < 0 {
+		return 0
+	}
+	return time.Duration(seconds) * time.Second
+}
@@ -0,0 +1,81 @@
+package rpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestRetryPolicy(t *testing.T) {
+	// fails with 503 the first 2 calls, and 400 from the fourth
+	var calls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch n := calls.Add(1); {
+		case n <= 2:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case n == 3:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, requestID(r.Body))
+		default:
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	client := rpc.Client{URL: server.URL, Retry: &rpc.RetryPolicy{MaxAttempts: 3, Backoff: time.Millisecond}}
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil || calls.Load() != 3 {
+		t.Fatal(err, calls.Load())
+	}
+
+	// not a transient status
+	_, err := client.Call(context.Background(), "getHealth")
+	var status *rpc.StatusError
+	if !errors.As(err, &status) || status.Code != http.StatusBadRequest || calls.Load() != 4 {
+		t.Fatal(err, calls.Load())
+	}
+
+	// retried statuses, until MaxAttempts
+	calls.Store(0)
+	client.Retry.StatusCodes = []int{http.StatusServiceUnavailable}
+	client.Retry.MaxAttempts = 2
+	if _, err := client.Call(context.Background(), "getHealth"); !errors.As(err, &status) || status.Code != http.StatusServiceUnavailable || calls.Load() != 2 {
+		t.Fatal(err, calls.Load())
+	}
+}
+
+func TestRetryPolicyTimeout(t *testing.T) {
+	var calls atomic.Int64
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			<-release
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, requestID(r.Body))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client := rpc.Client{
+		URL:     server.URL,
+		Timeout: 20 * time.Millisecond,
+		Retry:   &rpc.RetryPolicy{MaxAttempts: 2, Backoff: time.Millisecond},
+	}
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil || calls.Load() != 2 {
+		t.Fatal(err, calls.Load())
+	}
+
+	// a done ctx is not retried
+	calls.Store(0)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := client.Call(ctx, "getHealth"); !errors.Is(err, context.Canceled) || calls.Load() != 0 {
+		t.Fatal(err, calls.Load())
+	}
+}
@@ -2,6 +2,7 @@ package rpc
 
 import (
 	"encoding/json"
+	"fmt"
 	"net/http"
 )
 
@@ -14,12 +15,39 @@ type Request struct {
 	Method  string      `json:"method"`
 	Params  interface{} `json:"params,omitempty"`
 	ID      uint64      `json:"id"`
+	// StringID is sent as the id instead of ID, if set, see Client StringID
+	StringID string `json:"-"`
 }
 
 type Response struct {
-	Version string           `json:"jsonrpc"`
-	ID      uint64           `json:"id"`
-	Result  *json.RawMessage `json:"result,omitempty"`
-	Error   any           `json:"error,omitempty"`
+	Version string `json:"jsonrpc"`
+	ID      uint64 `json:"id"`
+	// StringID is the id of the response when it is a string, a numeric
+	// one is also read in ID
+	StringID string           `json:"-"`
+	Result   *json.RawMessage `json:"result,omitempty"`
+	Error    *RPCError        `json:"error,omitempty"`
 }
 
+// JSON-RPC 2.0 error codes, the server defines its own from -32000 to -32099
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// RPCError is the error object of a JSON-RPC response
+type RPCError struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *RPCError) Error() string {
+	if len(e.Data) == 0 {
+		return fmt.Sprintf("rpc error %d: %s", e.Code, e.Message)
+	}
+	return fmt.Sprintf("rpc error %d: %s: %s", e.Code, e.Message, e.Data)
+}
@@ -0,0 +1,44 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+// requestID returns the id of the JSON-RPC request in body
+func requestID(body io.Reader) uint64 {
+	var req rpc.Request
+	json.NewDecoder(body).Decode(&req)
+	return req.ID
+}
+
+func TestRPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"invalid params","data":{"field":"hash"}}}`, requestID(r.Body))
+	}))
+	defer server.Close()
+
+	client := rpc.Client{URL: server.URL}
+	_, err := client.Call(context.Background(), "getTransaction")
+	var rpcErr *rpc.RPCError
+	if !errors.As(err, &rpcErr) {
+		t.Fatal(err)
+	}
+	if rpcErr.Code != rpc.CodeInvalidParams || rpcErr.Message != "invalid params" || string(rpcErr.Data) != `{"field":"hash"}` {
+		t.Fatal(rpcErr)
+	}
+	if err.Error() != `rpc error -32602: invalid params: {"field":"hash"}` {
+		t.Fatal(err)
+	}
+	if (&rpc.RPCError{Code: rpc.CodeMethodNotFound, Message: "not found"}).Error() != "rpc error -32601: not found" {
+		t.Fatal("bad message without data")
+	}
+}
@@ -0,0 +1,38 @@
+package rpc
+
+import "context"
+
+// Semaphore caps the number of concurrent in-flight calls of the clients
+// sharing it, excess calls wait for a free slot.
+type Semaphore struct {
+	slots chan struct{}
+}
+
+// NewSemaphore returns a Semaphore allowing max concurrent calls, it panics
+// if max is not positive, no call would ever be sent
+func NewSemaphore(max int) *Semaphore {
+	if max <= 0 {
+		panic("rpc: non-positive semaphore size")
+	}
+	return &Semaphore{slots: make(chan struct{}, max)}
+}
+
+// Acquire waits for a free slot or ctx to be done
+func (s *Semaphore) Acquire(ctx context.Context) error {
+	select {
+	case s.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Release frees a slot acquired with Acquire
+func (s *Semaphore) Release() {
+	<-s.slots
+}
+
+// InFlight returns the number of calls currently holding a slot
+func (s *Semaphore) InFlight() int {
+	return len(s.slots)
+}
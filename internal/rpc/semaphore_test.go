@@ -0,0 +1,62 @@
+package rpc_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestSemaphore(t *testing.T) {
+	var current, peak atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := current.Add(1)
+		defer current.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(5 * time.Millisecond)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"ok"}`, requestID(r.Body))
+	}))
+	defer server.Close()
+
+	sem := rpc.NewSemaphore(2)
+	client := rpc.Client{URL: server.URL, InFlight: sem}
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+	if peak.Load() != 2 || sem.InFlight() != 0 {
+		t.Fatal(peak.Load(), sem.InFlight())
+	}
+
+	// a full semaphore waits for the context
+	sem.Acquire(context.Background())
+	sem.Acquire(context.Background())
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := sem.Acquire(ctx); err != context.DeadlineExceeded {
+		t.Fatal(err)
+	}
+}
+
+func TestSemaphoreSize(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatal("size 0 accepted")
+		}
+	}()
+	rpc.NewSemaphore(0)
+}
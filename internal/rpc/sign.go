@@ -0,0 +1,159 @@
+package rpc
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultSignatureHeader = "X-Signature"
+	DefaultTimestampHeader = "X-Timestamp"
+	DefaultJWTHeader       = "Authorization"
+	DefaultJWTTTL          = time.Minute
+)
+
+// RequestSigner signs an outgoing request before it is sent, body is the
+// JSON-RPC request
+type RequestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
+
+// TokenFunc returns the bearer token of a request. It is called for every
+// request with refresh false, so it should cache the token, and again with
+// refresh true when the server answers 401 Unauthorized, to get a new one.
+type TokenFunc func(ctx context.Context, refresh bool) (string, error)
+
+// CachedToken returns a TokenFunc caching the token of fetch until it
+// expires, or the server rejects it. A zero expires never expires.
+func CachedToken(fetch func(ctx context.Context) (token string, expires time.Time, err error)) TokenFunc {
+	var mu sync.Mutex
+	var token string
+	var expires time.Time
+	return func(ctx context.Context, refresh bool) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		if token != "" && !refresh && (expires.IsZero() || time.Now().Before(expires)) {
+			return token, nil
+		}
+		t, e, err := fetch(ctx)
+		if err != nil {
+			return "", err
+		}
+		token, expires = t, e
+		return token, nil
+	}
+}
+
+// HMACSigner signs requests with HMAC-SHA256 over
+// "timestamp\nmethod\npath\nbody", hex encoded in Header, with the unix
+// timestamp in TimestampHeader.
+type HMACSigner struct {
+	Header          string
+	TimestampHeader string
+	// Secret returns the shared secret, called on every request so it can
+	// be rotated
+	Secret func() ([]byte, error)
+}
+
+// SignRequest implements RequestSigner
+func (s HMACSigner) SignRequest(req *http.Request, body []byte) error {
+	secret, err := s.Secret()
+	if err != nil {
+		return errors.Join(errors.New("rpc, request signing secret:"), err)
+	}
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(timestamp + "\n" + req.Method + "\n" + req.URL.Path + "\n"))
+	mac.Write(body)
+	req.Header.Set(orDefault(s.TimestampHeader, DefaultTimestampHeader), timestamp)
+	req.Header.Set(orDefault(s.Header, DefaultSignatureHeader), hex.EncodeToString(mac.Sum(nil)))
+	return nil
+}
+
+// JWTSigner adds a short lived JWT to Header, "Bearer <token>" by default.
+// The token has the iat, exp, iss (if Issuer is set) and body_sha256 claims,
+// binding it to the request.
+type JWTSigner struct {
+	Header string
+	// Prefix of the header value, "Bearer " if empty
+	Prefix string
+	Issuer string
+	// TTL of the token, DefaultJWTTTL if 0
+	TTL time.Duration
+	// Key returns a []byte secret, signing with HS256, or an
+	// ed25519.PrivateKey, signing with EdDSA
+	Key func() (any, error)
+}
+
+// SignRequest implements RequestSigner
+func (s JWTSigner) SignRequest(req *http.Request, body []byte) error {
+	key, err := s.Key()
+	if err != nil {
+		return errors.Join(errors.New("rpc, request signing key:"), err)
+	}
+	alg := ""
+	switch key.(type) {
+	case []byte:
+		alg = "HS256"
+	case ed25519.PrivateKey:
+		alg = "EdDSA"
+	default:
+		return errors.New("rpc, request signing key: unsupported key type")
+	}
+	now := time.Now()
+	ttl := s.TTL
+	if ttl == 0 {
+		ttl = DefaultJWTTTL
+	}
+	bodyHash := sha256.Sum256(body)
+	claims := map[string]any{
+		"iat":         now.Unix(),
+		"exp":         now.Add(ttl).Unix(),
+		"body_sha256": hex.EncodeToString(bodyHash[:]),
+	}
+	if s.Issuer != "" {
+		claims["iss"] = s.Issuer
+	}
+	header, err := json.Marshal(map[string]string{"alg": alg, "typ": "JWT"})
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return err
+	}
+	signingInput := base64.RawURLEncoding.EncodeToString(header) + "." + base64.RawURLEncoding.EncodeToString(payload)
+	var signature []byte
+	switch key := key.(type) {
+	case []byte:
+		mac := hmac.New(sha256.New, key)
+		mac.Write([]byte(signingInput))
+		signature = mac.Sum(nil)
+	case ed25519.PrivateKey:
+		signature = ed25519.Sign(key, []byte(signingInput))
+	}
+	prefix := s.Prefix
+	if prefix == "" {
+		prefix = "Bearer "
+	}
+	token := signingInput + "." + base64.RawURLEncoding.EncodeToString(signature)
+	req.Header.Set(orDefault(s.Header, DefaultJWTHeader), prefix+token)
+	return nil
+}
+
+func orDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+	return value
+}
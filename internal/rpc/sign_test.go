@@ -0,0 +1,75 @@
+package rpc_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestHMACSigner(t *testing.T) {
+	secret := []byte("secret")
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(r.Header.Get("X-Timestamp") + "\nPOST\n/rpc\n"))
+		mac.Write(body)
+		if r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{}}`, requestID(bytes.NewReader(body)))
+	}))
+	defer server.Close()
+
+	client := rpc.Client{
+		URL:    server.URL + "/rpc",
+		Signer: rpc.HMACSigner{Secret: func() ([]byte, error) { return secret, nil }},
+	}
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+		t.Fatal(err)
+	}
+	client.Signer = rpc.HMACSigner{Secret: func() ([]byte, error) { return []byte("wrong"), nil }}
+	if _, err := client.Call(context.Background(), "getHealth"); err == nil {
+		t.Fatal("wrong secret accepted")
+	}
+}
+
+func TestJWTSigner(t *testing.T) {
+	public, private, _ := ed25519.GenerateKey(nil)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		parts := strings.Split(token, ".")
+		if !ok || len(parts) != 3 {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		signature, _ := base64.RawURLEncoding.DecodeString(parts[2])
+		claims, _ := base64.RawURLEncoding.DecodeString(parts[1])
+		if !ed25519.Verify(public, []byte(parts[0]+"."+parts[1]), signature) || !strings.Contains(string(claims), `"iss":"tests"`) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{}}`, requestID(r.Body))
+	}))
+	defer server.Close()
+
+	client := rpc.Client{
+		URL:    server.URL,
+		Signer: rpc.JWTSigner{Issuer: "tests", Key: func() (any, error) { return private, nil }},
+	}
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+		t.Fatal(err)
+	}
+}
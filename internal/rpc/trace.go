@@ -0,0 +1,37 @@
+package rpc
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TracerName is the instrumentation scope of the spans of the client
+const TracerName = "github.com/sebamiro/soroban"
+
+// startSpan starts the client span of req
+func (c Client) startSpan(ctx context.Context, req *Request) (context.Context, trace.Span) {
+	provider := c.TracerProvider
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return provider.Tracer(TracerName).Start(ctx, "rpc "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", req.Method),
+			attribute.String("rpc.jsonrpc.request_id", req.IDString()),
+		))
+}
+
+// EndSpan ends span, recording err if any
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,132 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// TransportConfig configures the HTTP of a Client for deployments behind
+// corporate proxies, or with a self-hosted RPC server with internal
+// certificates. The zero values keep the defaults of http.DefaultTransport.
+type TransportConfig struct {
+	// TLS of the https connections, CAFiles and CertFile are added to a
+	// clone of it
+	TLS *tls.Config
+	// CAFiles are PEM files of the CAs trusted besides the system ones, e.g.
+	// a private CA
+	CAFiles []string
+	// CertFile and KeyFile are the PEM client certificate of mutual TLS
+	CertFile string
+	KeyFile  string
+	// Proxy URL, http, https, socks5 or socks5h, the HTTP_PROXY, HTTPS_PROXY
+	// and NO_PROXY environment variables are used if empty
+	Proxy string
+
+	DialTimeout           time.Duration
+	KeepAlive             time.Duration
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration
+	IdleConnTimeout       time.Duration
+	MaxIdleConnsPerHost   int
+	MaxConnsPerHost       int
+	// ForceAttemptHTTP2 is disabled when TLS is set, set it to keep HTTP/2
+	ForceAttemptHTTP2 bool
+}
+
+// NewTransportHTTP returns an HTTP configured with config
+func NewTransportHTTP(config TransportConfig) (HTTP, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	tlsConfig, err := config.tls()
+	if err != nil {
+		return nil, err
+	}
+	if tlsConfig != nil {
+		transport.TLSClientConfig = tlsConfig
+		transport.ForceAttemptHTTP2 = config.ForceAttemptHTTP2
+	}
+	if config.Proxy != "" {
+		u, err := url.Parse(config.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("rpc, proxy: %w", err)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, fmt.Errorf("rpc, proxy scheme is not http, https, socks5 or socks5h: %q", config.Proxy)
+		}
+		transport.Proxy = http.ProxyURL(u)
+	}
+	if config.DialTimeout > 0 || config.KeepAlive > 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: 30 * time.Second}
+		if config.DialTimeout > 0 {
+			dialer.Timeout = config.DialTimeout
+		}
+		if config.KeepAlive > 0 {
+			dialer.KeepAlive = config.KeepAlive
+		}
+		transport.DialContext = dialer.DialContext
+	}
+	if config.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = config.TLSHandshakeTimeout
+	}
+	if config.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = config.ResponseHeaderTimeout
+	}
+	if config.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = config.IdleConnTimeout
+	}
+	if config.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = config.MaxIdleConnsPerHost
+	}
+	if config.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = config.MaxConnsPerHost
+	}
+	return &http.Client{Transport: transport}, nil
+}
+
+// tls returns the TLS config of c, nil if it has none
+func (c TransportConfig) tls() (*tls.Config, error) {
+	if c.TLS == nil && len(c.CAFiles) == 0 && c.CertFile == "" {
+		return nil, nil
+	}
+	config := &tls.Config{}
+	if c.TLS != nil {
+		config = c.TLS.Clone()
+	}
+	if len(c.CAFiles) > 0 {
+		var pool *x509.CertPool
+		if config.RootCAs != nil {
+			// the pool of TLS is shared by its clones, it is not modified
+			pool = config.RootCAs.Clone()
+		} else {
+			var err error
+			if pool, err = x509.SystemCertPool(); err != nil {
+				pool = x509.NewCertPool()
+			}
+		}
+		for _, path := range c.CAFiles {
+			b, err := os.ReadFile(path)
+			if err != nil {
+				return nil, fmt.Errorf("rpc, CA: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(b) {
+				return nil, fmt.Errorf("rpc, CA: no certificate in %s", path)
+			}
+		}
+		config.RootCAs = pool
+	}
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("rpc, client certificate: %w", err)
+		}
+		config.Certificates = append(config.Certificates, cert)
+	}
+	return config, nil
+}
@@ -0,0 +1,84 @@
+package rpc_test
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+func TestTransportPrivateCA(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"ok"}`, requestID(r.Body))
+	}))
+	defer server.Close()
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	if err := os.WriteFile(ca, cert, 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	untrusted, err := rpc.NewTransportHTTP(rpc.TransportConfig{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (rpc.Client{URL: server.URL, HTTP: untrusted}).Call(context.Background(), "getHealth"); err == nil {
+		t.Fatal("expected an unknown authority error")
+	}
+	trusted, err := rpc.NewTransportHTTP(rpc.TransportConfig{CAFiles: []string{ca}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := (rpc.Client{URL: server.URL, HTTP: trusted}).Call(context.Background(), "getHealth"); err != nil {
+		t.Fatal(err)
+	}
+
+	// the pool of the TLS config is not modified
+	pool := x509.NewCertPool()
+	own := &tls.Config{RootCAs: pool}
+	if _, err := rpc.NewTransportHTTP(rpc.TransportConfig{TLS: own, CAFiles: []string{ca}}); err != nil {
+		t.Fatal(err)
+	}
+	if !pool.Equal(x509.NewCertPool()) || own.RootCAs != pool {
+		t.Fatal("TLS RootCAs modified")
+	}
+
+	if _, err := rpc.NewTransportHTTP(rpc.TransportConfig{CAFiles: []string{filepath.Join(t.TempDir(), "missing.pem")}}); err == nil {
+		t.Fatal("expected a missing CA error")
+	}
+	if _, err := rpc.NewTransportHTTP(rpc.TransportConfig{CertFile: ca, KeyFile: ca}); err == nil {
+		t.Fatal("expected a client certificate error")
+	}
+}
+
+func TestTransportProxy(t *testing.T) {
+	var proxied string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proxied = r.URL.String()
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":"ok"}`, requestID(r.Body))
+	}))
+	defer proxy.Close()
+
+	transport, err := rpc.NewTransportHTTP(rpc.TransportConfig{Proxy: proxy.URL, MaxConnsPerHost: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.Client{URL: "http://rpc.internal/soroban", HTTP: transport}
+	if _, err := client.Call(context.Background(), "getHealth"); err != nil {
+		t.Fatal(err)
+	}
+	if proxied != "http://rpc.internal/soroban" {
+		t.Fatal(proxied)
+	}
+	if _, err := rpc.NewTransportHTTP(rpc.TransportConfig{Proxy: "ftp://proxy"}); err == nil {
+		t.Fatal("expected a proxy scheme error")
+	}
+}
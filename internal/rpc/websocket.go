@@ -0,0 +1,232 @@
+package rpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// WebSocket implements HTTP sending the JSON-RPC calls over a WebSocket
+// connection, so it can be used as Client.HTTP with the same Call interface.
+// The connection is dialed lazily and re-dialed after it breaks.
+type WebSocket struct {
+	URL    string
+	Origin string
+	// ReconnectDelay is the minimum time between dials, default 1 second
+	ReconnectDelay time.Duration
+
+	mu       sync.Mutex
+	conn     *websocket.Conn
+	lastDial time.Time
+	id       uint64
+	pending  map[uint64]chan []byte
+}
+
+// NewWebSocket returns a WebSocket transport for url (ws:// or wss://)
+func NewWebSocket(url string) *WebSocket {
+	return &WebSocket{URL: url}
+}
+
+// Do sends the body of the request over the WebSocket and waits for the
+// response with the same id. The request id is replaced by one unique to
+// the connection and restored in the response.
+func (w *WebSocket) Do(req *http.Request) (*http.Response, error) {
+	var request map[string]json.RawMessage
+	if err := json.NewDecoder(req.Body).Decode(&request); err != nil {
+		return nil, errors.Join(errors.New("rpc, websocket request:"), err)
+	}
+	originalID := request["id"]
+
+	conn, id, ch, err := w.register(req.Context())
+	if err != nil {
+		return nil, err
+	}
+	defer w.unregister(id)
+
+	request["id"], _ = json.Marshal(id)
+	if err := websocket.JSON.Send(conn, request); err != nil {
+		w.broken(conn)
+		return nil, errors.Join(errors.New("rpc, websocket send:"), err)
+	}
+
+	var b []byte
+	select {
+	case b = <-ch:
+	case <-req.Context().Done():
+		return nil, req.Context().Err()
+	}
+	if b == nil {
+		return nil, errors.New("rpc, websocket connection closed")
+	}
+
+	var response map[string]json.RawMessage
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, errors.Join(errors.New("rpc, websocket response:"), err)
+	}
+	var responseID wireID
+	if err := json.Unmarshal(response["id"], &responseID); err != nil || responseID.n != id {
+		return nil, fmt.Errorf("rpc, websocket response to an unknown request: %s", b)
+	}
+	response["id"] = originalID
+	b, err = json.Marshal(response)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": []string{"application/json"}},
+		Body:       io.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}, nil
+}
+
+// Close closes the current connection, if any
+func (w *WebSocket) Close() error {
+	w.mu.Lock()
+	conn := w.conn
+	w.mu.Unlock()
+	if conn == nil {
+		return nil
+	}
+	w.broken(conn)
+	return nil
+}
+
+func (w *WebSocket) register(ctx context.Context) (*websocket.Conn, uint64, chan []byte, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	for w.conn == nil {
+		if wait := w.dialWait(); wait > 0 {
+			// the calls on other connections go on meanwhile, another call
+			// may dial first
+			w.mu.Unlock()
+			select {
+			case <-ctx.Done():
+				w.mu.Lock()
+				return nil, 0, nil, ctx.Err()
+			case <-time.After(wait):
+			}
+			w.mu.Lock()
+			continue
+		}
+		// the dial runs without mu, the calls meanwhile wait for the
+		// ReconnectDelay
+		w.lastDial = time.Now()
+		w.mu.Unlock()
+		conn, err := w.dial(ctx)
+		w.mu.Lock()
+		if err != nil {
+			return nil, 0, nil, err
+		}
+		w.install(conn)
+	}
+	w.id++
+	ch := make(chan []byte, 1)
+	w.pending[w.id] = ch
+	return w.conn, w.id, ch, nil
+}
+
+func (w *WebSocket) unregister(id uint64) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.pending, id)
+}
+
+// dialWait returns the time until the next dial is allowed, it must be
+// called with mu held
+func (w *WebSocket) dialWait() time.Duration {
+	delay := w.ReconnectDelay
+	if delay == 0 {
+		delay = time.Second
+	}
+	return time.Until(w.lastDial.Add(delay))
+}
+
+// dial opens a connection to URL, it must be called without mu
+func (w *WebSocket) dial(ctx context.Context) (*websocket.Conn, error) {
+	origin := w.Origin
+	if origin == "" {
+		origin = "http://localhost/"
+	}
+	config, err := websocket.NewConfig(w.URL, origin)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("rpc, websocket dial %s:", w.URL), err)
+	}
+	conn, err := config.DialContext(ctx)
+	if err != nil {
+		return nil, errors.Join(fmt.Errorf("rpc, websocket dial %s:", w.URL), err)
+	}
+	return conn, nil
+}
+
+// install makes conn the connection of the calls, unless another call
+// installed one while it was dialed. It must be called with mu held.
+func (w *WebSocket) install(conn *websocket.Conn) {
+	if w.conn != nil {
+		conn.Close()
+		return
+	}
+	w.conn = conn
+	w.pending = map[uint64]chan []byte{}
+	go w.read(conn)
+}
+
+func (w *WebSocket) read(conn *websocket.Conn) {
+	for {
+		var b []byte
+		if err := websocket.Message.Receive(conn, &b); err != nil {
+			w.broken(conn)
+			return
+		}
+		var response struct {
+			ID wireID `json:"id"`
+		}
+		if err := json.Unmarshal(b, &response); err != nil {
+			continue
+		}
+		w.mu.Lock()
+		if ch, ok := w.pending[response.ID.n]; ok {
+			deliver(ch, b)
+		} else if response.ID.n == 0 || response.ID.n > w.id {
+			// a null id, the server could not read the request, or one never
+			// sent: it may answer any pending call, they all fail with it
+			for id, ch := range w.pending {
+				deliver(ch, b)
+				delete(w.pending, id)
+			}
+		}
+		w.mu.Unlock()
+	}
+}
+
+// deliver sends the response b to the call waiting on ch, once
+func deliver(ch chan []byte, b []byte) {
+	select {
+	case ch <- b:
+	default:
+	}
+}
+
+// broken closes conn and fails its pending calls, the next call will dial again
+func (w *WebSocket) broken(conn *websocket.Conn) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.conn != conn {
+		return
+	}
+	conn.Close()
+	w.conn = nil
+	for id, ch := range w.pending {
+		close(ch)
+		delete(w.pending, id)
+	}
+}
@@ -0,0 +1,132 @@
+package rpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+	"golang.org/x/net/websocket"
+)
+
+func TestWebSocketCall(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		for {
+			var req rpc.Request
+			if err := websocket.JSON.Receive(conn, &req); err != nil {
+				return
+			}
+			result := json.RawMessage(`"` + req.Method + `"`)
+			websocket.JSON.Send(conn, rpc.Response{Version: "2.0", ID: req.ID, Result: &result})
+		}
+	}))
+	defer server.Close()
+
+	ws := rpc.NewWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer ws.Close()
+	client := rpc.Client{URL: server.URL, HTTP: ws}
+	for _, method := range []string{"getHealth", "getNetwork"} {
+		res, err := client.Call(context.Background(), method)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(*res.Result) != `"`+method+`"` {
+			t.Fatal(string(*res.Result))
+		}
+	}
+}
+
+func TestWebSocketNullID(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		for {
+			var req rpc.Request
+			if err := websocket.JSON.Receive(conn, &req); err != nil {
+				return
+			}
+			websocket.Message.Send(conn, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`)
+		}
+	}))
+	defer server.Close()
+
+	ws := rpc.NewWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	defer ws.Close()
+	client := rpc.Client{URL: server.URL, HTTP: ws}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	_, err := client.Call(ctx, "getHealth")
+	if err == nil || ctx.Err() != nil || !strings.Contains(err.Error(), "Parse error") {
+		t.Fatal(err)
+	}
+}
+
+func TestWebSocketReconnectDelay(t *testing.T) {
+	server := httptest.NewServer(websocket.Handler(func(conn *websocket.Conn) {
+		var req rpc.Request
+		websocket.JSON.Receive(conn, &req)
+	}))
+	defer server.Close()
+
+	ws := rpc.NewWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	ws.ReconnectDelay = time.Hour
+	defer ws.Close()
+	client := rpc.Client{URL: server.URL, HTTP: ws}
+	// the server closes the connection without answering
+	if _, err := client.Call(context.Background(), "getHealth"); err == nil {
+		t.Fatal("answered")
+	}
+	// the next dial waits for the delay, or the context, without holding
+	// the lock
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		_, err := client.Call(ctx, "getHealth")
+		done <- err
+	}()
+	time.Sleep(10 * time.Millisecond)
+	ws.Close()
+	select {
+	case err := <-done:
+		t.Fatal("returned before the deadline", err)
+	default:
+	}
+	if err := <-done; !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
+
+func TestWebSocketDialContext(t *testing.T) {
+	release := make(chan struct{})
+	// the server never completes the handshake
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	ws := rpc.NewWebSocket("ws" + strings.TrimPrefix(server.URL, "http"))
+	client := rpc.Client{URL: server.URL, HTTP: ws}
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		_, err := client.Call(ctx, "getHealth")
+		done <- err
+	}()
+	// the dial does not hold the lock
+	time.Sleep(10 * time.Millisecond)
+	ws.Close()
+	select {
+	case err := <-done:
+		if err == nil || !strings.Contains(err.Error(), context.DeadlineExceeded.Error()) {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("the dial ignores the context")
+	}
+}
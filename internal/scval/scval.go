@@ -0,0 +1,129 @@
+// Package scval implements the ordering of xdr.ScVal used by the Soroban host
+package scval
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Compare orders values as the Soroban host does: first by type,
+// then by value. It returns -1, 0 or 1.
+func Compare(a, b xdr.ScVal) int {
+	if a.Type != b.Type {
+		return cmpInt(int64(a.Type), int64(b.Type))
+	}
+	switch a.Type {
+	case xdr.ScValTypeScvBool:
+		return cmpInt(boolInt(*a.B), boolInt(*b.B))
+	case xdr.ScValTypeScvVoid, xdr.ScValTypeScvLedgerKeyContractInstance:
+		return 0
+	case xdr.ScValTypeScvU32:
+		return cmpInt(int64(*a.U32), int64(*b.U32))
+	case xdr.ScValTypeScvI32:
+		return cmpInt(int64(*a.I32), int64(*b.I32))
+	case xdr.ScValTypeScvU64, xdr.ScValTypeScvTimepoint, xdr.ScValTypeScvDuration:
+		ua, ub := u64(a), u64(b)
+		switch {
+		case ua < ub:
+			return -1
+		case ua > ub:
+			return 1
+		}
+		return 0
+	case xdr.ScValTypeScvI64:
+		return cmpInt(int64(*a.I64), int64(*b.I64))
+	case xdr.ScValTypeScvBytes:
+		return bytes.Compare(*a.Bytes, *b.Bytes)
+	case xdr.ScValTypeScvString:
+		return bytes.Compare([]byte(*a.Str), []byte(*b.Str))
+	case xdr.ScValTypeScvSymbol:
+		return bytes.Compare([]byte(*a.Sym), []byte(*b.Sym))
+	case xdr.ScValTypeScvVec:
+		va, vb := vec(a), vec(b)
+		for i := 0; i < len(va) && i < len(vb); i++ {
+			if c := Compare(va[i], vb[i]); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(int64(len(va)), int64(len(vb)))
+	case xdr.ScValTypeScvMap:
+		ma, mb := smap(a), smap(b)
+		for i := 0; i < len(ma) && i < len(mb); i++ {
+			if c := Compare(ma[i].Key, mb[i].Key); c != 0 {
+				return c
+			}
+			if c := Compare(ma[i].Val, mb[i].Val); c != 0 {
+				return c
+			}
+		}
+		return cmpInt(int64(len(ma)), int64(len(mb)))
+	}
+	// U128, I128, U256, I256, Address, Error: the XDR encoding orders them
+	// like their values, big endian with the type discriminant first.
+	ba, _ := a.MarshalBinary()
+	bb, _ := b.MarshalBinary()
+	switch a.Type {
+	case xdr.ScValTypeScvI128, xdr.ScValTypeScvI256:
+		// flip the sign bit so negative values sort first
+		ba[4] ^= 0x80
+		bb[4] ^= 0x80
+	}
+	return bytes.Compare(ba, bb)
+}
+
+// SortMap sorts the entries of m by key. If there are duplicated keys it
+// returns one of them and false.
+func SortMap(m xdr.ScMap) (xdr.ScVal, bool) {
+	sort.SliceStable(m, func(i, j int) bool {
+		return Compare(m[i].Key, m[j].Key) < 0
+	})
+	for i := 1; i < len(m); i++ {
+		if Compare(m[i-1].Key, m[i].Key) == 0 {
+			return m[i].Key, false
+		}
+	}
+	return xdr.ScVal{}, true
+}
+
+func vec(v xdr.ScVal) xdr.ScVec {
+	if v.Vec == nil || *v.Vec == nil {
+		return nil
+	}
+	return **v.Vec
+}
+
+func smap(v xdr.ScVal) xdr.ScMap {
+	if v.Map == nil || *v.Map == nil {
+		return nil
+	}
+	return **v.Map
+}
+
+func u64(v xdr.ScVal) uint64 {
+	switch v.Type {
+	case xdr.ScValTypeScvTimepoint:
+		return uint64(*v.Timepoint)
+	case xdr.ScValTypeScvDuration:
+		return uint64(*v.Duration)
+	}
+	return uint64(*v.U64)
+}
+
+func boolInt(b bool) int64 {
+	if b {
+		return 1
+	}
+	return 0
+}
+
+func cmpInt(a, b int64) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
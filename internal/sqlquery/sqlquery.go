@@ -0,0 +1,27 @@
+// Package sqlquery writes the portable SQL queries of the SQL Outbox and Store
+package sqlquery
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Format sets the table of q, and numbers its ? placeholders as $1, $2...
+// if numbered, e.g. for PostgreSQL
+func Format(table, q string, numbered bool) string {
+	q = fmt.Sprintf(q, table)
+	if !numbered {
+		return q
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range q {
+		if r == '?' {
+			n++
+			fmt.Fprintf(&b, "$%d", n)
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
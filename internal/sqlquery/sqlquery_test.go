@@ -0,0 +1,17 @@
+package sqlquery_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban/internal/sqlquery"
+)
+
+func TestFormat(t *testing.T) {
+	q := "UPDATE %s SET hash = ? WHERE id = ? AND status = ''"
+	if got := sqlquery.Format("outbox", q, false); got != "UPDATE outbox SET hash = ? WHERE id = ? AND status = ''" {
+		t.Fatal(got)
+	}
+	if got := sqlquery.Format("outbox", q, true); got != "UPDATE outbox SET hash = $1 WHERE id = $2 AND status = ''" {
+		t.Fatal(got)
+	}
+}
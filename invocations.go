@@ -0,0 +1,121 @@
+package soroban
+
+import (
+	"context"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+// InvocationsOptions are the params of GetInvocations, StartLedger or
+// Cursor is required
+type InvocationsOptions struct {
+	// StartLedger and EndLedger of the scanned transactions, both included,
+	// with EndLedger 0 the scan stops at the latest ledger
+	StartLedger int64
+	EndLedger   int64
+	// Cursor resumes the scan after a previous GetInvocationsResult.Cursor
+	Cursor string
+	// Limit is the maximum number of invocations returned, 0 returns every
+	// invocation of the range
+	Limit int
+	// Function filters the invocations by function name, optional
+	Function string
+	// PageSize is the limit of every getTransactions call, optional
+	PageSize uint
+}
+
+// Invocation is a past invocation of a contract function
+type Invocation struct {
+	Hash      string
+	Ledger    int64
+	CreatedAt time.Time
+	// Caller is the source account of the operation, or of the transaction
+	Caller   string
+	Function string
+	Args     []xdr.ScVal
+	// Successful is false for failed invocations, their Result is nil
+	Successful bool
+	Result     *xdr.ScVal
+	// PagingToken of the transaction, see TransactionInfo.PagingToken
+	PagingToken string
+}
+
+// GetInvocationsResult is a page of invocations of GetInvocations
+type GetInvocationsResult struct {
+	Invocations []Invocation
+	// Cursor of the last scanned transaction, to continue with the next page
+	Cursor string
+}
+
+// GetInvocations returns the decoded invocations of the contract contractID
+// (C...), in execution order, scanning the transactions of getTransactions.
+// A page ends after opts.Limit invocations, or at the end of the range, use
+// its Cursor to get the next one.
+//
+//	Example:
+//	 page, err := client.GetInvocations(ctx, contractID, &soroban.InvocationsOptions{StartLedger: 1000, Limit: 20})
+//	 next, err := client.GetInvocations(ctx, contractID, &soroban.InvocationsOptions{Cursor: page.Cursor, Limit: 20})
+func (c Client) GetInvocations(ctx context.Context, contractID string, opts *InvocationsOptions) (*GetInvocationsResult, error) {
+	if opts == nil {
+		opts = &InvocationsOptions{}
+	}
+	result := &GetInvocationsResult{Cursor: opts.Cursor}
+	it := c.Transactions(opts.StartLedger, opts.EndLedger).Cursor(opts.Cursor).Limit(opts.PageSize)
+	for (opts.Limit == 0 || len(result.Invocations) < opts.Limit) && it.Next(ctx) {
+		tx := it.Transaction()
+		result.Cursor = it.PagingToken()
+		invocation, ok, err := decodeInvocation(tx, contractID)
+		if err != nil {
+			return nil, err
+		}
+		if ok && (opts.Function == "" || opts.Function == invocation.Function) {
+			result.Invocations = append(result.Invocations, invocation)
+		}
+	}
+	if err := it.Err(); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// decodeInvocation decodes tx if it invokes a function of contractID
+func decodeInvocation(tx TransactionInfo, contractID string) (Invocation, bool, error) {
+	res := tx.Result()
+	envelope, err := res.Envelope()
+	if err != nil {
+		return Invocation{}, false, err
+	}
+	operations := envelope.Operations()
+	if len(operations) != 1 || operations[0].Body.Type != xdr.OperationTypeInvokeHostFunction {
+		return Invocation{}, false, nil
+	}
+	function := operations[0].Body.InvokeHostFunctionOp.HostFunction
+	if function.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+		return Invocation{}, false, nil
+	}
+	if address, err := function.InvokeContract.ContractAddress.String(); err != nil || address != contractID {
+		return Invocation{}, false, nil
+	}
+	source := envelope.SourceAccount()
+	if operations[0].SourceAccount != nil {
+		source = *operations[0].SourceAccount
+	}
+	invocation := Invocation{
+		Hash:        tx.TxHash,
+		Ledger:      tx.Ledger,
+		CreatedAt:   time.Unix(tx.CreatedAt, 0),
+		Caller:      source.Address(),
+		Function:    string(function.InvokeContract.FunctionName),
+		Args:        function.InvokeContract.Args,
+		Successful:  tx.Status == "SUCCESS",
+		PagingToken: tx.PagingToken(),
+	}
+	if invocation.Successful {
+		invocation.Result, err = res.ReturnValue()
+		if err != nil {
+			return Invocation{}, false, err
+		}
+	}
+	return invocation, true, nil
+}
@@ -0,0 +1,117 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestGetInvocations(t *testing.T) {
+	caller, opSource := keypair.MustRandom(), keypair.MustRandom()
+	contract, other := xdr.ContractId{1}, xdr.ContractId{2}
+	contractID := strkey.MustEncode(strkey.VersionByteContract, contract[:])
+	envelope := func(op txnbuild.Operation) string {
+		tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+			SourceAccount: &txnbuild.SimpleAccount{AccountID: caller.Address(), Sequence: 1},
+			Operations:    []txnbuild.Operation{op},
+			BaseFee:       txnbuild.MinBaseFee,
+			Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewInfiniteTimeout()},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		b, _ := tx.Base64()
+		return b
+	}
+	invoke := func(id xdr.ContractId, function, source string) string {
+		return envelope(&txnbuild.InvokeHostFunction{
+			SourceAccount: source,
+			HostFunction: xdr.HostFunction{
+				Type: xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{
+					ContractAddress: xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id},
+					FunctionName:    xdr.ScSymbol(function),
+					Args:            []xdr.ScVal{sym("a")},
+				},
+			},
+		})
+	}
+	ret := sym("ok")
+	meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &ret}}})
+	txs := []string{
+		fmt.Sprintf(`{"status":"SUCCESS","txHash":"0","ledger":10,"applicationOrder":1,"createdAt":1700000000,"envelopeXdr":%q,"resultMetaXdr":%q}`, invoke(contract, "transfer", ""), meta),
+		fmt.Sprintf(`{"status":"SUCCESS","txHash":"1","ledger":10,"applicationOrder":2,"envelopeXdr":%q}`, envelope(&txnbuild.BumpSequence{BumpTo: 10})),
+		fmt.Sprintf(`{"status":"FAILED","txHash":"2","ledger":11,"applicationOrder":1,"envelopeXdr":%q}`, invoke(contract, "mint", "")),
+		fmt.Sprintf(`{"status":"SUCCESS","txHash":"3","ledger":11,"applicationOrder":2,"envelopeXdr":%q,"resultMetaXdr":%q}`, invoke(other, "transfer", ""), meta),
+		fmt.Sprintf(`{"status":"SUCCESS","txHash":"4","ledger":12,"applicationOrder":1,"envelopeXdr":%q,"resultMetaXdr":%q}`, invoke(contract, "transfer", opSource.Address()), meta),
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Params struct {
+				Pagination struct {
+					Cursor string `json:"cursor"`
+					Limit  int    `json:"limit"`
+				} `json:"pagination"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// the cursor is the paging token of the last transaction returned
+		var tokens []string
+		for _, tx := range txs {
+			var info soroban.TransactionInfo
+			json.Unmarshal([]byte(tx), &info)
+			tokens = append(tokens, info.PagingToken())
+		}
+		from := 0
+		if cursor := req.Params.Pagination.Cursor; cursor != "" {
+			from = slices.Index(tokens, cursor) + 1
+		}
+		to := min(from+req.Params.Pagination.Limit, len(txs))
+		cursor := req.Params.Pagination.Cursor
+		if to > from {
+			cursor = tokens[to-1]
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":13,"cursor":"%s","transactions":[%s]}}`,
+			req.ID, cursor, strings.Join(txs[from:to], ","))
+	}))
+	defer server.Close()
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+
+	page, err := client.GetInvocations(context.Background(), contractID, &soroban.InvocationsOptions{StartLedger: 10, Limit: 2, PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Invocations) != 2 {
+		t.Fatal(page.Invocations)
+	}
+	first, failed := page.Invocations[0], page.Invocations[1]
+	if first.Hash != "0" || first.Caller != caller.Address() || first.Function != "transfer" || *first.Args[0].Sym != "a" ||
+		!first.Successful || *first.Result.Sym != "ok" || first.CreatedAt.Unix() != 1700000000 || first.Ledger != 10 {
+		t.Fatal(first)
+	}
+	if failed.Hash != "2" || failed.Successful || failed.Result != nil || failed.Function != "mint" {
+		t.Fatal(failed)
+	}
+
+	next, err := client.GetInvocations(context.Background(), contractID, &soroban.InvocationsOptions{Cursor: page.Cursor, Function: "transfer", PageSize: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(next.Invocations) != 1 || next.Invocations[0].Hash != "4" || next.Invocations[0].Caller != opSource.Address() {
+		t.Fatal(next.Invocations)
+	}
+}
@@ -0,0 +1,52 @@
+package soroban
+
+import (
+	"context"
+	"time"
+)
+
+// DefaultKeepWarmInterval is below the idle timeout of the default HTTP
+// transport (90s), so the pooled connection is never closed
+const DefaultKeepWarmInterval = 30 * time.Second
+
+// KeepWarm establishes the connection to the server with a getHealth call,
+// returning its error, and keeps it open pinging getHealth every interval
+// (DefaultKeepWarmInterval if 0) until ctx is done. The first call after an
+// idle period does not pay the TCP and TLS setup, relevant for latency
+// sensitive services reacting to events. Errors of the background pings are
+// ignored, the next one dials again.
+func (c Client) KeepWarm(ctx context.Context, interval time.Duration) error {
+	if interval == 0 {
+		interval = DefaultKeepWarmInterval
+	}
+	ticker := time.NewTicker(interval)
+	if err := c.KeepWarmTicks(ctx, ticker.C); err != nil {
+		ticker.Stop()
+		return err
+	}
+	context.AfterFunc(ctx, ticker.Stop)
+	return nil
+}
+
+// KeepWarmTicks is like KeepWarm, pinging on every tick received from ticks
+// instead of an interval, e.g. to share a ticker among clients. The pings
+// stop when ctx is done or ticks is closed.
+func (c Client) KeepWarmTicks(ctx context.Context, ticks <-chan time.Time) error {
+	if _, err := c.GetHealth(ctx); err != nil {
+		return err
+	}
+	go func() {
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case _, ok := <-ticks:
+				if !ok {
+					return
+				}
+				c.GetHealth(ctx)
+			}
+		}
+	}()
+	return nil
+}
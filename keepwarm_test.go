@@ -0,0 +1,60 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+)
+
+func TestKeepWarm(t *testing.T) {
+	var conns atomic.Int32
+	pings := make(chan struct{}, 1)
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, req.ID)
+		if req.Method == soroban.GetHealth {
+			pings <- struct{}{}
+		}
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	client := soroban.Client{}
+	client.URL = server.URL
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ticks := make(chan time.Time)
+	if err := client.KeepWarmTicks(ctx, ticks); err != nil {
+		t.Fatal(err)
+	}
+	<-pings
+	for range 3 {
+		ticks <- time.Now()
+		<-pings
+	}
+	if n := conns.Load(); n != 1 {
+		t.Fatal("connections", n)
+	}
+
+	client.URL = "http://127.0.0.1:1"
+	if err := client.KeepWarm(context.Background(), 0); err == nil {
+		t.Fatal("expected the first ping to fail")
+	}
+}
@@ -0,0 +1,105 @@
+package soroban
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorInvalidLedgerKey = "Invalid ledger key"
+)
+
+// ledgerKeys are the constructors of Key
+type ledgerKeys struct{}
+
+// Key builds validated ledger keys, to be used instead of xdr.LedgerKey
+// literals, e.g. Key.Account(address) or Key.Ttl(dataKey).
+var Key ledgerKeys
+
+// Account returns the key of the account entry of accountID (G...)
+func (ledgerKeys) Account(accountID string) (xdr.LedgerKey, error) {
+	var id xdr.AccountId
+	if err := id.SetAddress(accountID); err != nil {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: account %w", ErrorInvalidLedgerKey, err)
+	}
+	return xdr.LedgerKey{
+		Type:    xdr.LedgerEntryTypeAccount,
+		Account: &xdr.LedgerKeyAccount{AccountId: id},
+	}, nil
+}
+
+// Trustline returns the key of the trustline of accountID (G...) to asset,
+// which can not be native
+func (ledgerKeys) Trustline(accountID string, asset txnbuild.Asset) (xdr.LedgerKey, error) {
+	var id xdr.AccountId
+	if err := id.SetAddress(accountID); err != nil {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: trustline %w", ErrorInvalidLedgerKey, err)
+	}
+	if asset == nil || asset.IsNative() {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: trustline to a native asset", ErrorInvalidLedgerKey)
+	}
+	xdrAsset, err := asset.ToXDR()
+	if err != nil {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: trustline %w", ErrorInvalidLedgerKey, err)
+	}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeTrustline,
+		TrustLine: &xdr.LedgerKeyTrustLine{
+			AccountId: id,
+			Asset:     xdrAsset.ToTrustLineAsset(),
+		},
+	}, nil
+}
+
+// ContractData returns the key of the contract data key, with durability, of
+// contract, which has to be a contract address
+func (ledgerKeys) ContractData(contract xdr.ScAddress, key xdr.ScVal, durability xdr.ContractDataDurability) (xdr.LedgerKey, error) {
+	if contract.Type != xdr.ScAddressTypeScAddressTypeContract || contract.ContractId == nil {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: contract data of a non contract address", ErrorInvalidLedgerKey)
+	}
+	if !durability.ValidEnum(int32(durability)) {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: contract data durability %d", ErrorInvalidLedgerKey, durability)
+	}
+	return contractDataKey(contract, key, durability), nil
+}
+
+// ContractCode returns the key of the wasm code with hash
+func (ledgerKeys) ContractCode(hash xdr.Hash) (xdr.LedgerKey, error) {
+	if hash == (xdr.Hash{}) {
+		return xdr.LedgerKey{}, errors.New(ErrorRequiredWasmHash)
+	}
+	return xdr.LedgerKey{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.LedgerKeyContractCode{Hash: hash},
+	}, nil
+}
+
+// ConfigSetting returns the key of the network config setting id
+func (ledgerKeys) ConfigSetting(id xdr.ConfigSettingId) (xdr.LedgerKey, error) {
+	if !id.ValidEnum(int32(id)) {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: config setting %d", ErrorInvalidLedgerKey, id)
+	}
+	return xdr.LedgerKey{
+		Type:          xdr.LedgerEntryTypeConfigSetting,
+		ConfigSetting: &xdr.LedgerKeyConfigSetting{ConfigSettingId: id},
+	}, nil
+}
+
+// Ttl returns the key of the ttl entry of key, a contract data or code key
+func (ledgerKeys) Ttl(key xdr.LedgerKey) (xdr.LedgerKey, error) {
+	if key.Type != xdr.LedgerEntryTypeContractData && key.Type != xdr.LedgerEntryTypeContractCode {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: ttl of a %s entry", ErrorInvalidLedgerKey, key.Type)
+	}
+	b, err := key.MarshalBinary()
+	if err != nil {
+		return xdr.LedgerKey{}, fmt.Errorf("%s: ttl %w", ErrorInvalidLedgerKey, err)
+	}
+	return xdr.LedgerKey{
+		Type: xdr.LedgerEntryTypeTtl,
+		Ttl:  &xdr.LedgerKeyTtl{KeyHash: sha256.Sum256(b)},
+	}, nil
+}
@@ -0,0 +1,71 @@
+package soroban_test
+
+import (
+	"crypto/sha256"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestKey(t *testing.T) {
+	pair := keypair.MustRandom()
+	account, err := soroban.Key.Account(pair.Address())
+	if err != nil || account.Type != xdr.LedgerEntryTypeAccount || account.Account.AccountId.Address() != pair.Address() {
+		t.Fatal(account, err)
+	}
+	if _, err := soroban.Key.Account("GBAD"); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorInvalidLedgerKey) {
+		t.Fatal(err)
+	}
+
+	usdc := txnbuild.CreditAsset{Code: "USDC", Issuer: keypair.MustRandom().Address()}
+	trustline, err := soroban.Key.Trustline(pair.Address(), usdc)
+	if err != nil || trustline.Type != xdr.LedgerEntryTypeTrustline || trustline.TrustLine.Asset.Type != xdr.AssetTypeAssetTypeCreditAlphanum4 {
+		t.Fatal(trustline, err)
+	}
+	if _, err := soroban.Key.Trustline(pair.Address(), txnbuild.NativeAsset{}); err == nil {
+		t.Fatal("trustline to native")
+	}
+
+	accountAddress := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &account.Account.AccountId}
+	if _, err := soroban.Key.ContractData(accountAddress, xdr.ScVal{Type: xdr.ScValTypeScvVoid}, soroban.Persistent); err == nil {
+		t.Fatal("contract data of an account")
+	}
+	contractId := xdr.ContractId{1}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}
+	data, err := soroban.Key.ContractData(contract, xdr.ScVal{Type: xdr.ScValTypeScvVoid}, soroban.Temporary)
+	if err != nil || data.ContractData.Durability != soroban.Temporary {
+		t.Fatal(data, err)
+	}
+
+	if _, err := soroban.Key.ContractCode(xdr.Hash{}); err == nil {
+		t.Fatal("empty hash")
+	}
+	code, err := soroban.Key.ContractCode(xdr.Hash{2})
+	if err != nil || code.ContractCode.Hash != (xdr.Hash{2}) {
+		t.Fatal(code, err)
+	}
+
+	if _, err := soroban.Key.ConfigSetting(xdr.ConfigSettingId(1000)); err == nil {
+		t.Fatal("unknown config setting")
+	}
+	setting, err := soroban.Key.ConfigSetting(xdr.ConfigSettingIdConfigSettingContractMaxSizeBytes)
+	if err != nil || setting.Type != xdr.LedgerEntryTypeConfigSetting {
+		t.Fatal(setting, err)
+	}
+
+	ttl, err := soroban.Key.Ttl(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, _ := data.MarshalBinary()
+	if ttl.Type != xdr.LedgerEntryTypeTtl || ttl.Ttl.KeyHash != xdr.Hash(sha256.Sum256(b)) {
+		t.Fatal(ttl)
+	}
+	if _, err := soroban.Key.Ttl(account); err == nil {
+		t.Fatal("ttl of an account")
+	}
+}
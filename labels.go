@@ -0,0 +1,156 @@
+package soroban
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/stellar/go/xdr"
+)
+
+// AddressBook maps account and contract addresses to human friendly labels.
+// It is safe for concurrent use, and its zero value is an empty AddressBook.
+type AddressBook struct {
+	mu     sync.RWMutex
+	labels map[string]string
+}
+
+// NewAddressBook returns an empty AddressBook
+func NewAddressBook() *AddressBook {
+	return &AddressBook{labels: map[string]string{}}
+}
+
+// Set registers a label for the address (G... account or C... contract)
+func (b *AddressBook) Set(address, label string) *AddressBook {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.labels == nil {
+		b.labels = map[string]string{}
+	}
+	b.labels[address] = label
+	return b
+}
+
+// Lookup returns the label of the address, if registered.
+func (b *AddressBook) Lookup(address string) (string, bool) {
+	if b == nil {
+		return "", false
+	}
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	label, ok := b.labels[address]
+	return label, ok
+}
+
+// Label returns the label of the address, or the address itself if it has
+// no label registered.
+func (b *AddressBook) Label(address string) string {
+	if label, ok := b.Lookup(address); ok {
+		return label
+	}
+	return address
+}
+
+// LabelScAddress is like Label for xdr.ScAddress
+func (b *AddressBook) LabelScAddress(address xdr.ScAddress) string {
+	str, err := address.String()
+	if err != nil {
+		return err.Error()
+	}
+	return b.Label(str)
+}
+
+// FormatScVal returns a readable representation of the value, replacing
+// addresses with their labels.
+func (b *AddressBook) FormatScVal(v xdr.ScVal) string {
+	switch v.Type {
+	case xdr.ScValTypeScvAddress:
+		return b.LabelScAddress(*v.Address)
+	case xdr.ScValTypeScvString:
+		return fmt.Sprintf("%q", string(*v.Str))
+	case xdr.ScValTypeScvVec:
+		if v.Vec == nil || *v.Vec == nil {
+			return "[]"
+		}
+		items := make([]string, 0, len(**v.Vec))
+		for _, item := range **v.Vec {
+			items = append(items, b.FormatScVal(item))
+		}
+		return "[" + strings.Join(items, ", ") + "]"
+	case xdr.ScValTypeScvMap:
+		if v.Map == nil || *v.Map == nil {
+			return "{}"
+		}
+		items := make([]string, 0, len(**v.Map))
+		for _, entry := range **v.Map {
+			items = append(items, b.FormatScVal(entry.Key)+": "+b.FormatScVal(entry.Val))
+		}
+		return "{" + strings.Join(items, ", ") + "}"
+	}
+	return v.String()
+}
+
+// Label returns the label registered in the client Labels for the address
+func (c Client) Label(address string) string {
+	return c.Labels.Label(address)
+}
+
+// FormatScVal formats the value using the client Labels
+func (c Client) FormatScVal(v xdr.ScVal) string {
+	return c.Labels.FormatScVal(v)
+}
+
+// FormatEvent formats the event of GetEvents using the client Labels
+func (c Client) FormatEvent(e Event) (string, error) {
+	return e.Format(c.Labels)
+}
+
+// FormatDiagnosticEvent formats the decoded event using the client Labels
+func (c Client) FormatDiagnosticEvent(e DiagnosticEvent) string {
+	return e.Format(c.Labels)
+}
+
+// DescribeTransaction returns a short human readable description of the
+// decoded transaction, one line per operation, using the client Labels.
+func (c Client) DescribeTransaction(d *TransactionDetails) string {
+	var sb strings.Builder
+	envelope := d.Envelope
+	hash := d.Hash
+	if d.FeeBump {
+		feeAccount := envelope.FeeBumpAccount()
+		fmt.Fprintf(&sb, "fee bump %s by %s, fee %d\n", d.Hash, c.Label(feeAccount.Address()), envelope.FeeBumpFee())
+		envelope = *d.InnerEnvelope
+		hash = d.InnerHash
+	}
+	status := "FAILED"
+	if d.Successful() {
+		status = "SUCCESS"
+	}
+	source := envelope.SourceAccount()
+	fmt.Fprintf(&sb, "transaction %s %s by %s, fee %d\n", hash, status, c.Label(source.Address()), envelope.Fee())
+	for i, op := range envelope.Operations() {
+		fmt.Fprintf(&sb, "  %d: %s\n", i, c.describeOperation(op))
+	}
+	return sb.String()
+}
+
+func (c Client) describeOperation(op xdr.Operation) string {
+	if op.Body.Type != xdr.OperationTypeInvokeHostFunction {
+		return op.Body.Type.String()
+	}
+	hf := op.Body.InvokeHostFunctionOp.HostFunction
+	switch hf.Type {
+	case xdr.HostFunctionTypeHostFunctionTypeInvokeContract:
+		args := make([]string, 0, len(hf.InvokeContract.Args))
+		for _, arg := range hf.InvokeContract.Args {
+			args = append(args, c.FormatScVal(arg))
+		}
+		return fmt.Sprintf("invoke %s.%s(%s)",
+			c.Labels.LabelScAddress(hf.InvokeContract.ContractAddress),
+			hf.InvokeContract.FunctionName,
+			strings.Join(args, ", "))
+	case xdr.HostFunctionTypeHostFunctionTypeUploadContractWasm:
+		return fmt.Sprintf("install wasm (%d bytes)", len(*hf.Wasm))
+	}
+	return hf.Type.String()
+}
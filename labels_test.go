@@ -0,0 +1,64 @@
+package soroban_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestAddressBookFormatScVal(t *testing.T) {
+	kp, _ := keypair.Random()
+	accountId := xdr.MustAddress(kp.Address())
+	address := xdr.ScVal{
+		Type:    xdr.ScValTypeScvAddress,
+		Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId},
+	}
+	amount := xdr.Uint32(10)
+	vec := &xdr.ScVec{address, {Type: xdr.ScValTypeScvU32, U32: &amount}}
+
+	book := soroban.NewAddressBook().Set(kp.Address(), "alice")
+	res := book.FormatScVal(xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vec})
+	if res != "[alice, 10]" {
+		t.Fatal(res)
+	}
+	if book.Label("unknown") != "unknown" {
+		t.Fatal("Label should default to the address")
+	}
+
+	// the zero value is usable
+	var zero soroban.AddressBook
+	if zero.Label(kp.Address()) != kp.Address() || zero.Set(kp.Address(), "bob").Label(kp.Address()) != "bob" {
+		t.Fatal("zero AddressBook")
+	}
+}
+
+func TestFormatEvent(t *testing.T) {
+	kp, _ := keypair.Random()
+	accountId := xdr.MustAddress(kp.Address())
+	to := xdr.ScVal{
+		Type:    xdr.ScValTypeScvAddress,
+		Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId},
+	}
+	amount := xdr.Uint32(10)
+	topic, _ := xdr.MarshalBase64(sym("mint"))
+	recipient, _ := xdr.MarshalBase64(to)
+	value, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &amount})
+	contractId := xdr.ContractId{1}
+	contract, _ := (xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}).String()
+	event := soroban.Event{Type: soroban.EventTypeContract, ContractId: contract, Topic: []string{topic, recipient}, Value: value}
+
+	client := soroban.Client{Labels: soroban.NewAddressBook().Set(kp.Address(), "alice").Set(contract, "token")}
+	res, err := client.FormatEvent(event)
+	if err != nil || res != "contract token: [mint, alice] 10" {
+		t.Fatal(res, err)
+	}
+	diagnostic := soroban.DiagnosticEvent{Type: "diagnostic", ContractID: contract, Topics: []xdr.ScVal{to}, Data: to}
+	if res := client.FormatDiagnosticEvent(diagnostic); res != "diagnostic token: [alice] alice" {
+		t.Fatal(res)
+	}
+	if res := diagnostic.String(); res != "diagnostic "+contract+": ["+kp.Address()+"] "+kp.Address() {
+		t.Fatal(res)
+	}
+}
@@ -0,0 +1,122 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestInvokeRoundTrips(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	if _, err := contract.Invoke().Function("hello").Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	want := []string{soroban.SimulateTransaction, soroban.SendTransaction}
+	if len(fake.Calls) != len(want) {
+		t.Fatal(fake.Calls)
+	}
+	for i := range want {
+		if fake.Calls[i] != want[i] {
+			t.Fatal(fake.Calls)
+		}
+	}
+}
+
+func TestInvokeNeedsRestore(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	instance, err := contract.GetFootprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, err := contract.GetDataKey(sym("balance"), soroban.Persistent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	archived := instance
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			return nil, err
+		}
+		res := &soroban.SimulateTransactionResult{}
+		res.TransactionData, _ = xdr.MarshalBase64(xdr.SorobanTransactionData{})
+		if envelope.Operations()[0].Body.Type == xdr.OperationTypeInvokeHostFunction {
+			res.RestorePreamble.MinResourceFee = 100
+			res.RestorePreamble.TransactionData, _ = xdr.MarshalBase64(xdr.SorobanTransactionData{
+				Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadWrite: []xdr.LedgerKey{archived}}},
+			})
+		}
+		return res, nil
+	}
+	if _, err := contract.Invoke().Function("hello").Send(context.Background()); err == nil || err.Error() != soroban.ErrorContractNeedsRestore {
+		t.Fatal(err)
+	}
+	archived = data
+	if _, err := contract.Invoke().Function("hello").Send(context.Background()); err == nil || err.Error() != soroban.ErrorContractDataNeedsRestore {
+		t.Fatal(err)
+	}
+	if n := countCalls(fake, soroban.SendTransaction); n != 0 {
+		t.Fatal(fake.Calls)
+	}
+
+	// the archived entries are restored in one transaction, with the
+	// footprint of the preamble
+	var restored []xdr.LedgerKey
+	var restoreFee uint32
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			return nil, err
+		}
+		if envelope.Operations()[0].Body.Type == xdr.OperationTypeRestoreFootprint {
+			restored = envelope.V1.Tx.Ext.SorobanData.Resources.Footprint.ReadWrite
+			restoreFee = envelope.Fee()
+		}
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+	if _, err := contract.Invoke().Function("hello").RestoreAndSend(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if n := countCalls(fake, soroban.SendTransaction); n != 2 || len(restored) != 1 || !restored[0].Equals(data) {
+		t.Fatal(restored, fake.Calls)
+	}
+	// the inclusion fee plus the resource fee of the preamble
+	if restoreFee != 200 {
+		t.Fatal(restoreFee)
+	}
+}
+
+func TestRestoreFailed(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	instance, err := contract.GetFootprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		res := &soroban.SimulateTransactionResult{}
+		res.TransactionData, _ = xdr.MarshalBase64(xdr.SorobanTransactionData{})
+		res.RestorePreamble.MinResourceFee = 100
+		res.RestorePreamble.TransactionData, _ = xdr.MarshalBase64(xdr.SorobanTransactionData{
+			Resources: xdr.SorobanResources{Footprint: xdr.LedgerFootprint{ReadWrite: []xdr.LedgerKey{instance}}},
+		})
+		return res, nil
+	}
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		return &soroban.GetTransactionResult{Status: "FAILED", TxHash: hash}, nil
+	}
+	_, err = contract.Invoke().Function("hello").RestoreAndSend(context.Background())
+	var failed *soroban.TransactionFailedError
+	if !errors.As(err, &failed) {
+		t.Fatal(err)
+	}
+	// the invocation is not sent after the failed restore
+	if n := countCalls(fake, soroban.SendTransaction); n != 1 {
+		t.Fatal(fake.Calls)
+	}
+}
@@ -0,0 +1,22 @@
+package soroban
+
+import (
+	"context"
+	"log/slog"
+)
+
+var discardLogger = slog.New(slog.DiscardHandler)
+
+// logger returns the Logger of client, if it is Configured with one, or a
+// logger discarding everything
+func logger(client SorobanClient) *slog.Logger {
+	if c := config(client); c.Logger != nil {
+		return c.Logger
+	}
+	return discardLogger
+}
+
+// debug logs msg at debug level to the Logger of client
+func debug(ctx context.Context, client SorobanClient, msg string, attrs ...slog.Attr) {
+	logger(client).LogAttrs(ctx, slog.LevelDebug, msg, attrs...)
+}
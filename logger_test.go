@@ -0,0 +1,89 @@
+package soroban_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestLogger(t *testing.T) {
+	data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		result := `{"hash":"abc","status":"PENDING","latestLedger":7}`
+		switch req.Method {
+		case soroban.SimulateTransaction:
+			result = fmt.Sprintf(`{"transactionData":%q,"minResourceFee":"250","latestLedger":7}`, data)
+		case soroban.GetTransaction:
+			result = `{"status":"SUCCESS","txHash":"abc","ledger":8}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	client.Logger = slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	pair := keypair.MustRandom()
+
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.InvokeHostFunction{HostFunction: xdr.HostFunction{
+			Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+			InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contractAddress(1), FunctionName: "hello"},
+		}})
+	if _, err := tx.Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := tx.Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var messages []string
+	records := map[string]map[string]any{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		msg := record["msg"].(string)
+		messages = append(messages, msg)
+		records[msg] = record
+	}
+	// the stages of the submission are logged to the same logger
+	expected := []string{
+		"rpc call", "simulated", "fee", "soroban submission assembled", "rpc call", "soroban submission submitted",
+		"rpc call", "poll transaction", "soroban submission confirmed",
+	}
+	if strings.Join(messages, ",") != strings.Join(expected, ",") {
+		t.Fatal(messages)
+	}
+	if records["simulated"]["minResourceFee"] != float64(250) || records["fee"]["resourceFee"] != float64(250) ||
+		records["poll transaction"]["status"] != "SUCCESS" || records["rpc call"]["method"] != soroban.GetTransaction ||
+		records["rpc call"]["id"] == "" {
+		t.Fatal(logs.String())
+	}
+}
@@ -0,0 +1,44 @@
+package soroban
+
+import (
+	"crypto/sha256"
+	"errors"
+
+	"github.com/stellar/go/keypair"
+)
+
+// MessagePrefix is prepended to off-chain messages before hashing, as
+// defined by SEP-53, so a signed message can never be a valid transaction
+// signature.
+const MessagePrefix = "Stellar Signed Message:\n"
+
+const (
+	ErrorInvalidMessageSignature = "Invalid message signature"
+)
+
+// MessageHash returns the SEP-53 hash of the message:
+// sha256(MessagePrefix + message)
+func MessageHash(message []byte) [32]byte {
+	return sha256.Sum256(append([]byte(MessagePrefix), message...))
+}
+
+// SignMessage signs an arbitrary message with the key pair, following SEP-53
+// https://github.com/stellar/stellar-protocol/blob/master/ecosystem/sep-0053.md
+func SignMessage(kp *keypair.Full, message []byte) ([]byte, error) {
+	hash := MessageHash(message)
+	return kp.Sign(hash[:])
+}
+
+// VerifyMessage verifies the SEP-53 signature of message by the account
+// address (G...)
+func VerifyMessage(address string, message []byte, signature []byte) error {
+	kp, err := keypair.ParseAddress(address)
+	if err != nil {
+		return err
+	}
+	hash := MessageHash(message)
+	if err := kp.Verify(hash[:], signature); err != nil {
+		return errors.New(ErrorInvalidMessageSignature)
+	}
+	return nil
+}
@@ -0,0 +1,28 @@
+package soroban_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+)
+
+// Test vector from SEP-53
+func TestSignMessage(t *testing.T) {
+	kp := keypair.MustParseFull("SAKICEVQLYWGSOJS4WW7HZJWAHZVEEBS527LHK5V4MLJALYKICQCJXMW")
+	signature, err := soroban.SignMessage(kp, []byte("Hello, World!"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "fO5dbYhXUhBMhe6kId/cuVq/AfEnHRHEvsP8vXh03M1uLpi5e46yO2Q8rEBzu3feXQewcQE5GArp88u6ePK6BA=="
+	if base64.StdEncoding.EncodeToString(signature) != expected {
+		t.Fatal(base64.StdEncoding.EncodeToString(signature))
+	}
+	if err := soroban.VerifyMessage(kp.Address(), []byte("Hello, World!"), signature); err != nil {
+		t.Fatal(err)
+	}
+	if err := soroban.VerifyMessage(kp.Address(), []byte("Hello, World?"), signature); err == nil {
+		t.Fatal("expected invalid signature")
+	}
+}
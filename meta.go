@@ -0,0 +1,197 @@
+package soroban
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorMetaUnsupportedVersion = "Transaction meta version not supported"
+	ErrorMetaNoSorobanMeta      = "Transaction meta has no soroban meta"
+	ErrorNoResult               = "No transaction result"
+)
+
+// DecodeTransactionMeta unmarshals a base64 TransactionMeta, as returned
+// in resultMetaXdr by getTransaction.
+func DecodeTransactionMeta(metaXdr string) (*xdr.TransactionMeta, error) {
+	var meta xdr.TransactionMeta
+	err := xdr.SafeUnmarshalBase64(metaXdr, &meta)
+	if err != nil {
+		return nil, err
+	}
+	return &meta, nil
+}
+
+// MetaReturnValue returns the value returned by the invoked host function,
+// it works with V3 and V4 (protocol 23) meta.
+func MetaReturnValue(meta xdr.TransactionMeta) (*xdr.ScVal, error) {
+	switch meta.V {
+	case 3:
+		if meta.V3.SorobanMeta == nil {
+			return nil, errors.New(ErrorMetaNoSorobanMeta)
+		}
+		return &meta.V3.SorobanMeta.ReturnValue, nil
+	case 4:
+		if meta.V4.SorobanMeta == nil || meta.V4.SorobanMeta.ReturnValue == nil {
+			return nil, errors.New(ErrorMetaNoSorobanMeta)
+		}
+		return meta.V4.SorobanMeta.ReturnValue, nil
+	}
+	return nil, fmt.Errorf("%s: %d", ErrorMetaUnsupportedVersion, meta.V)
+}
+
+// MetaContractEvents returns all the contract events emitted by the transaction,
+// V4 meta has them per operation, they are returned in operation order.
+func MetaContractEvents(meta xdr.TransactionMeta) ([]xdr.ContractEvent, error) {
+	switch meta.V {
+	case 0, 1, 2:
+		return nil, nil
+	case 3:
+		if meta.V3.SorobanMeta == nil {
+			return nil, nil
+		}
+		return meta.V3.SorobanMeta.Events, nil
+	case 4:
+		var events []xdr.ContractEvent
+		for _, op := range meta.V4.Operations {
+			events = append(events, op.Events...)
+		}
+		return events, nil
+	}
+	return nil, fmt.Errorf("%s: %d", ErrorMetaUnsupportedVersion, meta.V)
+}
+
+// MetaDiagnosticEvents returns the diagnostic events of the transaction, if any.
+func MetaDiagnosticEvents(meta xdr.TransactionMeta) ([]xdr.DiagnosticEvent, error) {
+	switch meta.V {
+	case 0, 1, 2:
+		return nil, nil
+	case 3, 4:
+		return meta.GetDiagnosticEvents()
+	}
+	return nil, fmt.Errorf("%s: %d", ErrorMetaUnsupportedVersion, meta.V)
+}
+
+// MetaOperationChanges returns the ledger entry changes of every operation,
+// none if the meta of its version is not set.
+func MetaOperationChanges(meta xdr.TransactionMeta) ([]xdr.LedgerEntryChanges, error) {
+	var changes []xdr.LedgerEntryChanges
+	switch {
+	case meta.V < 0 || meta.V > 4:
+		return nil, fmt.Errorf("%s: %d", ErrorMetaUnsupportedVersion, meta.V)
+	case meta.V == 0 && meta.Operations != nil:
+		for _, op := range *meta.Operations {
+			changes = append(changes, op.Changes)
+		}
+	case meta.V == 1 && meta.V1 != nil:
+		for _, op := range meta.V1.Operations {
+			changes = append(changes, op.Changes)
+		}
+	case meta.V == 2 && meta.V2 != nil:
+		for _, op := range meta.V2.Operations {
+			changes = append(changes, op.Changes)
+		}
+	case meta.V == 3 && meta.V3 != nil:
+		for _, op := range meta.V3.Operations {
+			changes = append(changes, op.Changes)
+		}
+	case meta.V == 4 && meta.V4 != nil:
+		for _, op := range meta.V4.Operations {
+			changes = append(changes, op.Changes)
+		}
+	}
+	return changes, nil
+}
+
+// OperationResults returns the operation results of a TransactionResult.
+// If the result wraps a fee bump, the inner transaction operation results
+// are returned.
+func OperationResults(result xdr.TransactionResult) ([]xdr.OperationResult, bool) {
+	switch result.Result.Code {
+	case xdr.TransactionResultCodeTxFeeBumpInnerSuccess, xdr.TransactionResultCodeTxFeeBumpInnerFailed:
+		return result.Result.InnerResultPair.Result.Result.GetResults()
+	}
+	return result.Result.GetResults()
+}
+
+// Meta decodes the ResultMetaXdr of the transaction.
+func (r GetTransactionResult) Meta() (*xdr.TransactionMeta, error) {
+	return DecodeTransactionMeta(r.ResultMetaXdr)
+}
+
+// Result decodes the ResultXdr of the transaction.
+func (r GetTransactionResult) Result() (*xdr.TransactionResult, error) {
+	var result xdr.TransactionResult
+	err := xdr.SafeUnmarshalBase64(r.ResultXdr, &result)
+	if err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// ReturnValue decodes the meta of the transaction and returns the value
+// returned by the invoked host function.
+func (r GetTransactionResult) ReturnValue() (*xdr.ScVal, error) {
+	meta, err := r.Meta()
+	if err != nil {
+		return nil, err
+	}
+	return MetaReturnValue(*meta)
+}
+
+// ReturnValue decodes the value returned by the simulated host function
+func (r SimulateTransactionResult) ReturnValue() (*xdr.ScVal, error) {
+	if len(r.Results) == 0 {
+		return nil, errors.New(ErrorNoResult)
+	}
+	if r.decoded {
+		return &r.Results[0].Value, nil
+	}
+	var val xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(r.Results[0].XDR, &val); err != nil {
+		return nil, err
+	}
+	return &val, nil
+}
+
+// ResultAs decodes the value returned by the invoked host function of res
+// into a T with FromScVal, e.g. a string, bool, big.Int, slice, map or struct
+// with `scval` tags. A value of another type fails with ErrorScValTypeMismatch.
+//
+//	Example:
+//	 res, err := pending.Wait(ctx)
+//	 balance, err := soroban.ResultAs[big.Int](res)
+func ResultAs[T any](res *GetTransactionResult) (T, error) {
+	var v T
+	if res == nil {
+		return v, errors.New(ErrorNoResult)
+	}
+	val, err := res.ReturnValue()
+	if err != nil {
+		return v, err
+	}
+	return ScValAs[T](*val)
+}
+
+// ReturnBigInt returns the integer returned by the invoked host function,
+// e.g. an i128 amount, see ScValToBigInt
+func (r GetTransactionResult) ReturnBigInt() (*big.Int, error) {
+	val, err := r.ReturnValue()
+	if err != nil {
+		return nil, err
+	}
+	return ScValToBigInt(*val)
+}
+
+// ReturnDecimal returns the integer returned by the invoked host function as
+// a decimal string with scale digits after the point, see ScValToDecimal
+func (r GetTransactionResult) ReturnDecimal(scale int) (string, error) {
+	n, err := r.ReturnBigInt()
+	if err != nil {
+		return "", err
+	}
+	return FormatDecimal(n, scale), nil
+}
@@ -0,0 +1,98 @@
+package soroban_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestMetaReturnValue(t *testing.T) {
+	world := xdr.ScSymbol("World")
+	value := xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &world}
+
+	metas := []xdr.TransactionMeta{
+		{V: 3, V3: &xdr.TransactionMetaV3{SorobanMeta: &xdr.SorobanTransactionMeta{ReturnValue: value}}},
+		{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &value}}},
+	}
+	for _, meta := range metas {
+		metaXdr, err := xdr.MarshalBase64(meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := soroban.GetTransactionResult{ResultMetaXdr: metaXdr}.ReturnValue()
+		if err != nil {
+			t.Fatal(meta.V, err)
+		}
+		if *res.Sym != world {
+			t.Fatal(meta.V, "Missmatch result")
+		}
+	}
+
+	_, err := soroban.MetaReturnValue(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{}})
+	if err == nil {
+		t.Fatal("expected error without soroban meta")
+	}
+}
+
+func TestMetaOperationChanges(t *testing.T) {
+	changes := xdr.LedgerEntryChanges{{Type: xdr.LedgerEntryChangeTypeLedgerEntryRemoved, Removed: &xdr.LedgerKey{}}}
+	metas := []xdr.TransactionMeta{
+		{V: 0, Operations: &[]xdr.OperationMeta{{Changes: changes}}},
+		{V: 3, V3: &xdr.TransactionMetaV3{Operations: []xdr.OperationMeta{{Changes: changes}}}},
+	}
+	for _, meta := range metas {
+		res, err := soroban.MetaOperationChanges(meta)
+		if err != nil || len(res) != 1 || len(res[0]) != 1 {
+			t.Fatal(meta.V, res, err)
+		}
+	}
+	// the meta of the version is not set
+	for v := range int32(5) {
+		res, err := soroban.MetaOperationChanges(xdr.TransactionMeta{V: v})
+		if err != nil || res != nil {
+			t.Fatal(v, res, err)
+		}
+	}
+}
+
+func TestResultAs(t *testing.T) {
+	result := func(v any) *soroban.GetTransactionResult {
+		val, err := soroban.ToScVal(v)
+		if err != nil {
+			t.Fatal(err)
+		}
+		meta := xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &val}}}
+		metaXdr, err := xdr.MarshalBase64(meta)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return &soroban.GetTransactionResult{Status: "SUCCESS", ResultMetaXdr: metaXdr}
+	}
+
+	type position struct {
+		Owner  string   `scval:"owner"`
+		Amount big.Int  `scval:"amount"`
+		Tags   []string `scval:"tags"`
+	}
+	pos, err := soroban.ResultAs[position](result(position{Owner: "alice", Amount: *big.NewInt(7), Tags: []string{"a"}}))
+	if err != nil || pos.Owner != "alice" || pos.Amount.Int64() != 7 || len(pos.Tags) != 1 {
+		t.Fatal(pos, err)
+	}
+	balances, err := soroban.ResultAs[map[string]uint64](result(map[string]uint64{"alice": 1, "bob": 2}))
+	if err != nil || balances["bob"] != 2 {
+		t.Fatal(balances, err)
+	}
+	if ok, err := soroban.ResultAs[bool](result(true)); err != nil || !ok {
+		t.Fatal(ok, err)
+	}
+
+	if _, err := soroban.ResultAs[string](result(uint32(1))); err == nil || !strings.Contains(err.Error(), soroban.ErrorScValTypeMismatch) {
+		t.Fatal(err)
+	}
+	if _, err := soroban.ResultAs[string](nil); err == nil || err.Error() != soroban.ErrorNoResult {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+package soroban
+
+import "time"
+
+// Metrics receives the measurements of a Client, set it as Client.Metrics.
+// See the sorobanprom package for a Prometheus implementation. The methods
+// are called concurrently.
+type Metrics interface {
+	// RPCCall is called after every call with its method, its duration and
+	// its error, if any, including the retries of the RetryPolicy
+	RPCCall(method string, duration time.Duration, err error)
+	// Submitted is called with the status of every sendTransaction,
+	// PENDING, DUPLICATE, TRY_AGAIN_LATER or ERROR, or "" if it failed
+	Submitted(status string)
+	// Completed is called with the final status of every transaction waited
+	// for, SUCCESS, FAILED or NOT_FOUND, or "" if the polling failed
+	Completed(status string)
+	// SimulationFailed is called when a simulation fails
+	SimulationFailed(err error)
+	// Restored is called for every restore submitted on behalf of the
+	// client, of a contract or of the data of an invocation
+	Restored()
+}
+
+// metrics returns the Metrics of client, if it is Configured with one
+func metrics(client SorobanClient) Metrics {
+	if c := config(client); c.Metrics != nil {
+		return c.Metrics
+	}
+	return nopMetrics{}
+}
+
+// restored counts a restore once it is accepted by sendTransaction
+func restored(client SorobanClient, pending *PendingTransaction) {
+	if pending != nil && pending.Sent().Status == "PENDING" {
+		metrics(client).Restored()
+	}
+}
+
+type nopMetrics struct{}
+
+func (nopMetrics) RPCCall(string, time.Duration, error) {}
+func (nopMetrics) Submitted(string)                     {}
+func (nopMetrics) Completed(string)                     {}
+func (nopMetrics) SimulationFailed(error)               {}
+func (nopMetrics) Restored()                            {}
@@ -0,0 +1,131 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+// recordedMetrics records the measurements as strings
+type recordedMetrics struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (m *recordedMetrics) record(event string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.events = append(m.events, event)
+}
+
+func (m *recordedMetrics) RPCCall(method string, duration time.Duration, err error) {
+	m.record("rpc " + method)
+}
+func (m *recordedMetrics) Submitted(status string)    { m.record("submitted " + status) }
+func (m *recordedMetrics) Completed(status string)    { m.record("completed " + status) }
+func (m *recordedMetrics) SimulationFailed(err error) { m.record("simulation failed") }
+func (m *recordedMetrics) Restored()                  { m.record("restored") }
+
+func TestClientMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case soroban.SimulateTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"error":{"code":-32602,"message":"invalid"}}`, req.ID)
+		case soroban.SendTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"hash":"abc","status":"PENDING","latestLedger":10}}`, req.ID)
+		case soroban.GetTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"FAILED","txHash":"abc","ledger":11,"latestLedger":11}}`, req.ID)
+		}
+	}))
+	defer server.Close()
+	metrics := &recordedMetrics{}
+	client := &soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase, Metrics: metrics}
+
+	source := keypair.MustRandom()
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}).
+		Signer(source).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+	if _, err := tx.Simulate(context.Background()); err == nil {
+		t.Fatal("simulated")
+	}
+	pending, err := tx.Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	expected := []string{
+		"rpc " + soroban.SimulateTransaction, "simulation failed",
+		"rpc " + soroban.SendTransaction, "submitted PENDING",
+		"rpc " + soroban.GetTransaction, "completed FAILED",
+	}
+	if len(metrics.events) != len(expected) {
+		t.Fatal(metrics.events)
+	}
+	for i := range expected {
+		if metrics.events[i] != expected[i] {
+			t.Fatal(metrics.events)
+		}
+	}
+}
+
+func TestCallMetrics(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"healthy"}}`, req.ID)
+	}))
+	defer server.Close()
+	metrics := &recordedMetrics{}
+	client := &soroban.Client{Client: rpc.Client{URL: server.URL}, Metrics: metrics}
+	if _, err := client.Call(context.Background(), soroban.GetHealth); err != nil {
+		t.Fatal(err)
+	}
+	if len(metrics.events) != 1 || metrics.events[0] != "rpc "+soroban.GetHealth {
+		t.Fatal(metrics.events)
+	}
+}
+
+func TestRestoreMetrics(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	metrics := &recordedMetrics{}
+	fake.Options = &soroban.Client{Metrics: metrics}
+	status := "ERROR"
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "abc", Status: status, LatestLedger: latestLedger}, nil
+	}
+	// a rejected restore is not counted
+	if _, err := contract.Restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	status = "PENDING"
+	if _, err := contract.Restore(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	restored := 0
+	for _, event := range metrics.events {
+		if event == "restored" {
+			restored++
+		}
+	}
+	if restored != 1 {
+		t.Fatal(metrics.events)
+	}
+}
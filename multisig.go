@@ -0,0 +1,170 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"errors"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorEnvelopeMismatch = "Envelope is not the same transaction"
+	ErrorEnvelopeFeeBump  = "Fee bump envelopes can not be partially signed"
+	ErrorRequiredEnvelope = "Envelope is required, call SignPartial or LoadEnvelope"
+)
+
+// SignPartial signs the transaction with signers, adding the signatures to
+// the stored envelope, which is built on the first call. It returns the
+// envelope as base64 XDR, to share with the other signers, which add their
+// signatures after LoadEnvelope. Signatures already present are not repeated.
+func (t *Transaction) SignPartial(signers ...*keypair.Full) (string, error) {
+	if t.envelope == nil {
+		tx, err := t.buildTx()
+		if err != nil {
+			return "", err
+		}
+		t.envelope = tx
+	}
+	tx, err := t.addSignatures(t.envelope, signers)
+	if err != nil {
+		return "", err
+	}
+	t.envelope = tx
+	return tx.Base64()
+}
+
+// LoadEnvelope loads an envelope, as base64 XDR, signed elsewhere. If the
+// Transaction already has an envelope the signatures of both are merged, they
+// must be the same transaction.
+func (t *Transaction) LoadEnvelope(envelopeXdr string) error {
+	if t.client == nil {
+		return errors.New(ErrorRequiredClient)
+	}
+	generic, err := txnbuild.TransactionFromXDR(envelopeXdr)
+	if err != nil {
+		return err
+	}
+	tx, ok := generic.Transaction()
+	if !ok {
+		return errors.New(ErrorEnvelopeFeeBump)
+	}
+	if t.envelope == nil {
+		t.envelope = tx
+		return nil
+	}
+	hash, err := t.envelope.Hash(t.client.NetworkPassphrase())
+	if err != nil {
+		return err
+	}
+	loadedHash, err := tx.Hash(t.client.NetworkPassphrase())
+	if err != nil {
+		return err
+	}
+	if hash != loadedHash {
+		return errors.New(ErrorEnvelopeMismatch)
+	}
+	var missing []xdr.DecoratedSignature
+	for _, sig := range tx.Signatures() {
+		if !hasSignature(t.envelope.Signatures(), sig) {
+			missing = append(missing, sig)
+		}
+	}
+	merged, err := t.envelope.AddSignatureDecorated(missing...)
+	if err != nil {
+		return err
+	}
+	t.envelope = merged
+	return nil
+}
+
+// ReadyToSubmit checks the signatures of the stored envelope against the
+// signers and thresholds of the source account, fetched from the network.
+// The threshold is the highest required by the operations of the source
+// account.
+func (t *Transaction) ReadyToSubmit(ctx context.Context) (bool, *SignatureReport, error) {
+	if t.envelope == nil {
+		return false, nil, errors.New(ErrorRequiredEnvelope)
+	}
+	source := t.envelope.SourceAccount().AccountID
+	entry, err := t.client.GetAccountEntry(ctx, source)
+	if err != nil {
+		return false, nil, err
+	}
+	// the master key is not one of the signers of the entry
+	signers := map[string]int32{source: int32(entry.MasterKeyWeight())}
+	for _, s := range entry.Signers {
+		signers[s.Key.Address()] = int32(s.Weight)
+	}
+	envelopeXdr, err := t.envelope.Base64()
+	if err != nil {
+		return false, nil, err
+	}
+	report, err := VerifySignatures(envelopeXdr, t.client.NetworkPassphrase(), signers)
+	if err != nil {
+		return false, nil, err
+	}
+	thresholds := AccountThresholds{
+		LowThreshold:  entry.ThresholdLow(),
+		MedThreshold:  entry.ThresholdMedium(),
+		HighThreshold: entry.ThresholdHigh(),
+	}
+	threshold := int32(operationsThreshold(t.envelope.Operations(), source, thresholds))
+	if threshold == 0 {
+		threshold = 1
+	}
+	return report.MissingWeight(threshold) == 0, report, nil
+}
+
+func (t *Transaction) addSignatures(tx *txnbuild.Transaction, signers []*keypair.Full) (*txnbuild.Transaction, error) {
+	hash, err := tx.Hash(t.client.NetworkPassphrase())
+	if err != nil {
+		return nil, err
+	}
+	var signatures []xdr.DecoratedSignature
+	for _, signer := range signers {
+		sig, err := signer.SignDecorated(hash[:])
+		if err != nil {
+			return nil, err
+		}
+		if !hasSignature(tx.Signatures(), sig) && !hasSignature(signatures, sig) {
+			signatures = append(signatures, sig)
+		}
+	}
+	return tx.AddSignatureDecorated(signatures...)
+}
+
+func hasSignature(signatures []xdr.DecoratedSignature, sig xdr.DecoratedSignature) bool {
+	for _, s := range signatures {
+		if s.Hint == sig.Hint && bytes.Equal(s.Signature, sig.Signature) {
+			return true
+		}
+	}
+	return false
+}
+
+// operationsThreshold returns the highest threshold of the operations with
+// source as their source account, at least the low threshold required by
+// the transaction itself
+func operationsThreshold(ops []txnbuild.Operation, source string, thresholds AccountThresholds) byte {
+	threshold := thresholds.LowThreshold
+	for _, op := range ops {
+		if opSource := op.GetSourceAccount(); opSource != "" && opSource != source {
+			continue
+		}
+		t := thresholds.MedThreshold
+		switch op.(type) {
+		case *txnbuild.AccountMerge, *txnbuild.SetOptions:
+			t = thresholds.HighThreshold
+		case *txnbuild.BumpSequence, *txnbuild.AllowTrust, *txnbuild.SetTrustLineFlags,
+			*txnbuild.ExtendFootprintTtl, *txnbuild.RestoreFootprint:
+			t = thresholds.LowThreshold
+		}
+		if t > threshold {
+			threshold = t
+		}
+	}
+	return threshold
+}
@@ -0,0 +1,102 @@
+package soroban_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSignPartial(t *testing.T) {
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	source, alice, bob := keypair.MustRandom(), keypair.MustRandom(), keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(account).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+
+	envelope, err := tx.SignPartial(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// another process signs the shared envelope
+	other := soroban.NewTransctionBuilder().Client(client)
+	if err := other.LoadEnvelope(envelope); err != nil {
+		t.Fatal(err)
+	}
+	otherEnvelope, err := other.SignPartial(bob, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.LoadEnvelope(otherEnvelope); err != nil {
+		t.Fatal(err)
+	}
+	merged, err := tx.SignPartial(alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signers := map[string]int32{alice.Address(): 1, bob.Address(): 1, source.Address(): 1}
+	report, err := soroban.VerifySignatures(merged, client.PassPhrase, signers)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Weight != 2 || len(report.Unmatched) != 0 || report.Missing[source.Address()] != 1 {
+		t.Fatal(report)
+	}
+
+	unrelated := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 5}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+	unrelatedEnvelope, err := unrelated.SignPartial(bob)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.LoadEnvelope(unrelatedEnvelope); err == nil {
+		t.Fatal("merged a different transaction")
+	}
+}
+
+func TestLoadEnvelopeNoClient(t *testing.T) {
+	if err := soroban.NewTransctionBuilder().LoadEnvelope(""); err == nil || err.Error() != soroban.ErrorRequiredClient {
+		t.Fatal(err)
+	}
+}
+
+func TestReadyToSubmit(t *testing.T) {
+	source, alice := keypair.MustRandom(), keypair.MustRandom()
+	entryXdr, _ := xdr.MarshalBase64(xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{
+		AccountId:  xdr.MustAddress(source.Address()),
+		SeqNum:     1,
+		Thresholds: xdr.Thresholds{1, 2, 2, 2},
+		Signers:    []xdr.Signer{{Key: xdr.MustSigner(alice.Address()), Weight: 1}},
+	}})
+	client, _ := replayServer(t, map[string]any{
+		soroban.GetLedgerEntries: map[string]any{"latestLedger": 100, "entries": []map[string]any{{"xdr": entryXdr}}},
+	})
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+
+	if _, err := tx.SignPartial(alice); err != nil {
+		t.Fatal(err)
+	}
+	ready, report, err := tx.ReadyToSubmit(context.Background())
+	if err != nil || ready || report.Missing[source.Address()] != 1 {
+		t.Fatal(ready, report, err)
+	}
+	// the master key adds its weight
+	if _, err := tx.SignPartial(source); err != nil {
+		t.Fatal(err)
+	}
+	if ready, report, err := tx.ReadyToSubmit(context.Background()); err != nil || !ready {
+		t.Fatal(ready, report, err)
+	}
+}
@@ -0,0 +1,31 @@
+package soroban
+
+import (
+	"context"
+	"fmt"
+)
+
+const (
+	ErrorPassphraseMismatch = "Network passphrase does not match the node"
+)
+
+// AutoConfigure calls getNetwork once and sets the PassPhrase, the
+// FriendbotURL (if not set) and the ProtocolVersion of the client.
+// A PassPhrase already set must match the one of the node, else it fails with
+// ErrorPassphraseMismatch and c is left unchanged, instead of every
+// transaction failing later with a bad signature.
+func (c *Client) AutoConfigure(ctx context.Context) error {
+	network, err := c.GetNetwork(ctx)
+	if err != nil {
+		return err
+	}
+	if c.PassPhrase != "" && c.PassPhrase != network.Passphrase {
+		return fmt.Errorf("%s: %q, the node is on %q", ErrorPassphraseMismatch, c.PassPhrase, network.Passphrase)
+	}
+	c.PassPhrase = network.Passphrase
+	if c.FriendbotURL == "" {
+		c.FriendbotURL = network.FriendbotURL
+	}
+	c.ProtocolVersion = network.ProtocolVersion
+	return nil
+}
@@ -0,0 +1,48 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/network"
+)
+
+func TestAutoConfigure(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		calls++
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"passphrase":%q,"friendbotUrl":"https://friendbot.stellar.org/","protocolVersion":23}}`,
+			req.ID, network.TestNetworkPassphrase)
+	}))
+	defer server.Close()
+
+	client := &soroban.Client{}
+	client.URL = server.URL
+	if err := client.AutoConfigure(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if client.PassPhrase != network.TestNetworkPassphrase || client.FriendbotURL != "https://friendbot.stellar.org/" ||
+		client.ProtocolVersion != 23 || calls != 1 {
+		t.Fatal(client.PassPhrase, client.FriendbotURL, client.ProtocolVersion, calls)
+	}
+
+	client = &soroban.Client{PassPhrase: network.PublicNetworkPassphrase}
+	client.URL = server.URL
+	err := client.AutoConfigure(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorPassphraseMismatch) {
+		t.Fatal(err)
+	}
+	if client.PassPhrase != network.PublicNetworkPassphrase || client.ProtocolVersion != 0 {
+		t.Fatal("client changed")
+	}
+}
@@ -0,0 +1,257 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorOutboxArgs = "Invalid outbox entry args"
+)
+
+// OutboxStatusAbandoned is the status of an OutboxResult whose invocation
+// failed MaxAttempts times without a final transaction
+const OutboxStatusAbandoned = "ABANDONED"
+
+// OutboxEntry is an intended invocation stored in an Outbox
+type OutboxEntry struct {
+	ID       string
+	Function string
+	// Args are base64 XDR ScVal
+	Args []string
+	// Attempts counts the claims of the entry, including the current one
+	Attempts int
+	// Hash is the transaction submitted by a previous attempt, if any
+	Hash string
+}
+
+// OutboxResult is the outcome of an OutboxEntry
+type OutboxResult struct {
+	Hash string
+	// Status is SUCCESS or FAILED, or OutboxStatusAbandoned
+	Status string
+	// ReturnValue is the base64 XDR ScVal returned on SUCCESS
+	ReturnValue string
+	Error       string
+}
+
+// Outbox stores the intended invocations of a contract, written by the
+// application, usually in the same database transaction as its own data,
+// until an OutboxWorker submits them. See SQLOutbox.
+type Outbox interface {
+	// Claim returns up to limit pending entries, hidden from other claims
+	// for lease, and increments their attempts
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEntry, error)
+	// Submitted records the hash of the transaction submitted for the entry,
+	// before waiting for it, so a later attempt does not submit it twice
+	Submitted(ctx context.Context, id string, hash string) error
+	// Complete stores the result of the entry and removes it from the
+	// pending ones, in one transaction
+	Complete(ctx context.Context, id string, result OutboxResult) error
+	// Retry returns the entry to the pending ones after a failed attempt
+	Retry(ctx context.Context, id string, cause error) error
+	// Defer returns the entry to the pending ones once delay elapsed,
+	// without counting the attempt, e.g. while the retry budget is
+	// exhausted
+	Defer(ctx context.Context, id string, delay time.Duration) error
+}
+
+// OutboxWorker submits the entries of an Outbox as invocations of a contract
+// and records their results.
+//
+//	Example:
+//	 worker := contract.OutboxWorker(outbox).Batch(10).Interval(time.Second)
+//	 err := worker.Run(ctx)
+type OutboxWorker struct {
+	contract    *Contract
+	outbox      Outbox
+	batch       int
+	lease       time.Duration
+	interval    time.Duration
+	maxAttempts int
+	onError     func(OutboxEntry, error)
+}
+
+// OutboxWorker returns a worker submitting the entries of outbox one at a
+// time, claiming 10 per poll with a lease of 5 minutes, polling every 5
+// seconds and abandoning an entry after 5 attempts
+func (c *Contract) OutboxWorker(outbox Outbox) *OutboxWorker {
+	return &OutboxWorker{
+		contract:    c,
+		outbox:      outbox,
+		batch:       10,
+		lease:       5 * time.Minute,
+		interval:    5 * time.Second,
+		maxAttempts: 5,
+	}
+}
+
+// Batch sets the number of entries claimed per poll
+func (w *OutboxWorker) Batch(n int) *OutboxWorker {
+	w.batch = n
+	return w
+}
+
+// Lease sets how long the claimed entries are hidden from other workers. It
+// must exceed the time to process a batch, including the Contract Timeout of
+// every transaction, so an interrupted submission expires before an entry is
+// submitted again.
+func (w *OutboxWorker) Lease(lease time.Duration) *OutboxWorker {
+	w.lease = lease
+	return w
+}
+
+// Interval sets the time between polls of an empty outbox, or of one whose
+// entries are all deferred by the retry budget
+func (w *OutboxWorker) Interval(interval time.Duration) *OutboxWorker {
+	w.interval = interval
+	return w
+}
+
+// MaxAttempts sets the attempts of an entry before it is completed as
+// OutboxStatusAbandoned
+func (w *OutboxWorker) MaxAttempts(n int) *OutboxWorker {
+	w.maxAttempts = n
+	return w
+}
+
+// OnError sets a callback for the failed attempts, that are retried, the
+// failures of the outbox itself and the *AuditError of the submitted
+// transactions, whose result is still recorded
+func (w *OutboxWorker) OnError(f func(OutboxEntry, error)) *OutboxWorker {
+	w.onError = f
+	return w
+}
+
+// Run processes the outbox until ctx is done
+func (w *OutboxWorker) Run(ctx context.Context) error {
+	for {
+		n, err := w.Process(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		if err != nil {
+			w.failed(OutboxEntry{}, err)
+		}
+		if n > 0 && err == nil {
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(w.interval):
+		}
+	}
+}
+
+// Process claims one batch of entries and processes them, returning the
+// number processed, without the ones deferred by the retry budget
+func (w *OutboxWorker) Process(ctx context.Context) (int, error) {
+	entries, err := w.outbox.Claim(ctx, w.batch, w.lease)
+	if err != nil {
+		return 0, err
+	}
+	n := 0
+	var errs []error
+	for _, entry := range entries {
+		processed, err := w.process(ctx, entry)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		if processed {
+			n++
+		}
+	}
+	return n, errors.Join(errs...)
+}
+
+// process submits entry, or finds the transaction of a previous attempt, and
+// records the result. It returns false if the entry is deferred by the retry
+// budget. Only the errors of the outbox are returned.
+func (w *OutboxWorker) process(ctx context.Context, entry OutboxEntry) (bool, error) {
+	if entry.Hash != "" {
+		res, err := w.contract.client.GetTransaction(ctx, entry.Hash)
+		if err != nil {
+			return true, w.retry(ctx, entry, err)
+		}
+		if res.Status != "NOT_FOUND" {
+			return true, w.outbox.Complete(ctx, entry.ID, outboxResult(entry.Hash, res))
+		}
+	}
+
+	args := make([]xdr.ScVal, len(entry.Args))
+	for i, arg := range entry.Args {
+		if err := xdr.SafeUnmarshalBase64(arg, &args[i]); err != nil {
+			return true, w.outbox.Complete(ctx, entry.ID, OutboxResult{
+				Status: OutboxStatusAbandoned,
+				Error:  fmt.Sprintf("%s: %s", ErrorOutboxArgs, err),
+			})
+		}
+	}
+	if entry.Attempts > 1 {
+		// a resubmission, it waits for the retry budget without being
+		// abandoned
+		if err := spendRetry(w.contract.client); err != nil {
+			w.failed(entry, err)
+			return false, w.outbox.Defer(ctx, entry.ID, w.interval)
+		}
+	}
+	// a pending transaction with an *AuditError is submitted
+	pending, err := w.contract.Invoke().Function(entry.Function).WithArgs(args...).Send(ctx)
+	if pending == nil {
+		return true, w.retry(ctx, entry, err)
+	}
+	if err != nil {
+		w.failed(entry, err)
+	}
+	entry.Hash = pending.Hash()
+	if err := w.outbox.Submitted(ctx, entry.ID, entry.Hash); err != nil {
+		// the transaction is in flight, its result is still recorded
+		w.failed(entry, err)
+	}
+	res, err := pending.Wait(ctx)
+	if err != nil {
+		return true, w.retry(ctx, entry, err)
+	}
+	return true, w.outbox.Complete(ctx, entry.ID, outboxResult(entry.Hash, res))
+}
+
+// retry returns entry to the outbox, or abandons it after MaxAttempts
+func (w *OutboxWorker) retry(ctx context.Context, entry OutboxEntry, cause error) error {
+	if ctx.Err() != nil {
+		// the lease expires, the next worker resumes it
+		return nil
+	}
+	w.failed(entry, cause)
+	if entry.Attempts >= w.maxAttempts {
+		return w.outbox.Complete(ctx, entry.ID, OutboxResult{Hash: entry.Hash, Status: OutboxStatusAbandoned, Error: cause.Error()})
+	}
+	return w.outbox.Retry(ctx, entry.ID, cause)
+}
+
+func (w *OutboxWorker) failed(entry OutboxEntry, err error) {
+	if w.onError != nil {
+		w.onError(entry, err)
+	}
+}
+
+func outboxResult(hash string, res *GetTransactionResult) OutboxResult {
+	result := OutboxResult{Hash: hash, Status: res.Status}
+	if res.Status != "SUCCESS" {
+		return result
+	}
+	value, err := res.ReturnValue()
+	if err != nil {
+		result.Error = err.Error()
+		return result
+	}
+	result.ReturnValue, err = xdr.MarshalBase64(*value)
+	if err != nil {
+		result.Error = err.Error()
+	}
+	return result
+}
@@ -0,0 +1,198 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// memoryOutbox hands out its entries once, or again once deferred,
+// recording the calls of the worker
+type memoryOutbox struct {
+	mu        sync.Mutex
+	entries   []soroban.OutboxEntry
+	claimed   map[string]soroban.OutboxEntry
+	claims    int
+	submitted map[string]string
+	results   map[string]soroban.OutboxResult
+	retried   []string
+	deferred  []string
+}
+
+func (o *memoryOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]soroban.OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.claims++
+	n := min(limit, len(o.entries))
+	claimed := o.entries[:n:n]
+	o.entries = o.entries[n:]
+	for _, entry := range claimed {
+		o.claimed[entry.ID] = entry
+	}
+	return claimed, nil
+}
+
+func (o *memoryOutbox) Submitted(ctx context.Context, id string, hash string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.submitted[id] = hash
+	return nil
+}
+
+func (o *memoryOutbox) Complete(ctx context.Context, id string, result soroban.OutboxResult) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.results[id] = result
+	return nil
+}
+
+func (o *memoryOutbox) Retry(ctx context.Context, id string, cause error) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.retried = append(o.retried, id)
+	return nil
+}
+
+func (o *memoryOutbox) Defer(ctx context.Context, id string, delay time.Duration) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.deferred = append(o.deferred, id)
+	o.entries = append(o.entries, o.claimed[id])
+	return nil
+}
+
+func newMemoryOutbox(entries ...soroban.OutboxEntry) *memoryOutbox {
+	return &memoryOutbox{
+		entries:   entries,
+		claimed:   make(map[string]soroban.OutboxEntry),
+		submitted: make(map[string]string),
+		results:   make(map[string]soroban.OutboxResult),
+	}
+}
+
+func TestOutboxWorker(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "new", Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		status := "SUCCESS"
+		if hash == "previous" {
+			status = "FAILED"
+		}
+		return &soroban.GetTransactionResult{Status: status, TxHash: hash, Ledger: latestLedger, LatestLedger: latestLedger}, nil
+	}
+	arg, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvVoid})
+	outbox := newMemoryOutbox(
+		soroban.OutboxEntry{ID: "a", Function: "hello", Args: []string{arg}, Attempts: 1},
+		// submitted by an interrupted attempt, it is not sent again
+		soroban.OutboxEntry{ID: "b", Function: "hello", Attempts: 2, Hash: "previous"},
+		soroban.OutboxEntry{ID: "c", Function: "hello", Args: []string{"not xdr"}, Attempts: 1},
+	)
+
+	n, err := contract.OutboxWorker(outbox).Batch(5).Process(context.Background())
+	if err != nil || n != 3 {
+		t.Fatal(n, err)
+	}
+	if fake.Count(soroban.SendTransaction) != 1 || outbox.submitted["a"] != "new" || len(outbox.submitted) != 1 {
+		t.Fatal(fake.Calls, outbox.submitted)
+	}
+	// the meta of the fake has no return value
+	if r := outbox.results["a"]; r.Status != "SUCCESS" || r.Hash != "new" {
+		t.Fatal(r)
+	}
+	if r := outbox.results["b"]; r.Status != "FAILED" || r.Hash != "previous" {
+		t.Fatal(r)
+	}
+	if r := outbox.results["c"]; r.Status != soroban.OutboxStatusAbandoned || r.Error == "" {
+		t.Fatal(r)
+	}
+}
+
+func TestOutboxWorkerRetries(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SimulateTransactionFunc = func(string) (*soroban.SimulateTransactionResult, error) {
+		return nil, errors.New("unavailable")
+	}
+	outbox := newMemoryOutbox(
+		soroban.OutboxEntry{ID: "a", Function: "hello", Attempts: 1},
+		soroban.OutboxEntry{ID: "b", Function: "hello", Attempts: 2},
+	)
+	var failures int
+	worker := contract.OutboxWorker(outbox).MaxAttempts(2).OnError(func(entry soroban.OutboxEntry, err error) {
+		failures++
+	})
+	if _, err := worker.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(outbox.retried) != 1 || outbox.retried[0] != "a" || failures != 2 {
+		t.Fatal(outbox.retried, failures)
+	}
+	if r := outbox.results["b"]; r.Status != soroban.OutboxStatusAbandoned || r.Error == "" {
+		t.Fatal(r)
+	}
+}
+
+func TestOutboxWorkerAuditError(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	full := errors.New("disk full")
+	// submitted, but not audited
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "new", Status: "PENDING", LatestLedger: latestLedger}, &soroban.AuditError{Err: full}
+	}
+	outbox := newMemoryOutbox(soroban.OutboxEntry{ID: "a", Function: "hello", Attempts: 1})
+	var failures []error
+	worker := contract.OutboxWorker(outbox).OnError(func(entry soroban.OutboxEntry, err error) {
+		failures = append(failures, err)
+	})
+	if _, err := worker.Process(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(outbox.retried) != 0 || outbox.submitted["a"] != "new" || outbox.results["a"].Status != "SUCCESS" {
+		t.Fatal(outbox.retried, outbox.submitted, outbox.results)
+	}
+	if len(failures) != 1 || !errors.Is(failures[0], full) {
+		t.Fatal(failures)
+	}
+}
+
+func TestOutboxWorkerRetryBudget(t *testing.T) {
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.RetryBudget = soroban.NewRetryBudget(1, 1)
+	client.RetryBudget.Allow()
+	contract := soroban.NewContract().Client(client).Address(xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &xdr.ContractId{1}})
+	outbox := newMemoryOutbox(soroban.OutboxEntry{ID: "a", Function: "hello", Attempts: 2})
+
+	var failures []error
+	worker := contract.OutboxWorker(outbox).MaxAttempts(2).OnError(func(entry soroban.OutboxEntry, err error) {
+		failures = append(failures, err)
+	})
+	n, err := worker.Process(context.Background())
+	if err != nil || n != 0 {
+		t.Fatal(n, err)
+	}
+	// not abandoned nor retried, it waits for the budget
+	if len(outbox.deferred) != 1 || len(outbox.retried) != 0 || len(outbox.results) != 0 || len(failures) != 1 || !errors.Is(failures[0], soroban.ErrRetryBudgetExhausted) {
+		t.Fatal(outbox.deferred, outbox.retried, outbox.results, failures)
+	}
+
+	// a pass deferring every entry waits for the interval before claiming
+	// again
+	ctx, cancel := context.WithTimeout(context.Background(), 250*time.Millisecond)
+	defer cancel()
+	if err := worker.Interval(100 * time.Millisecond).Run(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+	if outbox.claims > 4 {
+		t.Fatal(outbox.claims)
+	}
+}
@@ -0,0 +1,201 @@
+package soroban
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/sqlquery"
+	"github.com/stellar/go/xdr"
+)
+
+// SQLOutboxSchema creates the table of SQLOutbox, status is empty while the
+// entry is pending and claimed_until is a unix time in milliseconds
+const SQLOutboxSchema = `CREATE TABLE IF NOT EXISTS %s (
+	id            VARCHAR(128) PRIMARY KEY,
+	function      VARCHAR(64)  NOT NULL,
+	args          TEXT         NOT NULL,
+	attempts      INTEGER      NOT NULL DEFAULT 0,
+	claimed_until BIGINT       NOT NULL DEFAULT 0,
+	hash          VARCHAR(64)  NOT NULL DEFAULT '',
+	status        VARCHAR(16)  NOT NULL DEFAULT '',
+	return_value  TEXT         NOT NULL DEFAULT '',
+	error         TEXT         NOT NULL DEFAULT '',
+	created_at    BIGINT       NOT NULL
+)`
+
+// SQLExecer is implemented by *sql.DB and *sql.Tx
+type SQLExecer interface {
+	ExecContext(ctx context.Context, query string, args ...any) (sql.Result, error)
+}
+
+// SQLOutbox is the reference Outbox on a database/sql table, see
+// SQLOutboxSchema. It only uses portable SQL, a claim is a conditional
+// update, so it works with any driver.
+//
+//	Example:
+//	 outbox := soroban.NewSQLOutbox(db, "soroban_outbox").NumberedPlaceholders()
+//	 tx, _ := db.BeginTx(ctx, nil)
+//	 // ... the application writes its own data in tx
+//	 outbox.Enqueue(ctx, tx, orderID, "transfer", from, to, amount)
+//	 tx.Commit()
+type SQLOutbox struct {
+	db       *sql.DB
+	table    string
+	numbered bool
+	backoff  time.Duration
+	onResult func(ctx context.Context, tx *sql.Tx, id string, result OutboxResult) error
+}
+
+// DefaultSQLOutboxRetryBackoff is the delay before a failed entry is claimed
+// again, per attempt, when SQLOutbox RetryBackoff is not set
+const DefaultSQLOutboxRetryBackoff = 5 * time.Second
+
+// NewSQLOutbox returns an Outbox on table of db
+func NewSQLOutbox(db *sql.DB, table string) *SQLOutbox {
+	return &SQLOutbox{db: db, table: table, backoff: DefaultSQLOutboxRetryBackoff}
+}
+
+// NumberedPlaceholders uses $1, $2... placeholders, e.g. for PostgreSQL,
+// instead of ?
+func (o *SQLOutbox) NumberedPlaceholders() *SQLOutbox {
+	o.numbered = true
+	return o
+}
+
+// RetryBackoff sets the delay before a failed entry is claimed again, it is
+// multiplied by the attempts of the entry
+func (o *SQLOutbox) RetryBackoff(backoff time.Duration) *SQLOutbox {
+	o.backoff = backoff
+	return o
+}
+
+// OnResult sets a callback run in the transaction that completes an entry,
+// so the application records the result in its own tables atomically
+func (o *SQLOutbox) OnResult(f func(ctx context.Context, tx *sql.Tx, id string, result OutboxResult) error) *SQLOutbox {
+	o.onResult = f
+	return o
+}
+
+// CreateTable creates the table if it does not exist
+func (o *SQLOutbox) CreateTable(ctx context.Context) error {
+	_, err := o.db.ExecContext(ctx, fmt.Sprintf(SQLOutboxSchema, o.table))
+	return err
+}
+
+// Enqueue writes the invocation of function with args, identified by id, in
+// exec, usually the transaction of the application data
+func (o *SQLOutbox) Enqueue(ctx context.Context, exec SQLExecer, id string, function string, args ...xdr.ScVal) error {
+	encoded := make([]string, len(args))
+	for i, arg := range args {
+		s, err := xdr.MarshalBase64(arg)
+		if err != nil {
+			return err
+		}
+		encoded[i] = s
+	}
+	b, err := json.Marshal(encoded)
+	if err != nil {
+		return err
+	}
+	_, err = exec.ExecContext(ctx, o.query("INSERT INTO %s (id, function, args, created_at) VALUES (?, ?, ?, ?)"),
+		id, function, string(b), time.Now().UnixMilli())
+	return err
+}
+
+// Claim implements Outbox
+func (o *SQLOutbox) Claim(ctx context.Context, limit int, lease time.Duration) ([]OutboxEntry, error) {
+	now := time.Now()
+	rows, err := o.db.QueryContext(ctx,
+		o.query("SELECT id, function, args, attempts, hash FROM %s WHERE status = '' AND claimed_until < ? ORDER BY created_at LIMIT ?"),
+		now.UnixMilli(), limit)
+	if err != nil {
+		return nil, err
+	}
+	var candidates []OutboxEntry
+	for rows.Next() {
+		var entry OutboxEntry
+		var args string
+		if err := rows.Scan(&entry.ID, &entry.Function, &args, &entry.Attempts, &entry.Hash); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(args), &entry.Args); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("%s: %s: %w", ErrorOutboxArgs, entry.ID, err)
+		}
+		candidates = append(candidates, entry)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// another worker may claim the same candidates, the update only
+	// succeeds for one of them
+	entries := make([]OutboxEntry, 0, len(candidates))
+	for _, entry := range candidates {
+		res, err := o.db.ExecContext(ctx,
+			o.query("UPDATE %s SET claimed_until = ?, attempts = attempts + 1 WHERE id = ? AND status = '' AND claimed_until < ?"),
+			now.Add(lease).UnixMilli(), entry.ID, now.UnixMilli())
+		if err != nil {
+			return entries, err
+		}
+		if n, err := res.RowsAffected(); err != nil || n != 1 {
+			continue
+		}
+		entry.Attempts++
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Submitted implements Outbox
+func (o *SQLOutbox) Submitted(ctx context.Context, id string, hash string) error {
+	_, err := o.db.ExecContext(ctx, o.query("UPDATE %s SET hash = ? WHERE id = ?"), hash, id)
+	return err
+}
+
+// Complete implements Outbox, with the OnResult callback in the same
+// transaction
+func (o *SQLOutbox) Complete(ctx context.Context, id string, result OutboxResult) error {
+	tx, err := o.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.ExecContext(ctx,
+		o.query("UPDATE %s SET status = ?, hash = ?, return_value = ?, error = ?, claimed_until = 0 WHERE id = ?"),
+		result.Status, result.Hash, result.ReturnValue, result.Error, id)
+	if err != nil {
+		return err
+	}
+	if o.onResult != nil {
+		if err := o.onResult(ctx, tx, id, result); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Defer implements Outbox, the attempt counted by the claim is taken back
+func (o *SQLOutbox) Defer(ctx context.Context, id string, delay time.Duration) error {
+	_, err := o.db.ExecContext(ctx, o.query("UPDATE %s SET claimed_until = ?, attempts = attempts - 1 WHERE id = ?"),
+		time.Now().Add(delay).UnixMilli(), id)
+	return err
+}
+
+// Retry implements Outbox, the entry is claimed again after RetryBackoff
+// times its attempts
+func (o *SQLOutbox) Retry(ctx context.Context, id string, cause error) error {
+	_, err := o.db.ExecContext(ctx, o.query("UPDATE %s SET claimed_until = ? + attempts * ?, error = ? WHERE id = ?"),
+		time.Now().UnixMilli(), o.backoff.Milliseconds(), cause.Error(), id)
+	return err
+}
+
+// query sets the table of q, and numbers its placeholders if needed
+func (o *SQLOutbox) query(q string) string {
+	return sqlquery.Format(o.table, q, o.numbered)
+}
@@ -0,0 +1,131 @@
+//go:build cgo
+
+package soroban_test
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func openSQLite(t *testing.T) *sql.DB {
+	t.Helper()
+	db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "soroban.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func enqueue(t *testing.T, db *sql.DB, outbox *soroban.SQLOutbox, id string, args ...xdr.ScVal) {
+	t.Helper()
+	ctx := context.Background()
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := outbox.Enqueue(ctx, tx, id, "hello", args...); err != nil {
+		t.Fatal(err)
+	}
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestSQLOutbox(t *testing.T) {
+	for _, numbered := range []bool{false, true} {
+		// sqlite binds $1, $2... by position too
+		db := openSQLite(t)
+		outbox := soroban.NewSQLOutbox(db, "outbox").RetryBackoff(time.Hour)
+		if numbered {
+			outbox.NumberedPlaceholders()
+		}
+		ctx := context.Background()
+		if err := outbox.CreateTable(ctx); err != nil {
+			t.Fatal(err)
+		}
+		enqueue(t, db, outbox, "a", sym("World"))
+		enqueue(t, db, outbox, "b")
+
+		entries, err := outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(entries) != 2 {
+			t.Fatal(numbered, entries, err)
+		}
+		a := entries[0]
+		if a.ID != "a" || a.Function != "hello" || a.Attempts != 1 || len(a.Args) != 1 || len(entries[1].Args) != 0 {
+			t.Fatal(numbered, entries)
+		}
+		// leased
+		if entries, err := outbox.Claim(ctx, 10, time.Minute); err != nil || len(entries) != 0 {
+			t.Fatal(numbered, entries, err)
+		}
+
+		if err := outbox.Submitted(ctx, "a", "abc"); err != nil {
+			t.Fatal(err)
+		}
+		// a failed entry waits for the backoff, a deferred one for its delay
+		if err := outbox.Retry(ctx, "a", errors.New("failed")); err != nil {
+			t.Fatal(err)
+		}
+		if err := outbox.Defer(ctx, "b", 0); err != nil {
+			t.Fatal(err)
+		}
+		time.Sleep(time.Millisecond)
+		entries, err = outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(entries) != 1 || entries[0].ID != "b" || entries[0].Attempts != 1 {
+			t.Fatal(numbered, entries, err)
+		}
+
+		var recorded []string
+		outbox.OnResult(func(ctx context.Context, tx *sql.Tx, id string, result soroban.OutboxResult) error {
+			recorded = append(recorded, id+" "+result.Status)
+			return nil
+		})
+		if err := outbox.Complete(ctx, "b", soroban.OutboxResult{Hash: "def", Status: "SUCCESS"}); err != nil {
+			t.Fatal(err)
+		}
+		if len(recorded) != 1 || recorded[0] != "b SUCCESS" {
+			t.Fatal(recorded)
+		}
+		var status, hash string
+		if err := db.QueryRow("SELECT status, hash FROM outbox WHERE id = 'b'").Scan(&status, &hash); err != nil || status != "SUCCESS" || hash != "def" {
+			t.Fatal(status, hash, err)
+		}
+		var claimedUntil int64
+		if err := db.QueryRow("SELECT claimed_until, hash FROM outbox WHERE id = 'a'").Scan(&claimedUntil, &hash); err != nil || hash != "abc" {
+			t.Fatal(hash, err)
+		}
+		if claimedUntil < time.Now().Add(time.Hour-time.Minute).UnixMilli() {
+			t.Fatal("retried without backoff", claimedUntil)
+		}
+	}
+}
+
+func TestSQLOutboxOnResultRollback(t *testing.T) {
+	db := openSQLite(t)
+	outbox := soroban.NewSQLOutbox(db, "outbox")
+	ctx := context.Background()
+	if err := outbox.CreateTable(ctx); err != nil {
+		t.Fatal(err)
+	}
+	enqueue(t, db, outbox, "a")
+	failed := errors.New("failed")
+	outbox.OnResult(func(ctx context.Context, tx *sql.Tx, id string, result soroban.OutboxResult) error {
+		return failed
+	})
+	if err := outbox.Complete(ctx, "a", soroban.OutboxResult{Status: "SUCCESS"}); !errors.Is(err, failed) {
+		t.Fatal(err)
+	}
+	// still pending
+	if entries, err := outbox.Claim(ctx, 10, time.Minute); err != nil || len(entries) != 1 {
+		t.Fatal(entries, err)
+	}
+}
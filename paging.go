@@ -0,0 +1,96 @@
+package soroban
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+const (
+	ErrorInvalidPagingToken = "Invalid paging token"
+)
+
+// TOID is the Horizon total order id of an operation: its ledger, the
+// application order of its transaction in the ledger (from 1) and its index
+// in the transaction (from 1, 0 for the transaction itself). Ordering by TOID
+// orders by execution.
+type TOID struct {
+	Ledger           int64
+	ApplicationOrder int64
+	OperationIndex   int64
+}
+
+const (
+	toidLedgerShift = 32
+	toidOrderShift  = 12
+	toidOrderMask   = 1<<20 - 1
+	toidIndexMask   = 1<<12 - 1
+)
+
+// PagingToken returns the Horizon style paging token of id, its decimal
+// int64 encoding
+func (id TOID) PagingToken() string {
+	return strconv.FormatInt(id.Ledger<<toidLedgerShift|id.ApplicationOrder<<toidOrderShift|id.OperationIndex, 10)
+}
+
+// ParsePagingToken decodes a token of PagingToken, or the id of an Event
+// (the RPC cursor), whose event index is ignored
+func ParsePagingToken(token string) (TOID, error) {
+	toid, _, _ := strings.Cut(token, "-")
+	n, err := strconv.ParseInt(toid, 10, 64)
+	if err != nil || n < 0 {
+		return TOID{}, fmt.Errorf("%s: %s", ErrorInvalidPagingToken, token)
+	}
+	return TOID{
+		Ledger:           n >> toidLedgerShift,
+		ApplicationOrder: n >> toidOrderShift & toidOrderMask,
+		OperationIndex:   n & toidIndexMask,
+	}, nil
+}
+
+// PagingToken returns the paging token of the transaction, from its ledger
+// and application order. It is stable, an export can resume after it.
+func (r GetTransactionResult) PagingToken() string {
+	return TOID{Ledger: r.Ledger, ApplicationOrder: r.ApplicationOrder}.PagingToken()
+}
+
+// PagingToken returns the paging token of the event, its id, to be used as
+// the cursor of GetEventsRequest to resume after it
+func (e Event) PagingToken() string {
+	return e.Id
+}
+
+// MarshalJSON adds the pagingToken of the transaction, once it is in a
+// ledger, so an export of the records can resume after any of them
+func (r GetTransactionResult) MarshalJSON() ([]byte, error) {
+	type plain GetTransactionResult
+	var token string
+	if r.Ledger != 0 {
+		token = r.PagingToken()
+	}
+	return json.Marshal(struct {
+		plain
+		PagingToken string `json:"pagingToken,omitempty"`
+	}{plain(r), token})
+}
+
+// MarshalJSON adds the pagingToken of the transaction, see
+// GetTransactionResult.MarshalJSON
+func (t TransactionInfo) MarshalJSON() ([]byte, error) {
+	type plain TransactionInfo
+	return json.Marshal(struct {
+		plain
+		PagingToken string `json:"pagingToken"`
+	}{plain(t), t.PagingToken()})
+}
+
+// MarshalJSON adds the pagingToken of the event, see
+// GetTransactionResult.MarshalJSON
+func (e Event) MarshalJSON() ([]byte, error) {
+	type plain Event
+	return json.Marshal(struct {
+		plain
+		PagingToken string `json:"pagingToken"`
+	}{plain(e), e.PagingToken()})
+}
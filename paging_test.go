@@ -0,0 +1,73 @@
+package soroban_test
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+)
+
+func TestPagingToken(t *testing.T) {
+	tx := soroban.GetTransactionResult{Ledger: 42, ApplicationOrder: 3}
+	token := tx.PagingToken()
+	if token != "180388638720" {
+		t.Fatal(token)
+	}
+	toid, err := soroban.ParsePagingToken(token)
+	if err != nil || toid != (soroban.TOID{Ledger: 42, ApplicationOrder: 3}) {
+		t.Fatal(toid, err)
+	}
+	next := soroban.GetTransactionResult{Ledger: 42, ApplicationOrder: 4}.PagingToken()
+	later := soroban.GetTransactionResult{Ledger: 1 << 20}.PagingToken()
+	n, _ := strconv.ParseInt(token, 10, 64)
+	nextN, _ := strconv.ParseInt(next, 10, 64)
+	laterN, _ := strconv.ParseInt(later, 10, 64)
+	if n >= nextN || nextN >= laterN {
+		t.Fatal("not ordered", token, next, later)
+	}
+
+	event := soroban.Event{Id: "0000180388638721-0000000002"}
+	toid, err = soroban.ParsePagingToken(event.PagingToken())
+	if err != nil || toid != (soroban.TOID{Ledger: 42, ApplicationOrder: 3, OperationIndex: 1}) {
+		t.Fatal(toid, err)
+	}
+
+	if _, err := soroban.ParsePagingToken("abc"); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorInvalidPagingToken) {
+		t.Fatal(err)
+	}
+}
+
+func TestPagingTokenJSON(t *testing.T) {
+	var tx struct {
+		TxHash      string `json:"txHash"`
+		PagingToken string `json:"pagingToken"`
+	}
+	b, _ := json.Marshal(soroban.TransactionInfo{TxHash: "abc", Ledger: 42, ApplicationOrder: 3})
+	if err := json.Unmarshal(b, &tx); err != nil || tx.TxHash != "abc" || tx.PagingToken != "180388638720" {
+		t.Fatal(string(b), err)
+	}
+	b, _ = json.Marshal(soroban.GetTransactionResult{TxHash: "abc", Ledger: 42, ApplicationOrder: 3})
+	if err := json.Unmarshal(b, &tx); err != nil || tx.PagingToken != "180388638720" {
+		t.Fatal(string(b), err)
+	}
+	// a transaction not found has no token
+	if b, _ := json.Marshal(soroban.GetTransactionResult{Status: "NOT_FOUND"}); strings.Contains(string(b), "pagingToken") {
+		t.Fatal(string(b))
+	}
+
+	var event struct {
+		ID          string `json:"id"`
+		PagingToken string `json:"pagingToken"`
+	}
+	b, _ = json.Marshal(soroban.Event{Id: "0000180388638721-0000000002"})
+	if err := json.Unmarshal(b, &event); err != nil || event.PagingToken != event.ID || event.ID == "" {
+		t.Fatal(string(b), err)
+	}
+	// the records read back ignore it
+	var decoded soroban.Event
+	if err := json.Unmarshal(b, &decoded); err != nil || decoded.Id != event.ID {
+		t.Fatal(decoded, err)
+	}
+}
@@ -0,0 +1,179 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+const (
+	ErrorTransactionNotFound = "Transaction not found after polling"
+	ErrorTransactionRejected = "Transaction rejected"
+	ErrorTransactionPending  = "Transaction not finalized yet"
+)
+
+const (
+	// PendingTransactionPollInterval is the maximum interval between
+	// getLatestLedger polls while waiting for the next ledger, see
+	// LedgerPollInitialInterval
+	PendingTransactionPollInterval = time.Second
+	// PendingTransactionPollAttempts is the default number of
+	// getTransaction polls, one per closed ledger, before a
+	// PendingTransaction is resolved as not found, see WaitOptions
+	PendingTransactionPollAttempts = 60
+)
+
+// ErrPending is returned by PendingTransaction Result until the transaction
+// is finalized
+var ErrPending = errors.New(ErrorTransactionPending)
+
+// PendingTransaction is the handle of a submitted transaction. It polls the
+// network in the background, once, when the result is first requested.
+type PendingTransaction struct {
+	client SorobanClient
+	sent   *SendTransactionResult
+	log    *submissionLog
+	depth  uint32
+	wait   WaitOptions
+	// ctx is the context of the submission, without its cancellation, the
+	// polling outlives it. Its span is the parent of the span of the
+	// polling.
+	ctx context.Context
+
+	once sync.Once
+	done chan struct{}
+	res  *GetTransactionResult
+	err  error
+
+	mu          sync.Mutex
+	provisional *GetTransactionResult
+}
+
+func newPendingTransaction(client SorobanClient, sent *SendTransactionResult) *PendingTransaction {
+	c := config(client)
+	return &PendingTransaction{
+		client: client,
+		sent:   sent,
+		depth:  c.ConfirmationDepth,
+		wait:   c.WaitOptions,
+		done:   make(chan struct{}),
+	}
+}
+
+// ConfirmationDepth sets the number of ledgers the transaction must be
+// behind the latest ledger before it is finalized, the Client
+// ConfirmationDepth by default. Until then its result is Provisional. It has
+// to be set before Done, Wait or Result are called.
+func (p *PendingTransaction) ConfirmationDepth(depth uint32) *PendingTransaction {
+	p.depth = depth
+	return p
+}
+
+// WaitOptions sets the polling of getTransaction, the Client WaitOptions by
+// default. It has to be set before Done, Wait or Result are called.
+func (p *PendingTransaction) WaitOptions(opts WaitOptions) *PendingTransaction {
+	p.wait = opts
+	return p
+}
+
+// Provisional returns the result of the transaction seen while waiting for
+// the confirmation depth, it is nil until then
+func (p *PendingTransaction) Provisional() *GetTransactionResult {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.provisional
+}
+
+// Track returns a PendingTransaction for a transaction submitted with
+// SendTransaction or SendTransactionXDR
+func (c *Client) Track(sent *SendTransactionResult) *PendingTransaction {
+	return newPendingTransaction(c, sent)
+}
+
+// Hash returns the hash of the submitted transaction
+func (p *PendingTransaction) Hash() string {
+	return p.sent.Hash
+}
+
+// Sent returns the result of sendTransaction
+func (p *PendingTransaction) Sent() *SendTransactionResult {
+	return p.sent
+}
+
+// Done returns a channel that is closed once the transaction is
+// finalized (SUCCESS, FAILED) or rejected.
+func (p *PendingTransaction) Done() <-chan struct{} {
+	p.once.Do(func() { go p.poll() })
+	return p.done
+}
+
+// Wait blocks until the transaction is finalized or ctx is done, and returns
+// its result.
+func (p *PendingTransaction) Wait(ctx context.Context) (*GetTransactionResult, error) {
+	select {
+	case <-p.Done():
+		return p.res, p.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Result returns the final result, or ErrPending until Done is closed. It
+// does not start the polling.
+func (p *PendingTransaction) Result() (*GetTransactionResult, error) {
+	select {
+	case <-p.done:
+		return p.res, p.err
+	default:
+		return nil, ErrPending
+	}
+}
+
+func (p *PendingTransaction) poll() {
+	defer close(p.done)
+	switch p.sent.Status {
+	case "ERROR", "TRY_AGAIN_LATER":
+		p.err = p.sent.Err()
+		return
+	}
+	defer func() { p.log.finished(p.res, p.err) }()
+	ctx := p.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	ctx, span := startSpan(ctx, p.client, "soroban.wait",
+		TxHashAttribute.String(p.sent.Hash))
+	defer func() {
+		traceResult(span, p.res)
+		endSpan(span, p.err)
+		status := ""
+		switch {
+		case p.res != nil:
+			status = p.res.Status
+		case errors.Is(p.err, ErrTimeout):
+			status = "NOT_FOUND"
+		}
+		metrics(p.client).Completed(status)
+	}()
+	res, err := pollTransaction(ctx, p.client, p.sent.Hash, p.wait)
+	// the transaction is queried again on every ledger until it is depth
+	// ledgers deep, it could be gone or in another ledger
+	for err == nil && p.depth > 0 && res.Status != "NOT_FOUND" && res.LatestLedger < res.Ledger+int64(p.depth) {
+		p.mu.Lock()
+		p.provisional = res
+		p.mu.Unlock()
+		if _, err = waitNextLedger(ctx, p.client, res.LatestLedger); err != nil {
+			break
+		}
+		res, err = pollTransaction(ctx, p.client, p.sent.Hash, p.wait)
+	}
+	switch {
+	case err != nil:
+		p.err = err
+	case res.Status == "NOT_FOUND":
+		p.err = ErrTimeout
+	default:
+		p.res = res
+	}
+}
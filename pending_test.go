@@ -0,0 +1,150 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func sendPending(t *testing.T, fake *sorobantest.FakeClient) *soroban.PendingTransaction {
+	t.Helper()
+	pair := keypair.MustRandom()
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return pending
+}
+
+func TestPendingTransactionResult(t *testing.T) {
+	found := make(chan struct{})
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			<-found
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash}, nil
+		},
+	}
+	pending := sendPending(t, fake)
+	if pending.Hash() != "abc" || pending.Sent().Status != "PENDING" {
+		t.Fatal(pending.Sent())
+	}
+	if res, err := pending.Result(); !errors.Is(err, soroban.ErrPending) || res != nil {
+		t.Fatal(res, err)
+	}
+	done := pending.Done()
+	if res, err := pending.Result(); !errors.Is(err, soroban.ErrPending) || res != nil {
+		t.Fatal(res, err)
+	}
+
+	close(found)
+	<-done
+	if res, err := pending.Result(); err != nil || res.Status != "SUCCESS" {
+		t.Fatal(res, err)
+	}
+	// polled once, for every waiter
+	if res, err := pending.Wait(context.Background()); err != nil || res.TxHash != "abc" || fake.Count(soroban.GetTransaction) != 1 {
+		t.Fatal(res, err, fake.Calls)
+	}
+}
+
+func TestPendingTransactionRejected(t *testing.T) {
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "TRY_AGAIN_LATER"}, nil
+		},
+	}
+	pending := sendPending(t, fake)
+	res, err := pending.Wait(context.Background())
+	if err == nil || res != nil {
+		t.Fatal(res, err)
+	}
+	if fake.Count(soroban.GetTransaction) != 0 {
+		t.Fatal(fake.Calls)
+	}
+}
+
+func TestPendingTransactionWaitOptions(t *testing.T) {
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		Options:    &soroban.Client{WaitOptions: soroban.WaitOptions{MaxAttempts: 2, Interval: time.Millisecond}},
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{Status: "NOT_FOUND"}, nil
+		},
+	}
+	res, err := sendPending(t, fake).Wait(context.Background())
+	if !errors.Is(err, soroban.ErrTimeout) || res != nil || fake.Count(soroban.GetTransaction) != 2 {
+		t.Fatal(res, err, fake.Calls)
+	}
+
+	// the options of the handle override the ones of the client
+	res, err = sendPending(t, fake).WaitOptions(soroban.WaitOptions{MaxAttempts: 3, Interval: time.Millisecond}).Wait(context.Background())
+	if !errors.Is(err, soroban.ErrTimeout) || res != nil || fake.Count(soroban.GetTransaction) != 5 {
+		t.Fatal(res, err, fake.Calls)
+	}
+}
+
+func TestSendTransactionXDR(t *testing.T) {
+	var sent []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		switch req.Method {
+		case soroban.SendTransaction:
+			var params struct {
+				Transaction string `json:"transaction"`
+			}
+			json.Unmarshal(req.Params, &params)
+			sent = append(sent, params.Transaction)
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"hash":"abc","status":"PENDING","latestLedger":7}}`, req.ID)
+		case soroban.GetTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"SUCCESS","txHash":"abc","ledger":8,"latestLedger":8}}`, req.ID)
+		}
+	}))
+	defer server.Close()
+	client := &soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase}
+
+	// an envelope signed elsewhere is sent as is
+	tx, _ := signedTransaction(t)
+	envelopeXdr, _ := tx.Base64()
+	res, err := client.SendTransactionXDR(context.Background(), envelopeXdr)
+	if err != nil || res.Hash != "abc" || len(sent) != 1 || sent[0] != envelopeXdr {
+		t.Fatal(res, err, sent)
+	}
+	final, err := client.Track(res).Wait(context.Background())
+	if err != nil || final.Status != "SUCCESS" || final.Ledger != 8 {
+		t.Fatal(final, err)
+	}
+
+	if _, err := client.SendTransactionXDR(context.Background(), "not xdr"); err == nil || len(sent) != 1 {
+		t.Fatal("invalid envelope sent", err)
+	}
+}
@@ -0,0 +1,47 @@
+package soroban
+
+import "github.com/stellar/go/txnbuild"
+
+// Pin fixes the inputs of the envelopes that change between runs, so
+// identical inputs build byte-identical envelopes, e.g. to compare them with
+// golden files, see sorobantest.AssertGolden. The salt of a Contract is
+// already fixed by Salt.
+//
+//	Example:
+//	 contract.Pin(soroban.Pin{TimeBounds: txnbuild.NewTimebounds(0, 1700000000), Sequence: 2})
+type Pin struct {
+	// TimeBounds replace the ones of the timeout, if set
+	TimeBounds txnbuild.TimeBounds
+	// Sequence is the sequence number of the envelopes, if set, the source
+	// account is neither read nor incremented
+	Sequence int64
+}
+
+// Pin fixes the time bounds and the sequence number of the transaction
+func (t *Transaction) Pin(p Pin) *Transaction {
+	if p.TimeBounds != (txnbuild.TimeBounds{}) {
+		t.build.timeBounds = p.TimeBounds
+	}
+	t.build.sequence = p.Sequence
+	return t
+}
+
+// Pin fixes the time bounds and the sequence number of every transaction
+// sent by the Contract
+func (c *Contract) Pin(p Pin) *Contract {
+	c.pin = p
+	return c
+}
+
+// source returns the source account of the envelope, a copy with the pinned
+// sequence if any
+func (t *Transaction) source() txnbuild.Account {
+	seq := t.build.sequence
+	if seq == 0 || t.build.source == nil {
+		return t.build.source
+	}
+	if t.build.incrementSequenceNum {
+		seq--
+	}
+	return &txnbuild.SimpleAccount{AccountID: t.build.source.GetAccountID(), Sequence: seq}
+}
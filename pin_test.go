@@ -0,0 +1,54 @@
+package soroban_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPin(t *testing.T) {
+	var envelopes []string
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 100})
+			return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 100}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			envelopes = append(envelopes, envelopeXdr)
+			return &soroban.SendTransactionResult{Hash: "h", Status: "PENDING"}, nil
+		},
+	}
+	pair, _ := keypair.FromRawSeed([32]byte{1})
+	account := &soroban.Account{AccountId: pair.Address(), Sequence: 41}
+	contract := soroban.NewContract().
+		Client(fake).
+		WasmHash([32]byte{1}).
+		Salt("pin").
+		SourceAccount(account).
+		KeyPair(pair).
+		Pin(soroban.Pin{TimeBounds: txnbuild.NewTimebounds(0, 1700000000), Sequence: 42})
+	for range 2 {
+		if _, err := contract.Invoke().Function("hello").Symbol("world").Send(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if envelopes[0] != envelopes[1] || account.Sequence != 41 {
+		t.Fatal("envelopes not pinned", account.Sequence)
+	}
+	sorobantest.AssertGolden(t, "testdata/pin.golden", envelopes[0])
+
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopes[0], &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.SeqNum() != 42 || envelope.TimeBounds().MaxTime != 1700000000 {
+		t.Fatal(envelope.SeqNum(), envelope.TimeBounds())
+	}
+}
@@ -0,0 +1,243 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorPlanStepFailed = "Plan step failed"
+	ErrorPlanNoStep     = "Plan step option without a step"
+)
+
+// DefaultPlanBackoff is the wait before the first retry of a step, see
+// Plan.Backoff
+const DefaultPlanBackoff = time.Second
+
+type (
+	// Plan runs invocations in order, each one able to use the return values
+	// of the previous ones, e.g. a setup script initializing a contract,
+	// setting its admin and seeding data. A failed step rolls back the
+	// completed ones, in reverse order.
+	//
+	//	Example:
+	//	 report, err := soroban.NewPlan().
+	//		Invoke("deploy", factory, "deploy", salt).
+	//		Invoke("init", factory, "init").
+	//		Args(func(results soroban.PlanResults) ([]xdr.ScVal, error) {
+	//			return []xdr.ScVal{results["deploy"], admin}, nil
+	//		}).
+	//		Retry(3).
+	//		Run(ctx)
+	Plan struct {
+		steps []*planStep
+		err   error
+	}
+
+	planStep struct {
+		name     string
+		contract *Contract
+		function string
+		args     func(results PlanResults) ([]xdr.ScVal, error)
+		attempts int
+		backoff  time.Duration
+		rollback func(ctx context.Context, results PlanResults) error
+	}
+
+	// PlanResults are the return values of the completed steps, by name
+	PlanResults map[string]xdr.ScVal
+
+	// PlanStepReport is the outcome of a step of a Plan
+	PlanStepReport struct {
+		Name     string
+		Function string
+		// Attempts made, 0 if the step was not run
+		Attempts int
+		Hash     string
+		Status   string
+		Result   *xdr.ScVal
+		Err      error
+		// RolledBack is set if the rollback of the step ran, with the
+		// error of the rollback in RollbackErr
+		RolledBack  bool
+		RollbackErr error
+	}
+
+	// PlanReport are the steps of a Plan in order, including the ones not
+	// run after a failure
+	PlanReport struct {
+		Steps   []PlanStepReport
+		Results PlanResults
+	}
+)
+
+// NewPlan returns an empty Plan
+func NewPlan() *Plan {
+	return &Plan{}
+}
+
+// Invoke adds a step named name, unique in the plan, invoking function of
+// contract with args. The options following it, Args, Retry and Rollback,
+// apply to this step.
+func (p *Plan) Invoke(name string, contract *Contract, function string, args ...xdr.ScVal) *Plan {
+	p.steps = append(p.steps, &planStep{
+		name:     name,
+		contract: contract,
+		function: function,
+		args:     func(PlanResults) ([]xdr.ScVal, error) { return args, nil },
+		attempts: 1,
+		backoff:  DefaultPlanBackoff,
+	})
+	return p
+}
+
+// Args sets the args of the last step from the return values of the
+// previous ones, replacing the ones of Invoke
+func (p *Plan) Args(args func(results PlanResults) ([]xdr.ScVal, error)) *Plan {
+	if step := p.last(); step != nil {
+		step.args = args
+	}
+	return p
+}
+
+// Retry sets the attempts of the last step, a failed attempt is retried
+// until attempts are made. When the wait of an attempt failed, its
+// transaction is looked up before sending the invocation again.
+func (p *Plan) Retry(attempts int) *Plan {
+	if step := p.last(); step != nil {
+		step.attempts = max(attempts, 1)
+	}
+	return p
+}
+
+// Backoff sets the wait before the first retry of the last step, doubled
+// after every attempt, DefaultPlanBackoff by default
+func (p *Plan) Backoff(backoff time.Duration) *Plan {
+	if step := p.last(); step != nil {
+		step.backoff = backoff
+	}
+	return p
+}
+
+// Rollback sets the undo of the last step, run when a later step fails with
+// the results of the steps completed
+func (p *Plan) Rollback(rollback func(ctx context.Context, results PlanResults) error) *Plan {
+	if step := p.last(); step != nil {
+		step.rollback = rollback
+	}
+	return p
+}
+
+func (p *Plan) last() *planStep {
+	if len(p.steps) == 0 {
+		p.err = errors.New(ErrorPlanNoStep)
+		return nil
+	}
+	return p.steps[len(p.steps)-1]
+}
+
+// Run runs the steps in order, waiting for each transaction. When a step
+// fails after its attempts, the completed steps are rolled back in reverse
+// order and the error is returned wrapped in ErrorPlanStepFailed. The report
+// is returned in both cases.
+func (p *Plan) Run(ctx context.Context) (*PlanReport, error) {
+	report := &PlanReport{Results: PlanResults{}}
+	for _, step := range p.steps {
+		report.Steps = append(report.Steps, PlanStepReport{Name: step.name, Function: step.function})
+	}
+	if p.err != nil {
+		return report, p.err
+	}
+	for i, step := range p.steps {
+		r := &report.Steps[i]
+		r.Err = step.run(ctx, report.Results, r)
+		if r.Err != nil {
+			p.rollback(ctx, report, i)
+			return report, fmt.Errorf("%s: %s: %w", ErrorPlanStepFailed, step.name, r.Err)
+		}
+		if r.Result != nil {
+			report.Results[step.name] = *r.Result
+		}
+	}
+	return report, nil
+}
+
+// run makes the attempts of s, recording them in r
+func (s *planStep) run(ctx context.Context, results PlanResults, r *PlanStepReport) error {
+	args, err := s.args(results)
+	if err != nil {
+		return err
+	}
+	// unresolved is set when the wait of the transaction of r.Hash failed,
+	// it may still be included
+	unresolved := false
+	backoff := s.backoff
+	for r.Attempts < s.attempts {
+		if r.Attempts > 0 {
+			select {
+			case <-ctx.Done():
+				return ctx.Err()
+			case <-time.After(backoff):
+			}
+			backoff *= 2
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		r.Attempts++
+		var res *GetTransactionResult
+		res, unresolved, err = s.attempt(ctx, args, r, unresolved)
+		if err != nil {
+			continue
+		}
+		r.Status = res.Status
+		if res.Status != "SUCCESS" {
+			err = fmt.Errorf("%s: %s", res.Status, r.Hash)
+			continue
+		}
+		r.Result, err = res.ReturnValue()
+		return err
+	}
+	return err
+}
+
+// attempt looks up the transaction of the previous attempt if unresolved,
+// otherwise, or if it is not found, it sends the invocation and waits for it.
+// It returns whether the transaction of r.Hash is still unresolved.
+func (s *planStep) attempt(ctx context.Context, args []xdr.ScVal, r *PlanStepReport, unresolved bool) (*GetTransactionResult, bool, error) {
+	if unresolved {
+		res, err := s.contract.client.GetTransaction(ctx, r.Hash)
+		if err != nil {
+			return nil, true, err
+		}
+		if res.Status != "NOT_FOUND" {
+			return res, false, nil
+		}
+	}
+	// a pending transaction with an *AuditError is submitted
+	pending, err := s.contract.Invoke().Function(s.function).WithArgs(args...).Send(ctx)
+	if pending == nil {
+		return nil, false, err
+	}
+	r.Hash = pending.Hash()
+	res, err := pending.Wait(ctx)
+	if err != nil {
+		return nil, true, err
+	}
+	return res, false, nil
+}
+
+// rollback runs the rollbacks of the steps before failed, in reverse order
+func (p *Plan) rollback(ctx context.Context, report *PlanReport, failed int) {
+	for i := failed - 1; i >= 0; i-- {
+		if p.steps[i].rollback == nil {
+			continue
+		}
+		report.Steps[i].RolledBack = true
+		report.Steps[i].RollbackErr = p.steps[i].rollback(ctx, report.Results)
+	}
+}
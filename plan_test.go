@@ -0,0 +1,101 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPlan(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	// each invocation returns the symbol of its function, seed fails
+	var calls []string
+	results := map[string]xdr.ScVal{}
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		invoke := envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract
+		call := string(invoke.FunctionName)
+		for _, arg := range invoke.Args {
+			call += " " + string(*arg.Sym)
+		}
+		calls = append(calls, call)
+		hash := fmt.Sprint(len(calls))
+		results[hash] = sym(string(invoke.FunctionName))
+		return &soroban.SendTransactionResult{Hash: hash, Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		ret := results[hash]
+		if string(*ret.Sym) == "seed" {
+			return &soroban.GetTransactionResult{Status: "FAILED", TxHash: hash}, nil
+		}
+		meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &ret}}})
+		return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, ResultMetaXdr: meta}, nil
+	}
+
+	var rolledBack []string
+	plan := soroban.NewPlan().
+		Invoke("init", contract, "init", sym("admin")).
+		Rollback(func(ctx context.Context, results soroban.PlanResults) error {
+			rolledBack = append(rolledBack, string(*results["init"].Sym))
+			return nil
+		}).
+		Invoke("configure", contract, "configure").
+		Args(func(results soroban.PlanResults) ([]xdr.ScVal, error) {
+			return []xdr.ScVal{results["init"]}, nil
+		})
+	report, err := plan.Run(context.Background())
+	if err != nil || strings.Join(calls, ",") != "init admin,configure init" || *report.Results["configure"].Sym != "configure" {
+		t.Fatal(calls, err)
+	}
+
+	calls = nil
+	report, err = plan.Invoke("seed", contract, "seed").Retry(2).Backoff(time.Millisecond).Run(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorPlanStepFailed+": seed: FAILED") {
+		t.Fatal(err)
+	}
+	seed := report.Steps[2]
+	if len(calls) != 4 || seed.Attempts != 2 || seed.Status != "FAILED" || seed.Hash != "4" {
+		t.Fatal(calls, seed)
+	}
+	if strings.Join(rolledBack, ",") != "init" || !report.Steps[0].RolledBack || report.Steps[1].RolledBack {
+		t.Fatal(rolledBack, report.Steps)
+	}
+
+	if _, err := soroban.NewPlan().Retry(2).Run(context.Background()); err == nil || err.Error() != soroban.ErrorPlanNoStep {
+		t.Fatal(err)
+	}
+}
+
+func TestPlanRetryLooksUpTransaction(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+	// the wait of the first attempt fails, the transaction is included
+	fake.GetTransactionFunc = func(hash string) (*soroban.GetTransactionResult, error) {
+		if fake.Count(soroban.GetTransaction) == 1 {
+			return nil, errors.New("unavailable")
+		}
+		ret := sym("done")
+		meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &ret}}})
+		return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, ResultMetaXdr: meta}, nil
+	}
+	report, err := soroban.NewPlan().Invoke("init", contract, "init").Retry(3).Backoff(time.Millisecond).Run(context.Background())
+	if err != nil || *report.Results["init"].Sym != "done" {
+		t.Fatal(report, err)
+	}
+	if step := report.Steps[0]; step.Attempts != 2 || step.Hash != "abc" || fake.Count(soroban.SendTransaction) != 1 {
+		t.Fatal(step, fake.Calls)
+	}
+}
@@ -0,0 +1,76 @@
+package soroban
+
+import (
+	"errors"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+)
+
+const (
+	ErrorPreAuthRequiresSequence = "Pre-authorized transactions require the exact sequence number"
+)
+
+// PreAuthorized is a transaction authorized in advance: its hash is added as
+// a signer of the source account, so it is submitted later without
+// signatures, e.g. a recovery or the release of an escrow. The network removes
+// the signer once the transaction is applied.
+type PreAuthorized struct {
+	Hash [32]byte
+	// Signer is the hash as a pre-authorized transaction signer key (T...)
+	Signer string
+	// EnvelopeXdr is the unsigned envelope, to submit it elsewhere with
+	// SendTransactionXDR
+	EnvelopeXdr string
+}
+
+// PreAuthorize builds the envelope of the transaction with the exact
+// sequence number, the one the source account will have plus one when it is
+// submitted, and timeBounds, e.g. a MinTime to lock it until then. It is
+// stored like SignPartial does, Send submits it as is. The signers are
+// dropped, extra signatures would fail it with txBAD_AUTH_EXTRA. Add the
+// Signer to the source account with SignerOperation, in a transaction sent
+// before.
+//
+//	Example:
+//	 release := soroban.NewTransctionBuilder().Client(client).SourceAccount(escrow).Operation(payment)
+//	 pre, err := release.PreAuthorize(account.Sequence+2, txnbuild.NewTimebounds(unlockAt.Unix(), 0))
+//	 setup := soroban.NewTransctionBuilder().Client(client).SourceAccount(escrow).Signer(owner).Operation(pre.SignerOperation(1))
+//	 // ... once unlocked
+//	 pending, err := release.Send(ctx)
+func (t *Transaction) PreAuthorize(sequence int64, timeBounds txnbuild.TimeBounds) (*PreAuthorized, error) {
+	if sequence <= 0 {
+		return nil, errors.New(ErrorPreAuthRequiresSequence)
+	}
+	if t.client == nil {
+		return nil, errors.New(ErrorRequiredClient)
+	}
+	t.Pin(Pin{TimeBounds: timeBounds, Sequence: sequence})
+	tx, err := t.buildTx()
+	if err != nil {
+		return nil, err
+	}
+	hash, err := tx.Hash(t.client.NetworkPassphrase())
+	if err != nil {
+		return nil, err
+	}
+	signer, err := strkey.Encode(strkey.VersionByteHashTx, hash[:])
+	if err != nil {
+		return nil, err
+	}
+	envelopeXdr, err := tx.Base64()
+	if err != nil {
+		return nil, err
+	}
+	t.envelope = tx
+	t.build.signers = nil
+	t.build.coSigners = nil
+	return &PreAuthorized{Hash: hash, Signer: signer, EnvelopeXdr: envelopeXdr}, nil
+}
+
+// SignerOperation returns the operation adding the Signer to the source
+// account with weight, enough to reach the thresholds of the operations of
+// the pre-authorized transaction
+func (p *PreAuthorized) SignerOperation(weight txnbuild.Threshold) *txnbuild.SetOptions {
+	return &txnbuild.SetOptions{Signer: &txnbuild.Signer{Address: p.Signer, Weight: weight}}
+}
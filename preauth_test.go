@@ -0,0 +1,65 @@
+package soroban_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPreAuthorize(t *testing.T) {
+	var envelopes []string
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			envelopes = append(envelopes, envelopeXdr)
+			return &soroban.SendTransactionResult{Hash: "h", Status: "PENDING"}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 10}
+	release := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(account).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 100})
+	if _, err := release.PreAuthorize(0, txnbuild.NewTimebounds(1700000000, 0)); err == nil {
+		t.Fatal("sequence not required")
+	}
+	noClient := soroban.NewTransctionBuilder().SourceAccount(account).Operation(&txnbuild.BumpSequence{BumpTo: 100})
+	if _, err := noClient.PreAuthorize(12, txnbuild.NewTimebounds(1700000000, 0)); err == nil || err.Error() != soroban.ErrorRequiredClient {
+		t.Fatal(err)
+	}
+	pre, err := release.PreAuthorize(12, txnbuild.NewTimebounds(1700000000, 0))
+	if err != nil {
+		t.Fatal(err)
+	}
+	hash, err := strkey.Decode(strkey.VersionByteHashTx, pre.Signer)
+	if err != nil || [32]byte(hash) != pre.Hash {
+		t.Fatal(pre.Signer, err)
+	}
+	op := pre.SignerOperation(1)
+	if op.Signer.Address != pre.Signer || op.Signer.Weight != 1 {
+		t.Fatal(op.Signer)
+	}
+
+	if _, err := release.Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelopes) != 1 || envelopes[0] != pre.EnvelopeXdr || account.Sequence != 10 {
+		t.Fatal(envelopes, account.Sequence)
+	}
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(envelopes[0], &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if envelope.SeqNum() != 12 || envelope.TimeBounds().MinTime != 1700000000 || len(envelope.Signatures()) != 0 {
+		t.Fatal(envelope.SeqNum(), envelope.TimeBounds(), len(envelope.Signatures()))
+	}
+}
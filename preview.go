@@ -0,0 +1,183 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+type (
+	// InvocationPreview is what a wallet displays before signing an
+	// invocation, built from its simulation, see invokeBuilder.Preview
+	InvocationPreview struct {
+		Function    string
+		ReturnValue *xdr.ScVal
+		// Auth are the authorizations required, the ones of an Address
+		// must be signed, see AuthSigner
+		Auth []AuthPreview
+		// InclusionFee, ResourceFee and their sum Fee, in stroops
+		InclusionFee int64
+		ResourceFee  int64
+		Fee          int64
+		Resources    xdr.SorobanResources
+		StateChanges []StateChangePreview
+		// Events are the contract events emitted
+		Events []EventPreview
+		// LatestLedger of the simulation
+		LatestLedger int64
+		// AuthExpirationLedger is the last ledger of the signatures of Auth,
+		// when some must be signed
+		AuthExpirationLedger uint32
+		// ExpiresAt is the max time bound of the transaction
+		ExpiresAt time.Time
+		// RestoreFee is set when archived entries must be restored first,
+		// see RestoreAndSend
+		RestoreFee int64
+	}
+
+	// AuthPreview is an authorization required by an invocation
+	AuthPreview struct {
+		// Address authorizing, empty for the source account
+		Address string
+		// Invocations are the tree of the invocations authorized, flattened
+		// depth first
+		Invocations []AuthInvocationPreview
+	}
+
+	// AuthInvocationPreview is a node of the tree of an AuthPreview
+	AuthInvocationPreview struct {
+		// Depth is 0 for the root invocation
+		Depth    int
+		Contract string
+		Function string
+		Args     []xdr.ScVal
+		// CreateContract is set for the creation of a contract, without
+		// Contract nor Function
+		CreateContract bool
+	}
+
+	// StateChangePreview is a ledger entry the invocation creates, updates
+	// or deletes
+	StateChangePreview struct {
+		// Type is created, updated or deleted
+		Type   string
+		Key    xdr.LedgerKey
+		Before *xdr.LedgerEntry
+		After  *xdr.LedgerEntry
+	}
+
+	// EventPreview is a contract event emitted by the invocation
+	EventPreview struct {
+		Contract string
+		Topics   []xdr.ScVal
+		Data     xdr.ScVal
+	}
+)
+
+// Preview simulates the invocation, without signing nor sending it, and
+// returns what a wallet needs to display. The liveness of the contract is
+// not checked.
+//
+//	Requires client, sourceAccount, function
+func (c *invokeBuilder) Preview(ctx context.Context) (*InvocationPreview, error) {
+	if c.build.function == "" {
+		return nil, errors.New(ErrorInvokeRequiresFunction)
+	}
+	if c.build.err != nil {
+		return nil, c.build.err
+	}
+	transaction, res, err := c.contract.simulateInvoke(ctx, c.build)
+	if err != nil {
+		return nil, err
+	}
+	preview := &InvocationPreview{
+		Function:     c.build.function,
+		InclusionFee: transaction.build.inclusionFee,
+		ResourceFee:  res.MinResourceFee,
+		Fee:          transaction.build.inclusionFee + res.MinResourceFee,
+		LatestLedger: res.LatestLedger,
+		RestoreFee:   res.RestorePreamble.MinResourceFee,
+	}
+	if len(res.Results) > 0 {
+		if preview.ReturnValue, err = res.ReturnValue(); err != nil {
+			return nil, err
+		}
+	}
+	preview.Resources = res.Data.Resources
+	timeBounds, err := transaction.timeBounds()
+	if err != nil {
+		return nil, err
+	}
+	preview.ExpiresAt = time.Unix(timeBounds.MaxTime, 0)
+
+	for _, result := range res.Results {
+		for _, entry := range result.AuthEntries {
+			auth, err := authPreview(entry)
+			if err != nil {
+				return nil, err
+			}
+			if auth.Address != "" {
+				preview.AuthExpirationLedger = uint32(res.LatestLedger) + DefaultAuthValidityLedgers
+			}
+			preview.Auth = append(preview.Auth, auth)
+		}
+	}
+	for _, change := range res.StateChanges {
+		preview.StateChanges = append(preview.StateChanges, stateChangePreview(change))
+	}
+	for _, eventXdr := range res.Events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(eventXdr, &event); err != nil {
+			return nil, err
+		}
+		body, ok := event.Event.Body.GetV0()
+		if event.Event.Type != xdr.ContractEventTypeContract || event.Event.ContractId == nil || !ok {
+			continue
+		}
+		contract, err := (xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: event.Event.ContractId}).String()
+		if err != nil {
+			return nil, err
+		}
+		preview.Events = append(preview.Events, EventPreview{Contract: contract, Topics: body.Topics, Data: body.Data})
+	}
+	return preview, nil
+}
+
+// authPreview returns the preview of entry, with its tree of invocations
+func authPreview(entry xdr.SorobanAuthorizationEntry) (AuthPreview, error) {
+	var preview AuthPreview
+	address, _, err := AuthEntryAddress(entry)
+	if err != nil {
+		return preview, err
+	}
+	preview.Address = address
+	var walk func(invocation xdr.SorobanAuthorizedInvocation, depth int) error
+	walk = func(invocation xdr.SorobanAuthorizedInvocation, depth int) error {
+		node := AuthInvocationPreview{Depth: depth}
+		switch fn := invocation.Function; fn.Type {
+		case xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn:
+			contract, err := fn.ContractFn.ContractAddress.String()
+			if err != nil {
+				return err
+			}
+			node.Contract, node.Function, node.Args = contract, string(fn.ContractFn.FunctionName), fn.ContractFn.Args
+		default:
+			node.CreateContract = true
+		}
+		preview.Invocations = append(preview.Invocations, node)
+		for _, sub := range invocation.SubInvocations {
+			if err := walk(sub, depth+1); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	return preview, walk(entry.RootInvocation, 0)
+}
+
+// stateChangePreview returns the preview of the decoded change
+func stateChangePreview(change SimulateStateChange) StateChangePreview {
+	return StateChangePreview{Type: change.Type, Key: change.LedgerKey, Before: change.BeforeEntry, After: change.AfterEntry}
+}
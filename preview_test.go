@@ -0,0 +1,94 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPreview(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	contract.Pin(soroban.Pin{TimeBounds: txnbuild.NewTimebounds(0, 1700000000)})
+	token := xdr.ContractId{2}
+	tokenAddress := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &token}
+	user := keypair.MustRandom()
+	userId := xdr.MustAddress(user.Address())
+	userAddress := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &userId}
+
+	auth, _ := xdr.MarshalBase64(xdr.SorobanAuthorizationEntry{
+		Credentials: xdr.SorobanCredentials{
+			Type:    xdr.SorobanCredentialsTypeSorobanCredentialsAddress,
+			Address: &xdr.SorobanAddressCredentials{Address: userAddress, Signature: xdr.ScVal{Type: xdr.ScValTypeScvVoid}},
+		},
+		RootInvocation: xdr.SorobanAuthorizedInvocation{
+			Function: xdr.SorobanAuthorizedFunction{
+				Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+				ContractFn: &xdr.InvokeContractArgs{ContractAddress: tokenAddress, FunctionName: "swap", Args: []xdr.ScVal{sym("a")}},
+			},
+			SubInvocations: []xdr.SorobanAuthorizedInvocation{{
+				Function: xdr.SorobanAuthorizedFunction{
+					Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+					ContractFn: &xdr.InvokeContractArgs{ContractAddress: tokenAddress, FunctionName: "transfer"},
+				},
+			}},
+		},
+	})
+	key := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: tokenAddress, Key: sym("balance"), Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+	keyXdr, _ := xdr.MarshalBase64(key)
+	after, _ := xdr.MarshalBase64(xdr.LedgerEntry{Data: xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.ContractDataEntry{
+		Contract: tokenAddress, Key: sym("balance"), Durability: xdr.ContractDataDurabilityPersistent, Val: sym("100"),
+	}}})
+	event, _ := xdr.MarshalBase64(xdr.DiagnosticEvent{InSuccessfulContractCall: true, Event: xdr.ContractEvent{
+		ContractId: &token,
+		Type:       xdr.ContractEventTypeContract,
+		Body:       xdr.ContractEventBody{V: 0, V0: &xdr.ContractEventV0{Topics: []xdr.ScVal{sym("transfer")}, Data: sym("100")}},
+	}})
+	ret, _ := xdr.MarshalBase64(sym("ok"))
+	data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{Resources: xdr.SorobanResources{Instructions: 1000}})
+	body, _ := json.Marshal(map[string]any{
+		"transactionData": data,
+		"minResourceFee":  "250",
+		"latestLedger":    latestLedger,
+		"events":          []string{event},
+		"results":         []map[string]any{{"xdr": ret, "auth": []string{auth}}},
+		"stateChanges":    []map[string]any{{"type": "created", "key": keyXdr, "after": after}},
+	})
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		var res soroban.SimulateTransactionResult
+		return &res, json.Unmarshal(body, &res)
+	}
+
+	preview, err := contract.Invoke().Function("swap").Symbol("a").Preview(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if preview.Function != "swap" || *preview.ReturnValue.Sym != "ok" || preview.Fee != 350 || preview.ResourceFee != 250 ||
+		preview.Resources.Instructions != 1000 || preview.ExpiresAt.Unix() != 1700000000 || preview.RestoreFee != 0 {
+		t.Fatal(preview)
+	}
+	if len(preview.Auth) != 1 || preview.Auth[0].Address != user.Address() || preview.AuthExpirationLedger != 10+soroban.DefaultAuthValidityLedgers {
+		t.Fatal(preview.Auth, preview.AuthExpirationLedger)
+	}
+	invocations := preview.Auth[0].Invocations
+	if len(invocations) != 2 || invocations[0].Function != "swap" || invocations[1].Function != "transfer" || invocations[1].Depth != 1 || invocations[0].Contract[0] != 'C' {
+		t.Fatal(invocations)
+	}
+	if len(preview.StateChanges) != 1 || preview.StateChanges[0].Type != "created" || preview.StateChanges[0].Before != nil ||
+		*preview.StateChanges[0].After.Data.ContractData.Val.Sym != "100" {
+		t.Fatal(preview.StateChanges)
+	}
+	if len(preview.Events) != 1 || *preview.Events[0].Topics[0].Sym != "transfer" || *preview.Events[0].Data.Sym != "100" {
+		t.Fatal(preview.Events)
+	}
+	if fake.Count(soroban.SendTransaction) != 0 {
+		t.Fatal("preview sent")
+	}
+}
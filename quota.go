@@ -0,0 +1,108 @@
+package soroban
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+const (
+	ErrorQuotaExceeded = "Invocation quota exceeded"
+)
+
+// Quota caps the invocations submitted per minute, for the contract and for
+// each function, a kill-switch for a runaway loop draining the fee account.
+// Invocations over a cap fail with ErrorQuotaExceeded before they are
+// simulated. A Quota set on several contracts caps their invocations
+// together.
+//
+//	Example:
+//	 quota := soroban.NewQuota(60).Function("transfer", 10).OnExceeded(alert)
+//	 contract.Quota(quota)
+type Quota struct {
+	perMinute  int
+	functions  map[string]int
+	onExceeded func(function string, perMinute int)
+
+	mu sync.Mutex
+	// sent are the submission times of the last minute, by function, the
+	// ones of the contract under ""
+	sent map[string][]time.Time
+}
+
+// NewQuota returns a quota of perMinute invocations of the contract, 0 means
+// no cap besides the ones of Function
+func NewQuota(perMinute int) *Quota {
+	return &Quota{perMinute: perMinute, functions: map[string]int{}, sent: map[string][]time.Time{}}
+}
+
+// Function caps the invocations of function to perMinute
+func (q *Quota) Function(function string, perMinute int) *Quota {
+	q.functions[function] = perMinute
+	return q
+}
+
+// OnExceeded sets a callback for the invocations refused, with the function
+// whose cap was exceeded, empty for the cap of the contract
+func (q *Quota) OnExceeded(f func(function string, perMinute int)) *Quota {
+	q.onExceeded = f
+	return q
+}
+
+// quotaCap is the cap of a function, or of the contract if key is empty
+type quotaCap struct {
+	key       string
+	perMinute int
+}
+
+// take counts an invocation of function, returning ErrorQuotaExceeded if it
+// exceeds a cap, in which case it is not counted
+func (q *Quota) take(function string) error {
+	if q == nil {
+		return nil
+	}
+	q.mu.Lock()
+	now := time.Now()
+	var caps []quotaCap
+	if q.perMinute > 0 {
+		caps = append(caps, quotaCap{"", q.perMinute})
+	}
+	if perMinute, ok := q.functions[function]; ok {
+		caps = append(caps, quotaCap{function, perMinute})
+	}
+	for _, c := range caps {
+		if len(q.recent(c.key, now)) >= c.perMinute {
+			q.mu.Unlock()
+			if q.onExceeded != nil {
+				q.onExceeded(c.key, c.perMinute)
+			}
+			if c.key == "" {
+				return fmt.Errorf("%s: %d per minute", ErrorQuotaExceeded, c.perMinute)
+			}
+			return fmt.Errorf("%s: %s, %d per minute", ErrorQuotaExceeded, c.key, c.perMinute)
+		}
+	}
+	for _, c := range caps {
+		q.sent[c.key] = append(q.sent[c.key], now)
+	}
+	q.mu.Unlock()
+	return nil
+}
+
+// recent drops the submissions of key older than a minute and returns the
+// rest
+func (q *Quota) recent(key string, now time.Time) []time.Time {
+	sent := q.sent[key]
+	i := 0
+	for i < len(sent) && now.Sub(sent[i]) >= time.Minute {
+		i++
+	}
+	q.sent[key] = sent[i:]
+	return q.sent[key]
+}
+
+// Quota caps the invocations of the contract, see Quota
+func (c *Contract) Quota(quota *Quota) *Contract {
+	c.quota = quota
+	return c
+}
@@ -0,0 +1,63 @@
+package soroban_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestContractQuota(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	var exceeded []string
+	contract.Quota(soroban.NewQuota(3).Function("transfer", 1).OnExceeded(func(function string, perMinute int) {
+		exceeded = append(exceeded, function)
+	}))
+
+	send := func(function string) error {
+		_, err := contract.Invoke().Function(function).Send(context.Background())
+		return err
+	}
+	if err := send("transfer"); err != nil {
+		t.Fatal(err)
+	}
+	if err := send("transfer"); err == nil || !strings.Contains(err.Error(), soroban.ErrorQuotaExceeded) {
+		t.Fatal(err)
+	}
+	// the refused invocation is not counted for the contract
+	for range 2 {
+		if err := send("hello"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := send("hello"); err == nil || !strings.Contains(err.Error(), soroban.ErrorQuotaExceeded) {
+		t.Fatal(err)
+	}
+	if len(exceeded) != 2 || exceeded[0] != "transfer" || exceeded[1] != "" {
+		t.Fatal(exceeded)
+	}
+	if fake.Count(soroban.SendTransaction) != 3 || fake.Count(soroban.SimulateTransaction) != 3 {
+		t.Fatal(fake.Calls)
+	}
+}
+
+func TestBatchQuota(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	contract.Quota(soroban.NewQuota(0).Function("airdrop", 2))
+	items := []xdr.ScVal{u32(1), u32(2), u32(3), u32(4)}
+	res, err := contract.Batch("airdrop", items).ChunkSize(1).Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	failed := res.Failed()
+	if res.Succeeded() != 2 || len(failed) != 2 || !strings.Contains(failed[0].Err.Error(), soroban.ErrorQuotaExceeded) {
+		t.Fatal(res.Chunks)
+	}
+	if fake.Count(soroban.SendTransaction) != 2 {
+		t.Fatal(fake.Calls)
+	}
+}
@@ -0,0 +1,99 @@
+package soroban
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+)
+
+const (
+	ErrorNetworkNotRegistered = "Network not registered"
+)
+
+// Network tags of the public networks
+const (
+	Testnet = "testnet"
+	Mainnet = "mainnet"
+)
+
+// Registry holds the clients of several networks by tag (Testnet, Mainnet, or
+// custom ones, e.g. per tenant), so Contract and Transaction operations are
+// routed by tag. It is safe for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]SorobanClient
+}
+
+// NewRegistry returns an empty Registry
+func NewRegistry() *Registry {
+	return &Registry{clients: make(map[string]SorobanClient)}
+}
+
+// Register sets the client of the network tag, replacing the previous one
+func (r *Registry) Register(tag string, client SorobanClient) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.clients[tag] = client
+	return r
+}
+
+// Remove removes the client of the network tag
+func (r *Registry) Remove(tag string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, tag)
+}
+
+// Client returns the client of the network tag, or ErrorNetworkNotRegistered
+func (r *Registry) Client(tag string) (SorobanClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	client, ok := r.clients[tag]
+	if !ok {
+		return nil, fmt.Errorf("%s: %s", ErrorNetworkNotRegistered, tag)
+	}
+	return client, nil
+}
+
+// ByPassphrase returns the tag and client of the network with passphrase,
+// e.g. to route an envelope signed for it
+func (r *Registry) ByPassphrase(passphrase string) (string, SorobanClient, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	for tag, client := range r.clients {
+		if client.NetworkPassphrase() == passphrase {
+			return tag, client, nil
+		}
+	}
+	return "", nil, fmt.Errorf("%s: %s", ErrorNetworkNotRegistered, passphrase)
+}
+
+// Tags returns the registered network tags, sorted
+func (r *Registry) Tags() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	tags := make([]string, 0, len(r.clients))
+	for tag := range r.clients {
+		tags = append(tags, tag)
+	}
+	slices.Sort(tags)
+	return tags
+}
+
+// Contract returns a new Contract bound to the client of the network tag
+func (r *Registry) Contract(tag string) (*Contract, error) {
+	client, err := r.Client(tag)
+	if err != nil {
+		return nil, err
+	}
+	return NewContract().Client(client), nil
+}
+
+// Transaction returns a new Transaction bound to the client of the network tag
+func (r *Registry) Transaction(tag string) (*Transaction, error) {
+	client, err := r.Client(tag)
+	if err != nil {
+		return nil, err
+	}
+	return NewTransctionBuilder().Client(client), nil
+}
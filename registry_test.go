@@ -0,0 +1,37 @@
+package soroban_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/network"
+)
+
+func TestRegistry(t *testing.T) {
+	testnet := &sorobantest.FakeClient{Passphrase: network.TestNetworkPassphrase}
+	mainnet := &soroban.Client{PassPhrase: network.PublicNetworkPassphrase}
+	registry := soroban.NewRegistry().
+		Register(soroban.Testnet, testnet).
+		Register(soroban.Mainnet, mainnet)
+
+	if tags := registry.Tags(); strings.Join(tags, ",") != "mainnet,testnet" {
+		t.Fatal(tags)
+	}
+	client, err := registry.Client(soroban.Testnet)
+	if err != nil || client != testnet {
+		t.Fatal(client, err)
+	}
+	tag, client, err := registry.ByPassphrase(network.PublicNetworkPassphrase)
+	if err != nil || tag != soroban.Mainnet || client != mainnet {
+		t.Fatal(tag, client, err)
+	}
+	if _, err := registry.Contract("tenant-a"); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorNetworkNotRegistered) {
+		t.Fatal(err)
+	}
+	registry.Remove(soroban.Testnet)
+	if _, err := registry.Transaction(soroban.Testnet); err == nil {
+		t.Fatal("removed network still registered")
+	}
+}
@@ -0,0 +1,69 @@
+package soroban
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// TransactionRejectedError is the error of a transaction rejected by
+// sendTransaction, with the status ERROR or TRY_AGAIN_LATER, returned by Err
+// and by the PendingTransaction. For ERROR it has the decoded
+// errorResultXdr, to branch on its codes:
+//
+//	var rejected *soroban.TransactionRejectedError
+//	if errors.As(err, &rejected) && rejected.Code == xdr.TransactionResultCodeTxBadSeq {
+type TransactionRejectedError struct {
+	Hash   string
+	Status string
+	// Code of the transaction, of the inner transaction for fee bumps
+	Code xdr.TransactionResultCode
+	// OperationCodes are the result codes of the operations, see
+	// TransactionFailedError
+	OperationCodes []string
+	// DiagnosticEvents of the rejection, summarized in the message
+	DiagnosticEvents []DiagnosticEvent
+	Result           *SendTransactionResult
+}
+
+func (e *TransactionRejectedError) Error() string {
+	msg := fmt.Sprintf("%s: %s", ErrorTransactionRejected, e.Status)
+	if e.Status == "ERROR" && e.Result != nil && e.Result.ErrorResultXdr != "" {
+		msg += " " + e.Code.String()
+	}
+	if len(e.OperationCodes) > 0 {
+		msg += " (" + strings.Join(e.OperationCodes, ", ") + ")"
+	}
+	return withEvents(msg, e.DiagnosticEvents)
+}
+
+// Err returns a *TransactionRejectedError if the transaction was rejected,
+// nil otherwise
+func (r *SendTransactionResult) Err() error {
+	if r.Status != "ERROR" && r.Status != "TRY_AGAIN_LATER" {
+		return nil
+	}
+	rejected := &TransactionRejectedError{Hash: r.Hash, Status: r.Status, Result: r}
+	var result xdr.TransactionResult
+	if r.ErrorResultXdr != "" && xdr.SafeUnmarshalBase64(r.ErrorResultXdr, &result) == nil {
+		rejected.Code, rejected.OperationCodes = resultCodes(result)
+	}
+	rejected.DiagnosticEvents, _ = DecodeDiagnosticEvents(r.DiagnosticEventsXdr)
+	return rejected
+}
+
+// resultCodes returns the code of the transaction, of the inner transaction
+// for fee bumps, and the names of the result codes of its operations
+func resultCodes(result xdr.TransactionResult) (xdr.TransactionResultCode, []string) {
+	code := result.Result.Code
+	if inner, ok := result.Result.GetInnerResultPair(); ok {
+		code = inner.Result.Result.Code
+	}
+	operations, _ := OperationResults(result)
+	var codes []string
+	for _, op := range operations {
+		codes = append(codes, operationCode(op))
+	}
+	return code, codes
+}
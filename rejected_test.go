@@ -0,0 +1,63 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestTransactionRejectedError(t *testing.T) {
+	badSeq, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{Code: xdr.TransactionResultCodeTxBadSeq}})
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "ERROR", ErrorResultXdr: badSeq}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = pending.Wait(context.Background())
+	var rejected *soroban.TransactionRejectedError
+	if !errors.As(err, &rejected) || rejected.Code != xdr.TransactionResultCodeTxBadSeq || rejected.Hash != "abc" ||
+		!strings.HasPrefix(err.Error(), soroban.ErrorTransactionRejected) {
+		t.Fatal(err)
+	}
+
+	resourceLimit, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxFailed,
+		Results: &[]xdr.OperationResult{{
+			Code: xdr.OperationResultCodeOpInner,
+			Tr: &xdr.OperationResultTr{
+				Type:                     xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionResult: &xdr.InvokeHostFunctionResult{Code: xdr.InvokeHostFunctionResultCodeInvokeHostFunctionResourceLimitExceeded},
+			},
+		}},
+	}})
+	sent := &soroban.SendTransactionResult{Status: "ERROR", ErrorResultXdr: resourceLimit}
+	if !errors.As(sent.Err(), &rejected) || rejected.Code != xdr.TransactionResultCodeTxFailed ||
+		len(rejected.OperationCodes) != 1 || rejected.OperationCodes[0] != "InvokeHostFunctionResultCodeInvokeHostFunctionResourceLimitExceeded" {
+		t.Fatal(sent.Err())
+	}
+	if err := (&soroban.SendTransactionResult{Status: "PENDING"}).Err(); err != nil {
+		t.Fatal(err)
+	}
+	if err := (&soroban.SendTransactionResult{Status: "TRY_AGAIN_LATER"}).Err(); err == nil || err.Error() != soroban.ErrorTransactionRejected+": TRY_AGAIN_LATER" {
+		t.Fatal(err)
+	}
+}
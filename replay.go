@@ -0,0 +1,188 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorReplayNotSoroban = "Transaction does not invoke a host function"
+)
+
+// ReplayReport compares a historical transaction with its simulation at the
+// current state of the ledger
+type ReplayReport struct {
+	Hash string
+	// Historical is the result of the transaction when it was applied
+	Historical       *GetTransactionResult
+	HistoricalResult xdr.TransactionResultCode
+	HistoricalReturn *xdr.ScVal
+	HistoricalEvents []xdr.ContractEvent
+	// Simulation is the result of simulating the same host function now
+	Simulation      *SimulateTransactionResult
+	SimulatedReturn *xdr.ScVal
+	SimulatedEvents []xdr.DiagnosticEvent
+	// Footprint is the current state of the entries in the historical footprint
+	Footprint []ReplayFootprintEntry
+	// Differences are the readable differences between both executions
+	Differences []string
+}
+
+// ReplayFootprintEntry is the current state of a ledger entry of the
+// historical footprint
+type ReplayFootprintEntry struct {
+	Key                xdr.LedgerKey
+	ReadWrite          bool
+	Found              bool
+	LiveUntilLedgerSeq int64
+	// Archived is true when the entry ttl expired, it requires a restore
+	Archived bool
+}
+
+// Replay fetches the transaction hash and simulates its host function again,
+// with the authorization in recording mode, reporting the differences with
+// the historical result and the current state of its footprint entries. It is
+// meant for debugging why a transaction failed, or would fail now.
+func (c Client) Replay(ctx context.Context, hash string) (*ReplayReport, error) {
+	res, details, err := c.GetTransactionDetails(ctx, hash)
+	if err != nil {
+		return nil, err
+	}
+	if details == nil {
+		return nil, fmt.Errorf("%s: %s", ErrorTransactionNotFound, hash)
+	}
+	envelope := details.Envelope
+	if details.FeeBump {
+		envelope = *details.InnerEnvelope
+	}
+	if envelope.V1 == nil || len(envelope.V1.Tx.Operations) != 1 ||
+		envelope.V1.Tx.Operations[0].Body.Type != xdr.OperationTypeInvokeHostFunction {
+		return nil, errors.New(ErrorReplayNotSoroban)
+	}
+
+	report := &ReplayReport{Hash: hash, Historical: res, HistoricalResult: details.Result.Result.Code}
+	if details.FeeBump && details.InnerResult != nil {
+		report.HistoricalResult = details.InnerResult.Result.Code
+	}
+	if meta, err := res.Meta(); err == nil {
+		report.HistoricalReturn, _ = MetaReturnValue(*meta)
+		report.HistoricalEvents, _ = MetaContractEvents(*meta)
+	}
+
+	replay, footprint, err := replayEnvelope(envelope)
+	if err != nil {
+		return nil, err
+	}
+	report.Footprint, err = c.footprintState(ctx, footprint)
+	if err != nil {
+		return nil, err
+	}
+	report.Simulation, err = c.SimulateTransactionXDR(ctx, replay)
+	if err != nil {
+		return nil, err
+	}
+	if value, err := report.Simulation.ReturnValue(); err == nil {
+		report.SimulatedReturn = value
+	}
+	for _, e := range report.Simulation.Events {
+		var event xdr.DiagnosticEvent
+		if err := xdr.SafeUnmarshalBase64(e, &event); err == nil {
+			report.SimulatedEvents = append(report.SimulatedEvents, event)
+		}
+	}
+	report.Differences = report.differences()
+	return report, nil
+}
+
+// replayEnvelope returns the envelope without signatures, authorization and
+// transaction data, to be simulated in recording mode, and the historical
+// footprint.
+func replayEnvelope(envelope xdr.TransactionEnvelope) (string, *xdr.LedgerFootprint, error) {
+	b, err := envelope.MarshalBinary()
+	if err != nil {
+		return "", nil, err
+	}
+	var replay xdr.TransactionEnvelope
+	if err := replay.UnmarshalBinary(b); err != nil {
+		return "", nil, err
+	}
+	var footprint *xdr.LedgerFootprint
+	if data, ok := replay.V1.Tx.Ext.GetSorobanData(); ok {
+		footprint = &data.Resources.Footprint
+	}
+	replay.V1.Signatures = nil
+	replay.V1.Tx.Ext = xdr.TransactionExt{V: 0}
+	replay.V1.Tx.Operations[0].Body.InvokeHostFunctionOp.Auth = nil
+	replayXdr, err := xdr.MarshalBase64(replay)
+	return replayXdr, footprint, err
+}
+
+func (c Client) footprintState(ctx context.Context, footprint *xdr.LedgerFootprint) ([]ReplayFootprintEntry, error) {
+	if footprint == nil {
+		return nil, nil
+	}
+	var entries []ReplayFootprintEntry
+	var keys []string
+	for i, ledgerKeys := range [][]xdr.LedgerKey{footprint.ReadWrite, footprint.ReadOnly} {
+		for _, key := range ledgerKeys {
+			k, err := key.MarshalBinaryBase64()
+			if err != nil {
+				return nil, err
+			}
+			keys = append(keys, k)
+			entries = append(entries, ReplayFootprintEntry{Key: key, ReadWrite: i == 0})
+		}
+	}
+	if len(keys) == 0 {
+		return entries, nil
+	}
+	res, err := c.GetLedgerEntries(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	found := map[string]GetLedgerEntry{}
+	for _, e := range res.Entries {
+		found[e.Key] = e
+	}
+	for i, k := range keys {
+		e, ok := found[k]
+		if !ok {
+			continue
+		}
+		entries[i].Found = true
+		entries[i].LiveUntilLedgerSeq = e.LiveUntilLedgerSeq
+		entries[i].Archived = e.LiveUntilLedgerSeq != 0 && e.LiveUntilLedgerSeq < res.LatestLedger
+	}
+	return entries, nil
+}
+
+func (r *ReplayReport) differences() []string {
+	var diff []string
+	succeeded := r.HistoricalResult == xdr.TransactionResultCodeTxSuccess
+	switch {
+	case succeeded && r.Simulation.Error != "":
+		diff = append(diff, "historical succeeded, simulation failed: "+r.Simulation.Error)
+	case !succeeded && r.Simulation.Error == "":
+		diff = append(diff, fmt.Sprintf("historical failed with %s, simulation succeeded", r.HistoricalResult))
+	case !succeeded:
+		diff = append(diff, fmt.Sprintf("historical failed with %s, simulation failed: %s", r.HistoricalResult, r.Simulation.Error))
+	}
+	if r.HistoricalReturn != nil && r.SimulatedReturn != nil {
+		for _, d := range ScValDiff(*r.HistoricalReturn, *r.SimulatedReturn) {
+			diff = append(diff, "return value "+d)
+		}
+	}
+	for _, e := range r.Footprint {
+		key, _ := e.Key.MarshalBinaryBase64()
+		switch {
+		case !e.Found && !e.ReadWrite:
+			diff = append(diff, "footprint entry not found: "+key)
+		case e.Archived:
+			diff = append(diff, "footprint entry archived, requires a restore: "+key)
+		}
+	}
+	return diff
+}
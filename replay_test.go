@@ -0,0 +1,152 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+)
+
+// replayServer answers the methods of results with their JSON, recording
+// the params of every call
+func replayServer(t *testing.T, results map[string]any) (*soroban.Client, map[string]json.RawMessage) {
+	t.Helper()
+	params := map[string]json.RawMessage{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Method string          `json:"method"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		params[req.Method] = req.Params
+		b, err := json.Marshal(results[req.Method])
+		if err != nil {
+			t.Error(err)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, b)
+	}))
+	t.Cleanup(server.Close)
+	return &soroban.Client{Client: rpc.Client{URL: server.URL}, PassPhrase: network.TestNetworkPassphrase}, params
+}
+
+func TestReplay(t *testing.T) {
+	source := keypair.MustRandom()
+	contractID := xdr.ContractId{1}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractID}
+	counter := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: contract, Key: sym("counter"), Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+	instance := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: contract, Key: xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+	missing := xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractData, ContractData: &xdr.LedgerKeyContractData{
+		Contract: contract, Key: sym("missing"), Durability: xdr.ContractDataDurabilityPersistent,
+	}}
+	envelope := xdr.TransactionEnvelope{Type: xdr.EnvelopeTypeEnvelopeTypeTx, V1: &xdr.TransactionV1Envelope{
+		Tx: xdr.Transaction{
+			SourceAccount: xdr.MustMuxedAddress(source.Address()),
+			Fee:           1000,
+			SeqNum:        2,
+			Operations: []xdr.Operation{{Body: xdr.OperationBody{
+				Type: xdr.OperationTypeInvokeHostFunction,
+				InvokeHostFunctionOp: &xdr.InvokeHostFunctionOp{
+					HostFunction: xdr.HostFunction{
+						Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+						InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contract, FunctionName: "increment"},
+					},
+					Auth: []xdr.SorobanAuthorizationEntry{{
+						Credentials: xdr.SorobanCredentials{Type: xdr.SorobanCredentialsTypeSorobanCredentialsSourceAccount},
+						RootInvocation: xdr.SorobanAuthorizedInvocation{Function: xdr.SorobanAuthorizedFunction{
+							Type:       xdr.SorobanAuthorizedFunctionTypeSorobanAuthorizedFunctionTypeContractFn,
+							ContractFn: &xdr.InvokeContractArgs{ContractAddress: contract, FunctionName: "increment"},
+						}},
+					}},
+				},
+			}}},
+			Ext: xdr.TransactionExt{V: 1, SorobanData: &xdr.SorobanTransactionData{Resources: xdr.SorobanResources{
+				Footprint: xdr.LedgerFootprint{ReadOnly: []xdr.LedgerKey{instance, missing}, ReadWrite: []xdr.LedgerKey{counter}},
+			}}},
+		},
+		Signatures: []xdr.DecoratedSignature{{Signature: []byte{1}}},
+	}}
+	envelopeXdr, _ := xdr.MarshalBase64(envelope)
+	resultXdr, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{},
+	}})
+	historical := xdr.Uint32(1)
+	returned := xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &historical}
+	metaXdr, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &returned}}})
+	simulated := xdr.Uint32(2)
+	simulatedXdr, _ := xdr.MarshalBase64(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &simulated})
+	counterXdr, _ := counter.MarshalBinaryBase64()
+	instanceXdr, _ := instance.MarshalBinaryBase64()
+
+	client, params := replayServer(t, map[string]any{
+		soroban.GetTransaction: map[string]any{"status": "SUCCESS", "envelopeXdr": envelopeXdr, "resultXdr": resultXdr, "resultMetaXdr": metaXdr},
+		soroban.GetLedgerEntries: map[string]any{"latestLedger": 100, "entries": []map[string]any{
+			{"key": counterXdr, "liveUntilLedgerSeq": 50},
+			{"key": instanceXdr, "liveUntilLedgerSeq": 1000},
+		}},
+		soroban.SimulateTransaction: map[string]any{"latestLedger": 100, "results": []map[string]any{{"xdr": simulatedXdr}}},
+	})
+	report, err := client.Replay(context.Background(), "abc")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.HistoricalResult != xdr.TransactionResultCodeTxSuccess || *report.HistoricalReturn.U32 != 1 || *report.SimulatedReturn.U32 != 2 {
+		t.Fatal(report)
+	}
+	if len(report.Footprint) != 3 {
+		t.Fatal(report.Footprint)
+	}
+	written, read, notFound := report.Footprint[0], report.Footprint[1], report.Footprint[2]
+	if !written.ReadWrite || !written.Archived || read.ReadWrite || read.Archived || !read.Found || notFound.Found {
+		t.Fatal(report.Footprint)
+	}
+	if len(report.Differences) != 3 || !strings.HasPrefix(report.Differences[0], "return value") ||
+		!strings.HasPrefix(report.Differences[1], "footprint entry archived") || !strings.HasPrefix(report.Differences[2], "footprint entry not found") {
+		t.Fatal(report.Differences)
+	}
+
+	// simulated without signatures, authorization and transaction data
+	var simulate struct {
+		Transaction string `json:"transaction"`
+	}
+	json.Unmarshal(params[soroban.SimulateTransaction], &simulate)
+	var replayed xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(simulate.Transaction, &replayed); err != nil {
+		t.Fatal(err)
+	}
+	if len(replayed.Signatures()) != 0 || replayed.V1.Tx.Ext.V != 0 || len(replayed.Operations()[0].Body.InvokeHostFunctionOp.Auth) != 0 {
+		t.Fatal(replayed)
+	}
+}
+
+func TestReplayNotSoroban(t *testing.T) {
+	tx, _ := signedTransaction(t)
+	envelopeXdr, _ := tx.Base64()
+	resultXdr, _ := xdr.MarshalBase64(xdr.TransactionResult{Result: xdr.TransactionResultResult{
+		Code: xdr.TransactionResultCodeTxSuccess, Results: &[]xdr.OperationResult{},
+	}})
+	client, _ := replayServer(t, map[string]any{
+		soroban.GetTransaction: map[string]any{"status": "SUCCESS", "envelopeXdr": envelopeXdr, "resultXdr": resultXdr},
+	})
+	if _, err := client.Replay(context.Background(), "abc"); err == nil || err.Error() != soroban.ErrorReplayNotSoroban {
+		t.Fatal(err)
+	}
+
+	client, _ = replayServer(t, map[string]any{soroban.GetTransaction: map[string]any{"status": "NOT_FOUND"}})
+	if _, err := client.Replay(context.Background(), "abc"); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorTransactionNotFound) {
+		t.Fatal(err)
+	}
+}
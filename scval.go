@@ -0,0 +1,134 @@
+package soroban
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/sebamiro/soroban/internal/scval"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorScMapDuplicatedKey = "Map has duplicated key"
+)
+
+// ScValEqual returns if both values are deeply equal
+func ScValEqual(a, b xdr.ScVal) bool {
+	return len(ScValDiff(a, b)) == 0
+}
+
+// ScValDiff returns the differences between a and b, one per mismatching
+// path, e.g. `[1].balance: 10 != 11`. It is empty if the values are equal.
+func ScValDiff(a, b xdr.ScVal) []string {
+	return scValDiff("", a, b, nil)
+}
+
+func scValDiff(path string, a, b xdr.ScVal, diff []string) []string {
+	if a.Type != b.Type {
+		return append(diff, fmt.Sprintf("%s: type %s != %s", pathOrRoot(path), a.Type, b.Type))
+	}
+	switch a.Type {
+	case xdr.ScValTypeScvVec:
+		va, vb := scVec(a), scVec(b)
+		if len(va) != len(vb) {
+			diff = append(diff, fmt.Sprintf("%s: length %d != %d", pathOrRoot(path), len(va), len(vb)))
+		}
+		for i := 0; i < len(va) && i < len(vb); i++ {
+			diff = scValDiff(fmt.Sprintf("%s[%d]", path, i), va[i], vb[i], diff)
+		}
+		return diff
+	case xdr.ScValTypeScvMap:
+		ma, mb := scMap(a), scMap(b)
+		for _, ea := range ma {
+			keyPath := path + "." + ea.Key.String()
+			eb, ok := scMapGet(mb, ea.Key)
+			if !ok {
+				diff = append(diff, fmt.Sprintf("%s: missing in b", keyPath))
+				continue
+			}
+			diff = scValDiff(keyPath, ea.Val, eb.Val, diff)
+		}
+		for _, eb := range mb {
+			if _, ok := scMapGet(ma, eb.Key); !ok {
+				diff = append(diff, fmt.Sprintf("%s.%s: missing in a", path, eb.Key.String()))
+			}
+		}
+		return diff
+	}
+	ba, errA := a.MarshalBinary()
+	bb, errB := b.MarshalBinary()
+	if errA != nil || errB != nil || !bytes.Equal(ba, bb) {
+		diff = append(diff, fmt.Sprintf("%s: %s != %s", pathOrRoot(path), a.String(), b.String()))
+	}
+	return diff
+}
+
+func pathOrRoot(path string) string {
+	if path == "" {
+		return "."
+	}
+	return path
+}
+
+func scVec(v xdr.ScVal) xdr.ScVec {
+	if v.Vec == nil || *v.Vec == nil {
+		return nil
+	}
+	return **v.Vec
+}
+
+func scMap(v xdr.ScVal) xdr.ScMap {
+	if v.Map == nil || *v.Map == nil {
+		return nil
+	}
+	return **v.Map
+}
+
+func scMapGet(m xdr.ScMap, key xdr.ScVal) (xdr.ScMapEntry, bool) {
+	for _, e := range m {
+		if ScValEqual(e.Key, key) {
+			return e, true
+		}
+	}
+	return xdr.ScMapEntry{}, false
+}
+
+// CompareScVal orders values as the Soroban host does: first by type,
+// then by value. It returns -1, 0 or 1.
+func CompareScVal(a, b xdr.ScVal) int {
+	return scval.Compare(a, b)
+}
+
+// NewScVec returns a vec ScVal with the values
+func NewScVec(values ...xdr.ScVal) xdr.ScVal {
+	v := make(xdr.ScVec, len(values))
+	copy(v, values)
+	vp := &v
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vp}
+}
+
+// NewScMap returns a map ScVal with the entries sorted canonically, as
+// required by the host. It returns an error if there are duplicated keys.
+func NewScMap(entries ...xdr.ScMapEntry) (xdr.ScVal, error) {
+	m := make(xdr.ScMap, len(entries))
+	copy(m, entries)
+	if dup, ok := scval.SortMap(m); !ok {
+		return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorScMapDuplicatedKey, dup.String())
+	}
+	mp := &m
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mp}, nil
+}
+
+// ScMapFromMap returns a map ScVal with symbol keys, as contract structs
+// are encoded, sorted canonically.
+func ScMapFromMap(m map[string]xdr.ScVal) (xdr.ScVal, error) {
+	entries := make([]xdr.ScMapEntry, 0, len(m))
+	for k, v := range m {
+		sym := xdr.ScSymbol(k)
+		entries = append(entries, xdr.ScMapEntry{
+			Key: xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym},
+			Val: v,
+		})
+	}
+	return NewScMap(entries...)
+}
@@ -0,0 +1,72 @@
+package soroban_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func u32(i uint32) xdr.ScVal {
+	v := xdr.Uint32(i)
+	return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}
+}
+
+func sym(s string) xdr.ScVal {
+	v := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &v}
+}
+
+func vec(items ...xdr.ScVal) xdr.ScVal {
+	v := &xdr.ScVec{}
+	*v = items
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &v}
+}
+
+func scmap(entries ...xdr.ScMapEntry) xdr.ScVal {
+	m := &xdr.ScMap{}
+	*m = entries
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &m}
+}
+
+func TestScValDiff(t *testing.T) {
+	a := vec(u32(1), scmap(xdr.ScMapEntry{Key: sym("balance"), Val: u32(10)}))
+	b := vec(u32(1), scmap(xdr.ScMapEntry{Key: sym("balance"), Val: u32(11)}))
+
+	if !soroban.ScValEqual(a, a) {
+		t.Fatal("value must equal itself")
+	}
+	diff := soroban.ScValDiff(a, b)
+	if !reflect.DeepEqual(diff, []string{"[1].balance: 10 != 11"}) {
+		t.Fatal(diff)
+	}
+	diff = soroban.ScValDiff(u32(1), sym("a"))
+	if len(diff) != 1 {
+		t.Fatal(diff)
+	}
+}
+
+func TestNewScMap(t *testing.T) {
+	m, err := soroban.NewScMap(
+		xdr.ScMapEntry{Key: sym("b"), Val: u32(2)},
+		xdr.ScMapEntry{Key: u32(9), Val: u32(3)},
+		xdr.ScMapEntry{Key: sym("a"), Val: u32(1)},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	keys := []xdr.ScVal{u32(9), sym("a"), sym("b")}
+	for i, e := range **m.Map {
+		if !soroban.ScValEqual(e.Key, keys[i]) {
+			t.Fatal(i, e.Key.String())
+		}
+	}
+	_, err = soroban.NewScMap(
+		xdr.ScMapEntry{Key: sym("a"), Val: u32(2)},
+		xdr.ScMapEntry{Key: sym("a"), Val: u32(1)},
+	)
+	if err == nil {
+		t.Fatal("expected duplicated key error")
+	}
+}
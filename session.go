@@ -0,0 +1,159 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorSessionClosed = "Invocation session is closed"
+)
+
+type (
+	// InvocationSession serializes the invocations of a Contract, so they can
+	// be queued from multiple goroutines without sequence number collisions.
+	// Invocations are submitted in the order they were queued.
+	InvocationSession struct {
+		ctx      context.Context
+		contract *Contract
+		queue    chan *sessionCall
+		// footprints is set by OrderConflicts
+		footprints *footprintTracker
+
+		mu     sync.Mutex
+		closed bool
+		// queuing counts the invocations waiting for room in queue, Close
+		// waits for them before closing it
+		queuing sync.WaitGroup
+		wg      sync.WaitGroup
+	}
+
+	// InvocationFuture is the handle to the result of a queued invocation
+	InvocationFuture struct {
+		done chan struct{}
+		res  *PendingTransaction
+		err  error
+	}
+
+	sessionCall struct {
+		build   *invokeBuild
+		restore bool
+		future  *InvocationFuture
+	}
+)
+
+// Session starts an InvocationSession bound to the contract source account.
+// The invocations are sent with ctx. The session must be closed to release
+// its worker.
+//
+//	Requires wasm, client, sourceAccount, keyPair, salt
+func (c *Contract) Session(ctx context.Context) *InvocationSession {
+	s := &InvocationSession{
+		ctx:      ctx,
+		contract: c,
+		queue:    make(chan *sessionCall, 64),
+	}
+	s.wg.Add(1)
+	go s.run()
+	return s
+}
+
+// Invoke queues the invocation of function with the params
+func (s *InvocationSession) Invoke(function string, params ...xdr.ScVal) *InvocationFuture {
+	return s.enqueue(&invokeBuild{function: function, prams: params}, false)
+}
+
+// Queue queues an invocation built with Contract.Invoke, if restore is true
+// it behaves like RestoreAndSend.
+func (s *InvocationSession) Queue(b *invokeBuilder, restore bool) *InvocationFuture {
+	build := *b.build
+	build.prams = append([]xdr.ScVal{}, b.build.prams...)
+	return s.enqueue(&build, restore)
+}
+
+func (s *InvocationSession) enqueue(build *invokeBuild, restore bool) *InvocationFuture {
+	f := &InvocationFuture{done: make(chan struct{})}
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		f.resolve(nil, errors.New(ErrorSessionClosed))
+		return f
+	}
+	s.queuing.Add(1)
+	s.mu.Unlock()
+	// a full queue blocks without the lock, so Close and the other
+	// invocations are not blocked with it
+	s.queue <- &sessionCall{build: build, restore: restore, future: f}
+	s.queuing.Done()
+	return f
+}
+
+// Close stops accepting invocations and waits for the queued ones to be submitted
+func (s *InvocationSession) Close() {
+	s.mu.Lock()
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+	s.closed = true
+	s.mu.Unlock()
+	s.queuing.Wait()
+	close(s.queue)
+	s.wg.Wait()
+}
+
+func (s *InvocationSession) run() {
+	defer s.wg.Done()
+	for call := range s.queue {
+		// a submitted transaction consumed the sequence, even with an
+		// *AuditError, unless it was rejected
+		res, err := s.send(call)
+		if res == nil || res.Sent().Status == "ERROR" || res.Sent().Status == "TRY_AGAIN_LATER" {
+			s.resync()
+		}
+		call.future.resolve(res, err)
+	}
+}
+
+func (s *InvocationSession) send(call *sessionCall) (*PendingTransaction, error) {
+	call.build.footprints = s.footprints
+	b := &invokeBuilder{contract: s.contract, build: call.build}
+	if call.restore {
+		return b.RestoreAndSend(s.ctx)
+	}
+	return b.Send(s.ctx)
+}
+
+// resync reloads the sequence number of the source account after a failed
+// submission, the local sequence may have been increased without being used.
+func (s *InvocationSession) resync() {
+	account, ok := s.contract.source.(*Account)
+	if !ok || s.contract.client == nil {
+		return
+	}
+	fresh, err := s.contract.client.GetAccount(s.ctx, account.AccountId)
+	if err != nil {
+		return
+	}
+	account.Sequence = fresh.Sequence
+}
+
+func (f *InvocationFuture) resolve(res *PendingTransaction, err error) {
+	f.res = res
+	f.err = err
+	close(f.done)
+}
+
+// Done is closed once the invocation has been submitted
+func (f *InvocationFuture) Done() <-chan struct{} {
+	return f.done
+}
+
+// Result waits for the invocation to be submitted and returns its result
+func (f *InvocationFuture) Result() (*PendingTransaction, error) {
+	<-f.done
+	return f.res, f.err
+}
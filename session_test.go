@@ -0,0 +1,138 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestSessionOrder(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	var sequences []int64
+	var functions []string
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		sequences = append(sequences, envelope.SeqNum())
+		functions = append(functions, string(envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction.InvokeContract.FunctionName))
+		return &soroban.SendTransactionResult{Status: "PENDING", LatestLedger: latestLedger}, nil
+	}
+
+	session := contract.Session(context.Background())
+	names := []string{"a", "b", "c", "d"}
+	futures := make([]*soroban.InvocationFuture, len(names))
+	for i, name := range names {
+		futures[i] = session.Invoke(name)
+	}
+	session.Close()
+	for _, f := range futures {
+		if _, err := f.Result(); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for i := range names {
+		if functions[i] != names[i] || sequences[i] != int64(i+2) {
+			t.Fatal(functions, sequences)
+		}
+	}
+
+	if _, err := session.Invoke("e").Result(); err == nil || err.Error() != soroban.ErrorSessionClosed {
+		t.Fatal(err)
+	}
+}
+
+func TestSessionCloseFullQueue(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	started, release := make(chan struct{}), make(chan struct{})
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		select {
+		case started <- struct{}{}:
+		default:
+		}
+		<-release
+		return &soroban.SendTransactionResult{Status: "PENDING"}, nil
+	}
+
+	session := contract.Session(context.Background())
+	first := session.Invoke("first")
+	<-started
+	// the worker is busy with the first, fill the queue and block one more
+	var queued []*soroban.InvocationFuture
+	for range 64 {
+		queued = append(queued, session.Invoke("queued"))
+	}
+	blocked := make(chan *soroban.InvocationFuture)
+	go func() { blocked <- session.Invoke("blocked") }()
+	time.Sleep(20 * time.Millisecond)
+	closed := make(chan struct{})
+	go func() {
+		session.Close()
+		close(closed)
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// a closed session fails at once, even while the queue is full
+	probe := make(chan error)
+	go func() {
+		_, err := session.Invoke("late").Result()
+		probe <- err
+	}()
+	select {
+	case err := <-probe:
+		if err == nil || err.Error() != soroban.ErrorSessionClosed {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Invoke blocked on a full queue")
+	}
+
+	// Close waits for the queued invocations
+	close(release)
+	<-closed
+	for _, f := range append(queued, first, <-blocked) {
+		if _, err := f.Result(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestSessionResync(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	statuses := []string{"PENDING", "TRY_AGAIN_LATER", "PENDING"}
+	var sequences []int64
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			t.Fatal(err)
+		}
+		sequences = append(sequences, envelope.SeqNum())
+		res := &soroban.SendTransactionResult{Status: statuses[len(sequences)-1], LatestLedger: latestLedger}
+		// submitted, but not audited
+		return res, &soroban.AuditError{Err: errors.New("disk full")}
+	}
+	fake.GetAccountFunc = func(publicKey string) (*soroban.Account, error) {
+		return &soroban.Account{AccountId: publicKey, Sequence: 2}, nil
+	}
+
+	session := contract.Session(context.Background())
+	futures := []*soroban.InvocationFuture{session.Invoke("a"), session.Invoke("b"), session.Invoke("c")}
+	session.Close()
+	for _, f := range futures {
+		if res, _ := f.Result(); res == nil {
+			t.Fatal("not submitted")
+		}
+	}
+	// only the rejected transaction releases its sequence
+	if fake.Count("getAccount") != 1 || len(sequences) != 3 || sequences[0] != 2 || sequences[1] != 3 || sequences[2] != 3 {
+		t.Fatal(sequences, fake.Calls)
+	}
+}
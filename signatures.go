@@ -0,0 +1,105 @@
+package soroban
+
+import (
+	"bytes"
+	"crypto/sha256"
+
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+// SignatureReport is the result of VerifySignatures
+type SignatureReport struct {
+	// Signed are the signers, and their weights, with a valid signature
+	Signed map[string]int32
+	// Missing are the signers, and their weights, without a valid signature
+	Missing map[string]int32
+	// Unmatched are the signatures not valid for any of the signers
+	Unmatched []xdr.DecoratedSignature
+	// Weight is the sum of the weights of the Signed signers
+	Weight int32
+}
+
+// MissingWeight returns the weight still needed to reach threshold
+func (r SignatureReport) MissingWeight(threshold int32) int32 {
+	if r.Weight >= threshold {
+		return 0
+	}
+	return threshold - r.Weight
+}
+
+// VerifySignatures checks the signatures of the envelope against the signers
+// in signerSummary (as returned by Account.SignerSummary). Ed25519 (G...) and
+// sha256 hash (X...) signers are verified, other signer types are reported as
+// missing. For fee bump envelopes the outer signatures are checked.
+func VerifySignatures(envelopeXdr string, networkPassphrase string, signerSummary map[string]int32) (*SignatureReport, error) {
+	var envelope xdr.TransactionEnvelope
+	err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := network.HashTransactionInEnvelope(envelope, networkPassphrase)
+	if err != nil {
+		return nil, err
+	}
+	signatures := envelope.Signatures()
+	if envelope.IsFeeBump() {
+		signatures = envelope.FeeBumpSignatures()
+	}
+
+	report := &SignatureReport{
+		Signed:  map[string]int32{},
+		Missing: map[string]int32{},
+	}
+	for _, sig := range signatures {
+		signer, ok := matchSignature(hash, sig, signerSummary)
+		if !ok {
+			report.Unmatched = append(report.Unmatched, sig)
+			continue
+		}
+		if _, seen := report.Signed[signer]; !seen {
+			report.Signed[signer] = signerSummary[signer]
+			report.Weight += signerSummary[signer]
+		}
+	}
+	for signer, weight := range signerSummary {
+		if _, ok := report.Signed[signer]; !ok {
+			report.Missing[signer] = weight
+		}
+	}
+	return report, nil
+}
+
+func matchSignature(hash [32]byte, sig xdr.DecoratedSignature, signerSummary map[string]int32) (string, bool) {
+	for signer := range signerSummary {
+		version, payload, err := strkey.DecodeAny(signer)
+		if err != nil {
+			continue
+		}
+		switch version {
+		case strkey.VersionByteAccountID:
+			kp, err := keypair.ParseAddress(signer)
+			if err != nil {
+				continue
+			}
+			hint := kp.Hint()
+			if !bytes.Equal(hint[:], sig.Hint[:]) {
+				continue
+			}
+			if kp.Verify(hash[:], sig.Signature) == nil {
+				return signer, true
+			}
+		case strkey.VersionByteHashX:
+			if !bytes.Equal(payload[len(payload)-4:], sig.Hint[:]) {
+				continue
+			}
+			preimage := sha256.Sum256(sig.Signature)
+			if bytes.Equal(preimage[:], payload) {
+				return signer, true
+			}
+		}
+	}
+	return "", false
+}
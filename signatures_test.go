@@ -0,0 +1,46 @@
+package soroban_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestVerifySignatures(t *testing.T) {
+	alice, _ := keypair.Random()
+	bob, _ := keypair.Random()
+	account := txnbuild.NewSimpleAccount(alice.Address(), 1)
+	tx, err := txnbuild.NewTransaction(txnbuild.TransactionParams{
+		SourceAccount: &account,
+		Operations:    []txnbuild.Operation{&txnbuild.BumpSequence{BumpTo: 10}},
+		BaseFee:       txnbuild.MinBaseFee,
+		Preconditions: txnbuild.Preconditions{TimeBounds: txnbuild.NewTimeout(30)},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tx, err = tx.Sign(network.TestNetworkPassphrase, alice)
+	if err != nil {
+		t.Fatal(err)
+	}
+	envelopeXdr, _ := tx.Base64()
+
+	report, err := soroban.VerifySignatures(envelopeXdr, network.TestNetworkPassphrase, map[string]int32{
+		alice.Address(): 1,
+		bob.Address():   2,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Weight != 1 || report.Missing[bob.Address()] != 2 || report.MissingWeight(3) != 2 {
+		t.Fatal(report)
+	}
+
+	report, _ = soroban.VerifySignatures(envelopeXdr, network.PublicNetworkPassphrase, map[string]int32{alice.Address(): 1})
+	if report.Weight != 0 || len(report.Unmatched) != 1 {
+		t.Fatal("signature must not be valid on another network", report)
+	}
+}
@@ -0,0 +1,71 @@
+package soroban
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// SorobanClient is the interface of the network calls used by Contract and
+// Transaction, implemented by *Client. Every call is canceled when its ctx is
+// done. Depend on it to replace the network in unit tests, e.g. with
+// sorobantest.FakeClient.
+type SorobanClient interface {
+	// NetworkPassphrase returns the passphrase of the network, used to hash
+	// and sign transactions
+	NetworkPassphrase() string
+
+	SendTransaction(ctx context.Context, tx *txnbuild.Transaction) (*SendTransactionResult, error)
+	SendTransactionXDR(ctx context.Context, envelopeXdr string) (*SendTransactionResult, error)
+	SimulateTransaction(ctx context.Context, tx *txnbuild.Transaction) (*SimulateTransactionResult, error)
+	SimulateTransactionXDR(ctx context.Context, envelopeXdr string) (*SimulateTransactionResult, error)
+	SimulateTransactionWithOptions(ctx context.Context, envelopeXdr string, opts SimulateOptions) (*SimulateTransactionResult, error)
+	GetTransaction(ctx context.Context, hash string) (*GetTransactionResult, error)
+	GetHealth(ctx context.Context) (*GetHealthResult, error)
+	GetLedgerEntries(ctx context.Context, keys ...string) (*GetLedgerEntriesResult, error)
+	GetNetwork(ctx context.Context) (*GetNetworkResult, error)
+	GetLatestLedger(ctx context.Context) (*GetLatestLedgerResult, error)
+	GetAccountEntry(ctx context.Context, publicKey string) (*xdr.AccountEntry, error)
+	GetAccount(ctx context.Context, publicKey string) (*Account, error)
+	Fund(ctx context.Context, publicKey string) (*http.Response, error)
+}
+
+var _ SorobanClient = (*Client)(nil)
+
+// NetworkPassphrase returns PassPhrase
+func (c Client) NetworkPassphrase() string {
+	return c.PassPhrase
+}
+
+// Configured is implemented by the clients carrying the options of a Client
+// read by the transactions, contracts and pending transactions using them,
+// e.g. MaxFee, Logger, Metrics or RetryBudget. A SorobanClient wrapping a
+// Client implements it to keep them, the defaults are used otherwise.
+type Configured interface {
+	Config() *Client
+}
+
+// Config returns c, a Client carries its own options
+func (c *Client) Config() *Client {
+	return c
+}
+
+var defaultConfig = &Client{}
+
+// config returns the options of client, the defaults if it is not Configured
+func config(client SorobanClient) *Client {
+	if c, ok := client.(Configured); ok {
+		if config := c.Config(); config != nil {
+			return config
+		}
+	}
+	return defaultConfig
+}
+
+// rootFunder is implemented by the clients able to fund accounts from the
+// root account of the local network, like Client
+type rootFunder interface {
+	FundFromRoot(ctx context.Context, publicKey string, amount string) (*PendingTransaction, error)
+}
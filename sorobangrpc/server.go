@@ -0,0 +1,204 @@
+// Package sorobangrpc serves the Install, Deploy, Invoke and Query operations
+// of the soroban package as the gRPC ContractService of soroban.proto, so
+// the services of other languages use the library instead of
+// re-implementing it.
+//
+//	Example:
+//	 s := grpc.NewServer()
+//	 sorobangrpc.RegisterContractServiceServer(s, sorobangrpc.NewServer(client, account, pair))
+//	 s.Serve(listener)
+package sorobangrpc
+
+import (
+	"context"
+	"crypto/sha256"
+	"errors"
+	"strings"
+	"sync"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Server implements ContractServiceServer, every transaction is sent from
+// one source account and signed with its key pair
+type Server struct {
+	UnimplementedContractServiceServer
+
+	client soroban.SorobanClient
+	source txnbuild.Account
+	kp     *keypair.Full
+	// mu serializes the sending, the transactions share the sequence of
+	// source
+	mu sync.Mutex
+}
+
+// NewServer returns the Server sending the transactions of source, signed
+// with kp, through client
+func NewServer(client soroban.SorobanClient, source txnbuild.Account, kp *keypair.Full) *Server {
+	return &Server{client: client, source: source, kp: kp}
+}
+
+func (s *Server) contract() *soroban.Contract {
+	return soroban.NewContract().Client(s.client).SourceAccount(s.source).KeyPair(s.kp)
+}
+
+// Install uploads the wasm and waits for the transaction
+func (s *Server) Install(ctx context.Context, req *InstallRequest) (*TransactionResponse, error) {
+	if len(req.GetWasm()) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "wasm is required")
+	}
+	hash := sha256.Sum256(req.GetWasm())
+	res, err := s.wait(ctx, func() (*soroban.PendingTransaction, error) {
+		return s.contract().Wasm(req.GetWasm()).Install(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.WasmHash = hash[:]
+	return res, nil
+}
+
+// Deploy creates an instance of the installed wasm and waits for the
+// transaction
+func (s *Server) Deploy(ctx context.Context, req *DeployRequest) (*TransactionResponse, error) {
+	if len(req.GetWasmHash()) != 32 {
+		return nil, status.Error(codes.InvalidArgument, "wasm_hash is not a sha256")
+	}
+	contract := s.contract().WasmHash([32]byte(req.GetWasmHash())).Salt(req.GetSalt())
+	address, err := contract.GetAddress()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res, err := s.wait(ctx, func() (*soroban.PendingTransaction, error) {
+		return contract.Deploy(ctx)
+	})
+	if err != nil {
+		return nil, err
+	}
+	res.ContractId, err = strkey.Encode(strkey.VersionByteContract, address.ContractId[:])
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return res, nil
+}
+
+// Invoke sends the invocation and waits for the transaction
+func (s *Server) Invoke(ctx context.Context, req *InvokeRequest) (*TransactionResponse, error) {
+	contract, args, err := s.invocation(req)
+	if err != nil {
+		return nil, err
+	}
+	return s.wait(ctx, func() (*soroban.PendingTransaction, error) {
+		invoke := contract.Invoke().Function(req.GetFunction()).WithArgs(args...)
+		if req.GetRestore() {
+			return invoke.RestoreAndSend(ctx)
+		}
+		return invoke.Send(ctx)
+	})
+}
+
+// Query simulates the invocation
+func (s *Server) Query(ctx context.Context, req *InvokeRequest) (*QueryResponse, error) {
+	contract, args, err := s.invocation(req)
+	if err != nil {
+		return nil, err
+	}
+	sim, err := contract.Invoke().Function(req.GetFunction()).WithArgs(args...).Simulate(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	val, err := sim.ReturnValue()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	ret, err := xdr.MarshalBase64(val)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	return &QueryResponse{ReturnValue: ret, MinResourceFee: sim.MinResourceFee, LatestLedger: sim.LatestLedger}, nil
+}
+
+// invocation returns the contract and the decoded arguments of req. The
+// wasm of the contract is unknown, its liveness is checked by the simulation.
+func (s *Server) invocation(req *InvokeRequest) (*soroban.Contract, []xdr.ScVal, error) {
+	id, err := strkey.Decode(strkey.VersionByteContract, req.GetContractId())
+	if err != nil {
+		return nil, nil, status.Errorf(codes.InvalidArgument, "contract_id: %v", err)
+	}
+	if req.GetFunction() == "" {
+		return nil, nil, status.Error(codes.InvalidArgument, "function is required")
+	}
+	args := make([]xdr.ScVal, len(req.GetArgs()))
+	for i, arg := range req.GetArgs() {
+		if err := xdr.SafeUnmarshalBase64(arg, &args[i]); err != nil {
+			return nil, nil, status.Errorf(codes.InvalidArgument, "args[%d]: %v", i, err)
+		}
+	}
+	contractId := xdr.ContractId(id)
+	address := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &contractId}
+	return s.contract().Address(address), args, nil
+}
+
+// wait sends the transaction of send, one at a time, and waits for its
+// final result
+func (s *Server) wait(ctx context.Context, send func() (*soroban.PendingTransaction, error)) (*TransactionResponse, error) {
+	s.mu.Lock()
+	pending, err := send()
+	s.mu.Unlock()
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	final, err := pending.Wait(ctx)
+	if err != nil {
+		return nil, toStatus(err)
+	}
+	res := &TransactionResponse{Hash: pending.Hash(), Status: final.Status, Ledger: final.Ledger}
+	if final.Status == "SUCCESS" {
+		if val, err := final.ReturnValue(); err == nil {
+			res.ReturnValue, _ = xdr.MarshalBase64(val)
+		}
+	}
+	return res, nil
+}
+
+// toStatus returns the gRPC status error of err
+func toStatus(err error) error {
+	var rejected *soroban.TransactionRejectedError
+	var rpcErr *soroban.RPCError
+	var statusErr *soroban.StatusError
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case errors.As(err, &rejected):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &rpcErr):
+		return status.Error(rpcCode(rpcErr.Code), err.Error())
+	case errors.As(err, &statusErr):
+		return status.Error(codes.Unavailable, err.Error())
+	case strings.HasPrefix(err.Error(), soroban.ErrorWasmCodeNeedsRestore),
+		strings.HasPrefix(err.Error(), soroban.ErrorContractNeedsRestore),
+		strings.HasPrefix(err.Error(), soroban.ErrorContractDataNeedsRestore):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	}
+	return status.Error(codes.Internal, err.Error())
+}
+
+// rpcCode returns the gRPC code of a JSON-RPC error code, the errors defined
+// by the server are Unavailable
+func rpcCode(code int) codes.Code {
+	switch code {
+	case soroban.CodeInvalidParams, soroban.CodeInvalidRequest:
+		return codes.InvalidArgument
+	case soroban.CodeMethodNotFound:
+		return codes.Unimplemented
+	case soroban.CodeParseError, soroban.CodeInternalError:
+		return codes.Internal
+	}
+	return codes.Unavailable
+}
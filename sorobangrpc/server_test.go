@@ -0,0 +1,128 @@
+package sorobangrpc_test
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobangrpc"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/xdr"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func serve(t *testing.T, fake *sorobantest.FakeClient) sorobangrpc.ContractServiceClient {
+	pair := keypair.MustRandom()
+	server := grpc.NewServer()
+	sorobangrpc.RegisterContractServiceServer(server, sorobangrpc.NewServer(fake, &soroban.Account{AccountId: pair.Address(), Sequence: 1}, pair))
+	listener := bufconn.Listen(1 << 20)
+	go server.Serve(listener)
+	t.Cleanup(server.Stop)
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return sorobangrpc.NewContractServiceClient(conn)
+}
+
+func TestServer(t *testing.T) {
+	world, _ := xdr.MarshalBase64(symbol("World"))
+	var invoked []string
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetLedgerEntriesFunc: func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+			return &soroban.GetLedgerEntriesResult{
+				LatestLedger: 10,
+				Entries:      []soroban.GetLedgerEntry{{LiveUntilLedgerSeq: 1000}, {LiveUntilLedgerSeq: 1000}},
+			}, nil
+		},
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+			var res soroban.SimulateTransactionResult
+			err := json.Unmarshal([]byte(`{"transactionData":"`+data+`","minResourceFee":"100","latestLedger":10,"results":[{"xdr":"`+world+`"}]}`), &res)
+			return &res, err
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			fn := envelope.Operations()[0].Body.InvokeHostFunctionOp.HostFunction
+			if fn.Type == xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+				invoked = append(invoked, string(fn.InvokeContract.FunctionName))
+			}
+			return &soroban.SendTransactionResult{Hash: "h", Status: "PENDING", LatestLedger: 10}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			ret := symbol("World")
+			meta, _ := xdr.MarshalBase64(xdr.TransactionMeta{V: 4, V4: &xdr.TransactionMetaV4{SorobanMeta: &xdr.SorobanTransactionMetaV2{ReturnValue: &ret}}})
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, Ledger: 11, ResultMetaXdr: meta}, nil
+		},
+	}
+	client := serve(t, fake)
+	ctx := context.Background()
+
+	installed, err := client.Install(ctx, &sorobangrpc.InstallRequest{Wasm: []byte{0, 'a', 's', 'm'}})
+	if err != nil || installed.Status != "SUCCESS" || len(installed.WasmHash) != 32 {
+		t.Fatal(installed, err)
+	}
+	deployed, err := client.Deploy(ctx, &sorobangrpc.DeployRequest{WasmHash: installed.WasmHash, Salt: "salt"})
+	if err != nil || deployed.ContractId[0] != 'C' {
+		t.Fatal(deployed, err)
+	}
+
+	dev, _ := xdr.MarshalBase64(symbol("Dev"))
+	req := &sorobangrpc.InvokeRequest{ContractId: deployed.ContractId, Function: "hello", Args: []string{dev}}
+	query, err := client.Query(ctx, req)
+	if err != nil || query.ReturnValue != world || query.MinResourceFee != 100 || query.LatestLedger != 10 {
+		t.Fatal(query, err)
+	}
+	if len(invoked) != 0 {
+		t.Fatal("query sent", invoked)
+	}
+	res, err := client.Invoke(ctx, req)
+	if err != nil || res.Hash != "h" || res.Ledger != 11 || res.ReturnValue != world {
+		t.Fatal(res, err)
+	}
+	if len(invoked) != 1 || invoked[0] != "hello" {
+		t.Fatal(invoked)
+	}
+
+	for _, bad := range []*sorobangrpc.InvokeRequest{
+		{ContractId: "C123", Function: "hello"},
+		{ContractId: deployed.ContractId},
+		{ContractId: deployed.ContractId, Function: "hello", Args: []string{"!"}},
+	} {
+		if _, err := client.Invoke(ctx, bad); status.Code(err) != codes.InvalidArgument {
+			t.Fatal(bad, err)
+		}
+	}
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "h", Status: "ERROR"}, nil
+	}
+	if _, err := client.Invoke(ctx, req); status.Code(err) != codes.Aborted {
+		t.Fatal(err)
+	}
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		return nil, &soroban.RPCError{Code: soroban.CodeInvalidParams, Message: "invalid parameters"}
+	}
+	if _, err := client.Query(ctx, req); status.Code(err) != codes.InvalidArgument {
+		t.Fatal(err)
+	}
+}
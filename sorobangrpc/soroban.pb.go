@@ -0,0 +1,449 @@
+// ContractService installs, deploys and invokes Soroban contracts with the
+// github.com/sebamiro/soroban library, for the services that are not
+// written in Go.
+//
+// The values are base64 XDR, as in the Stellar SDKs of every language: the
+// arguments and return values are ScVal, the contracts are C... strkeys.
+//
+// Regenerate with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	  --go-grpc_out=. --go-grpc_opt=paths=source_relative soroban.proto
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.9
+// 	protoc        (unknown)
+// source: soroban.proto
+
+package sorobangrpc
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type InstallRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Wasm          []byte                 `protobuf:"bytes,1,opt,name=wasm,proto3" json:"wasm,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InstallRequest) Reset() {
+	*x = InstallRequest{}
+	mi := &file_soroban_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InstallRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InstallRequest) ProtoMessage() {}
+
+func (x *InstallRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_soroban_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InstallRequest.ProtoReflect.Descriptor instead.
+func (*InstallRequest) Descriptor() ([]byte, []int) {
+	return file_soroban_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *InstallRequest) GetWasm() []byte {
+	if x != nil {
+		return x.Wasm
+	}
+	return nil
+}
+
+type DeployRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// wasm_hash is the sha256 of an installed wasm
+	WasmHash []byte `protobuf:"bytes,1,opt,name=wasm_hash,json=wasmHash,proto3" json:"wasm_hash,omitempty"`
+	// salt makes the contract id unique for the source account
+	Salt          string `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeployRequest) Reset() {
+	*x = DeployRequest{}
+	mi := &file_soroban_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeployRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeployRequest) ProtoMessage() {}
+
+func (x *DeployRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_soroban_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeployRequest.ProtoReflect.Descriptor instead.
+func (*DeployRequest) Descriptor() ([]byte, []int) {
+	return file_soroban_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeployRequest) GetWasmHash() []byte {
+	if x != nil {
+		return x.WasmHash
+	}
+	return nil
+}
+
+func (x *DeployRequest) GetSalt() string {
+	if x != nil {
+		return x.Salt
+	}
+	return ""
+}
+
+type InvokeRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// contract_id is the C... strkey of the contract
+	ContractId string `protobuf:"bytes,1,opt,name=contract_id,json=contractId,proto3" json:"contract_id,omitempty"`
+	Function   string `protobuf:"bytes,2,opt,name=function,proto3" json:"function,omitempty"`
+	// args are the base64 XDR ScVal of the arguments
+	Args []string `protobuf:"bytes,3,rep,name=args,proto3" json:"args,omitempty"`
+	// restore restores the expired entries of the footprint first, Invoke
+	// only
+	Restore       bool `protobuf:"varint,4,opt,name=restore,proto3" json:"restore,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *InvokeRequest) Reset() {
+	*x = InvokeRequest{}
+	mi := &file_soroban_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *InvokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*InvokeRequest) ProtoMessage() {}
+
+func (x *InvokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_soroban_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use InvokeRequest.ProtoReflect.Descriptor instead.
+func (*InvokeRequest) Descriptor() ([]byte, []int) {
+	return file_soroban_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *InvokeRequest) GetContractId() string {
+	if x != nil {
+		return x.ContractId
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *InvokeRequest) GetArgs() []string {
+	if x != nil {
+		return x.Args
+	}
+	return nil
+}
+
+func (x *InvokeRequest) GetRestore() bool {
+	if x != nil {
+		return x.Restore
+	}
+	return false
+}
+
+type TransactionResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	Hash  string                 `protobuf:"bytes,1,opt,name=hash,proto3" json:"hash,omitempty"`
+	// status is the final status, SUCCESS or FAILED, a FAILED transaction is
+	// not an error of the call
+	Status string `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	Ledger int64  `protobuf:"varint,3,opt,name=ledger,proto3" json:"ledger,omitempty"`
+	// return_value is the base64 XDR ScVal returned, of SUCCESS invocations
+	ReturnValue string `protobuf:"bytes,4,opt,name=return_value,json=returnValue,proto3" json:"return_value,omitempty"`
+	// contract_id is the C... strkey of the deployed contract, of Deploy
+	ContractId string `protobuf:"bytes,5,opt,name=contract_id,json=contractId,proto3" json:"contract_id,omitempty"`
+	// wasm_hash is the sha256 of the installed wasm, of Install
+	WasmHash      []byte `protobuf:"bytes,6,opt,name=wasm_hash,json=wasmHash,proto3" json:"wasm_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransactionResponse) Reset() {
+	*x = TransactionResponse{}
+	mi := &file_soroban_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransactionResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransactionResponse) ProtoMessage() {}
+
+func (x *TransactionResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_soroban_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransactionResponse.ProtoReflect.Descriptor instead.
+func (*TransactionResponse) Descriptor() ([]byte, []int) {
+	return file_soroban_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *TransactionResponse) GetHash() string {
+	if x != nil {
+		return x.Hash
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetLedger() int64 {
+	if x != nil {
+		return x.Ledger
+	}
+	return 0
+}
+
+func (x *TransactionResponse) GetReturnValue() string {
+	if x != nil {
+		return x.ReturnValue
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetContractId() string {
+	if x != nil {
+		return x.ContractId
+	}
+	return ""
+}
+
+func (x *TransactionResponse) GetWasmHash() []byte {
+	if x != nil {
+		return x.WasmHash
+	}
+	return nil
+}
+
+type QueryResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// return_value is the base64 XDR ScVal returned
+	ReturnValue    string `protobuf:"bytes,1,opt,name=return_value,json=returnValue,proto3" json:"return_value,omitempty"`
+	MinResourceFee int64  `protobuf:"varint,2,opt,name=min_resource_fee,json=minResourceFee,proto3" json:"min_resource_fee,omitempty"`
+	LatestLedger   int64  `protobuf:"varint,3,opt,name=latest_ledger,json=latestLedger,proto3" json:"latest_ledger,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *QueryResponse) Reset() {
+	*x = QueryResponse{}
+	mi := &file_soroban_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QueryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QueryResponse) ProtoMessage() {}
+
+func (x *QueryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_soroban_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QueryResponse.ProtoReflect.Descriptor instead.
+func (*QueryResponse) Descriptor() ([]byte, []int) {
+	return file_soroban_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *QueryResponse) GetReturnValue() string {
+	if x != nil {
+		return x.ReturnValue
+	}
+	return ""
+}
+
+func (x *QueryResponse) GetMinResourceFee() int64 {
+	if x != nil {
+		return x.MinResourceFee
+	}
+	return 0
+}
+
+func (x *QueryResponse) GetLatestLedger() int64 {
+	if x != nil {
+		return x.LatestLedger
+	}
+	return 0
+}
+
+var File_soroban_proto protoreflect.FileDescriptor
+
+const file_soroban_proto_rawDesc = "" +
+	"\n" +
+	"\rsoroban.proto\x12\n" +
+	"soroban.v1\"$\n" +
+	"\x0eInstallRequest\x12\x12\n" +
+	"\x04wasm\x18\x01 \x01(\fR\x04wasm\"@\n" +
+	"\rDeployRequest\x12\x1b\n" +
+	"\twasm_hash\x18\x01 \x01(\fR\bwasmHash\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\tR\x04salt\"z\n" +
+	"\rInvokeRequest\x12\x1f\n" +
+	"\vcontract_id\x18\x01 \x01(\tR\n" +
+	"contractId\x12\x1a\n" +
+	"\bfunction\x18\x02 \x01(\tR\bfunction\x12\x12\n" +
+	"\x04args\x18\x03 \x03(\tR\x04args\x12\x18\n" +
+	"\arestore\x18\x04 \x01(\bR\arestore\"\xba\x01\n" +
+	"\x13TransactionResponse\x12\x12\n" +
+	"\x04hash\x18\x01 \x01(\tR\x04hash\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x16\n" +
+	"\x06ledger\x18\x03 \x01(\x03R\x06ledger\x12!\n" +
+	"\freturn_value\x18\x04 \x01(\tR\vreturnValue\x12\x1f\n" +
+	"\vcontract_id\x18\x05 \x01(\tR\n" +
+	"contractId\x12\x1b\n" +
+	"\twasm_hash\x18\x06 \x01(\fR\bwasmHash\"\x81\x01\n" +
+	"\rQueryResponse\x12!\n" +
+	"\freturn_value\x18\x01 \x01(\tR\vreturnValue\x12(\n" +
+	"\x10min_resource_fee\x18\x02 \x01(\x03R\x0eminResourceFee\x12#\n" +
+	"\rlatest_ledger\x18\x03 \x01(\x03R\flatestLedger2\xa4\x02\n" +
+	"\x0fContractService\x12F\n" +
+	"\aInstall\x12\x1a.soroban.v1.InstallRequest\x1a\x1f.soroban.v1.TransactionResponse\x12D\n" +
+	"\x06Deploy\x12\x19.soroban.v1.DeployRequest\x1a\x1f.soroban.v1.TransactionResponse\x12D\n" +
+	"\x06Invoke\x12\x19.soroban.v1.InvokeRequest\x1a\x1f.soroban.v1.TransactionResponse\x12=\n" +
+	"\x05Query\x12\x19.soroban.v1.InvokeRequest\x1a\x19.soroban.v1.QueryResponseB)Z'github.com/sebamiro/soroban/sorobangrpcb\x06proto3"
+
+var (
+	file_soroban_proto_rawDescOnce sync.Once
+	file_soroban_proto_rawDescData []byte
+)
+
+func file_soroban_proto_rawDescGZIP() []byte {
+	file_soroban_proto_rawDescOnce.Do(func() {
+		file_soroban_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_soroban_proto_rawDesc), len(file_soroban_proto_rawDesc)))
+	})
+	return file_soroban_proto_rawDescData
+}
+
+var file_soroban_proto_msgTypes = make([]protoimpl.MessageInfo, 5)
+var file_soroban_proto_goTypes = []any{
+	(*InstallRequest)(nil),      // 0: soroban.v1.InstallRequest
+	(*DeployRequest)(nil),       // 1: soroban.v1.DeployRequest
+	(*InvokeRequest)(nil),       // 2: soroban.v1.InvokeRequest
+	(*TransactionResponse)(nil), // 3: soroban.v1.TransactionResponse
+	(*QueryResponse)(nil),       // 4: soroban.v1.QueryResponse
+}
+var file_soroban_proto_depIdxs = []int32{
+	0, // 0: soroban.v1.ContractService.Install:input_type -> soroban.v1.InstallRequest
+	1, // 1: soroban.v1.ContractService.Deploy:input_type -> soroban.v1.DeployRequest
+	2, // 2: soroban.v1.ContractService.Invoke:input_type -> soroban.v1.InvokeRequest
+	2, // 3: soroban.v1.ContractService.Query:input_type -> soroban.v1.InvokeRequest
+	3, // 4: soroban.v1.ContractService.Install:output_type -> soroban.v1.TransactionResponse
+	3, // 5: soroban.v1.ContractService.Deploy:output_type -> soroban.v1.TransactionResponse
+	3, // 6: soroban.v1.ContractService.Invoke:output_type -> soroban.v1.TransactionResponse
+	4, // 7: soroban.v1.ContractService.Query:output_type -> soroban.v1.QueryResponse
+	4, // [4:8] is the sub-list for method output_type
+	0, // [0:4] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_soroban_proto_init() }
+func file_soroban_proto_init() {
+	if File_soroban_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_soroban_proto_rawDesc), len(file_soroban_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   5,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_soroban_proto_goTypes,
+		DependencyIndexes: file_soroban_proto_depIdxs,
+		MessageInfos:      file_soroban_proto_msgTypes,
+	}.Build()
+	File_soroban_proto = out.File
+	file_soroban_proto_goTypes = nil
+	file_soroban_proto_depIdxs = nil
+}
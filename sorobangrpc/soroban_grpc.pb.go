@@ -0,0 +1,259 @@
+// ContractService installs, deploys and invokes Soroban contracts with the
+// github.com/sebamiro/soroban library, for the services that are not
+// written in Go.
+//
+// The values are base64 XDR, as in the Stellar SDKs of every language: the
+// arguments and return values are ScVal, the contracts are C... strkeys.
+//
+// Regenerate with:
+//
+//	protoc --go_out=. --go_opt=paths=source_relative \
+//	  --go-grpc_out=. --go-grpc_opt=paths=source_relative soroban.proto
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             (unknown)
+// source: soroban.proto
+
+package sorobangrpc
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	ContractService_Install_FullMethodName = "/soroban.v1.ContractService/Install"
+	ContractService_Deploy_FullMethodName  = "/soroban.v1.ContractService/Deploy"
+	ContractService_Invoke_FullMethodName  = "/soroban.v1.ContractService/Invoke"
+	ContractService_Query_FullMethodName   = "/soroban.v1.ContractService/Query"
+)
+
+// ContractServiceClient is the client API for ContractService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ContractServiceClient interface {
+	// Install uploads a wasm and waits for the transaction
+	Install(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Deploy creates an instance of an installed wasm and waits for the
+	// transaction, the contract id is in the response
+	Deploy(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Invoke sends an invocation of a contract function and waits for the
+	// transaction
+	Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*TransactionResponse, error)
+	// Query simulates an invocation of a contract function, nothing is sent
+	Query(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*QueryResponse, error)
+}
+
+type contractServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewContractServiceClient(cc grpc.ClientConnInterface) ContractServiceClient {
+	return &contractServiceClient{cc}
+}
+
+func (c *contractServiceClient) Install(ctx context.Context, in *InstallRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, ContractService_Install_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractServiceClient) Deploy(ctx context.Context, in *DeployRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, ContractService_Deploy_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractServiceClient) Invoke(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*TransactionResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransactionResponse)
+	err := c.cc.Invoke(ctx, ContractService_Invoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *contractServiceClient) Query(ctx context.Context, in *InvokeRequest, opts ...grpc.CallOption) (*QueryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QueryResponse)
+	err := c.cc.Invoke(ctx, ContractService_Query_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ContractServiceServer is the server API for ContractService service.
+// All implementations must embed UnimplementedContractServiceServer
+// for forward compatibility.
+type ContractServiceServer interface {
+	// Install uploads a wasm and waits for the transaction
+	Install(context.Context, *InstallRequest) (*TransactionResponse, error)
+	// Deploy creates an instance of an installed wasm and waits for the
+	// transaction, the contract id is in the response
+	Deploy(context.Context, *DeployRequest) (*TransactionResponse, error)
+	// Invoke sends an invocation of a contract function and waits for the
+	// transaction
+	Invoke(context.Context, *InvokeRequest) (*TransactionResponse, error)
+	// Query simulates an invocation of a contract function, nothing is sent
+	Query(context.Context, *InvokeRequest) (*QueryResponse, error)
+	mustEmbedUnimplementedContractServiceServer()
+}
+
+// UnimplementedContractServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedContractServiceServer struct{}
+
+func (UnimplementedContractServiceServer) Install(context.Context, *InstallRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Install not implemented")
+}
+func (UnimplementedContractServiceServer) Deploy(context.Context, *DeployRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Deploy not implemented")
+}
+func (UnimplementedContractServiceServer) Invoke(context.Context, *InvokeRequest) (*TransactionResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Invoke not implemented")
+}
+func (UnimplementedContractServiceServer) Query(context.Context, *InvokeRequest) (*QueryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Query not implemented")
+}
+func (UnimplementedContractServiceServer) mustEmbedUnimplementedContractServiceServer() {}
+func (UnimplementedContractServiceServer) testEmbeddedByValue()                         {}
+
+// UnsafeContractServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ContractServiceServer will
+// result in compilation errors.
+type UnsafeContractServiceServer interface {
+	mustEmbedUnimplementedContractServiceServer()
+}
+
+func RegisterContractServiceServer(s grpc.ServiceRegistrar, srv ContractServiceServer) {
+	// If the following call pancis, it indicates UnimplementedContractServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&ContractService_ServiceDesc, srv)
+}
+
+func _ContractService_Install_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InstallRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractServiceServer).Install(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContractService_Install_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractServiceServer).Install(ctx, req.(*InstallRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContractService_Deploy_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeployRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractServiceServer).Deploy(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContractService_Deploy_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractServiceServer).Deploy(ctx, req.(*DeployRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContractService_Invoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractServiceServer).Invoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContractService_Invoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractServiceServer).Invoke(ctx, req.(*InvokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ContractService_Query_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(InvokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ContractServiceServer).Query(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ContractService_Query_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ContractServiceServer).Query(ctx, req.(*InvokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ContractService_ServiceDesc is the grpc.ServiceDesc for ContractService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ContractService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "soroban.v1.ContractService",
+	HandlerType: (*ContractServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Install",
+			Handler:    _ContractService_Install_Handler,
+		},
+		{
+			MethodName: "Deploy",
+			Handler:    _ContractService_Deploy_Handler,
+		},
+		{
+			MethodName: "Invoke",
+			Handler:    _ContractService_Invoke_Handler,
+		},
+		{
+			MethodName: "Query",
+			Handler:    _ContractService_Query_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "soroban.proto",
+}
@@ -0,0 +1,101 @@
+// Package sorobanprom implements soroban.Metrics with Prometheus collectors.
+//
+//	Example:
+//	 metrics := sorobanprom.New("myservice")
+//	 prometheus.MustRegister(metrics)
+//	 client.Metrics = metrics
+package sorobanprom
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/sebamiro/soroban"
+)
+
+// Metrics collects the measurements of a soroban.Client, it is a
+// prometheus.Collector
+type Metrics struct {
+	rpcDuration *prometheus.HistogramVec
+	rpcErrors   *prometheus.CounterVec
+	submissions *prometheus.CounterVec
+	completions *prometheus.CounterVec
+	simulations prometheus.Counter
+	restores    prometheus.Counter
+}
+
+var _ soroban.Metrics = (*Metrics)(nil)
+
+// New returns the metrics, named <namespace>_soroban_*:
+//
+//	rpc_duration_seconds{method}   histogram of the RPC calls
+//	rpc_errors_total{method}       failed RPC calls
+//	submissions_total{status}      sendTransaction results, "" if it failed
+//	transactions_total{status}     final results of the waited transactions
+//	simulation_failures_total      failed simulations
+//	restores_total                 submitted restores
+func New(namespace string) *Metrics {
+	opts := func(name string, help string) prometheus.Opts {
+		return prometheus.Opts{Namespace: namespace, Subsystem: "soroban", Name: name, Help: help}
+	}
+	return &Metrics{
+		rpcDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "soroban",
+			Name:      "rpc_duration_seconds",
+			Help:      "Duration of the RPC calls, by method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method"}),
+		rpcErrors:   prometheus.NewCounterVec(prometheus.CounterOpts(opts("rpc_errors_total", "Failed RPC calls, by method.")), []string{"method"}),
+		submissions: prometheus.NewCounterVec(prometheus.CounterOpts(opts("submissions_total", "Submitted transactions, by sendTransaction status.")), []string{"status"}),
+		completions: prometheus.NewCounterVec(prometheus.CounterOpts(opts("transactions_total", "Waited transactions, by final status.")), []string{"status"}),
+		simulations: prometheus.NewCounter(prometheus.CounterOpts(opts("simulation_failures_total", "Failed simulations."))),
+		restores:    prometheus.NewCounter(prometheus.CounterOpts(opts("restores_total", "Submitted restores."))),
+	}
+}
+
+// RPCCall observes the duration of the call and counts its error
+func (m *Metrics) RPCCall(method string, duration time.Duration, err error) {
+	m.rpcDuration.WithLabelValues(method).Observe(duration.Seconds())
+	if err != nil {
+		m.rpcErrors.WithLabelValues(method).Inc()
+	}
+}
+
+// Submitted counts a submission by status
+func (m *Metrics) Submitted(status string) {
+	m.submissions.WithLabelValues(status).Inc()
+}
+
+// Completed counts a final result by status
+func (m *Metrics) Completed(status string) {
+	m.completions.WithLabelValues(status).Inc()
+}
+
+// SimulationFailed counts a failed simulation
+func (m *Metrics) SimulationFailed(err error) {
+	m.simulations.Inc()
+}
+
+// Restored counts a restore
+func (m *Metrics) Restored() {
+	m.restores.Inc()
+}
+
+// Describe implements prometheus.Collector
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	for _, c := range m.collectors() {
+		c.Describe(ch)
+	}
+}
+
+// Collect implements prometheus.Collector
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	for _, c := range m.collectors() {
+		c.Collect(ch)
+	}
+}
+
+func (m *Metrics) collectors() []prometheus.Collector {
+	return []prometheus.Collector{m.rpcDuration, m.rpcErrors, m.submissions, m.completions, m.simulations, m.restores}
+}
@@ -0,0 +1,52 @@
+package sorobanprom_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/sebamiro/soroban/sorobanprom"
+)
+
+func TestMetrics(t *testing.T) {
+	metrics := sorobanprom.New("test")
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(metrics); err != nil {
+		t.Fatal(err)
+	}
+	metrics.RPCCall("getHealth", time.Millisecond, nil)
+	metrics.RPCCall("getHealth", time.Millisecond, errors.New("unavailable"))
+	metrics.Submitted("PENDING")
+	metrics.Completed("SUCCESS")
+	metrics.SimulationFailed(errors.New("failed"))
+	metrics.Restored()
+
+	expected := `
+# HELP test_soroban_rpc_errors_total Failed RPC calls, by method.
+# TYPE test_soroban_rpc_errors_total counter
+test_soroban_rpc_errors_total{method="getHealth"} 1
+# HELP test_soroban_submissions_total Submitted transactions, by sendTransaction status.
+# TYPE test_soroban_submissions_total counter
+test_soroban_submissions_total{status="PENDING"} 1
+# HELP test_soroban_transactions_total Waited transactions, by final status.
+# TYPE test_soroban_transactions_total counter
+test_soroban_transactions_total{status="SUCCESS"} 1
+# HELP test_soroban_simulation_failures_total Failed simulations.
+# TYPE test_soroban_simulation_failures_total counter
+test_soroban_simulation_failures_total 1
+# HELP test_soroban_restores_total Submitted restores.
+# TYPE test_soroban_restores_total counter
+test_soroban_restores_total 1
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(expected),
+		"test_soroban_rpc_errors_total", "test_soroban_submissions_total", "test_soroban_transactions_total",
+		"test_soroban_simulation_failures_total", "test_soroban_restores_total"); err != nil {
+		t.Fatal(err)
+	}
+	if n := testutil.CollectAndCount(metrics, "test_soroban_rpc_duration_seconds"); n != 1 {
+		t.Fatal(n)
+	}
+}
@@ -0,0 +1,290 @@
+// Package sorobansql maps contract data entries and events to flat rows, with
+// JSON columns for the values, to persist a read model of the contracts in a
+// database without writing decoders.
+//
+//	Example:
+//	 store := sorobansql.NewSQLStore(db, "contract_data", "contract_events").NumberedPlaceholders()
+//	 rows, err := sorobansql.ContractDataRows(res.Entries)
+//	 err = store.PutContractData(ctx, rows)
+package sorobansql
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+// ContractDataRow is a contract data entry, unique by ContractID, KeyXDR and
+// Durability
+type ContractDataRow struct {
+	ContractID string
+	// Key and Value are the JSON of the values, see ValueJSON
+	Key    json.RawMessage
+	KeyXDR string
+	// Durability is persistent or temporary
+	Durability string
+	// Instance is true for the instance entry of the contract, its Value is
+	// the executable and the instance storage
+	Instance           bool
+	Value              json.RawMessage
+	ValueXDR           string
+	LastModifiedLedger int64
+	LiveUntilLedger    int64
+}
+
+// EventRow is a contract event, unique by ID, which is also its paging
+// token, the cursor to resume getEvents after it
+type EventRow struct {
+	ID         string
+	ContractID string
+	Type       string
+	Ledger     int64
+	ClosedAt   time.Time
+	TxHash     string
+	Successful bool
+	// Name is the first topic if it is a symbol or a string, usually the
+	// name of the event
+	Name string
+	// Topics is a JSON array and Value the JSON of the values, see ValueJSON
+	Topics   json.RawMessage
+	Value    json.RawMessage
+	ValueXDR string
+}
+
+// Store persists the rows, e.g. SQLStore
+type Store interface {
+	// PutContractData inserts or replaces the entries
+	PutContractData(ctx context.Context, rows []ContractDataRow) error
+	// PutEvents inserts or replaces the events
+	PutEvents(ctx context.Context, rows []EventRow) error
+}
+
+// ContractDataRows maps the contract data entries of getLedgerEntries,
+// other entries are skipped
+func ContractDataRows(entries []soroban.GetLedgerEntry) ([]ContractDataRow, error) {
+	var rows []ContractDataRow
+	for _, entry := range entries {
+		var data xdr.LedgerEntryData
+		if err := xdr.SafeUnmarshalBase64(entry.Xdr, &data); err != nil {
+			return nil, err
+		}
+		if data.Type != xdr.LedgerEntryTypeContractData {
+			continue
+		}
+		row, err := ContractDataRowOf(*data.ContractData)
+		if err != nil {
+			return nil, err
+		}
+		row.LastModifiedLedger = entry.LastModifiedLedgerSeq
+		row.LiveUntilLedger = entry.LiveUntilLedgerSeq
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// ContractDataRowOf maps a contract data entry, without its ledgers
+func ContractDataRowOf(data xdr.ContractDataEntry) (ContractDataRow, error) {
+	contractID, err := data.Contract.String()
+	if err != nil {
+		return ContractDataRow{}, err
+	}
+	row := ContractDataRow{
+		ContractID: contractID,
+		Durability: "persistent",
+		Instance:   data.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance,
+	}
+	if data.Durability == xdr.ContractDataDurabilityTemporary {
+		row.Durability = "temporary"
+	}
+	if row.Key, row.KeyXDR, err = valueColumns(data.Key); err != nil {
+		return ContractDataRow{}, err
+	}
+	if row.Value, row.ValueXDR, err = valueColumns(data.Val); err != nil {
+		return ContractDataRow{}, err
+	}
+	return row, nil
+}
+
+// EventRows maps the events of getEvents
+func EventRows(events []soroban.Event) ([]EventRow, error) {
+	rows := make([]EventRow, 0, len(events))
+	for _, event := range events {
+		row := EventRow{
+			ID:         event.Id,
+			ContractID: event.ContractId,
+			Type:       event.Type,
+			Ledger:     event.Ledger,
+			TxHash:     event.TxHash,
+			Successful: event.InSuccessfulContractCall,
+			ValueXDR:   event.Value,
+		}
+		if event.LedgerClosedAt != "" {
+			closedAt, err := time.Parse(time.RFC3339, event.LedgerClosedAt)
+			if err != nil {
+				return nil, err
+			}
+			row.ClosedAt = closedAt
+		}
+		topics, err := event.Topics()
+		if err != nil {
+			return nil, err
+		}
+		values := make([]any, len(topics))
+		for i, topic := range topics {
+			if values[i], err = jsonValue(topic); err != nil {
+				return nil, err
+			}
+		}
+		if len(topics) > 0 {
+			if name, ok := values[0].(string); ok && (topics[0].Type == xdr.ScValTypeScvSymbol || topics[0].Type == xdr.ScValTypeScvString) {
+				row.Name = name
+			}
+		}
+		if row.Topics, err = json.Marshal(values); err != nil {
+			return nil, err
+		}
+		value, err := event.ScVal()
+		if err != nil {
+			return nil, err
+		}
+		if row.Value, err = ValueJSON(value); err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func valueColumns(val xdr.ScVal) (json.RawMessage, string, error) {
+	b, err := ValueJSON(val)
+	if err != nil {
+		return nil, "", err
+	}
+	valXDR, err := xdr.MarshalBase64(val)
+	if err != nil {
+		return nil, "", err
+	}
+	return b, valXDR, nil
+}
+
+// ValueJSON returns the JSON of val without its contract spec: integers of
+// 64 bits or more are strings, bytes are hex, addresses strkeys, maps with
+// symbol or string keys objects and other maps arrays of {"key", "value"}
+func ValueJSON(val xdr.ScVal) (json.RawMessage, error) {
+	v, err := jsonValue(val)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func jsonValue(val xdr.ScVal) (any, error) {
+	switch val.Type {
+	case xdr.ScValTypeScvVoid:
+		return nil, nil
+	case xdr.ScValTypeScvBool:
+		return *val.B, nil
+	case xdr.ScValTypeScvU32:
+		return uint32(*val.U32), nil
+	case xdr.ScValTypeScvI32:
+		return int32(*val.I32), nil
+	case xdr.ScValTypeScvU64:
+		return fmt.Sprint(uint64(*val.U64)), nil
+	case xdr.ScValTypeScvI64:
+		return fmt.Sprint(int64(*val.I64)), nil
+	case xdr.ScValTypeScvTimepoint:
+		return fmt.Sprint(uint64(*val.Timepoint)), nil
+	case xdr.ScValTypeScvDuration:
+		return fmt.Sprint(uint64(*val.Duration)), nil
+	case xdr.ScValTypeScvU128, xdr.ScValTypeScvI128, xdr.ScValTypeScvU256, xdr.ScValTypeScvI256:
+		n, err := soroban.ScValToBigInt(val)
+		if err != nil {
+			return nil, err
+		}
+		return n.String(), nil
+	case xdr.ScValTypeScvBytes:
+		return hex.EncodeToString(*val.Bytes), nil
+	case xdr.ScValTypeScvString:
+		return string(*val.Str), nil
+	case xdr.ScValTypeScvSymbol:
+		return string(*val.Sym), nil
+	case xdr.ScValTypeScvAddress:
+		return val.Address.String()
+	case xdr.ScValTypeScvVec:
+		values := []any{}
+		if val.Vec != nil && *val.Vec != nil {
+			for _, v := range **val.Vec {
+				value, err := jsonValue(v)
+				if err != nil {
+					return nil, err
+				}
+				values = append(values, value)
+			}
+		}
+		return values, nil
+	case xdr.ScValTypeScvMap:
+		if val.Map == nil || *val.Map == nil {
+			return map[string]any{}, nil
+		}
+		return mapValue(**val.Map)
+	case xdr.ScValTypeScvError:
+		if val.Error.Type == xdr.ScErrorTypeSceContract {
+			return map[string]any{"error": val.Error.Type.String(), "code": uint32(*val.Error.ContractCode)}, nil
+		}
+		return map[string]any{"error": val.Error.Type.String(), "code": val.Error.Code.String()}, nil
+	case xdr.ScValTypeScvLedgerKeyContractInstance:
+		return "instance", nil
+	case xdr.ScValTypeScvLedgerKeyNonce:
+		return map[string]any{"nonce": fmt.Sprint(int64(val.NonceKey.Nonce))}, nil
+	case xdr.ScValTypeScvContractInstance:
+		instance := map[string]any{}
+		switch val.Instance.Executable.Type {
+		case xdr.ContractExecutableTypeContractExecutableWasm:
+			instance["wasmHash"] = hex.EncodeToString(val.Instance.Executable.WasmHash[:])
+		case xdr.ContractExecutableTypeContractExecutableStellarAsset:
+			instance["stellarAsset"] = true
+		}
+		instance["storage"] = map[string]any{}
+		if val.Instance.Storage != nil {
+			storage, err := mapValue(*val.Instance.Storage)
+			if err != nil {
+				return nil, err
+			}
+			instance["storage"] = storage
+		}
+		return instance, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %s", val.Type)
+}
+
+// mapValue returns an object if every key is a symbol or a string, else an
+// array of {"key", "value"}
+func mapValue(m xdr.ScMap) (any, error) {
+	object := map[string]any{}
+	entries := []any{}
+	for _, entry := range m {
+		key, err := jsonValue(entry.Key)
+		if err != nil {
+			return nil, err
+		}
+		value, err := jsonValue(entry.Val)
+		if err != nil {
+			return nil, err
+		}
+		if s, ok := key.(string); ok && object != nil && (entry.Key.Type == xdr.ScValTypeScvSymbol || entry.Key.Type == xdr.ScValTypeScvString) {
+			object[s] = value
+		} else {
+			object = nil
+		}
+		entries = append(entries, map[string]any{"key": key, "value": value})
+	}
+	if object != nil {
+		return object, nil
+	}
+	return entries, nil
+}
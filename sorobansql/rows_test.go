@@ -0,0 +1,108 @@
+package sorobansql_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobansql"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+func sym(s string) xdr.ScVal {
+	symbol := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &symbol}
+}
+
+func TestValueJSON(t *testing.T) {
+	amount, _ := soroban.ToScVal(int64(-5))
+	u64 := xdr.Uint64(18446744073709551615)
+	bytes := xdr.ScBytes{0xca, 0xfe}
+	symbolMap, _ := soroban.ScMapFromMap(map[string]xdr.ScVal{"amount": amount, "to": sym("bob")})
+	u32 := xdr.Uint32(1)
+	otherMap, _ := soroban.NewScMap(xdr.ScMapEntry{Key: xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u32}, Val: sym("one")})
+	code := xdr.Uint32(3)
+	for _, test := range []struct {
+		val  xdr.ScVal
+		json string
+	}{
+		{xdr.ScVal{Type: xdr.ScValTypeScvVoid}, `null`},
+		{xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &u64}, `"18446744073709551615"`},
+		{xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &bytes}, `"cafe"`},
+		{soroban.NewScVec(sym("a"), amount), `["a","-5"]`},
+		{symbolMap, `{"amount":"-5","to":"bob"}`},
+		{otherMap, `[{"key":1,"value":"one"}]`},
+		{xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}, `{"code":3,"error":"ScErrorTypeSceContract"}`},
+	} {
+		b, err := sorobansql.ValueJSON(test.val)
+		if err != nil || string(b) != test.json {
+			t.Error(test.val.Type, string(b), err)
+		}
+	}
+}
+
+func TestContractDataRows(t *testing.T) {
+	id := xdr.ContractId{1}
+	contract := xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}
+	storage := xdr.ScMap{{Key: sym("admin"), Val: sym("alice")}}
+	entry := func(data xdr.ContractDataEntry) soroban.GetLedgerEntry {
+		b, _ := xdr.MarshalBase64(xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeContractData, ContractData: &data})
+		return soroban.GetLedgerEntry{Xdr: b, LastModifiedLedgerSeq: 5, LiveUntilLedgerSeq: 100}
+	}
+	account, _ := xdr.MarshalBase64(xdr.LedgerEntryData{Type: xdr.LedgerEntryTypeAccount, Account: &xdr.AccountEntry{AccountId: xdr.MustAddress(keypair.MustRandom().Address())}})
+	rows, err := sorobansql.ContractDataRows([]soroban.GetLedgerEntry{
+		entry(xdr.ContractDataEntry{
+			Contract:   contract,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+				Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &xdr.Hash{0xab}},
+				Storage:    &storage,
+			}},
+		}),
+		{Xdr: account},
+		entry(xdr.ContractDataEntry{Contract: contract, Key: sym("counter"), Durability: xdr.ContractDataDurabilityTemporary, Val: sym("x")}),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(rows) != 2 {
+		t.Fatal(rows)
+	}
+	instance, counter := rows[0], rows[1]
+	if instance.ContractID != strkey.MustEncode(strkey.VersionByteContract, id[:]) || !instance.Instance || instance.Durability != "persistent" ||
+		string(instance.Value) != `{"storage":{"admin":"alice"},"wasmHash":"ab00000000000000000000000000000000000000000000000000000000000000"}` ||
+		instance.LastModifiedLedger != 5 || instance.LiveUntilLedger != 100 {
+		t.Fatal(instance, string(instance.Value))
+	}
+	var key xdr.ScVal
+	if err := xdr.SafeUnmarshalBase64(counter.KeyXDR, &key); err != nil || *key.Sym != "counter" ||
+		string(counter.Key) != `"counter"` || string(counter.Value) != `"x"` || counter.Durability != "temporary" || counter.Instance {
+		t.Fatal(counter, err)
+	}
+}
+
+func TestEventRows(t *testing.T) {
+	topic, _ := xdr.MarshalBase64(sym("transfer"))
+	value, _ := xdr.MarshalBase64(sym("100"))
+	rows, err := sorobansql.EventRows([]soroban.Event{{
+		Id:                       "0000000021474840576-0000000001",
+		Type:                     "contract",
+		Ledger:                   5,
+		LedgerClosedAt:           "2024-01-02T03:04:05Z",
+		ContractId:               "CA",
+		TxHash:                   "abc",
+		InSuccessfulContractCall: true,
+		Topic:                    []string{topic, topic},
+		Value:                    value,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	row := rows[0]
+	if row.Name != "transfer" || string(row.Topics) != `["transfer","transfer"]` || string(row.Value) != `"100"` ||
+		row.ClosedAt.Unix() != 1704164645 || !row.Successful || row.ValueXDR != value || row.ID != "0000000021474840576-0000000001" {
+		t.Fatal(row)
+	}
+}
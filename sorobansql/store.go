@@ -0,0 +1,123 @@
+package sorobansql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/sebamiro/soroban/internal/sqlquery"
+)
+
+// ContractDataSchema creates the table of the ContractDataRows, the JSON
+// columns are TEXT to be portable, a driver specific JSON type works too. A
+// row is identified by its contract, durability and key.
+const ContractDataSchema = `CREATE TABLE IF NOT EXISTS %s (
+	contract_id          VARCHAR(56) NOT NULL,
+	key_json             TEXT        NOT NULL,
+	key_xdr              TEXT        NOT NULL,
+	durability           VARCHAR(16) NOT NULL,
+	instance             BOOLEAN     NOT NULL,
+	value_json           TEXT        NOT NULL,
+	value_xdr            TEXT        NOT NULL,
+	last_modified_ledger BIGINT      NOT NULL,
+	live_until_ledger    BIGINT      NOT NULL,
+	PRIMARY KEY (contract_id, durability, key_xdr)
+)`
+
+// EventsSchema creates the table of the EventRows, closed_at is a unix time
+// in seconds
+const EventsSchema = `CREATE TABLE IF NOT EXISTS %s (
+	id          VARCHAR(64)  PRIMARY KEY,
+	contract_id VARCHAR(56)  NOT NULL,
+	type        VARCHAR(16)  NOT NULL,
+	ledger      BIGINT       NOT NULL,
+	closed_at   BIGINT       NOT NULL,
+	tx_hash     VARCHAR(64)  NOT NULL,
+	successful  BOOLEAN      NOT NULL,
+	name        VARCHAR(64)  NOT NULL,
+	topics_json TEXT         NOT NULL,
+	value_json  TEXT         NOT NULL,
+	value_xdr   TEXT         NOT NULL
+)`
+
+// SQLStore is the reference Store on database/sql tables, see
+// ContractDataSchema and EventsSchema. It only uses portable SQL, every row
+// is deleted and inserted again in a transaction, so it works with any driver.
+type SQLStore struct {
+	db          *sql.DB
+	dataTable   string
+	eventsTable string
+	numbered    bool
+}
+
+var _ Store = (*SQLStore)(nil)
+
+// NewSQLStore returns a Store on the tables dataTable and eventsTable of db
+func NewSQLStore(db *sql.DB, dataTable, eventsTable string) *SQLStore {
+	return &SQLStore{db: db, dataTable: dataTable, eventsTable: eventsTable}
+}
+
+// NumberedPlaceholders uses $1, $2... placeholders, e.g. for PostgreSQL,
+// instead of ?
+func (s *SQLStore) NumberedPlaceholders() *SQLStore {
+	s.numbered = true
+	return s
+}
+
+// CreateTables creates the tables if they do not exist
+func (s *SQLStore) CreateTables(ctx context.Context) error {
+	if _, err := s.db.ExecContext(ctx, fmt.Sprintf(ContractDataSchema, s.dataTable)); err != nil {
+		return err
+	}
+	_, err := s.db.ExecContext(ctx, fmt.Sprintf(EventsSchema, s.eventsTable))
+	return err
+}
+
+// PutContractData implements Store
+func (s *SQLStore) PutContractData(ctx context.Context, rows []ContractDataRow) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, row := range rows {
+		_, err := tx.ExecContext(ctx, s.query(s.dataTable, "DELETE FROM %s WHERE contract_id = ? AND key_xdr = ? AND durability = ?"),
+			row.ContractID, row.KeyXDR, row.Durability)
+		if err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			s.query(s.dataTable, "INSERT INTO %s (contract_id, key_json, key_xdr, durability, instance, value_json, value_xdr, last_modified_ledger, live_until_ledger) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+			row.ContractID, string(row.Key), row.KeyXDR, row.Durability, row.Instance, string(row.Value), row.ValueXDR, row.LastModifiedLedger, row.LiveUntilLedger)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// PutEvents implements Store
+func (s *SQLStore) PutEvents(ctx context.Context, rows []EventRow) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	for _, row := range rows {
+		if _, err := tx.ExecContext(ctx, s.query(s.eventsTable, "DELETE FROM %s WHERE id = ?"), row.ID); err != nil {
+			return err
+		}
+		_, err = tx.ExecContext(ctx,
+			s.query(s.eventsTable, "INSERT INTO %s (id, contract_id, type, ledger, closed_at, tx_hash, successful, name, topics_json, value_json, value_xdr) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"),
+			row.ID, row.ContractID, row.Type, row.Ledger, row.ClosedAt.Unix(), row.TxHash, row.Successful, row.Name, string(row.Topics), string(row.Value), row.ValueXDR)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// query sets the table of q, and numbers its placeholders if needed
+func (s *SQLStore) query(table, q string) string {
+	return sqlquery.Format(table, q, s.numbered)
+}
@@ -0,0 +1,73 @@
+//go:build cgo
+
+package sorobansql_test
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/sebamiro/soroban/sorobansql"
+)
+
+func TestSQLStore(t *testing.T) {
+	for _, numbered := range []bool{false, true} {
+		db, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "soroban.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer db.Close()
+		// sqlite binds $1, $2... by position too
+		store := sorobansql.NewSQLStore(db, "contract_data", "events")
+		if numbered {
+			store.NumberedPlaceholders()
+		}
+		ctx := context.Background()
+		if err := store.CreateTables(ctx); err != nil {
+			t.Fatal(err)
+		}
+
+		row := sorobansql.ContractDataRow{
+			ContractID: "C1", Key: json.RawMessage(`"counter"`), KeyXDR: "AAAADwAAAAdjb3VudGVy", Durability: "persistent",
+			Value: json.RawMessage(`1`), ValueXDR: "AAAAAwAAAAE=", LastModifiedLedger: 10, LiveUntilLedger: 100,
+		}
+		temporary := row
+		temporary.Durability = "temporary"
+		if err := store.PutContractData(ctx, []sorobansql.ContractDataRow{row, temporary}); err != nil {
+			t.Fatal(err)
+		}
+		// replaced
+		row.Value, row.LastModifiedLedger = json.RawMessage(`2`), 11
+		if err := store.PutContractData(ctx, []sorobansql.ContractDataRow{row}); err != nil {
+			t.Fatal(err)
+		}
+		var count int
+		var value string
+		if err := db.QueryRow("SELECT COUNT(*) FROM contract_data").Scan(&count); err != nil || count != 2 {
+			t.Fatal(numbered, count, err)
+		}
+		if err := db.QueryRow("SELECT value_json FROM contract_data WHERE durability = 'persistent'").Scan(&value); err != nil || value != "2" {
+			t.Fatal(numbered, value, err)
+		}
+		// the key is unique
+		if _, err := db.Exec("INSERT INTO contract_data (contract_id, key_json, key_xdr, durability, instance, value_json, value_xdr, last_modified_ledger, live_until_ledger) VALUES ('C1', '', 'AAAADwAAAAdjb3VudGVy', 'persistent', false, '', '', 0, 0)"); err == nil {
+			t.Fatal(numbered, "duplicated contract data")
+		}
+
+		event := sorobansql.EventRow{
+			ID: "0000000042-0000000001", ContractID: "C1", Type: "contract", Ledger: 42, ClosedAt: time.Unix(1700000000, 0),
+			TxHash: "abc", Successful: true, Name: "transfer", Topics: json.RawMessage(`["transfer"]`), Value: json.RawMessage(`1`),
+		}
+		if err := store.PutEvents(ctx, []sorobansql.EventRow{event, event}); err != nil {
+			t.Fatal(err)
+		}
+		var closedAt int64
+		if err := db.QueryRow("SELECT COUNT(*), MAX(closed_at) FROM events").Scan(&count, &closedAt); err != nil || count != 1 || closedAt != 1700000000 {
+			t.Fatal(numbered, count, closedAt, err)
+		}
+	}
+}
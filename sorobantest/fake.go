@@ -0,0 +1,243 @@
+package sorobantest
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// ErrorNotImplemented is returned by FakeClient methods without a function set
+const ErrorNotImplemented = "Fake method not implemented"
+
+// FakeClient implements soroban.SorobanClient without a network, each method
+// calls the function set for it, or fails with ErrorNotImplemented, or the
+// ctx error if it is done. The called methods are recorded in Calls.
+//
+// Example:
+//
+//	fake := &sorobantest.FakeClient{
+//		Passphrase: network.TestNetworkPassphrase,
+//		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+//			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+//		},
+//	}
+//	contract := soroban.NewContract().Client(fake)
+type FakeClient struct {
+	Passphrase string
+	// Options of a Client applied to the transactions and contracts using
+	// the fake, e.g. MaxFee or RetryBudget, optional
+	Options *soroban.Client
+
+	SendTransactionFunc     func(envelopeXdr string) (*soroban.SendTransactionResult, error)
+	SimulateTransactionFunc func(envelopeXdr string) (*soroban.SimulateTransactionResult, error)
+	// SimulateTransactionWithOptionsFunc replaces SimulateTransactionFunc
+	// when set, for the tests of the options
+	SimulateTransactionWithOptionsFunc func(envelopeXdr string, opts soroban.SimulateOptions) (*soroban.SimulateTransactionResult, error)
+	GetTransactionFunc                 func(hash string) (*soroban.GetTransactionResult, error)
+	GetHealthFunc                      func() (*soroban.GetHealthResult, error)
+	GetLedgerEntriesFunc               func(keys ...string) (*soroban.GetLedgerEntriesResult, error)
+	GetNetworkFunc                     func() (*soroban.GetNetworkResult, error)
+	GetLatestLedgerFunc                func() (*soroban.GetLatestLedgerResult, error)
+	GetAccountEntryFunc                func(publicKey string) (*xdr.AccountEntry, error)
+	GetAccountFunc                     func(publicKey string) (*soroban.Account, error)
+	FundFunc                           func(publicKey string) (*http.Response, error)
+
+	mu    sync.Mutex
+	Calls []string
+}
+
+var _ soroban.SorobanClient = (*FakeClient)(nil)
+
+func (f *FakeClient) record(method string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Calls = append(f.Calls, method)
+}
+
+// Count returns the number of calls to method, safe to use while calls are
+// in progress
+func (f *FakeClient) Count(method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	n := 0
+	for _, call := range f.Calls {
+		if call == method {
+			n++
+		}
+	}
+	return n
+}
+
+var _ soroban.Configured = (*FakeClient)(nil)
+
+// Config returns Options
+func (f *FakeClient) Config() *soroban.Client {
+	return f.Options
+}
+
+// NetworkPassphrase returns Passphrase
+func (f *FakeClient) NetworkPassphrase() string {
+	return f.Passphrase
+}
+
+// SendTransaction calls SendTransactionFunc with the envelope of tx
+func (f *FakeClient) SendTransaction(ctx context.Context, tx *txnbuild.Transaction) (*soroban.SendTransactionResult, error) {
+	base64, err := tx.Base64()
+	if err != nil {
+		return nil, err
+	}
+	return f.SendTransactionXDR(ctx, base64)
+}
+
+// SendTransactionXDR calls SendTransactionFunc
+func (f *FakeClient) SendTransactionXDR(ctx context.Context, envelopeXdr string) (*soroban.SendTransactionResult, error) {
+	f.record(soroban.SendTransaction)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.SendTransactionFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.SendTransactionFunc(envelopeXdr)
+}
+
+// SimulateTransaction calls SimulateTransactionFunc with the envelope of tx
+func (f *FakeClient) SimulateTransaction(ctx context.Context, tx *txnbuild.Transaction) (*soroban.SimulateTransactionResult, error) {
+	base64, err := tx.Base64()
+	if err != nil {
+		return nil, err
+	}
+	return f.SimulateTransactionXDR(ctx, base64)
+}
+
+// SimulateTransactionXDR calls SimulateTransactionFunc
+func (f *FakeClient) SimulateTransactionXDR(ctx context.Context, envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+	return f.SimulateTransactionWithOptions(ctx, envelopeXdr, soroban.SimulateOptions{})
+}
+
+// SimulateTransactionWithOptions calls SimulateTransactionWithOptionsFunc,
+// or SimulateTransactionFunc without the options
+func (f *FakeClient) SimulateTransactionWithOptions(ctx context.Context, envelopeXdr string, opts soroban.SimulateOptions) (*soroban.SimulateTransactionResult, error) {
+	f.record(soroban.SimulateTransaction)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	simulate := f.SimulateTransactionFunc
+	if f.SimulateTransactionWithOptionsFunc != nil {
+		simulate = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			return f.SimulateTransactionWithOptionsFunc(envelopeXdr, opts)
+		}
+	}
+	if simulate == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	res, err := simulate(envelopeXdr)
+	if err != nil || res == nil {
+		return res, err
+	}
+	if err := res.Decode(); err != nil {
+		return nil, err
+	}
+	return res, nil
+}
+
+// GetTransaction calls GetTransactionFunc
+func (f *FakeClient) GetTransaction(ctx context.Context, hash string) (*soroban.GetTransactionResult, error) {
+	f.record(soroban.GetTransaction)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetTransactionFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetTransactionFunc(hash)
+}
+
+// GetHealth calls GetHealthFunc
+func (f *FakeClient) GetHealth(ctx context.Context) (*soroban.GetHealthResult, error) {
+	f.record(soroban.GetHealth)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetHealthFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetHealthFunc()
+}
+
+// GetLedgerEntries calls GetLedgerEntriesFunc
+func (f *FakeClient) GetLedgerEntries(ctx context.Context, keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+	f.record(soroban.GetLedgerEntries)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetLedgerEntriesFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetLedgerEntriesFunc(keys...)
+}
+
+// GetNetwork calls GetNetworkFunc
+func (f *FakeClient) GetNetwork(ctx context.Context) (*soroban.GetNetworkResult, error) {
+	f.record(soroban.GetNetwork)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetNetworkFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetNetworkFunc()
+}
+
+// GetLatestLedger calls GetLatestLedgerFunc
+func (f *FakeClient) GetLatestLedger(ctx context.Context) (*soroban.GetLatestLedgerResult, error) {
+	f.record(soroban.GetLatestLedger)
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetLatestLedgerFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetLatestLedgerFunc()
+}
+
+// GetAccountEntry calls GetAccountEntryFunc
+func (f *FakeClient) GetAccountEntry(ctx context.Context, publicKey string) (*xdr.AccountEntry, error) {
+	f.record("getAccountEntry")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetAccountEntryFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetAccountEntryFunc(publicKey)
+}
+
+// GetAccount calls GetAccountFunc
+func (f *FakeClient) GetAccount(ctx context.Context, publicKey string) (*soroban.Account, error) {
+	f.record("getAccount")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.GetAccountFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.GetAccountFunc(publicKey)
+}
+
+// Fund calls FundFunc
+func (f *FakeClient) Fund(ctx context.Context, publicKey string) (*http.Response, error) {
+	f.record("fund")
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if f.FundFunc == nil {
+		return nil, errors.New(ErrorNotImplemented)
+	}
+	return f.FundFunc(publicKey)
+}
@@ -0,0 +1,75 @@
+package sorobantest_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestFakeClient(t *testing.T) {
+	var sent string
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			sent = envelopeXdr
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{TxHash: hash, Status: "SUCCESS"}, nil
+		},
+	}
+	pair := sorobantest.KeyPair(fake, "source")
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := pending.Wait(context.Background())
+	if err != nil || res.Status != "SUCCESS" || res.TxHash != "abc" {
+		t.Fatal(res, err)
+	}
+
+	var envelope xdr.TransactionEnvelope
+	if err := xdr.SafeUnmarshalBase64(sent, &envelope); err != nil {
+		t.Fatal(err)
+	}
+	if len(envelope.Signatures()) != 1 {
+		t.Fatal(envelope)
+	}
+	if len(fake.Calls) != 2 || fake.Calls[0] != soroban.SendTransaction || fake.Calls[1] != soroban.GetTransaction {
+		t.Fatal(fake.Calls)
+	}
+	if _, err := fake.GetHealth(context.Background()); err == nil {
+		t.Fatal("not implemented method succeeded")
+	}
+}
+
+func TestFakeClientOptions(t *testing.T) {
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		Options:    &soroban.Client{MaxFee: 50},
+	}
+	pair := sorobantest.KeyPair(fake, "source")
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorFeeCeilingExceeded) {
+		t.Fatal(err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Fatal(fake.Calls)
+	}
+}
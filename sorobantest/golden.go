@@ -0,0 +1,66 @@
+package sorobantest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// UpdateGoldenEnv is the environment variable rewriting the golden files of
+// AssertGolden with the current envelopes, when set to 1
+const UpdateGoldenEnv = "SOROBAN_UPDATE_GOLDEN"
+
+// AssertGolden fails t if envelopeXdr, a base64 XDR envelope, is not the
+// one of the golden file at path, guarding against encoding changes across
+// library upgrades. The envelopes must be built with soroban.Pin to be
+// reproducible. Run the tests with SOROBAN_UPDATE_GOLDEN=1 to write them.
+//
+// Example:
+//
+//	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+//		sorobantest.AssertGolden(t, "testdata/hello.golden", envelopeXdr)
+//		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+//	}
+func AssertGolden(t testing.TB, path string, envelopeXdr string) {
+	t.Helper()
+	if os.Getenv(UpdateGoldenEnv) == "1" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(envelopeXdr+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("golden %s: %v, run with %s=1 to write it", path, err, UpdateGoldenEnv)
+	}
+	golden := strings.TrimSpace(string(b))
+	if golden == envelopeXdr {
+		return
+	}
+	want, _ := base64.StdEncoding.DecodeString(golden)
+	got, err := base64.StdEncoding.DecodeString(envelopeXdr)
+	if err != nil {
+		t.Fatalf("golden %s: envelope is not base64: %v", path, err)
+	}
+	offset := 0
+	for offset < len(want) && offset < len(got) && want[offset] == got[offset] {
+		offset++
+	}
+	t.Errorf("golden %s: envelope differs at byte %d of %d (golden %d)\n got: %s\nwant: %s",
+		path, offset, len(got), len(want), excerpt(got, offset), excerpt(want, offset))
+}
+
+// excerpt returns the hex of b around offset, the byte at offset in brackets
+func excerpt(b []byte, offset int) string {
+	start, end := max(offset-8, 0), min(offset+8, len(b))
+	if offset >= end {
+		return fmt.Sprintf("%x[]", b[start:end])
+	}
+	return fmt.Sprintf("%x[%02x]%x", b[start:offset], b[offset], b[offset+1:end])
+}
@@ -0,0 +1,41 @@
+package sorobantest_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban/sorobantest"
+)
+
+// recordedTB records the errors of AssertGolden
+type recordedTB struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recordedTB) Helper() {}
+
+func (r *recordedTB) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+func TestAssertGolden(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "testdata", "envelope.golden")
+	t.Setenv(sorobantest.UpdateGoldenEnv, "1")
+	sorobantest.AssertGolden(t, path, "AAAAAQID")
+	if b, err := os.ReadFile(path); err != nil || string(b) != "AAAAAQID\n" {
+		t.Fatal(string(b), err)
+	}
+
+	t.Setenv(sorobantest.UpdateGoldenEnv, "")
+	sorobantest.AssertGolden(t, path, "AAAAAQID")
+	recorded := &recordedTB{TB: t}
+	sorobantest.AssertGolden(recorded, path, "AAAAAQIE")
+	if len(recorded.errors) != 1 || !strings.Contains(recorded.errors[0], "differs at byte 5") ||
+		!strings.Contains(recorded.errors[0], "0000000102[04]") {
+		t.Fatal(recorded.errors)
+	}
+}
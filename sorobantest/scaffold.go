@@ -0,0 +1,213 @@
+package sorobantest
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"math/rand"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/xdr"
+)
+
+// Scaffold configures the test generated by GenerateTest
+type Scaffold struct {
+	// Package of the test file, e.g. token_test
+	Package string
+	// Name of the contract, the test is TestName
+	Name string
+	// WasmPath is the path of the compiled contract, relative to the test
+	WasmPath string
+}
+
+// constructorName is the function run by the host when the contract is
+// deployed
+const constructorName = "__constructor"
+
+type scaffoldFunction struct {
+	Name string
+	// Args is the Go literal of the placeholder args JSON
+	Args string
+	// Want is the Go literal of the placeholder result JSON, empty if the
+	// function returns nothing
+	Want string
+}
+
+var scaffoldTemplate = template.Must(template.New("scaffold").Parse(`// Code generated by soroban-testgen, edit the placeholder args and results.
+
+package {{.Package}}
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+)
+
+func Test{{.Name}}(t *testing.T) {
+	if os.Getenv(soroban.RPCURLEnv) == "" {
+		t.Skip(soroban.RPCURLEnv + " is not set")
+	}
+	ctx := context.Background()
+	config, err := soroban.ConfigFromEnv()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client, err := soroban.NewClient(config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wasm, err := os.ReadFile({{printf "%q" .WasmPath}})
+	if err != nil {
+		t.Fatal(err)
+	}
+{{- if .Constructor}}
+	// Deploy does not pass constructor arguments, deploy the contract with
+	// these placeholder args and remove the skip:
+	//
+	//	{{.Constructor}}
+	t.Skip("the constructor of the contract takes arguments")
+{{- end}}
+	pair, account := sorobantest.FundedAccount(t, client)
+	contract := soroban.NewContract().
+		Wasm(wasm).
+		Client(client).
+		Salt(t.Name()).
+		SourceAccount(account).
+		KeyPair(pair)
+	if _, err := soroban.InstallWasms(ctx, contract); err != nil {
+		t.Fatal(err)
+	}
+	pending, err := contract.Deploy(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Wait(ctx); err != nil {
+		t.Fatal(err)
+	}
+	s, err := contract.Spec()
+	if err != nil {
+		t.Fatal(err)
+	}
+{{range .Functions}}
+	t.Run({{printf "%q" .Name}}, func(t *testing.T) {
+		args, err := s.ArgsFromJSON({{printf "%q" .Name}}, []byte({{.Args}}))
+		if err != nil {
+			t.Fatal(err)
+		}
+		pending, err := contract.Invoke().Function({{printf "%q" .Name}}).WithArgs(args...).Send(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+		res, err := pending.Wait(ctx)
+		if err != nil {
+			t.Fatal(err)
+		}
+{{- if .Want}}
+		result, err := res.ReturnValue()
+		if err != nil {
+			t.Fatal(err)
+		}
+		f, err := s.Function({{printf "%q" .Name}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		got, err := s.ValueToJSON(f.Outputs[0], *result)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := {{.Want}}; string(got) != want {
+			t.Fatalf("got %s, want %s", got, want)
+		}
+{{- else}}
+		_ = res
+{{- end}}
+	})
+{{end -}}
+}
+`))
+
+// GenerateTest returns the source of a Go test of the contract of s, to run
+// against a network configured with soroban.ConfigFromEnv: it installs and
+// deploys the contract and has one subtest per function, invoking it with
+// placeholder args and comparing its result to a placeholder, both random
+// valid JSON values to replace. The constructor is not tested, if it takes
+// arguments the generated test skips, with placeholder args in a comment, as
+// Deploy does not pass them.
+//
+//	Example:
+//	 src, err := sorobantest.GenerateTest(s, sorobantest.Scaffold{Package: "token_test", Name: "Token", WasmPath: "testdata/token.wasm"})
+func GenerateTest(s *spec.Spec, scaffold Scaffold) ([]byte, error) {
+	rng := rand.New(rand.NewSource(1))
+	var constructor string
+	var functions []scaffoldFunction
+	for _, f := range s.Functions() {
+		name := string(f.Name)
+		if strings.HasPrefix(name, "__") && name != constructorName {
+			continue
+		}
+		args, err := scaffoldArgs(s, f, rng)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", name, err)
+		}
+		if name == constructorName {
+			if len(f.Inputs) > 0 {
+				constructor = args
+			}
+			continue
+		}
+		function := scaffoldFunction{Name: name, Args: goString(args)}
+		if len(f.Outputs) > 0 {
+			want, err := s.GenValue(f.Outputs[0], rng)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			b, err := s.ValueToJSON(f.Outputs[0], want)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+			function.Want = goString(string(b))
+		}
+		functions = append(functions, function)
+	}
+	var buf bytes.Buffer
+	err := scaffoldTemplate.Execute(&buf, struct {
+		Scaffold
+		Constructor string
+		Functions   []scaffoldFunction
+	}{scaffold, constructor, functions})
+	if err != nil {
+		return nil, err
+	}
+	return format.Source(buf.Bytes())
+}
+
+// scaffoldArgs returns the placeholder args JSON of f
+func scaffoldArgs(s *spec.Spec, f xdr.ScSpecFunctionV0, rng *rand.Rand) (string, error) {
+	args, err := s.GenArgs(string(f.Name), rng)
+	if err != nil {
+		return "", err
+	}
+	fields := make([]string, len(args))
+	for i, arg := range args {
+		b, err := s.ValueToJSON(f.Inputs[i].Type, arg)
+		if err != nil {
+			return "", err
+		}
+		fields[i] = strconv.Quote(f.Inputs[i].Name) + ":" + string(b)
+	}
+	return "{" + strings.Join(fields, ",") + "}", nil
+}
+
+// goString returns the Go literal of s, a raw string if it has no backquote
+func goString(s string) string {
+	if strings.Contains(s, "`") {
+		return strconv.Quote(s)
+	}
+	return "`" + s + "`"
+}
@@ -0,0 +1,80 @@
+package sorobantest_test
+
+import (
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/xdr"
+)
+
+func scaffoldSpec() *spec.Spec {
+	typeDef := func(t xdr.ScSpecType) xdr.ScSpecTypeDef { return xdr.ScSpecTypeDef{Type: t} }
+	return &spec.Spec{Entries: []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name:   "__constructor",
+			Inputs: []xdr.ScSpecFunctionInputV0{{Name: "admin", Type: typeDef(xdr.ScSpecTypeScSpecTypeAddress)}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "hello",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "to", Type: typeDef(xdr.ScSpecTypeScSpecTypeSymbol)},
+				{Name: "times", Type: typeDef(xdr.ScSpecTypeScSpecTypeU32)},
+			},
+			Outputs: []xdr.ScSpecTypeDef{{Type: xdr.ScSpecTypeScSpecTypeVec, Vec: &xdr.ScSpecTypeVec{ElementType: typeDef(xdr.ScSpecTypeScSpecTypeSymbol)}}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{Name: "reset"}},
+	}}
+}
+
+func TestGenerateTest(t *testing.T) {
+	s := scaffoldSpec()
+	src, err := sorobantest.GenerateTest(s, sorobantest.Scaffold{Package: "hello_test", Name: "Hello", WasmPath: "testdata/hello_world.wasm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the importer caches the packages between the checks
+	fset := token.NewFileSet()
+	imports := importer.ForCompiler(fset, "source", nil)
+	typeCheck(t, fset, imports, src)
+	code := string(src)
+	if !strings.Contains(code, `t.Run("hello"`) || !strings.Contains(code, `t.Run("reset"`) || strings.Contains(code, `t.Run("__constructor"`) ||
+		!strings.Contains(code, "func TestHello(t *testing.T)") || !strings.Contains(code, `os.ReadFile("testdata/hello_world.wasm")`) ||
+		strings.Count(code, "s.ValueToJSON") != 1 {
+		t.Fatal(code)
+	}
+	// the constructor takes an address
+	if !strings.Contains(code, `//	{"admin":"`) || !strings.Contains(code, "t.Skip(") {
+		t.Fatal(code)
+	}
+
+	// without constructor args the test deploys the contract
+	s.Entries = s.Entries[1:]
+	src, err = sorobantest.GenerateTest(s, sorobantest.Scaffold{Package: "hello_test", Name: "Hello", WasmPath: "testdata/hello_world.wasm"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	typeCheck(t, fset, imports, src)
+	if strings.Contains(string(src), "t.Skip(\"the constructor") {
+		t.Fatal(string(src))
+	}
+}
+
+// typeCheck parses and type checks the generated test
+func typeCheck(t *testing.T, fset *token.FileSet, imports types.Importer, src []byte) {
+	t.Helper()
+	file, err := parser.ParseFile(fset, "hello_test.go", src, 0)
+	if err != nil {
+		t.Fatal(err, string(src))
+	}
+	config := types.Config{Importer: imports}
+	if _, err := config.Check("hello_test", fset, []*ast.File{file}, nil); err != nil {
+		t.Fatal(err, string(src))
+	}
+}
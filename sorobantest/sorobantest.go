@@ -0,0 +1,109 @@
+// Package sorobantest provides helpers for tests running against a Soroban
+// network, usually a local standalone one.
+package sorobantest
+
+import (
+	"context"
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+)
+
+var (
+	mu     sync.Mutex
+	funded = map[string]bool{}
+)
+
+// KeyPair returns a key pair derived from name and the client network, the
+// same name always returns the same key pair.
+func KeyPair(client soroban.SorobanClient, name string) *keypair.Full {
+	seed := sha256.Sum256([]byte("sorobantest/" + client.NetworkPassphrase() + "/" + name))
+	pair, err := keypair.FromRawSeed(seed)
+	if err != nil {
+		panic(err)
+	}
+	return pair
+}
+
+// FundedAccount returns the deterministic key pair of the test, and its
+// account loaded from the network. The account is funded with friendbot
+// only if it does not exist yet, so test runs against a long lived local
+// network reuse the same accounts.
+func FundedAccount(t testing.TB, client soroban.SorobanClient) (*keypair.Full, *soroban.Account) {
+	t.Helper()
+	return NamedAccount(t, client, t.Name())
+}
+
+// NamedAccount is like FundedAccount for an account identified by name,
+// so one test can have many accounts or tests can share one.
+func NamedAccount(t testing.TB, client soroban.SorobanClient, name string) (*keypair.Full, *soroban.Account) {
+	t.Helper()
+	pair := KeyPair(client, name)
+
+	mu.Lock()
+	defer mu.Unlock()
+	if !funded[pair.Address()] {
+		if _, err := client.GetAccount(context.Background(), pair.Address()); err != nil {
+			fund(t, client, pair.Address())
+		}
+		funded[pair.Address()] = true
+	}
+
+	var account *soroban.Account
+	var err error
+	for i := 0; i < 10; i++ {
+		account, err = client.GetAccount(context.Background(), pair.Address())
+		if err == nil {
+			return pair, account
+		}
+		time.Sleep(time.Second)
+	}
+	t.Fatal(err)
+	return nil, nil
+}
+
+type rootFunder interface {
+	FundFromRoot(ctx context.Context, publicKey string, amount string) (*soroban.PendingTransaction, error)
+}
+
+// fund funds address with friendbot, falling back to the root account for
+// standalone networks without friendbot
+func fund(t testing.TB, client soroban.SorobanClient, address string) {
+	t.Helper()
+	err := friendbot(client, address)
+	if err == nil {
+		return
+	}
+	root, ok := client.(rootFunder)
+	if !ok {
+		t.Fatal(err)
+	}
+	pending, err := root.FundFromRoot(context.Background(), address, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// friendbot funds address with friendbot, a response other than 2xx is an
+// error
+func friendbot(client soroban.SorobanClient, address string) error {
+	res, err := client.Fund(context.Background(), address)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.StatusCode < 200 || res.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, 512))
+		return fmt.Errorf("friendbot status %s: %s", res.Status, body)
+	}
+	return nil
+}
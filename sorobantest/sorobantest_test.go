@@ -0,0 +1,103 @@
+package sorobantest_test
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/network"
+)
+
+// fatalTB records the failure of a helper instead of failing the test
+type fatalTB struct {
+	testing.TB
+	name   string
+	failed string
+}
+
+func (f *fatalTB) Helper()      {}
+func (f *fatalTB) Name() string { return f.name }
+
+func (f *fatalTB) Fatal(args ...any) {
+	f.failed = fmt.Sprint(args...)
+	runtime.Goexit()
+}
+
+// run runs f with tb, returning once it finished or failed
+func run(tb *fatalTB, f func(testing.TB)) {
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		f(tb)
+	}()
+	<-done
+}
+
+// friendbotClient returns a fake whose accounts exist once friendbot,
+// answering status, funded them
+func friendbotClient(status int) *sorobantest.FakeClient {
+	accounts := map[string]bool{}
+	return &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetAccountFunc: func(publicKey string) (*soroban.Account, error) {
+			if !accounts[publicKey] {
+				return nil, errors.New("not found")
+			}
+			return &soroban.Account{AccountId: publicKey, Sequence: 1}, nil
+		},
+		FundFunc: func(publicKey string) (*http.Response, error) {
+			if status/100 == 2 {
+				accounts[publicKey] = true
+			}
+			return &http.Response{StatusCode: status, Status: http.StatusText(status), Body: io.NopCloser(strings.NewReader("op_already_exists"))}, nil
+		},
+	}
+}
+
+func TestKeyPair(t *testing.T) {
+	fake := &sorobantest.FakeClient{Passphrase: network.TestNetworkPassphrase}
+	if sorobantest.KeyPair(fake, "a").Address() != sorobantest.KeyPair(fake, "a").Address() {
+		t.Fatal("not deterministic")
+	}
+	if sorobantest.KeyPair(fake, "a").Address() == sorobantest.KeyPair(fake, "b").Address() {
+		t.Fatal("same key pair for two names")
+	}
+	public := &sorobantest.FakeClient{Passphrase: network.PublicNetworkPassphrase}
+	if sorobantest.KeyPair(fake, "a").Address() == sorobantest.KeyPair(public, "a").Address() {
+		t.Fatal("same key pair on two networks")
+	}
+}
+
+func TestFundedAccount(t *testing.T) {
+	fake := friendbotClient(http.StatusCreated)
+	tb := &fatalTB{TB: t, name: t.Name()}
+	run(tb, func(tb testing.TB) {
+		pair, account := sorobantest.FundedAccount(tb, fake)
+		if account.AccountId != pair.Address() || pair.Address() != sorobantest.KeyPair(fake, t.Name()).Address() {
+			t.Error(account)
+		}
+		// reused
+		sorobantest.FundedAccount(tb, fake)
+	})
+	if tb.failed != "" || fake.Count("fund") != 1 {
+		t.Fatal(tb.failed, fake.Calls)
+	}
+}
+
+func TestFundedAccountFriendbotError(t *testing.T) {
+	fake := friendbotClient(http.StatusBadRequest)
+	tb := &fatalTB{TB: t, name: t.Name()}
+	run(tb, func(tb testing.TB) {
+		sorobantest.FundedAccount(tb, fake)
+		t.Error("funded")
+	})
+	if !strings.Contains(tb.failed, "friendbot status") || !strings.Contains(tb.failed, "op_already_exists") {
+		t.Fatal(tb.failed)
+	}
+}
@@ -0,0 +1,136 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+)
+
+const (
+	ErrorSourceNotLoaded = "Source account is not loaded"
+)
+
+// LazyAccount is a source account given only by its public key. Its sequence
+// number is loaded with the client of the transaction when it is simulated
+// or sent, and loaded again when it is stale: after a submission rejected
+// without consuming the sequence number, after Refresh or, if MaxAge is set,
+// when it was loaded longer ago, e.g. when the account is shared with other
+// processes.
+//
+//	Example:
+//	 contract.SourceAccountID(pair.Address())
+type LazyAccount struct {
+	AccountID string
+	// MaxAge of the loaded sequence number, 0 means it is loaded once
+	MaxAge time.Duration
+
+	mu       sync.Mutex
+	sequence int64
+	loadedAt time.Time
+}
+
+var _ txnbuild.Account = (*LazyAccount)(nil)
+
+// NewLazyAccount returns the LazyAccount of accountID
+func NewLazyAccount(accountID string) *LazyAccount {
+	return &LazyAccount{AccountID: accountID}
+}
+
+// SourceAccountID sets the source account by its public key, see LazyAccount
+func (t *Transaction) SourceAccountID(accountID string) *Transaction {
+	return t.SourceAccount(NewLazyAccount(accountID))
+}
+
+// SourceAccountID sets the source account by its public key, see LazyAccount
+func (c *Contract) SourceAccountID(accountID string) *Contract {
+	return c.SourceAccount(NewLazyAccount(accountID))
+}
+
+// GetAccountID returns the AccountID
+func (a *LazyAccount) GetAccountID() string {
+	return a.AccountID
+}
+
+// GetSequenceNumber returns the loaded sequence number, it fails with
+// ErrorSourceNotLoaded if it was not loaded
+func (a *LazyAccount) GetSequenceNumber() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loadedAt.IsZero() {
+		return 0, fmt.Errorf("%s: %s", ErrorSourceNotLoaded, a.AccountID)
+	}
+	return a.sequence, nil
+}
+
+// IncrementSequenceNumber increments the loaded sequence number, it fails
+// with ErrorSourceNotLoaded if it was not loaded
+func (a *LazyAccount) IncrementSequenceNumber() (int64, error) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if a.loadedAt.IsZero() {
+		return 0, fmt.Errorf("%s: %s", ErrorSourceNotLoaded, a.AccountID)
+	}
+	if a.sequence == math.MaxInt64 {
+		return 0, errors.New("sequence cannot be increased, it already reached MaxInt64")
+	}
+	a.sequence++
+	return a.sequence, nil
+}
+
+// Load loads the sequence number of the account if it is stale. It is
+// called by Transaction before building the envelope, call it before
+// SignPartial.
+func (a *LazyAccount) Load(ctx context.Context, client SorobanClient) error {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if !a.loadedAt.IsZero() && (a.MaxAge == 0 || time.Since(a.loadedAt) < a.MaxAge) {
+		return nil
+	}
+	account, err := client.GetAccount(ctx, a.AccountID)
+	if err != nil {
+		return err
+	}
+	a.sequence = account.Sequence
+	a.loadedAt = time.Now()
+	return nil
+}
+
+// Refresh marks the sequence number as stale, it is loaded again before the
+// next transaction is built
+func (a *LazyAccount) Refresh() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.loadedAt = time.Time{}
+}
+
+// loadSource loads the source account if it is a LazyAccount, unless the
+// sequence number is pinned. A missing account is funded if the Client
+// AutoFund is set.
+func (t *Transaction) loadSource(ctx context.Context) error {
+	account, ok := t.build.source.(*LazyAccount)
+	if !ok || t.build.sequence != 0 {
+		return nil
+	}
+	err := account.Load(ctx, t.client)
+	if err != nil && err.Error() == ErrorAccountNotFound && autoFund(t.client) {
+		if err := t.fund(ctx); err != nil {
+			return err
+		}
+		return account.Load(ctx, t.client)
+	}
+	return err
+}
+
+// refreshSource marks a LazyAccount source as stale if the submission was
+// rejected, so the sequence number it incremented was not consumed
+func (t *Transaction) refreshSource(res *SendTransactionResult) {
+	account, ok := t.build.source.(*LazyAccount)
+	if ok && (res == nil || res.Status == "ERROR" || res.Status == "TRY_AGAIN_LATER") {
+		account.Refresh()
+	}
+}
@@ -0,0 +1,62 @@
+package soroban_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestLazyAccount(t *testing.T) {
+	pair := keypair.MustRandom()
+	ledgerSequence := int64(5)
+	status := "ERROR"
+	var sequences []int64
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetAccountFunc: func(publicKey string) (*soroban.Account, error) {
+			return &soroban.Account{AccountId: publicKey, Sequence: ledgerSequence}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			xdr.SafeUnmarshalBase64(envelopeXdr, &envelope)
+			sequences = append(sequences, envelope.SeqNum())
+			return &soroban.SendTransactionResult{Hash: "abc", Status: status}, nil
+		},
+	}
+	account := soroban.NewLazyAccount(pair.Address())
+	send := func() {
+		t.Helper()
+		_, err := soroban.NewTransctionBuilder().
+			Client(fake).
+			SourceAccount(account).
+			Signer(pair).
+			Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+			Send(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := account.GetSequenceNumber(); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorSourceNotLoaded) {
+		t.Fatal(err)
+	}
+	// rejected, the sequence number is loaded again
+	send()
+	status = "PENDING"
+	send()
+	send()
+	ledgerSequence = 9
+	account.Refresh()
+	send()
+	if fake.Count("getAccount") != 3 || len(sequences) != 4 ||
+		sequences[0] != 6 || sequences[1] != 6 || sequences[2] != 7 || sequences[3] != 10 {
+		t.Fatal(fake.Count("getAccount"), sequences)
+	}
+}
@@ -0,0 +1,269 @@
+package spec
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// Change is a difference between two versions of a spec
+type Change struct {
+	// Entry is the name of the function or type that changed
+	Entry string
+	// Breaking is true when callers of the old interface may fail with the
+	// new one
+	Breaking bool
+	// Description is a human readable description of the change
+	Description string
+}
+
+func (c Change) String() string {
+	if c.Breaking {
+		return "BREAKING " + c.Entry + ": " + c.Description
+	}
+	return c.Entry + ": " + c.Description
+}
+
+// Compare reports the changes of the interface from old to new. Removed
+// functions and types, changed signatures, fields and enum values are
+// breaking. Added functions, types, union cases and enum values are not.
+func Compare(old, new *Spec) []Change {
+	var changes []Change
+	oldEntries, newEntries := entriesByName(old), entriesByName(new)
+	for _, e := range old.Entries {
+		name := EntryName(e)
+		n, ok := newEntries[entryKey(e)]
+		if !ok {
+			changes = append(changes, Change{Entry: name, Breaking: true, Description: "removed " + kindName(e.Kind)})
+			continue
+		}
+		changes = append(changes, compareEntry(name, e, n)...)
+	}
+	for _, e := range new.Entries {
+		if _, ok := oldEntries[entryKey(e)]; !ok {
+			changes = append(changes, Change{Entry: EntryName(e), Description: "added " + kindName(e.Kind)})
+		}
+	}
+	return changes
+}
+
+// Breaking returns the breaking changes in changes
+func Breaking(changes []Change) []Change {
+	var breaking []Change
+	for _, c := range changes {
+		if c.Breaking {
+			breaking = append(breaking, c)
+		}
+	}
+	return breaking
+}
+
+func compareEntry(name string, old, new xdr.ScSpecEntry) []Change {
+	switch old.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+		return compareFunction(name, *old.FunctionV0, *new.FunctionV0)
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		return compareFields(name, "field", structFields(old.UdtStructV0.Fields), structFields(new.UdtStructV0.Fields))
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		return compareUnion(name, *old.UdtUnionV0, *new.UdtUnionV0)
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		return compareEnum(name, enumCases(old.UdtEnumV0.Cases), enumCases(new.UdtEnumV0.Cases))
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		return compareEnum(name, errorCases(old.UdtErrorEnumV0.Cases), errorCases(new.UdtErrorEnumV0.Cases))
+	case xdr.ScSpecEntryKindScSpecEntryEventV0:
+		return compareFields(name, "param", eventParams(old.EventV0.Params), eventParams(new.EventV0.Params))
+	}
+	return nil
+}
+
+func compareFunction(name string, old, new xdr.ScSpecFunctionV0) []Change {
+	var changes []Change
+	oldInputs, newInputs := functionInputs(old.Inputs), functionInputs(new.Inputs)
+	if signature(oldInputs) != signature(newInputs) {
+		changes = append(changes, Change{
+			Entry:       name,
+			Breaking:    true,
+			Description: fmt.Sprintf("inputs changed from (%s) to (%s)", signature(oldInputs), signature(newInputs)),
+		})
+	}
+	oldOutputs, newOutputs := typeNames(old.Outputs), typeNames(new.Outputs)
+	if oldOutputs != newOutputs {
+		changes = append(changes, Change{
+			Entry:       name,
+			Breaking:    true,
+			Description: fmt.Sprintf("outputs changed from (%s) to (%s)", oldOutputs, newOutputs),
+		})
+	}
+	return changes
+}
+
+func compareFields(name, what string, old, new []Field) []Change {
+	if signature(old) == signature(new) {
+		return nil
+	}
+	return []Change{{
+		Entry:       name,
+		Breaking:    true,
+		Description: fmt.Sprintf("%ss changed from (%s) to (%s)", what, signature(old), signature(new)),
+	}}
+}
+
+func compareUnion(name string, old, new xdr.ScSpecUdtUnionV0) []Change {
+	var changes []Change
+	oldCases, newCases := unionCases(old.Cases), unionCases(new.Cases)
+	for _, c := range old.Cases {
+		caseName, types := unionCase(c)
+		newTypes, ok := newCases[caseName]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Entry: name, Breaking: true, Description: "removed case " + caseName})
+		case types != newTypes:
+			changes = append(changes, Change{
+				Entry:       name,
+				Breaking:    true,
+				Description: fmt.Sprintf("case %s changed from (%s) to (%s)", caseName, types, newTypes),
+			})
+		}
+	}
+	for _, c := range new.Cases {
+		caseName, _ := unionCase(c)
+		if _, ok := oldCases[caseName]; !ok {
+			changes = append(changes, Change{Entry: name, Description: "added case " + caseName})
+		}
+	}
+	return changes
+}
+
+func compareEnum(name string, old, new []EnumCase) []Change {
+	var changes []Change
+	newValues := map[string]uint32{}
+	for _, c := range new {
+		newValues[c.Name] = c.Value
+	}
+	oldValues := map[string]uint32{}
+	for _, c := range old {
+		oldValues[c.Name] = c.Value
+		value, ok := newValues[c.Name]
+		switch {
+		case !ok:
+			changes = append(changes, Change{Entry: name, Breaking: true, Description: "removed case " + c.Name})
+		case value != c.Value:
+			changes = append(changes, Change{
+				Entry:       name,
+				Breaking:    true,
+				Description: fmt.Sprintf("case %s value changed from %d to %d", c.Name, c.Value, value),
+			})
+		}
+	}
+	for _, c := range new {
+		if _, ok := oldValues[c.Name]; !ok {
+			changes = append(changes, Change{Entry: name, Description: fmt.Sprintf("added case %s = %d", c.Name, c.Value)})
+		}
+	}
+	return changes
+}
+
+// entryKey identifies an entry, functions and events live in a different
+// namespace than types.
+func entryKey(e xdr.ScSpecEntry) string {
+	return kindName(e.Kind) + ":" + EntryName(e)
+}
+
+func entriesByName(s *Spec) map[string]xdr.ScSpecEntry {
+	entries := map[string]xdr.ScSpecEntry{}
+	for _, e := range s.Entries {
+		entries[entryKey(e)] = e
+	}
+	return entries
+}
+
+func kindName(kind xdr.ScSpecEntryKind) string {
+	switch kind {
+	case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+		return "function"
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		return "struct"
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		return "union"
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		return "enum"
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		return "error enum"
+	case xdr.ScSpecEntryKindScSpecEntryEventV0:
+		return "event"
+	}
+	return kind.String()
+}
+
+func signature(fields []Field) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, f.Name+": "+f.Type)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func typeNames(types []xdr.ScSpecTypeDef) string {
+	names := make([]string, 0, len(types))
+	for _, t := range types {
+		names = append(names, TypeName(t))
+	}
+	return strings.Join(names, ", ")
+}
+
+func functionInputs(inputs []xdr.ScSpecFunctionInputV0) []Field {
+	fields := make([]Field, 0, len(inputs))
+	for _, in := range inputs {
+		fields = append(fields, Field{Name: in.Name, Type: TypeName(in.Type)})
+	}
+	return fields
+}
+
+func structFields(in []xdr.ScSpecUdtStructFieldV0) []Field {
+	fields := make([]Field, 0, len(in))
+	for _, f := range in {
+		fields = append(fields, Field{Name: f.Name, Type: TypeName(f.Type)})
+	}
+	return fields
+}
+
+func eventParams(params []xdr.ScSpecEventParamV0) []Field {
+	fields := make([]Field, 0, len(params))
+	for _, p := range params {
+		fields = append(fields, Field{Name: p.Name, Type: TypeName(p.Type)})
+	}
+	return fields
+}
+
+func unionCase(c xdr.ScSpecUdtUnionCaseV0) (string, string) {
+	if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0 {
+		return c.VoidCase.Name, ""
+	}
+	return c.TupleCase.Name, typeNames(c.TupleCase.Type)
+}
+
+func unionCases(cases []xdr.ScSpecUdtUnionCaseV0) map[string]string {
+	m := map[string]string{}
+	for _, c := range cases {
+		name, types := unionCase(c)
+		m[name] = types
+	}
+	return m
+}
+
+func enumCases(cases []xdr.ScSpecUdtEnumCaseV0) []EnumCase {
+	out := make([]EnumCase, 0, len(cases))
+	for _, c := range cases {
+		out = append(out, EnumCase{Name: c.Name, Value: uint32(c.Value)})
+	}
+	return out
+}
+
+func errorCases(cases []xdr.ScSpecUdtErrorEnumCaseV0) []EnumCase {
+	out := make([]EnumCase, 0, len(cases))
+	for _, c := range cases {
+		out = append(out, EnumCase{Name: c.Name, Value: uint32(c.Value)})
+	}
+	return out
+}
@@ -0,0 +1,54 @@
+package spec_test
+
+import (
+	"testing"
+
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/xdr"
+)
+
+func function(name string, inputs ...xdr.ScSpecType) xdr.ScSpecEntry {
+	f := &xdr.ScSpecFunctionV0{Name: xdr.ScSymbol(name)}
+	for i, in := range inputs {
+		f.Inputs = append(f.Inputs, xdr.ScSpecFunctionInputV0{Name: string(rune('a' + i)), Type: xdr.ScSpecTypeDef{Type: in}})
+	}
+	return xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: f}
+}
+
+func errorEnum(cases map[string]uint32) xdr.ScSpecEntry {
+	e := &xdr.ScSpecUdtErrorEnumV0{Name: "Error"}
+	for name, value := range cases {
+		e.Cases = append(e.Cases, xdr.ScSpecUdtErrorEnumCaseV0{Name: name, Value: xdr.Uint32(value)})
+	}
+	return xdr.ScSpecEntry{Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0, UdtErrorEnumV0: e}
+}
+
+func TestCompare(t *testing.T) {
+	old := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		function("transfer", xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeI128),
+		function("burn", xdr.ScSpecTypeScSpecTypeI128),
+		function("name"),
+		errorEnum(map[string]uint32{"NotAllowed": 1, "Overflow": 2}),
+	}}
+	new := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		function("transfer", xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeU64),
+		function("name"),
+		function("mint", xdr.ScSpecTypeScSpecTypeI128),
+		errorEnum(map[string]uint32{"NotAllowed": 1, "Overflow": 3}),
+	}}
+
+	changes := spec.Compare(old, new)
+	if len(changes) != 4 {
+		t.Fatal(changes)
+	}
+	breaking := spec.Breaking(changes)
+	if len(breaking) != 3 || breaking[0].Entry != "transfer" || breaking[1].Entry != "burn" || breaking[2].Entry != "Error" {
+		t.Fatal(breaking)
+	}
+	if changes[3].Breaking || changes[3].Entry != "mint" {
+		t.Fatal(changes[3])
+	}
+	if len(spec.Compare(old, old)) != 0 {
+		t.Fatal("same spec has changes")
+	}
+}
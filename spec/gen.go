@@ -0,0 +1,287 @@
+package spec
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strconv"
+
+	"github.com/sebamiro/soroban/internal/scval"
+	"github.com/stellar/go/xdr"
+)
+
+// MaxGenDepth is the maximum nesting of the values generated by GenValue,
+// deeper optional values are generated as void and collections empty.
+const MaxGenDepth = 8
+
+const symbolChars = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789_"
+
+// GenValue returns a random valid value of the type, user defined types are
+// looked up in the spec. The same rng state generates the same value.
+func (s *Spec) GenValue(t xdr.ScSpecTypeDef, rng *rand.Rand) (xdr.ScVal, error) {
+	return s.genValue(t, rng, 0)
+}
+
+// GenArgs returns random valid arguments of the function called name
+func (s *Spec) GenArgs(name string, rng *rand.Rand) ([]xdr.ScVal, error) {
+	f, err := s.Function(name)
+	if err != nil {
+		return nil, err
+	}
+	args := make([]xdr.ScVal, 0, len(f.Inputs))
+	for _, input := range f.Inputs {
+		arg, err := s.GenValue(input.Type, rng)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", input.Name, err)
+		}
+		args = append(args, arg)
+	}
+	return args, nil
+}
+
+func (s *Spec) genValue(t xdr.ScSpecTypeDef, rng *rand.Rand, depth int) (xdr.ScVal, error) {
+	if depth > MaxGenDepth*2 {
+		return xdr.ScVal{}, errors.New(ErrorMaxDepthExceeded)
+	}
+	deep := depth >= MaxGenDepth
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVal, xdr.ScSpecTypeScSpecTypeU32:
+		v := xdr.Uint32(rng.Uint32())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeBool:
+		b := rng.Intn(2) == 1
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case xdr.ScSpecTypeScSpecTypeError:
+		code := xdr.Uint32(rng.Intn(100) + 1)
+		return xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}, nil
+	case xdr.ScSpecTypeScSpecTypeI32:
+		v := xdr.Int32(rng.Int31() - rng.Int31())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU64:
+		v := xdr.Uint64(rng.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI64:
+		v := xdr.Int64(rng.Int63() - rng.Int63())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		v := xdr.TimePoint(rng.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		v := xdr.Duration(rng.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU128:
+		v := xdr.UInt128Parts{Hi: xdr.Uint64(rng.Uint64()), Lo: xdr.Uint64(rng.Uint64())}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI128:
+		v := xdr.Int128Parts{Hi: xdr.Int64(rng.Uint64()), Lo: xdr.Uint64(rng.Uint64())}
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU256:
+		v := xdr.UInt256Parts{HiHi: xdr.Uint64(rng.Uint64()), HiLo: xdr.Uint64(rng.Uint64()), LoHi: xdr.Uint64(rng.Uint64()), LoLo: xdr.Uint64(rng.Uint64())}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI256:
+		v := xdr.Int256Parts{HiHi: xdr.Int64(rng.Uint64()), HiLo: xdr.Uint64(rng.Uint64()), LoHi: xdr.Uint64(rng.Uint64()), LoLo: xdr.Uint64(rng.Uint64())}
+		return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeBytes:
+		return genBytes(rng, rng.Intn(33)), nil
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		return genBytes(rng, int(t.BytesN.N)), nil
+	case xdr.ScSpecTypeScSpecTypeString:
+		str := xdr.ScString(genSymbol(rng, rng.Intn(33)))
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &str}, nil
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		sym := xdr.ScSymbol(genSymbol(rng, rng.Intn(32)+1))
+		return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}, nil
+	case xdr.ScSpecTypeScSpecTypeAddress:
+		return genAddress(rng), nil
+	case xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		var key xdr.Uint256
+		rng.Read(key[:])
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{
+			Type:         xdr.ScAddressTypeScAddressTypeMuxedAccount,
+			MuxedAccount: &xdr.MuxedEd25519Account{Id: xdr.Uint64(rng.Uint64()), Ed25519: key},
+		}}, nil
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if deep || rng.Intn(2) == 0 {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return s.genValue(t.Option.ValueType, rng, depth+1)
+	case xdr.ScSpecTypeScSpecTypeResult:
+		return s.genValue(t.Result.OkType, rng, depth+1)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		n := 0
+		if !deep {
+			n = rng.Intn(4)
+		}
+		items := make([]xdr.ScVal, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := s.genValue(t.Vec.ElementType, rng, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			items = append(items, item)
+		}
+		return newVec(items), nil
+	case xdr.ScSpecTypeScSpecTypeMap:
+		n := 0
+		if !deep {
+			n = rng.Intn(4)
+		}
+		m := make(xdr.ScMap, 0, n)
+		for i := 0; i < n; i++ {
+			key, err := s.genValue(t.Map.KeyType, rng, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			val, err := s.genValue(t.Map.ValueType, rng, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			m = append(m, xdr.ScMapEntry{Key: key, Val: val})
+		}
+		return newMap(m), nil
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		return s.genTuple(t.Tuple.ValueTypes, rng, depth)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return s.genUdt(t.Udt.Name, rng, depth)
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorUnsupportedType, t.Type)
+}
+
+func (s *Spec) genTuple(types []xdr.ScSpecTypeDef, rng *rand.Rand, depth int) (xdr.ScVal, error) {
+	items := make([]xdr.ScVal, 0, len(types))
+	for _, t := range types {
+		item, err := s.genValue(t, rng, depth+1)
+		if err != nil {
+			return xdr.ScVal{}, err
+		}
+		items = append(items, item)
+	}
+	return newVec(items), nil
+}
+
+func (s *Spec) genUdt(name string, rng *rand.Rand, depth int) (xdr.ScVal, error) {
+	entry, err := s.Type(name)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	switch entry.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		fields := entry.UdtStructV0.Fields
+		if IsTupleStruct(*entry.UdtStructV0) {
+			types := make([]xdr.ScSpecTypeDef, 0, len(fields))
+			for _, f := range fields {
+				types = append(types, f.Type)
+			}
+			return s.genTuple(types, rng, depth)
+		}
+		m := make(xdr.ScMap, 0, len(fields))
+		for _, f := range fields {
+			val, err := s.genValue(f.Type, rng, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("%s.%s: %w", name, f.Name, err)
+			}
+			m = append(m, xdr.ScMapEntry{Key: symbol(f.Name), Val: val})
+		}
+		return newMap(m), nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		cases := entry.UdtUnionV0.Cases
+		if len(cases) == 0 {
+			return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorEmptyType, name)
+		}
+		c := cases[rng.Intn(len(cases))]
+		if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0 {
+			return newVec([]xdr.ScVal{symbol(c.VoidCase.Name)}), nil
+		}
+		items := []xdr.ScVal{symbol(c.TupleCase.Name)}
+		for _, t := range c.TupleCase.Type {
+			item, err := s.genValue(t, rng, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, err
+			}
+			items = append(items, item)
+		}
+		return newVec(items), nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		cases := entry.UdtEnumV0.Cases
+		if len(cases) == 0 {
+			return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorEmptyType, name)
+		}
+		v := cases[rng.Intn(len(cases))].Value
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		cases := entry.UdtErrorEnumV0.Cases
+		if len(cases) == 0 {
+			return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorEmptyType, name)
+		}
+		v := cases[rng.Intn(len(cases))].Value
+		return xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &v}}, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorUnsupportedType, name)
+}
+
+// IsTupleStruct returns if the struct fields are unnamed (0, 1, ...), those
+// structs are encoded as a vec instead of a map.
+func IsTupleStruct(s xdr.ScSpecUdtStructV0) bool {
+	if len(s.Fields) == 0 {
+		return false
+	}
+	for i, f := range s.Fields {
+		if f.Name != strconv.Itoa(i) {
+			return false
+		}
+	}
+	return true
+}
+
+func genBytes(rng *rand.Rand, n int) xdr.ScVal {
+	b := make(xdr.ScBytes, n)
+	rng.Read(b)
+	return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &b}
+}
+
+func genSymbol(rng *rand.Rand, n int) string {
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = symbolChars[rng.Intn(len(symbolChars))]
+	}
+	return string(b)
+}
+
+func genAddress(rng *rand.Rand) xdr.ScVal {
+	if rng.Intn(2) == 0 {
+		var id xdr.ContractId
+		rng.Read(id[:])
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}}
+	}
+	var key xdr.Uint256
+	rng.Read(key[:])
+	accountId := xdr.AccountId{Type: xdr.PublicKeyTypePublicKeyTypeEd25519, Ed25519: &key}
+	return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountId}}
+}
+
+func symbol(s string) xdr.ScVal {
+	sym := xdr.ScSymbol(s)
+	return xdr.ScVal{Type: xdr.ScValTypeScvSymbol, Sym: &sym}
+}
+
+func newVec(items []xdr.ScVal) xdr.ScVal {
+	v := xdr.ScVec(items)
+	vp := &v
+	return xdr.ScVal{Type: xdr.ScValTypeScvVec, Vec: &vp}
+}
+
+// newMap sorts the entries and drops the duplicated keys
+func newMap(m xdr.ScMap) xdr.ScVal {
+	scval.SortMap(m)
+	dedup := make(xdr.ScMap, 0, len(m))
+	for i, e := range m {
+		if i > 0 && scval.Compare(m[i-1].Key, e.Key) == 0 {
+			continue
+		}
+		dedup = append(dedup, e)
+	}
+	mp := &dedup
+	return xdr.ScVal{Type: xdr.ScValTypeScvMap, Map: &mp}
+}
@@ -0,0 +1,209 @@
+package spec
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+type (
+	// Interface is the JSON friendly description of a Spec, similar to an ABI
+	Interface struct {
+		Functions []Function `json:"functions"`
+		Structs   []Struct   `json:"structs"`
+		Unions    []Union    `json:"unions"`
+		Enums     []Enum     `json:"enums"`
+		Errors    []Enum     `json:"errors"`
+		Events    []Event    `json:"events,omitempty"`
+	}
+
+	Function struct {
+		Name    string   `json:"name"`
+		Doc     string   `json:"doc,omitempty"`
+		Inputs  []Field  `json:"inputs"`
+		Outputs []string `json:"outputs"`
+	}
+
+	Field struct {
+		Name string `json:"name"`
+		Doc  string `json:"doc,omitempty"`
+		Type string `json:"type"`
+	}
+
+	Struct struct {
+		Name   string  `json:"name"`
+		Doc    string  `json:"doc,omitempty"`
+		Fields []Field `json:"fields"`
+	}
+
+	Union struct {
+		Name  string      `json:"name"`
+		Doc   string      `json:"doc,omitempty"`
+		Cases []UnionCase `json:"cases"`
+	}
+
+	UnionCase struct {
+		Name  string   `json:"name"`
+		Doc   string   `json:"doc,omitempty"`
+		Types []string `json:"types,omitempty"`
+	}
+
+	Enum struct {
+		Name  string     `json:"name"`
+		Doc   string     `json:"doc,omitempty"`
+		Cases []EnumCase `json:"cases"`
+	}
+
+	EnumCase struct {
+		Name  string `json:"name"`
+		Doc   string `json:"doc,omitempty"`
+		Value uint32 `json:"value"`
+	}
+
+	Event struct {
+		Name         string   `json:"name"`
+		Doc          string   `json:"doc,omitempty"`
+		PrefixTopics []string `json:"prefix_topics,omitempty"`
+		Params       []Field  `json:"params"`
+	}
+)
+
+// Interface returns the description of the functions, types, errors and
+// events of the spec, in spec order.
+func (s *Spec) Interface() Interface {
+	i := Interface{
+		Functions: []Function{},
+		Structs:   []Struct{},
+		Unions:    []Union{},
+		Enums:     []Enum{},
+		Errors:    []Enum{},
+	}
+	for _, e := range s.Entries {
+		switch e.Kind {
+		case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+			f := Function{Name: string(e.FunctionV0.Name), Doc: e.FunctionV0.Doc, Inputs: []Field{}, Outputs: []string{}}
+			for _, in := range e.FunctionV0.Inputs {
+				f.Inputs = append(f.Inputs, Field{Name: in.Name, Doc: in.Doc, Type: TypeName(in.Type)})
+			}
+			for _, out := range e.FunctionV0.Outputs {
+				f.Outputs = append(f.Outputs, TypeName(out))
+			}
+			i.Functions = append(i.Functions, f)
+		case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+			st := Struct{Name: e.UdtStructV0.Name, Doc: e.UdtStructV0.Doc, Fields: []Field{}}
+			for _, f := range e.UdtStructV0.Fields {
+				st.Fields = append(st.Fields, Field{Name: f.Name, Doc: f.Doc, Type: TypeName(f.Type)})
+			}
+			i.Structs = append(i.Structs, st)
+		case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+			u := Union{Name: e.UdtUnionV0.Name, Doc: e.UdtUnionV0.Doc, Cases: []UnionCase{}}
+			for _, c := range e.UdtUnionV0.Cases {
+				if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0 {
+					u.Cases = append(u.Cases, UnionCase{Name: c.VoidCase.Name, Doc: c.VoidCase.Doc})
+					continue
+				}
+				uc := UnionCase{Name: c.TupleCase.Name, Doc: c.TupleCase.Doc}
+				for _, t := range c.TupleCase.Type {
+					uc.Types = append(uc.Types, TypeName(t))
+				}
+				u.Cases = append(u.Cases, uc)
+			}
+			i.Unions = append(i.Unions, u)
+		case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+			en := Enum{Name: e.UdtEnumV0.Name, Doc: e.UdtEnumV0.Doc, Cases: []EnumCase{}}
+			for _, c := range e.UdtEnumV0.Cases {
+				en.Cases = append(en.Cases, EnumCase{Name: c.Name, Doc: c.Doc, Value: uint32(c.Value)})
+			}
+			i.Enums = append(i.Enums, en)
+		case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+			en := Enum{Name: e.UdtErrorEnumV0.Name, Doc: e.UdtErrorEnumV0.Doc, Cases: []EnumCase{}}
+			for _, c := range e.UdtErrorEnumV0.Cases {
+				en.Cases = append(en.Cases, EnumCase{Name: c.Name, Doc: c.Doc, Value: uint32(c.Value)})
+			}
+			i.Errors = append(i.Errors, en)
+		case xdr.ScSpecEntryKindScSpecEntryEventV0:
+			ev := Event{Name: string(e.EventV0.Name), Doc: e.EventV0.Doc, Params: []Field{}}
+			for _, t := range e.EventV0.PrefixTopics {
+				ev.PrefixTopics = append(ev.PrefixTopics, string(t))
+			}
+			for _, p := range e.EventV0.Params {
+				ev.Params = append(ev.Params, Field{Name: p.Name, Doc: p.Doc, Type: TypeName(p.Type)})
+			}
+			i.Events = append(i.Events, ev)
+		}
+	}
+	return i
+}
+
+// ToJSON returns the Interface of the spec as indented JSON. The output is
+// stable, the same spec always produces the same bytes.
+func (s *Spec) ToJSON() ([]byte, error) {
+	return json.MarshalIndent(s.Interface(), "", "  ")
+}
+
+// TypeName returns the name of the type as written in Rust contracts,
+// e.g. u32, Vec<Address>, Option<i128>, BytesN<32>, or the name of the
+// user defined type.
+func TypeName(t xdr.ScSpecTypeDef) string {
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVal:
+		return "Val"
+	case xdr.ScSpecTypeScSpecTypeBool:
+		return "bool"
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		return "()"
+	case xdr.ScSpecTypeScSpecTypeError:
+		return "Error"
+	case xdr.ScSpecTypeScSpecTypeU32:
+		return "u32"
+	case xdr.ScSpecTypeScSpecTypeI32:
+		return "i32"
+	case xdr.ScSpecTypeScSpecTypeU64:
+		return "u64"
+	case xdr.ScSpecTypeScSpecTypeI64:
+		return "i64"
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		return "Timepoint"
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		return "Duration"
+	case xdr.ScSpecTypeScSpecTypeU128:
+		return "u128"
+	case xdr.ScSpecTypeScSpecTypeI128:
+		return "i128"
+	case xdr.ScSpecTypeScSpecTypeU256:
+		return "U256"
+	case xdr.ScSpecTypeScSpecTypeI256:
+		return "I256"
+	case xdr.ScSpecTypeScSpecTypeBytes:
+		return "Bytes"
+	case xdr.ScSpecTypeScSpecTypeString:
+		return "String"
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		return "Symbol"
+	case xdr.ScSpecTypeScSpecTypeAddress:
+		return "Address"
+	case xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		return "MuxedAddress"
+	case xdr.ScSpecTypeScSpecTypeOption:
+		return "Option<" + TypeName(t.Option.ValueType) + ">"
+	case xdr.ScSpecTypeScSpecTypeResult:
+		return "Result<" + TypeName(t.Result.OkType) + ", " + TypeName(t.Result.ErrorType) + ">"
+	case xdr.ScSpecTypeScSpecTypeVec:
+		return "Vec<" + TypeName(t.Vec.ElementType) + ">"
+	case xdr.ScSpecTypeScSpecTypeMap:
+		return "Map<" + TypeName(t.Map.KeyType) + ", " + TypeName(t.Map.ValueType) + ">"
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		names := make([]string, 0, len(t.Tuple.ValueTypes))
+		for _, vt := range t.Tuple.ValueTypes {
+			names = append(names, TypeName(vt))
+		}
+		return "(" + strings.Join(names, ", ") + ")"
+	case xdr.ScSpecTypeScSpecTypeBytesN:
+		return fmt.Sprintf("BytesN<%d>", t.BytesN.N)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return t.Udt.Name
+	}
+	return t.Type.String()
+}
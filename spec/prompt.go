@@ -0,0 +1,121 @@
+package spec
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/stellar/go/xdr"
+)
+
+// PromptArgs asks for each argument of the function called name, one line
+// per argument, writing the prompts to out and reading the answers from in.
+// An answer is the JSON of the value, see ValueFromJSON, or plain text for
+// strings, symbols, addresses and the name of an enum case; an empty one is
+// null, a missing option. Invalid answers are reported and asked again,
+// until in ends.
+//
+//	Example:
+//	 args, err := s.PromptArgs("transfer", os.Stdin, os.Stderr)
+func (s *Spec) PromptArgs(name string, in io.Reader, out io.Writer) ([]xdr.ScVal, error) {
+	f, err := s.Function(name)
+	if err != nil {
+		return nil, err
+	}
+	lines := bufio.NewScanner(in)
+	args := make([]xdr.ScVal, len(f.Inputs))
+	for i, input := range f.Inputs {
+		if args[i], err = s.promptValue(input.Name, input.Type, lines, out); err != nil {
+			return nil, fmt.Errorf("%s: %w", input.Name, err)
+		}
+	}
+	return args, nil
+}
+
+func (s *Spec) promptValue(name string, t xdr.ScSpecTypeDef, lines *bufio.Scanner, out io.Writer) (xdr.ScVal, error) {
+	options := s.options(t)
+	for {
+		fmt.Fprintf(out, "%s (%s)", name, TypeName(t))
+		if len(options) > 0 {
+			fmt.Fprintf(out, " [%s]", strings.Join(options, ", "))
+		}
+		fmt.Fprint(out, ": ")
+		if !lines.Scan() {
+			if err := lines.Err(); err != nil {
+				return xdr.ScVal{}, err
+			}
+			return xdr.ScVal{}, io.ErrUnexpectedEOF
+		}
+		val, err := s.ValueFromJSON(t, s.answerJSON(t, strings.TrimSpace(lines.Text())))
+		if err == nil {
+			return val, nil
+		}
+		fmt.Fprintf(out, "invalid %s: %v\n", name, err)
+	}
+}
+
+// answerJSON returns the JSON of the answer, quoting plain text and
+// replacing an enum case name by its value
+func (s *Spec) answerJSON(t xdr.ScSpecTypeDef, answer string) json.RawMessage {
+	if answer == "" {
+		return json.RawMessage("null")
+	}
+	if entry := s.udt(t); entry != nil && entry.Kind == xdr.ScSpecEntryKindScSpecEntryUdtEnumV0 {
+		for _, c := range entry.UdtEnumV0.Cases {
+			if string(c.Name) == answer {
+				return json.RawMessage(strconv.FormatUint(uint64(c.Value), 10))
+			}
+		}
+	}
+	if json.Valid([]byte(answer)) {
+		return json.RawMessage(answer)
+	}
+	b, _ := json.Marshal(answer)
+	return b
+}
+
+// options returns the cases of an enum or union type, or the values of a
+// bool, to show in the prompt
+func (s *Spec) options(t xdr.ScSpecTypeDef) []string {
+	if t.Type == xdr.ScSpecTypeScSpecTypeOption {
+		return s.options(t.Option.ValueType)
+	}
+	if t.Type == xdr.ScSpecTypeScSpecTypeBool {
+		return []string{"true", "false"}
+	}
+	entry := s.udt(t)
+	if entry == nil {
+		return nil
+	}
+	var options []string
+	switch entry.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		for _, c := range entry.UdtEnumV0.Cases {
+			options = append(options, fmt.Sprintf("%s=%d", c.Name, c.Value))
+		}
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		for _, c := range entry.UdtUnionV0.Cases {
+			name, types := unionCase(c)
+			if types != "" {
+				name = fmt.Sprintf(`{"%s":[%s]}`, name, types)
+			}
+			options = append(options, name)
+		}
+	}
+	return options
+}
+
+// udt returns the entry of a user defined type, nil for the other types
+func (s *Spec) udt(t xdr.ScSpecTypeDef) *xdr.ScSpecEntry {
+	if t.Type != xdr.ScSpecTypeScSpecTypeUdt {
+		return nil
+	}
+	entry, err := s.Type(t.Udt.Name)
+	if err != nil {
+		return nil
+	}
+	return entry
+}
@@ -0,0 +1,48 @@
+package spec_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func TestPromptArgs(t *testing.T) {
+	s := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, UdtEnumV0: &xdr.ScSpecUdtEnumV0{
+			Name:  "Level",
+			Cases: []xdr.ScSpecUdtEnumCaseV0{{Name: "Low", Value: 1}, {Name: "High", Value: 2}},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "set",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "owner", Type: typeDef(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "level", Type: udt("Level")},
+				{Name: "amount", Type: typeDef(xdr.ScSpecTypeScSpecTypeI128)},
+				{Name: "memo", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: typeDef(xdr.ScSpecTypeScSpecTypeString)}}},
+			},
+		}},
+	}}
+	owner := keypair.MustRandom().Address()
+	var out strings.Builder
+	in := strings.NewReader(owner + "\nMedium\nHigh\nten\n10\n\n")
+	args, err := s.PromptArgs("set", in, &out)
+	if err != nil {
+		t.Fatal(err, out.String())
+	}
+	if len(args) != 4 || args[0].Type != xdr.ScValTypeScvAddress || *args[1].U32 != 2 ||
+		args[2].I128.Lo != 10 || args[3].Type != xdr.ScValTypeScvVoid {
+		t.Fatal(args)
+	}
+	prompts := out.String()
+	if !strings.Contains(prompts, "level (Level) [Low=1, High=2]: ") || strings.Count(prompts, "invalid level") != 1 ||
+		strings.Count(prompts, "invalid amount") != 1 || !strings.Contains(prompts, "memo (Option<String>): ") {
+		t.Fatal(prompts)
+	}
+
+	if _, err := s.PromptArgs("set", strings.NewReader(owner+"\n"), &out); err == nil || !strings.HasPrefix(err.Error(), "level") {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,141 @@
+// Package spec reads the interface of a contract, the spec entries
+// embedded by the SDK in the contractspecv0 custom section of the wasm.
+package spec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"github.com/stellar/go/xdr"
+)
+
+// SectionName is the wasm custom section holding the contract spec
+const SectionName = "contractspecv0"
+
+const (
+	ErrorInvalidWasm       = "Invalid wasm"
+	ErrorSpecNotFound      = "Wasm has no contract spec"
+	ErrorFunctionNotFound  = "Function not found in spec"
+	ErrorTypeNotFound      = "Type not found in spec"
+	ErrorUnsupportedType   = "Type not supported"
+	ErrorMaxDepthExceeded  = "Type nesting is too deep"
+	ErrorEmptyType         = "Type has no cases"
+	ErrorInvalidSpecStream = "Invalid spec XDR stream"
+)
+
+// Spec is the interface of a contract
+type Spec struct {
+	Entries []xdr.ScSpecEntry
+}
+
+// FromWasm reads the spec of the compiled contract
+func FromWasm(wasm []byte) (*Spec, error) {
+	section, err := customSection(wasm, SectionName)
+	if err != nil {
+		return nil, err
+	}
+	return FromXDR(section)
+}
+
+// FromXDR decodes a stream of XDR encoded ScSpecEntry, as stored in the
+// contractspecv0 section.
+func FromXDR(b []byte) (*Spec, error) {
+	r := bytes.NewReader(b)
+	spec := &Spec{}
+	for r.Len() > 0 {
+		var entry xdr.ScSpecEntry
+		if _, err := xdr.Unmarshal(r, &entry); err != nil {
+			return nil, errors.Join(errors.New(ErrorInvalidSpecStream), err)
+		}
+		spec.Entries = append(spec.Entries, entry)
+	}
+	return spec, nil
+}
+
+// Functions returns the functions of the contract, in spec order
+func (s *Spec) Functions() []xdr.ScSpecFunctionV0 {
+	var functions []xdr.ScSpecFunctionV0
+	for _, e := range s.Entries {
+		if e.Kind == xdr.ScSpecEntryKindScSpecEntryFunctionV0 {
+			functions = append(functions, *e.FunctionV0)
+		}
+	}
+	return functions
+}
+
+// Function returns the function called name
+func (s *Spec) Function(name string) (*xdr.ScSpecFunctionV0, error) {
+	for _, e := range s.Entries {
+		if e.Kind == xdr.ScSpecEntryKindScSpecEntryFunctionV0 && string(e.FunctionV0.Name) == name {
+			return e.FunctionV0, nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %s", ErrorFunctionNotFound, name)
+}
+
+// Type returns the entry of the user defined type called name: a struct,
+// union, enum or error enum.
+func (s *Spec) Type(name string) (*xdr.ScSpecEntry, error) {
+	for i, e := range s.Entries {
+		if EntryName(e) == name && e.Kind != xdr.ScSpecEntryKindScSpecEntryFunctionV0 &&
+			e.Kind != xdr.ScSpecEntryKindScSpecEntryEventV0 {
+			return &s.Entries[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s: %s", ErrorTypeNotFound, name)
+}
+
+// EntryName returns the name of the function or type of the entry
+func EntryName(e xdr.ScSpecEntry) string {
+	switch e.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryFunctionV0:
+		return string(e.FunctionV0.Name)
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		return e.UdtStructV0.Name
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		return e.UdtUnionV0.Name
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		return e.UdtEnumV0.Name
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		return e.UdtErrorEnumV0.Name
+	case xdr.ScSpecEntryKindScSpecEntryEventV0:
+		return string(e.EventV0.Name)
+	}
+	return ""
+}
+
+// customSection returns the content of the wasm custom section called name
+func customSection(wasm []byte, name string) ([]byte, error) {
+	if len(wasm) < 8 || !bytes.Equal(wasm[:4], []byte("\x00asm")) {
+		return nil, errors.New(ErrorInvalidWasm)
+	}
+	r := bytes.NewReader(wasm[8:])
+	for r.Len() > 0 {
+		id, err := r.ReadByte()
+		if err != nil {
+			return nil, errors.New(ErrorInvalidWasm)
+		}
+		size, err := binary.ReadUvarint(r)
+		if err != nil || size > uint64(r.Len()) {
+			return nil, errors.New(ErrorInvalidWasm)
+		}
+		content := make([]byte, size)
+		r.Read(content)
+		if id != 0 {
+			continue
+		}
+		cr := bytes.NewReader(content)
+		nameLen, err := binary.ReadUvarint(cr)
+		if err != nil || nameLen > uint64(cr.Len()) {
+			return nil, errors.New(ErrorInvalidWasm)
+		}
+		sectionName := make([]byte, nameLen)
+		cr.Read(sectionName)
+		if string(sectionName) == name {
+			return content[len(content)-cr.Len():], nil
+		}
+	}
+	return nil, errors.New(ErrorSpecNotFound)
+}
@@ -0,0 +1,91 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/xdr"
+)
+
+func TestFromWasm(t *testing.T) {
+	wasm, err := os.ReadFile("../testdata/hello_world.wasm")
+	if err != nil {
+		t.Fatal(err)
+	}
+	s, err := spec.FromWasm(wasm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f, err := s.Function("hello")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(f.Inputs) != 1 || f.Inputs[0].Type.Type != xdr.ScSpecTypeScSpecTypeSymbol {
+		t.Fatal(f)
+	}
+
+	args, err := s.GenArgs("hello", rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 1 || args[0].Type != xdr.ScValTypeScvSymbol {
+		t.Fatal(args)
+	}
+}
+
+func TestGenValueUdt(t *testing.T) {
+	s := &spec.Spec{Entries: []xdr.ScSpecEntry{{
+		Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0,
+		UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Point",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "y", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI32}},
+				{Name: "x", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeI32}},
+			},
+		},
+	}}}
+	v, err := s.GenValue(xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: "Point"}}, rand.New(rand.NewSource(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	m := **v.Map
+	if len(m) != 2 || *m[0].Key.Sym != "x" || *m[1].Key.Sym != "y" {
+		t.Fatal(v.String())
+	}
+}
+
+func TestGenValueEmptyUdt(t *testing.T) {
+	s := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0, UdtUnionV0: &xdr.ScSpecUdtUnionV0{Name: "Union"}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, UdtEnumV0: &xdr.ScSpecUdtEnumV0{Name: "Enum"}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0, UdtErrorEnumV0: &xdr.ScSpecUdtErrorEnumV0{Name: "Error"}},
+	}}
+	for _, name := range []string{"Union", "Enum", "Error"} {
+		_, err := s.GenValue(xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}, rand.New(rand.NewSource(1)))
+		if err == nil || err.Error() != spec.ErrorEmptyType+": "+name {
+			t.Fatal(name, err)
+		}
+	}
+}
+
+func TestToJSON(t *testing.T) {
+	wasm, _ := os.ReadFile("../testdata/hello_world.wasm")
+	s, err := spec.FromWasm(wasm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err := s.ToJSON()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var i spec.Interface
+	if err := json.Unmarshal(b, &i); err != nil {
+		t.Fatal(err)
+	}
+	if len(i.Functions) != 1 || i.Functions[0].Inputs[0].Type != "Symbol" || i.Functions[0].Outputs[0] != "Vec<Symbol>" {
+		t.Fatal(string(b))
+	}
+}
@@ -0,0 +1,689 @@
+package spec
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/stellar/go/strkey"
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorInvalidJSONValue = "Invalid JSON value for type"
+)
+
+// ArgsFromJSON returns the arguments of the function called name from a
+// JSON object of the arguments by name, see ValueFromJSON
+func (s *Spec) ArgsFromJSON(name string, data []byte) ([]xdr.ScVal, error) {
+	f, err := s.Function(name)
+	if err != nil {
+		return nil, err
+	}
+	var fields map[string]json.RawMessage
+	if len(bytes.TrimSpace(data)) > 0 {
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return nil, fmt.Errorf("%s: %w", ErrorInvalidJSONValue, err)
+		}
+	}
+	args := make([]xdr.ScVal, len(f.Inputs))
+	for i, input := range f.Inputs {
+		raw, ok := fields[input.Name]
+		if !ok {
+			raw = json.RawMessage("null")
+		}
+		if args[i], err = s.ValueFromJSON(input.Type, raw); err != nil {
+			return nil, fmt.Errorf("%s: %w", input.Name, err)
+		}
+	}
+	return args, nil
+}
+
+// ValueFromJSON returns the value of type t from its JSON:
+//   - bool as bool, void and a missing option as null
+//   - u32, i32 and enums as numbers, the wider integers as numbers or
+//     decimal strings
+//   - bytes as hex strings, string and symbol as strings
+//   - addresses as strkeys, G..., C... or M...
+//   - vec, tuples and tuple structs as arrays
+//   - maps as objects if their keys are strings or symbols, else as
+//     arrays of [key, value] pairs
+//   - structs as objects by field name
+//   - unions as the name of a void case or an object with the name of a
+//     tuple case and the array of its values, {"Case": [values]}
+func (s *Spec) ValueFromJSON(t xdr.ScSpecTypeDef, data json.RawMessage) (xdr.ScVal, error) {
+	return s.valueFromJSON(t, data, 0)
+}
+
+func (s *Spec) valueFromJSON(t xdr.ScSpecTypeDef, data json.RawMessage, depth int) (xdr.ScVal, error) {
+	if depth > MaxGenDepth*2 {
+		return xdr.ScVal{}, errors.New(ErrorMaxDepthExceeded)
+	}
+	invalid := func(err error) error {
+		if err != nil {
+			return fmt.Errorf("%s %s: %w", ErrorInvalidJSONValue, TypeName(t), err)
+		}
+		return fmt.Errorf("%s %s: %s", ErrorInvalidJSONValue, TypeName(t), data)
+	}
+	isNull := bytes.Equal(bytes.TrimSpace(data), []byte("null"))
+	if isNull && t.Type != xdr.ScSpecTypeScSpecTypeVoid && t.Type != xdr.ScSpecTypeScSpecTypeOption {
+		return xdr.ScVal{}, invalid(nil)
+	}
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		if !isNull {
+			return xdr.ScVal{}, invalid(nil)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+	case xdr.ScSpecTypeScSpecTypeBool:
+		var b bool
+		if err := json.Unmarshal(data, &b); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvBool, B: &b}, nil
+	case xdr.ScSpecTypeScSpecTypeU32, xdr.ScSpecTypeScSpecTypeI32,
+		xdr.ScSpecTypeScSpecTypeU64, xdr.ScSpecTypeScSpecTypeI64,
+		xdr.ScSpecTypeScSpecTypeTimepoint, xdr.ScSpecTypeScSpecTypeDuration,
+		xdr.ScSpecTypeScSpecTypeU128, xdr.ScSpecTypeScSpecTypeI128,
+		xdr.ScSpecTypeScSpecTypeU256, xdr.ScSpecTypeScSpecTypeI256:
+		n, err := jsonInt(data)
+		if err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		val, err := intScVal(t.Type, n)
+		if err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		return val, nil
+	case xdr.ScSpecTypeScSpecTypeBytes, xdr.ScSpecTypeScSpecTypeBytesN:
+		var h string
+		if err := json.Unmarshal(data, &h); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		b, err := hex.DecodeString(h)
+		if err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		if t.Type == xdr.ScSpecTypeScSpecTypeBytesN && len(b) != int(t.BytesN.N) {
+			return xdr.ScVal{}, invalid(fmt.Errorf("%d bytes", len(b)))
+		}
+		sb := xdr.ScBytes(b)
+		return xdr.ScVal{Type: xdr.ScValTypeScvBytes, Bytes: &sb}, nil
+	case xdr.ScSpecTypeScSpecTypeString:
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		sc := xdr.ScString(str)
+		return xdr.ScVal{Type: xdr.ScValTypeScvString, Str: &sc}, nil
+	case xdr.ScSpecTypeScSpecTypeSymbol:
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		return symbol(str), nil
+	case xdr.ScSpecTypeScSpecTypeAddress, xdr.ScSpecTypeScSpecTypeMuxedAddress:
+		var str string
+		if err := json.Unmarshal(data, &str); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		address, err := parseAddress(str)
+		if err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvAddress, Address: &address}, nil
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if isNull {
+			return xdr.ScVal{Type: xdr.ScValTypeScvVoid}, nil
+		}
+		return s.valueFromJSON(t.Option.ValueType, data, depth+1)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		var items []json.RawMessage
+		if err := json.Unmarshal(data, &items); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		vals := make([]xdr.ScVal, len(items))
+		for i, item := range items {
+			val, err := s.valueFromJSON(t.Vec.ElementType, item, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+			}
+			vals[i] = val
+		}
+		return newVec(vals), nil
+	case xdr.ScSpecTypeScSpecTypeMap:
+		return s.mapFromJSON(t.Map.KeyType, t.Map.ValueType, data, depth, invalid)
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		return s.tupleFromJSON(t.Tuple.ValueTypes, data, depth, invalid)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return s.udtFromJSON(t.Udt.Name, data, depth, invalid)
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorUnsupportedType, TypeName(t))
+}
+
+func (s *Spec) mapFromJSON(keyType, valueType xdr.ScSpecTypeDef, data json.RawMessage, depth int, invalid func(error) error) (xdr.ScVal, error) {
+	var m xdr.ScMap
+	if stringKeys(keyType) {
+		var fields map[string]json.RawMessage
+		if err := json.Unmarshal(data, &fields); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		for name, raw := range fields {
+			key, _ := json.Marshal(name)
+			entry, err := s.entryFromJSON(keyType, valueType, key, raw, depth)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("[%s]: %w", name, err)
+			}
+			m = append(m, entry)
+		}
+		return newMap(m), nil
+	}
+	var pairs [][2]json.RawMessage
+	if err := json.Unmarshal(data, &pairs); err != nil {
+		return xdr.ScVal{}, invalid(err)
+	}
+	for i, pair := range pairs {
+		entry, err := s.entryFromJSON(keyType, valueType, pair[0], pair[1], depth)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+		}
+		m = append(m, entry)
+	}
+	return newMap(m), nil
+}
+
+func (s *Spec) entryFromJSON(keyType, valueType xdr.ScSpecTypeDef, key, value json.RawMessage, depth int) (xdr.ScMapEntry, error) {
+	k, err := s.valueFromJSON(keyType, key, depth+1)
+	if err != nil {
+		return xdr.ScMapEntry{}, err
+	}
+	v, err := s.valueFromJSON(valueType, value, depth+1)
+	if err != nil {
+		return xdr.ScMapEntry{}, err
+	}
+	return xdr.ScMapEntry{Key: k, Val: v}, nil
+}
+
+func (s *Spec) tupleFromJSON(types []xdr.ScSpecTypeDef, data json.RawMessage, depth int, invalid func(error) error) (xdr.ScVal, error) {
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return xdr.ScVal{}, invalid(err)
+	}
+	if len(items) != len(types) {
+		return xdr.ScVal{}, invalid(fmt.Errorf("%d values, expected %d", len(items), len(types)))
+	}
+	vals := make([]xdr.ScVal, len(items))
+	for i, item := range items {
+		val, err := s.valueFromJSON(types[i], item, depth+1)
+		if err != nil {
+			return xdr.ScVal{}, fmt.Errorf("[%d]: %w", i, err)
+		}
+		vals[i] = val
+	}
+	return newVec(vals), nil
+}
+
+func (s *Spec) udtFromJSON(name string, data json.RawMessage, depth int, invalid func(error) error) (xdr.ScVal, error) {
+	entry, err := s.Type(name)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	switch entry.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		fields := entry.UdtStructV0.Fields
+		if IsTupleStruct(*entry.UdtStructV0) {
+			types := make([]xdr.ScSpecTypeDef, len(fields))
+			for i, f := range fields {
+				types[i] = f.Type
+			}
+			return s.tupleFromJSON(types, data, depth, invalid)
+		}
+		var values map[string]json.RawMessage
+		if err := json.Unmarshal(data, &values); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		m := make(xdr.ScMap, 0, len(fields))
+		for _, f := range fields {
+			raw, ok := values[f.Name]
+			if !ok {
+				raw = json.RawMessage("null")
+			}
+			val, err := s.valueFromJSON(f.Type, raw, depth+1)
+			if err != nil {
+				return xdr.ScVal{}, fmt.Errorf("%s.%s: %w", name, f.Name, err)
+			}
+			m = append(m, xdr.ScMapEntry{Key: symbol(f.Name), Val: val})
+		}
+		return newMap(m), nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		caseName, values, err := unionCaseFromJSON(data)
+		if err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		for _, c := range entry.UdtUnionV0.Cases {
+			if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0 && c.VoidCase.Name == caseName && values == nil {
+				return newVec([]xdr.ScVal{symbol(caseName)}), nil
+			}
+			if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0 && c.TupleCase.Name == caseName {
+				raw, _ := json.Marshal(values)
+				tuple, err := s.tupleFromJSON(c.TupleCase.Type, raw, depth, invalid)
+				if err != nil {
+					return xdr.ScVal{}, fmt.Errorf("%s::%s: %w", name, caseName, err)
+				}
+				return newVec(append([]xdr.ScVal{symbol(caseName)}, **tuple.Vec...)), nil
+			}
+		}
+		return xdr.ScVal{}, invalid(fmt.Errorf("no case %q", caseName))
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0, xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		var v uint32
+		if err := json.Unmarshal(data, &v); err != nil {
+			return xdr.ScVal{}, invalid(err)
+		}
+		cases := map[uint32]bool{}
+		if entry.UdtEnumV0 != nil {
+			for _, c := range entry.UdtEnumV0.Cases {
+				cases[uint32(c.Value)] = true
+			}
+		} else {
+			for _, c := range entry.UdtErrorEnumV0.Cases {
+				cases[uint32(c.Value)] = true
+			}
+		}
+		if !cases[v] {
+			return xdr.ScVal{}, invalid(fmt.Errorf("no case %d", v))
+		}
+		code := xdr.Uint32(v)
+		if entry.UdtErrorEnumV0 != nil {
+			return xdr.ScVal{Type: xdr.ScValTypeScvError, Error: &xdr.ScError{Type: xdr.ScErrorTypeSceContract, ContractCode: &code}}, nil
+		}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &code}, nil
+	}
+	return xdr.ScVal{}, fmt.Errorf("%s: %s", ErrorUnsupportedType, name)
+}
+
+// unionCaseFromJSON returns the case name and values of the JSON of a
+// union, a string for a void case or {"Case": [values]}
+func unionCaseFromJSON(data json.RawMessage) (string, []json.RawMessage, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return name, nil, nil
+	}
+	var tuple map[string][]json.RawMessage
+	if err := json.Unmarshal(data, &tuple); err != nil {
+		return "", nil, err
+	}
+	if len(tuple) != 1 {
+		return "", nil, fmt.Errorf("%d cases", len(tuple))
+	}
+	for name, values := range tuple {
+		return name, values, nil
+	}
+	return "", nil, nil
+}
+
+// ValueToJSON returns the JSON of the value val of type t, the reverse of
+// ValueFromJSON. The integers wider than 32 bits are decimal strings.
+func (s *Spec) ValueToJSON(t xdr.ScSpecTypeDef, val xdr.ScVal) (json.RawMessage, error) {
+	v, err := s.valueToJSON(t, val, 0)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(v)
+}
+
+func (s *Spec) valueToJSON(t xdr.ScSpecTypeDef, val xdr.ScVal, depth int) (any, error) {
+	if depth > MaxGenDepth*2 {
+		return nil, errors.New(ErrorMaxDepthExceeded)
+	}
+	mismatch := func() error {
+		return fmt.Errorf("%s %s: %s", ErrorInvalidJSONValue, TypeName(t), val.Type)
+	}
+	switch t.Type {
+	case xdr.ScSpecTypeScSpecTypeVoid:
+		return nil, nil
+	case xdr.ScSpecTypeScSpecTypeOption:
+		if val.Type == xdr.ScValTypeScvVoid {
+			return nil, nil
+		}
+		return s.valueToJSON(t.Option.ValueType, val, depth+1)
+	case xdr.ScSpecTypeScSpecTypeResult:
+		return s.valueToJSON(t.Result.OkType, val, depth+1)
+	case xdr.ScSpecTypeScSpecTypeUdt:
+		return s.udtToJSON(t.Udt.Name, val, depth)
+	case xdr.ScSpecTypeScSpecTypeVec:
+		items, ok := val.GetVec()
+		if !ok || items == nil {
+			return nil, mismatch()
+		}
+		out := make([]any, len(*items))
+		for i, item := range *items {
+			v, err := s.valueToJSON(t.Vec.ElementType, item, depth+1)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			out[i] = v
+		}
+		return out, nil
+	case xdr.ScSpecTypeScSpecTypeMap:
+		m, ok := val.GetMap()
+		if !ok || m == nil {
+			return nil, mismatch()
+		}
+		if stringKeys(t.Map.KeyType) {
+			out := make(map[string]any, len(*m))
+			for _, e := range *m {
+				key, ok := scString(e.Key)
+				if !ok {
+					return nil, mismatch()
+				}
+				v, err := s.valueToJSON(t.Map.ValueType, e.Val, depth+1)
+				if err != nil {
+					return nil, fmt.Errorf("[%s]: %w", key, err)
+				}
+				out[key] = v
+			}
+			return out, nil
+		}
+		out := make([][2]any, len(*m))
+		for i, e := range *m {
+			k, err := s.valueToJSON(t.Map.KeyType, e.Key, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			v, err := s.valueToJSON(t.Map.ValueType, e.Val, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			out[i] = [2]any{k, v}
+		}
+		return out, nil
+	case xdr.ScSpecTypeScSpecTypeTuple:
+		return s.tupleToJSON(t.Tuple.ValueTypes, val, depth, mismatch)
+	}
+	return plainToJSON(val, mismatch)
+}
+
+func (s *Spec) tupleToJSON(types []xdr.ScSpecTypeDef, val xdr.ScVal, depth int, mismatch func() error) (any, error) {
+	items, ok := val.GetVec()
+	if !ok || items == nil || len(*items) != len(types) {
+		return nil, mismatch()
+	}
+	out := make([]any, len(types))
+	for i, item := range *items {
+		v, err := s.valueToJSON(types[i], item, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("[%d]: %w", i, err)
+		}
+		out[i] = v
+	}
+	return out, nil
+}
+
+func (s *Spec) udtToJSON(name string, val xdr.ScVal, depth int) (any, error) {
+	entry, err := s.Type(name)
+	if err != nil {
+		return nil, err
+	}
+	mismatch := func() error {
+		return fmt.Errorf("%s %s: %s", ErrorInvalidJSONValue, name, val.Type)
+	}
+	switch entry.Kind {
+	case xdr.ScSpecEntryKindScSpecEntryUdtStructV0:
+		fields := entry.UdtStructV0.Fields
+		if IsTupleStruct(*entry.UdtStructV0) {
+			types := make([]xdr.ScSpecTypeDef, len(fields))
+			for i, f := range fields {
+				types[i] = f.Type
+			}
+			return s.tupleToJSON(types, val, depth, mismatch)
+		}
+		m, ok := val.GetMap()
+		if !ok || m == nil {
+			return nil, mismatch()
+		}
+		out := make(map[string]any, len(fields))
+		for _, f := range fields {
+			for _, e := range *m {
+				if key, _ := scString(e.Key); key == f.Name {
+					v, err := s.valueToJSON(f.Type, e.Val, depth+1)
+					if err != nil {
+						return nil, fmt.Errorf("%s.%s: %w", name, f.Name, err)
+					}
+					out[f.Name] = v
+				}
+			}
+		}
+		return out, nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtUnionV0:
+		items, ok := val.GetVec()
+		if !ok || items == nil || len(*items) == 0 {
+			return nil, mismatch()
+		}
+		caseName, _ := scString((*items)[0])
+		for _, c := range entry.UdtUnionV0.Cases {
+			if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0 && c.VoidCase.Name == caseName {
+				return caseName, nil
+			}
+			if c.Kind == xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0 && c.TupleCase.Name == caseName {
+				values, err := s.tupleToJSON(c.TupleCase.Type, newVec((*items)[1:]), depth, mismatch)
+				if err != nil {
+					return nil, err
+				}
+				return map[string]any{caseName: values}, nil
+			}
+		}
+		return nil, mismatch()
+	case xdr.ScSpecEntryKindScSpecEntryUdtEnumV0:
+		if val.Type != xdr.ScValTypeScvU32 {
+			return nil, mismatch()
+		}
+		return uint32(*val.U32), nil
+	case xdr.ScSpecEntryKindScSpecEntryUdtErrorEnumV0:
+		if val.Type != xdr.ScValTypeScvError || val.Error.ContractCode == nil {
+			return nil, mismatch()
+		}
+		return uint32(*val.Error.ContractCode), nil
+	}
+	return nil, fmt.Errorf("%s: %s", ErrorUnsupportedType, name)
+}
+
+// plainToJSON returns the JSON of the values without a nested type
+func plainToJSON(val xdr.ScVal, mismatch func() error) (any, error) {
+	switch val.Type {
+	case xdr.ScValTypeScvVoid:
+		return nil, nil
+	case xdr.ScValTypeScvBool:
+		return *val.B, nil
+	case xdr.ScValTypeScvU32:
+		return uint32(*val.U32), nil
+	case xdr.ScValTypeScvI32:
+		return int32(*val.I32), nil
+	case xdr.ScValTypeScvU64:
+		return fmt.Sprint(uint64(*val.U64)), nil
+	case xdr.ScValTypeScvI64:
+		return fmt.Sprint(int64(*val.I64)), nil
+	case xdr.ScValTypeScvTimepoint:
+		return fmt.Sprint(uint64(*val.Timepoint)), nil
+	case xdr.ScValTypeScvDuration:
+		return fmt.Sprint(uint64(*val.Duration)), nil
+	case xdr.ScValTypeScvU128:
+		return fromWords(false, uint64(val.U128.Hi), uint64(val.U128.Lo)).String(), nil
+	case xdr.ScValTypeScvI128:
+		return fromWords(true, uint64(val.I128.Hi), uint64(val.I128.Lo)).String(), nil
+	case xdr.ScValTypeScvU256:
+		p := val.U256
+		return fromWords(false, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)).String(), nil
+	case xdr.ScValTypeScvI256:
+		p := val.I256
+		return fromWords(true, uint64(p.HiHi), uint64(p.HiLo), uint64(p.LoHi), uint64(p.LoLo)).String(), nil
+	case xdr.ScValTypeScvBytes:
+		return hex.EncodeToString(*val.Bytes), nil
+	case xdr.ScValTypeScvString:
+		return string(*val.Str), nil
+	case xdr.ScValTypeScvSymbol:
+		return string(*val.Sym), nil
+	case xdr.ScValTypeScvAddress:
+		return val.Address.String()
+	}
+	return nil, mismatch()
+}
+
+// stringKeys returns if the keys of a map of keyType are JSON object keys
+func stringKeys(keyType xdr.ScSpecTypeDef) bool {
+	return keyType.Type == xdr.ScSpecTypeScSpecTypeString || keyType.Type == xdr.ScSpecTypeScSpecTypeSymbol
+}
+
+// scString returns the string of a string or symbol
+func scString(val xdr.ScVal) (string, bool) {
+	switch val.Type {
+	case xdr.ScValTypeScvString:
+		return string(*val.Str), true
+	case xdr.ScValTypeScvSymbol:
+		return string(*val.Sym), true
+	}
+	return "", false
+}
+
+// jsonInt returns the integer of a JSON number or decimal string
+func jsonInt(data json.RawMessage) (*big.Int, error) {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		var n json.Number
+		if err := json.Unmarshal(data, &n); err != nil {
+			return nil, err
+		}
+		s = n.String()
+	}
+	n, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, fmt.Errorf("not an integer: %s", data)
+	}
+	return n, nil
+}
+
+// intScVal returns n as a value of the integer type t
+func intScVal(t xdr.ScSpecType, n *big.Int) (xdr.ScVal, error) {
+	signed := map[xdr.ScSpecType]bool{
+		xdr.ScSpecTypeScSpecTypeI32: true, xdr.ScSpecTypeScSpecTypeI64: true,
+		xdr.ScSpecTypeScSpecTypeI128: true, xdr.ScSpecTypeScSpecTypeI256: true,
+	}[t]
+	count := 1
+	switch t {
+	case xdr.ScSpecTypeScSpecTypeU128, xdr.ScSpecTypeScSpecTypeI128:
+		count = 2
+	case xdr.ScSpecTypeScSpecTypeU256, xdr.ScSpecTypeScSpecTypeI256:
+		count = 4
+	}
+	words, err := toWords(n, signed, count)
+	if err != nil {
+		return xdr.ScVal{}, err
+	}
+	switch t {
+	case xdr.ScSpecTypeScSpecTypeU32:
+		if !n.IsUint64() || n.Uint64() > 1<<32-1 {
+			return xdr.ScVal{}, fmt.Errorf("%s overflows", n)
+		}
+		v := xdr.Uint32(n.Uint64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI32:
+		if !n.IsInt64() || n.Int64() > 1<<31-1 || n.Int64() < -1<<31 {
+			return xdr.ScVal{}, fmt.Errorf("%s overflows", n)
+		}
+		v := xdr.Int32(n.Int64())
+		return xdr.ScVal{Type: xdr.ScValTypeScvI32, I32: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU64:
+		v := xdr.Uint64(words[0])
+		return xdr.ScVal{Type: xdr.ScValTypeScvU64, U64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI64:
+		v := xdr.Int64(words[0])
+		return xdr.ScVal{Type: xdr.ScValTypeScvI64, I64: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeTimepoint:
+		v := xdr.TimePoint(words[0])
+		return xdr.ScVal{Type: xdr.ScValTypeScvTimepoint, Timepoint: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeDuration:
+		v := xdr.Duration(words[0])
+		return xdr.ScVal{Type: xdr.ScValTypeScvDuration, Duration: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU128:
+		v := xdr.UInt128Parts{Hi: xdr.Uint64(words[0]), Lo: xdr.Uint64(words[1])}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU128, U128: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeI128:
+		v := xdr.Int128Parts{Hi: xdr.Int64(words[0]), Lo: xdr.Uint64(words[1])}
+		return xdr.ScVal{Type: xdr.ScValTypeScvI128, I128: &v}, nil
+	case xdr.ScSpecTypeScSpecTypeU256:
+		v := xdr.UInt256Parts{HiHi: xdr.Uint64(words[0]), HiLo: xdr.Uint64(words[1]), LoHi: xdr.Uint64(words[2]), LoLo: xdr.Uint64(words[3])}
+		return xdr.ScVal{Type: xdr.ScValTypeScvU256, U256: &v}, nil
+	}
+	v := xdr.Int256Parts{HiHi: xdr.Int64(words[0]), HiLo: xdr.Uint64(words[1]), LoHi: xdr.Uint64(words[2]), LoLo: xdr.Uint64(words[3])}
+	return xdr.ScVal{Type: xdr.ScValTypeScvI256, I256: &v}, nil
+}
+
+// parseAddress returns the address of a strkey, G..., C... or M...
+func parseAddress(s string) (xdr.ScAddress, error) {
+	switch {
+	case strkey.IsValidEd25519PublicKey(s):
+		var accountID xdr.AccountId
+		if err := accountID.SetAddress(s); err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeAccount, AccountId: &accountID}, nil
+	case strkey.IsValidContractAddress(s):
+		raw, err := strkey.Decode(strkey.VersionByteContract, s)
+		if err != nil {
+			return xdr.ScAddress{}, err
+		}
+		var id xdr.ContractId
+		copy(id[:], raw)
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeContract, ContractId: &id}, nil
+	case strkey.IsValidMuxedAccountEd25519PublicKey(s):
+		var muxed xdr.MuxedAccount
+		if err := muxed.SetAddress(s); err != nil {
+			return xdr.ScAddress{}, err
+		}
+		return xdr.ScAddress{Type: xdr.ScAddressTypeScAddressTypeMuxedAccount, MuxedAccount: &xdr.MuxedEd25519Account{
+			Id:      muxed.Med25519.Id,
+			Ed25519: muxed.Med25519.Ed25519,
+		}}, nil
+	}
+	return xdr.ScAddress{}, fmt.Errorf("invalid address %q", s)
+}
+
+// fromWords returns the integer of the big endian words, in two's complement
+// if signed
+func fromWords(signed bool, words ...uint64) *big.Int {
+	n := new(big.Int)
+	for _, w := range words {
+		n.Lsh(n, 64).Or(n, new(big.Int).SetUint64(w))
+	}
+	if signed && words[0]>>63 == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(64*len(words))))
+	}
+	return n
+}
+
+// toWords returns n as count big endian words, in two's complement if
+// signed, failing if it overflows
+func toWords(n *big.Int, signed bool, count int) ([]uint64, error) {
+	bits := 64 * count
+	m := new(big.Int).Set(n)
+	if signed {
+		limit := new(big.Int).Lsh(big.NewInt(1), uint(bits-1))
+		if m.Cmp(limit) >= 0 || m.Cmp(new(big.Int).Neg(limit)) < 0 {
+			return nil, fmt.Errorf("%s overflows", n)
+		}
+		if m.Sign() < 0 {
+			m.Add(m, new(big.Int).Lsh(big.NewInt(1), uint(bits)))
+		}
+	} else if m.Sign() < 0 || m.BitLen() > bits {
+		return nil, fmt.Errorf("%s overflows", n)
+	}
+	words := make([]uint64, count)
+	mask := new(big.Int).SetUint64(^uint64(0))
+	for i := count - 1; i >= 0; i-- {
+		words[i] = new(big.Int).And(m, mask).Uint64()
+		m.Rsh(m, 64)
+	}
+	return words, nil
+}
@@ -0,0 +1,90 @@
+package spec_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban/spec"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/xdr"
+)
+
+func typeDef(t xdr.ScSpecType) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: t}
+}
+
+func udt(name string) xdr.ScSpecTypeDef {
+	return xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeUdt, Udt: &xdr.ScSpecTypeUdt{Name: name}}
+}
+
+func TestValueJSON(t *testing.T) {
+	s := &spec.Spec{Entries: []xdr.ScSpecEntry{
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtStructV0, UdtStructV0: &xdr.ScSpecUdtStructV0{
+			Name: "Order",
+			Fields: []xdr.ScSpecUdtStructFieldV0{
+				{Name: "owner", Type: typeDef(xdr.ScSpecTypeScSpecTypeAddress)},
+				{Name: "amount", Type: typeDef(xdr.ScSpecTypeScSpecTypeI128)},
+				{Name: "memo", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeOption, Option: &xdr.ScSpecTypeOption{ValueType: typeDef(xdr.ScSpecTypeScSpecTypeString)}}},
+				{Name: "side", Type: udt("Side")},
+				{Name: "limits", Type: xdr.ScSpecTypeDef{Type: xdr.ScSpecTypeScSpecTypeMap, Map: &xdr.ScSpecTypeMap{
+					KeyType:   typeDef(xdr.ScSpecTypeScSpecTypeSymbol),
+					ValueType: typeDef(xdr.ScSpecTypeScSpecTypeU64),
+				}}},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryUdtUnionV0, UdtUnionV0: &xdr.ScSpecUdtUnionV0{
+			Name: "Side",
+			Cases: []xdr.ScSpecUdtUnionCaseV0{
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseVoidV0, VoidCase: &xdr.ScSpecUdtUnionCaseVoidV0{Name: "Buy"}},
+				{Kind: xdr.ScSpecUdtUnionCaseV0KindScSpecUdtUnionCaseTupleV0, TupleCase: &xdr.ScSpecUdtUnionCaseTupleV0{
+					Name: "Sell",
+					Type: []xdr.ScSpecTypeDef{typeDef(xdr.ScSpecTypeScSpecTypeBytes)},
+				}},
+			},
+		}},
+		{Kind: xdr.ScSpecEntryKindScSpecEntryFunctionV0, FunctionV0: &xdr.ScSpecFunctionV0{
+			Name: "place",
+			Inputs: []xdr.ScSpecFunctionInputV0{
+				{Name: "order", Type: udt("Order")},
+				{Name: "count", Type: typeDef(xdr.ScSpecTypeScSpecTypeU32)},
+			},
+		}},
+	}}
+	owner := keypair.MustRandom().Address()
+	order := `{"amount":"-170141183460469231731687303715884105728","limits":{"max":"18446744073709551615"},"memo":null,"owner":"` + owner + `","side":{"Sell":["cafe"]}}`
+
+	args, err := s.ArgsFromJSON("place", []byte(`{"order":`+order+`,"count":3}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(args) != 2 || args[0].Type != xdr.ScValTypeScvMap || *args[1].U32 != 3 {
+		t.Fatal(args)
+	}
+	b, err := s.ValueToJSON(udt("Order"), args[0])
+	if err != nil || string(b) != order {
+		t.Fatal(string(b), err)
+	}
+
+	buy, err := s.ValueFromJSON(udt("Side"), json.RawMessage(`"Buy"`))
+	if err != nil || len(**buy.Vec) != 1 {
+		t.Fatal(buy, err)
+	}
+	for _, c := range []struct {
+		t    xdr.ScSpecTypeDef
+		data string
+	}{
+		{typeDef(xdr.ScSpecTypeScSpecTypeU32), `-1`},
+		{typeDef(xdr.ScSpecTypeScSpecTypeI128), `"1e3"`},
+		{typeDef(xdr.ScSpecTypeScSpecTypeAddress), `"G123"`},
+		{typeDef(xdr.ScSpecTypeScSpecTypeBool), `null`},
+		{udt("Side"), `"Hold"`},
+	} {
+		if _, err := s.ValueFromJSON(c.t, json.RawMessage(c.data)); err == nil || !strings.Contains(err.Error(), spec.ErrorInvalidJSONValue) {
+			t.Fatal(c.data, err)
+		}
+	}
+	if _, err := s.ArgsFromJSON("place", []byte(`{"count":1}`)); err == nil || !strings.HasPrefix(err.Error(), "order: ") {
+		t.Fatal(err)
+	}
+}
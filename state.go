@@ -0,0 +1,255 @@
+package soroban
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorContractInstanceNotFound = "Contract instance not found"
+)
+
+// StateSnapshot is the state of a contract at LatestLedger, as exported by
+// Contract.ExportState
+type StateSnapshot struct {
+	NetworkPassphrase string               `json:"network_passphrase"`
+	LatestLedger      int64                `json:"latest_ledger"`
+	Contract          string               `json:"contract"`
+	Entries           []StateSnapshotEntry `json:"entries"`
+}
+
+// StateSnapshotEntry is a ledger entry of a StateSnapshot, Key and Entry are
+// base64 XDR of xdr.LedgerKey and xdr.LedgerEntryData.
+type StateSnapshotEntry struct {
+	Key                string `json:"key"`
+	Entry              string `json:"entry"`
+	LastModifiedLedger int64  `json:"last_modified_ledger"`
+	LiveUntilLedger    int64  `json:"live_until_ledger"`
+}
+
+// LedgerKey decodes Key
+func (e StateSnapshotEntry) LedgerKey() (xdr.LedgerKey, error) {
+	var key xdr.LedgerKey
+	err := xdr.SafeUnmarshalBase64(e.Key, &key)
+	return key, err
+}
+
+// LedgerEntryData decodes Entry
+func (e StateSnapshotEntry) LedgerEntryData() (xdr.LedgerEntryData, error) {
+	var data xdr.LedgerEntryData
+	err := xdr.SafeUnmarshalBase64(e.Entry, &data)
+	return data, err
+}
+
+// StateKeys registers keys of persistent contract data to be exported by
+// ExportState
+func (c *Contract) StateKeys(keys ...xdr.ScVal) *Contract {
+	c.stateKeys = append(c.stateKeys, keys...)
+	return c
+}
+
+// ExportState writes a JSON StateSnapshot of the contract, with the instance
+// (and its instance storage), the wasm code entry and the persistent data
+// registered with StateKeys, along with their ttls. Registered keys not found
+// are skipped. The wasm hash is read from the instance, so only the address is
+// needed.
+//
+//	Requires client, and address or sourceAccount and salt
+func (c *Contract) ExportState(ctx context.Context, w io.Writer) (*StateSnapshot, error) {
+	if c.client == nil {
+		return nil, errors.New(ErrorRequiredClient)
+	}
+	address, err := c.GetAddress()
+	if err != nil {
+		return nil, err
+	}
+	instanceKey := contractDataKey(*address, xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance}, xdr.ContractDataDurabilityPersistent)
+	instanceKeyXdr, err := instanceKey.MarshalBinaryBase64()
+	if err != nil {
+		return nil, err
+	}
+	instance, err := c.client.GetLedgerEntries(ctx, instanceKeyXdr)
+	if err != nil {
+		return nil, err
+	}
+	if len(instance.Entries) == 0 {
+		return nil, errors.New(ErrorContractInstanceNotFound)
+	}
+	var instanceData xdr.LedgerEntryData
+	if err := xdr.SafeUnmarshalBase64(instance.Entries[0].Xdr, &instanceData); err != nil {
+		return nil, err
+	}
+
+	var keys []string
+	executable := instanceData.ContractData.Val.Instance.Executable
+	if executable.Type == xdr.ContractExecutableTypeContractExecutableWasm {
+		codeKey, err := Key.ContractCode(*executable.WasmHash)
+		if err != nil {
+			return nil, err
+		}
+		k, err := codeKey.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	for _, key := range c.stateKeys {
+		k, err := contractDataKey(*address, key, xdr.ContractDataDurabilityPersistent).MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, k)
+	}
+	entries := instance.Entries
+	latestLedger := instance.LatestLedger
+	if len(keys) > 0 {
+		res, err := c.client.GetLedgerEntries(ctx, keys...)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, res.Entries...)
+		latestLedger = res.LatestLedger
+	}
+
+	contract, err := address.String()
+	if err != nil {
+		return nil, err
+	}
+	snapshot := &StateSnapshot{
+		NetworkPassphrase: c.client.NetworkPassphrase(),
+		LatestLedger:      latestLedger,
+		Contract:          contract,
+		Entries:           make([]StateSnapshotEntry, 0, len(entries)),
+	}
+	for _, e := range entries {
+		snapshot.Entries = append(snapshot.Entries, StateSnapshotEntry{
+			Key:                e.Key,
+			Entry:              e.Xdr,
+			LastModifiedLedger: e.LastModifiedLedgerSeq,
+			LiveUntilLedger:    e.LiveUntilLedgerSeq,
+		})
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return snapshot, encoder.Encode(snapshot)
+}
+
+// StateLoader writes an entry of contract data into the imported contract,
+// usually invoking a setter of the contract. instance is true for entries of
+// the instance storage.
+type StateLoader func(ctx context.Context, contract *Contract, key, val xdr.ScVal, instance bool) error
+
+// SetterLoader returns a StateLoader invoking function(key, val) on the
+// contract for every entry, waiting for each to succeed
+func SetterLoader(function string) StateLoader {
+	return func(ctx context.Context, contract *Contract, key, val xdr.ScVal, instance bool) error {
+		pending, err := contract.Invoke().Function(function).Params(key, val).Send(ctx)
+		if err != nil {
+			return err
+		}
+		return waitSuccess(ctx, pending)
+	}
+}
+
+// StateImport is the result of Contract.ImportState
+type StateImport struct {
+	// Loaded are the data entries written by the loader
+	Loaded int
+	// Skipped are the entries of the snapshot that could not be imported
+	Skipped []StateSnapshotEntry
+}
+
+// ReadStateSnapshot decodes a JSON StateSnapshot written by ExportState
+func ReadStateSnapshot(r io.Reader) (*StateSnapshot, error) {
+	var snapshot StateSnapshot
+	if err := json.NewDecoder(r).Decode(&snapshot); err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
+
+// ImportState replays the snapshot on the network of the Contract, usually a
+// local standalone one: it installs the wasm of the snapshot, deploys a new
+// instance with the Contract salt (its address differs from the exported one,
+// the network is part of it) and writes the instance storage and persistent
+// data with loader. If loader is nil only the contract is deployed. Entries
+// that can not be imported are returned in Skipped.
+//
+//	Requires client, sourceAccount, keyPair, salt
+func (c *Contract) ImportState(ctx context.Context, snapshot *StateSnapshot, loader StateLoader) (*StateImport, error) {
+	var code *xdr.ContractCodeEntry
+	var instance *xdr.ScContractInstance
+	var data []xdr.ContractDataEntry
+	result := &StateImport{}
+	for _, e := range snapshot.Entries {
+		entry, err := e.LedgerEntryData()
+		if err != nil {
+			return nil, err
+		}
+		switch {
+		case entry.Type == xdr.LedgerEntryTypeContractCode:
+			code = entry.ContractCode
+		case entry.Type == xdr.LedgerEntryTypeContractData &&
+			entry.ContractData.Key.Type == xdr.ScValTypeScvLedgerKeyContractInstance:
+			instance = entry.ContractData.Val.Instance
+		case entry.Type == xdr.LedgerEntryTypeContractData &&
+			entry.ContractData.Durability == xdr.ContractDataDurabilityPersistent:
+			data = append(data, *entry.ContractData)
+		default:
+			result.Skipped = append(result.Skipped, e)
+		}
+	}
+	if code == nil || instance == nil {
+		return nil, errors.New(ErrorContractInstanceNotFound)
+	}
+
+	c.Wasm(code.Code)
+	pending, err := c.Install(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitSuccess(ctx, pending); err != nil {
+		return nil, err
+	}
+	pending, err = c.Deploy(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if err := waitSuccess(ctx, pending); err != nil {
+		return nil, err
+	}
+	if loader == nil {
+		return result, nil
+	}
+	if instance.Storage != nil {
+		for _, e := range *instance.Storage {
+			if err := loader(ctx, c, e.Key, e.Val, true); err != nil {
+				return result, err
+			}
+			result.Loaded++
+		}
+	}
+	for _, d := range data {
+		if err := loader(ctx, c, d.Key, d.Val, false); err != nil {
+			return result, err
+		}
+		result.Loaded++
+	}
+	return result, nil
+}
+
+func waitSuccess(ctx context.Context, pending *PendingTransaction) error {
+	res, err := pending.Wait(ctx)
+	if err != nil {
+		return err
+	}
+	if res.Status != "SUCCESS" {
+		return fmt.Errorf("%s: %s %s", ErrorTransactionRejected, res.TxHash, res.Status)
+	}
+	return nil
+}
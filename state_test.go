@@ -0,0 +1,142 @@
+package soroban_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestExportState(t *testing.T) {
+	address := contractAddress(7)
+	wasmHash := xdr.Hash{9}
+	entries := map[xdr.LedgerEntryType]xdr.LedgerEntryData{
+		xdr.LedgerEntryTypeContractCode: {
+			Type:         xdr.LedgerEntryTypeContractCode,
+			ContractCode: &xdr.ContractCodeEntry{Hash: wasmHash, Code: []byte("\x00asm")},
+		},
+	}
+	contract, fake := fakeContract()
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		res := &soroban.GetLedgerEntriesResult{LatestLedger: 100}
+		for _, k := range keys {
+			var key xdr.LedgerKey
+			xdr.SafeUnmarshalBase64(k, &key)
+			data, ok := entries[key.Type]
+			if key.Type == xdr.LedgerEntryTypeContractData {
+				if key.ContractData.Key.Type != xdr.ScValTypeScvLedgerKeyContractInstance {
+					// registered data keys are not found
+					continue
+				}
+				data, ok = xdr.LedgerEntryData{
+					Type: xdr.LedgerEntryTypeContractData,
+					ContractData: &xdr.ContractDataEntry{
+						Contract:   address,
+						Key:        key.ContractData.Key,
+						Durability: xdr.ContractDataDurabilityPersistent,
+						Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+							Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableWasm, WasmHash: &wasmHash},
+						}},
+					},
+				}, true
+			}
+			if !ok {
+				continue
+			}
+			entry, _ := xdr.MarshalBase64(data)
+			res.Entries = append(res.Entries, soroban.GetLedgerEntry{Key: k, Xdr: entry, LiveUntilLedgerSeq: 500})
+		}
+		return res, nil
+	}
+
+	var out bytes.Buffer
+	snapshot, err := contract.
+		Address(address).
+		StateKeys(xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: new(xdr.Uint32)}).
+		ExportState(context.Background(), &out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded soroban.StateSnapshot
+	if err := json.Unmarshal(out.Bytes(), &decoded); err != nil {
+		t.Fatal(err)
+	}
+	if len(decoded.Entries) != 2 || decoded.Contract != snapshot.Contract || decoded.LatestLedger != 100 {
+		t.Fatal(out.String())
+	}
+	code, err := decoded.Entries[1].LedgerEntryData()
+	if err != nil || code.ContractCode.Hash != wasmHash || decoded.Entries[1].LiveUntilLedger != 500 {
+		t.Fatal(code, err)
+	}
+}
+
+func TestImportStateMissingCode(t *testing.T) {
+	data, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.ContractCodeEntry{Code: []byte("\x00asm")},
+	})
+	snapshot, err := soroban.ReadStateSnapshot(bytes.NewBufferString(`{"entries":[{"entry":"` + data + `"}]}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = soroban.NewContract().ImportState(context.Background(), snapshot, soroban.SetterLoader("set"))
+	if err == nil || err.Error() != soroban.ErrorContractInstanceNotFound {
+		t.Fatal(err)
+	}
+}
+
+func TestImportState(t *testing.T) {
+	address := contractAddress(7)
+	code, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type:         xdr.LedgerEntryTypeContractCode,
+		ContractCode: &xdr.ContractCodeEntry{Code: []byte("\x00asm")},
+	})
+	instance, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+		Type: xdr.LedgerEntryTypeContractData,
+		ContractData: &xdr.ContractDataEntry{
+			Contract:   address,
+			Key:        xdr.ScVal{Type: xdr.ScValTypeScvLedgerKeyContractInstance},
+			Durability: xdr.ContractDataDurabilityPersistent,
+			Val: xdr.ScVal{Type: xdr.ScValTypeScvContractInstance, Instance: &xdr.ScContractInstance{
+				Executable: xdr.ContractExecutable{Type: xdr.ContractExecutableTypeContractExecutableStellarAsset},
+				Storage:    &xdr.ScMap{{Key: sym("admin"), Val: sym("alice")}},
+			}},
+		},
+	})
+	data := func(key string, durability xdr.ContractDataDurability) string {
+		entry, _ := xdr.MarshalBase64(xdr.LedgerEntryData{
+			Type:         xdr.LedgerEntryTypeContractData,
+			ContractData: &xdr.ContractDataEntry{Contract: address, Key: sym(key), Durability: durability, Val: sym("1")},
+		})
+		return entry
+	}
+	snapshot := &soroban.StateSnapshot{Entries: []soroban.StateSnapshotEntry{
+		{Entry: code},
+		{Entry: instance},
+		{Entry: data("balance", xdr.ContractDataDurabilityPersistent)},
+		{Entry: data("nonce", xdr.ContractDataDurabilityTemporary)},
+	}}
+
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	var loaded []string
+	loader := func(ctx context.Context, c *soroban.Contract, key, val xdr.ScVal, instance bool) error {
+		loaded = append(loaded, fmt.Sprintf("%s=%s %t", *key.Sym, *val.Sym, instance))
+		return nil
+	}
+	res, err := contract.ImportState(context.Background(), snapshot, loader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the temporary entry is skipped, the code installed and the contract deployed
+	if res.Loaded != 2 || len(res.Skipped) != 1 || res.Skipped[0] != snapshot.Entries[3] || fake.Count(soroban.SendTransaction) != 2 {
+		t.Fatal(res, fake.Calls)
+	}
+	if len(loaded) != 2 || loaded[0] != "admin=alice true" || loaded[1] != "balance=1 false" {
+		t.Fatal(loaded)
+	}
+}
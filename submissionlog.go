@@ -0,0 +1,98 @@
+package soroban
+
+import (
+	"context"
+	"encoding/hex"
+	"log/slog"
+	"time"
+
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+// Stages of a submission logged to Client.Logger
+const (
+	SubmissionAssembled = "assembled"
+	SubmissionSubmitted = "submitted"
+	SubmissionConfirmed = "confirmed"
+	SubmissionFailed    = "failed"
+)
+
+// submissionLog logs the lifecycle of a transaction sent with Transaction.Send,
+// a nil *submissionLog logs nothing. The final stage is logged once the
+// PendingTransaction is polled.
+type submissionLog struct {
+	logger *slog.Logger
+	start  time.Time
+	attrs  []any
+}
+
+func newSubmissionLog(client SorobanClient, tx *txnbuild.Transaction) *submissionLog {
+	c := config(client)
+	if c.Logger == nil {
+		return nil
+	}
+	attrs := []any{
+		slog.String("source", c.Label(tx.SourceAccount().AccountID)),
+		slog.Int64("fee", tx.MaxFee()),
+	}
+	if hash, err := tx.Hash(client.NetworkPassphrase()); err == nil {
+		attrs = append(attrs, slog.String("hash", hex.EncodeToString(hash[:])))
+	}
+	for _, op := range tx.Operations() {
+		invoke, ok := op.(*txnbuild.InvokeHostFunction)
+		if !ok || invoke.HostFunction.Type != xdr.HostFunctionTypeHostFunctionTypeInvokeContract {
+			continue
+		}
+		args := invoke.HostFunction.InvokeContract
+		if c.Labels != nil {
+			attrs = append(attrs, slog.String("contract", c.Labels.LabelScAddress(args.ContractAddress)))
+		} else if contract, err := args.ContractAddress.String(); err == nil {
+			attrs = append(attrs, slog.String("contract", contract))
+		}
+		attrs = append(attrs, slog.String("function", string(args.FunctionName)))
+	}
+	return &submissionLog{logger: c.Logger, start: time.Now(), attrs: attrs}
+}
+
+func (l *submissionLog) log(stage string, attrs ...any) {
+	if l == nil {
+		return
+	}
+	attrs = append(append([]any{slog.String("stage", stage)}, l.attrs...), attrs...)
+	attrs = append(attrs, slog.Duration("duration", time.Since(l.start)))
+	level := slog.LevelInfo
+	if stage == SubmissionFailed {
+		level = slog.LevelWarn
+	}
+	l.logger.Log(context.Background(), level, "soroban submission "+stage, attrs...)
+}
+
+func (l *submissionLog) assembled() {
+	l.log(SubmissionAssembled)
+}
+
+func (l *submissionLog) submitted(res *SendTransactionResult, err error) {
+	if res == nil {
+		l.log(SubmissionFailed, slog.String("error", err.Error()))
+		return
+	}
+	attrs := []any{slog.String("status", res.Status), slog.Int64("latest_ledger", res.LatestLedger)}
+	switch res.Status {
+	case "ERROR", "TRY_AGAIN_LATER":
+		l.log(SubmissionFailed, attrs...)
+	default:
+		l.log(SubmissionSubmitted, attrs...)
+	}
+}
+
+func (l *submissionLog) finished(res *GetTransactionResult, err error) {
+	switch {
+	case err != nil:
+		l.log(SubmissionFailed, slog.String("error", err.Error()))
+	case res.Status == "SUCCESS":
+		l.log(SubmissionConfirmed, slog.String("status", res.Status), slog.Int64("ledger", res.Ledger))
+	default:
+		l.log(SubmissionFailed, slog.String("status", res.Status), slog.Int64("ledger", res.Ledger))
+	}
+}
@@ -0,0 +1,77 @@
+package soroban_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestSubmissionLog(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		result := `{"hash":"abc","status":"PENDING","latestLedger":7}`
+		if req.Method == soroban.GetTransaction {
+			result = `{"status":"SUCCESS","txHash":"abc","ledger":8}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	var logs bytes.Buffer
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	// the handler drops the debug logs, only the stages are at info level
+	client.Logger = slog.New(slog.NewJSONHandler(&logs, nil))
+	pair := keypair.MustRandom()
+	client.Labels = soroban.NewAddressBook().Set(pair.Address(), "treasury")
+
+	pending, err := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := pending.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	stages := []string{soroban.SubmissionAssembled, soroban.SubmissionSubmitted, soroban.SubmissionConfirmed}
+	if len(lines) != len(stages) {
+		t.Fatal(logs.String())
+	}
+	hash := ""
+	for i, line := range lines {
+		var record map[string]any
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatal(err)
+		}
+		if record["stage"] != stages[i] || record["source"] != "treasury" || record["fee"] != float64(100) {
+			t.Fatal(line)
+		}
+		if i == 0 {
+			hash, _ = record["hash"].(string)
+		}
+		if hash == "" || record["hash"] != hash {
+			t.Fatal(line)
+		}
+	}
+}
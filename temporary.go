@@ -0,0 +1,118 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorTemporaryDataExpired = "Temporary contract data expired, it has to be written again"
+	ErrorNothingToExtend      = "No temporary contract data needs to be extended"
+)
+
+// ErrNothingToExtend is returned by ExtendTemporary when every key has at
+// least threshold ledgers left, no transaction is sent
+var ErrNothingToExtend = errors.New(ErrorNothingToExtend)
+
+// LedgerCloseTime is the expected time between ledgers, used to estimate
+// when an entry expires
+var LedgerCloseTime = 5 * time.Second
+
+// TemporaryEntry is the ttl of a temporary contract data key, as reported
+// by Contract.TemporaryEntries
+type TemporaryEntry struct {
+	Key xdr.ScVal
+	// Found is false once the entry expired, temporary entries are deleted
+	Found              bool
+	LiveUntilLedgerSeq int64
+	LatestLedger       int64
+}
+
+// RemainingLedgers returns the ledgers left until the entry expires, 0 if
+// it has
+func (e TemporaryEntry) RemainingLedgers() int64 {
+	if !e.Found || e.LiveUntilLedgerSeq < e.LatestLedger {
+		return 0
+	}
+	return e.LiveUntilLedgerSeq - e.LatestLedger + 1
+}
+
+// ExpiresAt estimates when the entry expires from now, using LedgerCloseTime
+func (e TemporaryEntry) ExpiresAt(now time.Time) time.Time {
+	return now.Add(time.Duration(e.RemainingLedgers()) * LedgerCloseTime)
+}
+
+// TemporaryEntries reports the ttl of the temporary contract data keys, e.g.
+// nonces or auction bids, in the same order, with a single getLedgerEntries
+// call.
+//
+//	Requires client, and address or sourceAccount and salt
+func (c *Contract) TemporaryEntries(ctx context.Context, keys ...xdr.ScVal) ([]TemporaryEntry, error) {
+	if c.client == nil {
+		return nil, errors.New(ErrorRequiredClient)
+	}
+	ledgerKeys := make([]string, len(keys))
+	for i, key := range keys {
+		ledgerKey, err := c.GetDataKey(key, Temporary)
+		if err != nil {
+			return nil, err
+		}
+		if ledgerKeys[i], err = ledgerKey.MarshalBinaryBase64(); err != nil {
+			return nil, err
+		}
+	}
+	res, err := c.client.GetLedgerEntries(ctx, ledgerKeys...)
+	if err != nil {
+		return nil, err
+	}
+	found := make(map[string]GetLedgerEntry, len(res.Entries))
+	for _, e := range res.Entries {
+		found[e.Key] = e
+	}
+	entries := make([]TemporaryEntry, len(keys))
+	for i, key := range keys {
+		e, ok := found[ledgerKeys[i]]
+		entries[i] = TemporaryEntry{
+			Key:                key,
+			Found:              ok,
+			LiveUntilLedgerSeq: e.LiveUntilLedgerSeq,
+			LatestLedger:       res.LatestLedger,
+		}
+	}
+	return entries, nil
+}
+
+// ExtendTemporary extends the ttl of the temporary keys with less than
+// threshold ledgers left to extendTo ledgers from the current one. It returns
+// ErrNothingToExtend if none needs it, and ErrorTemporaryDataExpired, with the
+// keys, if some already expired: unlike persistent data they can not be
+// restored.
+//
+//	Requires client, sourceAccount, keyPair, and address or salt
+func (c *Contract) ExtendTemporary(ctx context.Context, threshold, extendTo uint32, keys ...xdr.ScVal) (*PendingTransaction, error) {
+	entries, err := c.TemporaryEntries(ctx, keys...)
+	if err != nil {
+		return nil, err
+	}
+	var expiring []xdr.ScVal
+	var expired []string
+	for _, e := range entries {
+		switch {
+		case !e.Found:
+			expired = append(expired, e.Key.String())
+		case e.RemainingLedgers() < int64(threshold):
+			expiring = append(expiring, e.Key)
+		}
+	}
+	if len(expired) > 0 {
+		return nil, fmt.Errorf("%s: %v", ErrorTemporaryDataExpired, expired)
+	}
+	if len(expiring) == 0 {
+		return nil, ErrNothingToExtend
+	}
+	return c.ExtendData(ctx, Temporary, extendTo, expiring...)
+}
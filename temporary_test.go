@@ -0,0 +1,65 @@
+package soroban_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestTemporaryEntries(t *testing.T) {
+	nonce := func(n uint32) xdr.ScVal {
+		u := xdr.Uint32(n)
+		return xdr.ScVal{Type: xdr.ScValTypeScvU32, U32: &u}
+	}
+	// nonce 1 expires soon, nonce 2 later, nonce 3 expired
+	ttls := map[uint32]int64{1: 105, 2: 1000}
+	var extended []xdr.LedgerKey
+	contract, fake := fakeContract()
+	contract.Address(contractAddress(4))
+	fake.GetLedgerEntriesFunc = func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+		res := &soroban.GetLedgerEntriesResult{LatestLedger: 100}
+		for _, k := range keys {
+			var key xdr.LedgerKey
+			xdr.SafeUnmarshalBase64(k, &key)
+			if ttl, ok := ttls[uint32(*key.ContractData.Key.U32)]; ok {
+				res.Entries = append(res.Entries, soroban.GetLedgerEntry{Key: k, LiveUntilLedgerSeq: ttl})
+			}
+		}
+		return res, nil
+	}
+	simulate := fake.SimulateTransactionFunc
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		extended = simulatedFootprint(envelopeXdr).ReadOnly
+		return simulate(envelopeXdr)
+	}
+
+	entries, err := contract.TemporaryEntries(context.Background(), nonce(1), nonce(2), nonce(3))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if entries[0].RemainingLedgers() != 6 || !entries[1].Found || entries[2].Found || entries[2].RemainingLedgers() != 0 {
+		t.Fatal(entries)
+	}
+	now := time.Now()
+	if entries[0].ExpiresAt(now) != now.Add(6*soroban.LedgerCloseTime) {
+		t.Fatal(entries[0].ExpiresAt(now))
+	}
+
+	if _, err := contract.ExtendTemporary(context.Background(), 10, 500, nonce(1), nonce(3)); err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorTemporaryDataExpired) {
+		t.Fatal(err)
+	}
+	if _, err := contract.ExtendTemporary(context.Background(), 10, 500, nonce(1), nonce(2)); err != nil {
+		t.Fatal(err)
+	}
+	if len(extended) != 1 || *extended[0].ContractData.Key.U32 != 1 {
+		t.Fatal(extended)
+	}
+	if _, err := contract.ExtendTemporary(context.Background(), 10, 500, nonce(2)); !errors.Is(err, soroban.ErrNothingToExtend) {
+		t.Fatal(err)
+	}
+}
@@ -0,0 +1,46 @@
+package soroban
+
+import (
+	"context"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attributes of the spans of the transaction lifecycle, besides the ones of
+// the RPC calls, "rpc.method"
+const (
+	TxHashAttribute       = attribute.Key("soroban.tx.hash")
+	TxStatusAttribute     = attribute.Key("soroban.tx.status")
+	LedgerAttribute       = attribute.Key("soroban.ledger")
+	LatestLedgerAttribute = attribute.Key("soroban.latest_ledger")
+	ResourceFeeAttribute  = attribute.Key("soroban.resource_fee")
+)
+
+// startSpan starts a span of the tracer of client, from the TracerProvider
+// of a Configured client or the global one of OpenTelemetry
+func startSpan(ctx context.Context, client SorobanClient, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	provider := otel.GetTracerProvider()
+	if c := config(client); c.TracerProvider != nil {
+		provider = c.TracerProvider
+	}
+	return provider.Tracer(rpc.TracerName).Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// endSpan ends span, recording err if any
+func endSpan(span trace.Span, err error) {
+	rpc.EndSpan(span, err)
+}
+
+// traceResult sets the attributes of a final transaction result on span
+func traceResult(span trace.Span, res *GetTransactionResult) {
+	if res != nil {
+		span.SetAttributes(
+			TxStatusAttribute.String(res.Status),
+			LedgerAttribute.Int64(res.Ledger),
+			LatestLedgerAttribute.Int64(res.LatestLedger),
+		)
+	}
+}
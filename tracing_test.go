@@ -0,0 +1,81 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/internal/rpc"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracing(t *testing.T) {
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req rpc.Request
+		json.NewDecoder(r.Body).Decode(&req)
+		traceparents = append(traceparents, r.Header.Get("Traceparent"))
+		switch req.Method {
+		case soroban.SendTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"hash":"abc","status":"PENDING","latestLedger":10}}`, req.ID)
+		case soroban.GetTransaction:
+			fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":"SUCCESS","txHash":"abc","ledger":11,"latestLedger":11}}`, req.ID)
+		}
+	}))
+	defer server.Close()
+
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	client := &soroban.Client{Client: rpc.Client{URL: server.URL, TracerProvider: provider}, PassPhrase: network.TestNetworkPassphrase}
+
+	source := keypair.MustRandom()
+	ctx, cancel := context.WithCancel(context.Background())
+	pending, err := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: source.Address(), Sequence: 1}).
+		Signer(source).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// the polling keeps the span of the submission, not its cancellation
+	cancel()
+	if _, err := pending.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		spans[span.Name()] = span
+	}
+	send, wait := spans["soroban.send"], spans["soroban.wait"]
+	sendCall, getCall := spans["rpc "+soroban.SendTransaction], spans["rpc "+soroban.GetTransaction]
+	if send == nil || wait == nil || sendCall == nil || getCall == nil {
+		t.Fatal(spans)
+	}
+	if sendCall.Parent().SpanID() != send.SpanContext().SpanID() ||
+		wait.Parent().SpanID() != send.SpanContext().SpanID() ||
+		getCall.Parent().SpanID() != wait.SpanContext().SpanID() {
+		t.Fatal("unexpected span tree")
+	}
+	attrs := map[string]string{}
+	for _, attr := range append(send.Attributes(), wait.Attributes()...) {
+		attrs[string(attr.Key)] = attr.Value.Emit()
+	}
+	if attrs["soroban.tx.hash"] != "abc" || attrs["soroban.tx.status"] != "SUCCESS" || attrs["soroban.ledger"] != "11" {
+		t.Fatal(attrs)
+	}
+	// the global propagator of OpenTelemetry propagates nothing by default
+	if len(traceparents) != 2 || traceparents[0] != "" {
+		t.Fatal(traceparents)
+	}
+}
@@ -1,15 +1,32 @@
 package soroban
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+
 	"github.com/stellar/go/keypair"
 	"github.com/stellar/go/txnbuild"
 	"github.com/stellar/go/xdr"
 )
 
+// DefaultTimeout of the transactions when neither TimeBounds, Timeout or
+// the Client DefaultTimeout are set
+const DefaultTimeout = 30 * time.Second
+
+const (
+	ErrorSorobanInfiniteTimeBounds = "Soroban transactions require a max time bound"
+	ErrorFeeCeilingExceeded        = "Transaction fee exceeds the max fee"
+)
+
 type (
 	Transaction struct {
-		client *Client
+		client SorobanClient
 		build  *transactionBuild
+		// envelope is the partially signed transaction, see SignPartial
+		envelope *txnbuild.Transaction
 	}
 
 	transactionBuild struct {
@@ -23,8 +40,23 @@ type (
 		minSequenceNumberLedgerGap uint32
 		extraSigners               []string
 		Memo                       txnbuild.Memo
-		baseFee                    int64
+		timeout                    time.Duration
+		inclusionFee               int64
+		resourceFee                int64
 		incrementSequenceNum       bool
+		authSigners                []*keypair.Full
+		maxFee                     int64
+		// sequence of the envelope, see Pin
+		sequence int64
+		// checkEnvelope runs CheckEnvelope before Send, with expectedSigners
+		checkEnvelope   bool
+		expectedSigners []string
+		// feeEscalation overrides the Client FeeEscalation
+		feeEscalation *FeeEscalation
+		// coSigners sign on Send, after the signers
+		coSigners []TransactionSigner
+		// simulateOptions are the options of Simulate
+		simulateOptions SimulateOptions
 		// sorobanData                *xdr.SorobanTransactionData
 	}
 )
@@ -32,13 +64,13 @@ type (
 func NewTransctionBuilder() *Transaction {
 	return &Transaction{
 		build: &transactionBuild{
-			baseFee:              txnbuild.MinBaseFee,
+			inclusionFee:         txnbuild.MinBaseFee,
 			incrementSequenceNum: true,
 		},
 	}
 }
 
-func (t *Transaction) Client(c *Client) *Transaction {
+func (t *Transaction) Client(c SorobanClient) *Transaction {
 	t.client = c
 	return t
 }
@@ -64,6 +96,14 @@ func (t *Transaction) TimeBounds(tb txnbuild.TimeBounds) *Transaction {
 	return t
 }
 
+// Timeout sets the time bounds to now + timeout when the transaction is built,
+// used only if TimeBounds are not set. If neither is set, the Client DefaultTimeout
+// is used.
+func (t *Transaction) Timeout(timeout time.Duration) *Transaction {
+	t.build.timeout = timeout
+	return t
+}
+
 // Transaction is valid for ledger numbers n such that minLedger <= n <
 // maxLedger (if maxLedger == 0, then only minLedger is checked)
 func (t *Transaction) LedgerBounds(lb *txnbuild.LedgerBounds) *Transaction {
@@ -107,8 +147,57 @@ func (t *Transaction) Memo(m txnbuild.Memo) *Transaction {
 	return t
 }
 
+// BaseFee sets the inclusion fee, the fee bid to get the transaction
+// included in the ledger. It is the same as InclusionFee.
 func (t *Transaction) BaseFee(f int64) *Transaction {
-	t.build.baseFee = f
+	return t.InclusionFee(f)
+}
+
+// InclusionFee sets the fee bid to get the transaction included in the
+// ledger, default txnbuild.MinBaseFee
+func (t *Transaction) InclusionFee(f int64) *Transaction {
+	t.build.inclusionFee = f
+	return t
+}
+
+// ResourceFee sets the resource fee of the Soroban transaction data,
+// overriding the minimum resource fee set by Simulate. The fee of the
+// transaction is the inclusion fee plus the resource fee. It is ignored
+// without a Soroban operation.
+func (t *Transaction) ResourceFee(f int64) *Transaction {
+	t.build.resourceFee = f
+	return t
+}
+
+// MaxFee sets the ceiling of the total fee (inclusion plus resource fee, of
+// every operation), Send fails with ErrorFeeCeilingExceeded before signing if
+// it is exceeded, e.g. by a simulated resource fee during a network anomaly.
+// It overrides the Client MaxFee.
+func (t *Transaction) MaxFee(f int64) *Transaction {
+	t.build.maxFee = f
+	return t
+}
+
+// InstructionLeeway sets the instructions added by Simulate to the simulated
+// ones, headroom for heavy invocations whose cost grows by submission
+func (t *Transaction) InstructionLeeway(leeway uint64) *Transaction {
+	t.build.simulateOptions.ResourceConfig = &ResourceConfig{InstructionLeeway: leeway}
+	return t
+}
+
+// AuthMode sets the auth mode of Simulate, e.g. AuthModeRecord to preview
+// the required authorization entries or AuthModeEnforce to check the
+// provided ones during preflight
+func (t *Transaction) AuthMode(mode string) *Transaction {
+	t.build.simulateOptions.AuthMode = mode
+	return t
+}
+
+// AuthSigner adds signers for the Soroban authorization entries returned by
+// Simulate. Each entry is signed by the signer matching its address, see
+// SignAuthEntries.
+func (t *Transaction) AuthSigner(signers ...*keypair.Full) *Transaction {
+	t.build.authSigners = append(t.build.authSigners, signers...)
 	return t
 }
 
@@ -138,80 +227,319 @@ func (t *Transaction) SorobanData(data xdr.SorobanTransactionData) *Transaction
 				V:           1,
 				SorobanData: &data,
 			}
+		case *txnbuild.ExtendFootprintTtl:
+			op.(*txnbuild.ExtendFootprintTtl).Ext = xdr.TransactionExt{
+				V:           1,
+				SorobanData: &data,
+			}
 		}
 	}
 	return t
 }
 
 // Simulate simulates an prepares the transaction adding authorization, transactionData,
-// and fee
-func (t *Transaction) Simulate() (*SimulateTransactionResult, error) {
+// and resource fee. If AuthSigner is set, the authorization entries are signed and
+// the transaction simulated again, to account for the signatures verification.
+// It is traced in a span "soroban.simulate".
+func (t *Transaction) Simulate(ctx context.Context) (res *SimulateTransactionResult, err error) {
+	ctx, span := startSpan(ctx, t.client, "soroban.simulate")
+	defer func() {
+		if res != nil {
+			span.SetAttributes(ResourceFeeAttribute.Int64(res.MinResourceFee), LatestLedgerAttribute.Int64(res.LatestLedger))
+		}
+		if err != nil {
+			metrics(t.client).SimulationFailed(err)
+		}
+		endSpan(span, err)
+	}()
+	res, auth, err := t.simulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(t.build.authSigners) == 0 || !needsAuthSignatures(auth) {
+		return res, nil
+	}
+	validUntil := uint32(res.LatestLedger) + DefaultAuthValidityLedgers
+	auth, err = SignAuthEntries(auth, t.build.authSigners, validUntil, t.client.NetworkPassphrase())
+	if err != nil {
+		return nil, err
+	}
+	t.Authorization(auth)
+	res, _, err = t.simulate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	t.Authorization(auth)
+	return res, nil
+}
+
+// simulate simulates the transaction, setting the simulated transactionData,
+// resource fee and authorization
+func (t *Transaction) simulate(ctx context.Context) (*SimulateTransactionResult, []xdr.SorobanAuthorizationEntry, error) {
+	if err := t.loadSource(ctx); err != nil {
+		return nil, nil, err
+	}
 	increase := t.build.incrementSequenceNum
 	t.build.incrementSequenceNum = false
 	tx, err := t.buildTx()
 	t.build.incrementSequenceNum = increase
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	res, err := t.client.SimulateTransaction(tx)
+	res, err := t.simulateTx(ctx, tx)
 	if err != nil {
-		return nil, err
+		return nil, nil, err
+	}
+	if res.Error != "" {
+		return nil, nil, simulationError(res)
+	}
+	if err := res.Decode(); err != nil {
+		return nil, nil, err
 	}
 	var auth []xdr.SorobanAuthorizationEntry
-	for _, res := range res.Results {
-		var decodedRes xdr.ScVal
-		err := xdr.SafeUnmarshalBase64(res.XDR, &decodedRes)
+	for _, result := range res.Results {
+		auth = append(auth, result.AuthEntries...)
+	}
+	debug(ctx, t.client, "simulated",
+		slog.Int64("minResourceFee", res.MinResourceFee),
+		slog.Int64("latestLedger", res.LatestLedger),
+		slog.Int("auth", len(auth)),
+		slog.Bool("restore", res.RestorePreamble.MinResourceFee != 0),
+		slog.String("error", res.Error),
+	)
+	data := res.Data
+	data.ResourceFee = xdr.Int64(res.MinResourceFee)
+	t = t.
+		SorobanData(data).
+		Authorization(auth)
+	return res, auth, nil
+}
+
+// simulateTx simulates tx with the options, if any
+func (t *Transaction) simulateTx(ctx context.Context, tx *txnbuild.Transaction) (*SimulateTransactionResult, error) {
+	if t.build.simulateOptions == (SimulateOptions{}) {
+		return t.client.SimulateTransaction(ctx, tx)
+	}
+	envelopeXdr, err := tx.Base64()
+	if err != nil {
+		return nil, err
+	}
+	return t.client.SimulateTransactionWithOptions(ctx, envelopeXdr, t.build.simulateOptions)
+}
+
+// Send signs and submits the transaction, the returned PendingTransaction
+// can be used to wait for its final result.
+// If the transaction was partially signed, the stored envelope is sent, after
+// adding the signatures of the Signers.
+// It is traced in a span "soroban.send", the parent of the span
+// "soroban.wait" of the PendingTransaction.
+func (t *Transaction) Send(ctx context.Context) (pending *PendingTransaction, err error) {
+	ctx, span := startSpan(ctx, t.client, "soroban.send")
+	defer func() {
+		if pending != nil {
+			span.SetAttributes(
+				TxHashAttribute.String(pending.sent.Hash),
+				TxStatusAttribute.String(pending.sent.Status),
+				LatestLedgerAttribute.Int64(pending.sent.LatestLedger),
+			)
+			pending.ctx = context.WithoutCancel(ctx)
+		}
+		endSpan(span, err)
+	}()
+	return t.send(ctx, autoFund(t.client))
+}
+
+// send submits the transaction, if fund is set and the submission is
+// rejected because the source account is missing or underfunded, it is
+// funded and the transaction sent again, see Client AutoFund
+func (t *Transaction) send(ctx context.Context, fund bool) (pending *PendingTransaction, err error) {
+	tx := t.envelope
+	if tx == nil {
+		if err := t.loadSource(ctx); err != nil {
+			return nil, err
+		}
+		tx, err = t.newTx()
 		if err != nil {
 			return nil, err
 		}
-		for _, authBase64 := range res.Auth {
-			var authEntry xdr.SorobanAuthorizationEntry
-			err = xdr.SafeUnmarshalBase64(authBase64, &authEntry)
-			if err != nil {
-				return nil, err
-			}
-			auth = append(auth, authEntry)
+	}
+	if err := t.checkMaxFee(tx); err != nil {
+		return nil, err
+	}
+	if t.envelope == nil {
+		if err := t.incrementSequence(); err != nil {
+			return nil, err
 		}
 	}
-	var transactionData xdr.SorobanTransactionData
-	err = xdr.SafeUnmarshalBase64(res.TransactionData, &transactionData)
+	tx, err = t.addSignatures(tx, t.build.signers)
 	if err != nil {
 		return nil, err
 	}
-	t = t.
-		BaseFee(res.MinResourceFee + txnbuild.MinBaseFee).
-		SorobanData(transactionData).
-		Authorization(auth)
-	return res, nil
-}
-
-func (t *Transaction) Send() (*SendTransactionResult, error) {
-	tx, err := t.buildTx()
+	tx, err = t.addCoSignatures(ctx, tx)
 	if err != nil {
 		return nil, err
 	}
-	tx, err = tx.Sign(t.client.PassPhrase, t.build.signers...)
-	if err != nil {
+	if err := t.checkEnvelope(tx); err != nil {
+		return nil, err
+	}
+	debug(ctx, t.client, "fee",
+		slog.Int64("inclusionFee", t.build.inclusionFee),
+		slog.Int64("resourceFee", t.resourceFee()),
+		slog.Int64("maxFee", tx.MaxFee()),
+	)
+	log := newSubmissionLog(t.client, tx)
+	log.assembled()
+	res, err := t.client.SendTransaction(ctx, tx)
+	log.submitted(res, err)
+	t.refreshSource(res)
+	if res == nil {
+		metrics(t.client).Submitted("")
 		return nil, err
 	}
-	return t.client.SendTransaction(tx)
+	metrics(t.client).Submitted(res.Status)
+	if fund && err == nil && t.fundSource(ctx, res) {
+		return t.send(ctx, false)
+	}
+	if res.Status == "TRY_AGAIN_LATER" && err == nil {
+		if pending, ok, err := t.escalate(ctx, tx.SequenceNumber(), fund); ok {
+			return pending, err
+		}
+	}
+	// err can only be an *AuditError here, the transaction was submitted
+	pending = newPendingTransaction(t.client, res)
+	pending.log = log
+	return pending, err
+}
+
+// checkMaxFee returns ErrorFeeCeilingExceeded if the fee of tx is above the
+// max fee of the transaction or client. It is checked before the sequence
+// number of the source account is incremented.
+func (t *Transaction) checkMaxFee(tx *txnbuild.Transaction) error {
+	maxFee := t.build.maxFee
+	if maxFee == 0 {
+		maxFee = config(t.client).MaxFee
+	}
+	if maxFee == 0 {
+		return nil
+	}
+	if fee := tx.MaxFee(); fee > maxFee {
+		return fmt.Errorf("%s: %d > %d", ErrorFeeCeilingExceeded, fee, maxFee)
+	}
+	return nil
+}
+
+func (t *Transaction) timeBounds() (txnbuild.TimeBounds, error) {
+	timeBounds := t.build.timeBounds
+	if timeBounds == (txnbuild.TimeBounds{}) {
+		timeout := t.build.timeout
+		if timeout == 0 {
+			timeout = config(t.client).DefaultTimeout
+		}
+		if timeout == 0 {
+			timeout = DefaultTimeout
+		}
+		// rounded up, a sub-second timeout would be infinite
+		timeBounds = txnbuild.NewTimeout(int64((timeout + time.Second - 1) / time.Second))
+	}
+	if timeBounds.MaxTime == 0 && t.isSoroban() {
+		return timeBounds, errors.New(ErrorSorobanInfiniteTimeBounds)
+	}
+	return timeBounds, nil
+}
+
+func (t *Transaction) isSoroban() bool {
+	for _, op := range t.build.operations {
+		switch op.(type) {
+		case *txnbuild.InvokeHostFunction, *txnbuild.RestoreFootprint, *txnbuild.ExtendFootprintTtl:
+			return true
+		}
+	}
+	return false
 }
 
+// buildTx builds the transaction, incrementing the sequence number of the
+// source account
 func (t *Transaction) buildTx() (*txnbuild.Transaction, error) {
+	tx, err := t.newTx()
+	if err != nil {
+		return nil, err
+	}
+	return tx, t.incrementSequence()
+}
+
+// newTx builds the transaction on a copy of the source account, leaving its
+// sequence number untouched, see incrementSequence
+func (t *Transaction) newTx() (*txnbuild.Transaction, error) {
+	timeBounds, err := t.timeBounds()
+	if err != nil {
+		return nil, err
+	}
 	precondirtions := txnbuild.Preconditions{
-		TimeBounds:                 t.build.timeBounds,
+		TimeBounds:                 timeBounds,
 		LedgerBounds:               t.build.ledgerBounds,
 		MinSequenceNumber:          t.build.minSequenceNumber,
 		MinSequenceNumberAge:       t.build.minSequenceNumberAge,
 		MinSequenceNumberLedgerGap: t.build.minSequenceNumberLedgerGap,
 		ExtraSigners:               t.build.extraSigners,
 	}
+	if data := t.sorobanData(); data != nil && t.build.resourceFee != 0 {
+		data.ResourceFee = xdr.Int64(t.build.resourceFee)
+	}
+	source := t.source()
+	if source != nil {
+		seq, err := source.GetSequenceNumber()
+		if err != nil {
+			return nil, err
+		}
+		source = &txnbuild.SimpleAccount{AccountID: source.GetAccountID(), Sequence: seq}
+	}
+	// txnbuild adds the resource fee of the Soroban data to the base fee
 	params := txnbuild.TransactionParams{
-		SourceAccount:        t.build.source,
+		SourceAccount:        source,
 		Operations:           t.build.operations,
 		Preconditions:        precondirtions,
-		BaseFee:              t.build.baseFee,
+		BaseFee:              t.build.inclusionFee,
 		IncrementSequenceNum: t.build.incrementSequenceNum,
 	}
 	return txnbuild.NewTransaction(params)
 }
+
+// incrementSequence increments the sequence number of the source account
+// after newTx, unless it is pinned
+func (t *Transaction) incrementSequence() error {
+	if !t.build.incrementSequenceNum || t.build.sequence != 0 || t.build.source == nil {
+		return nil
+	}
+	_, err := t.build.source.IncrementSequenceNumber()
+	return err
+}
+
+// sorobanData returns the Soroban transaction data of the operation, nil if
+// it is not set
+func (t *Transaction) sorobanData() *xdr.SorobanTransactionData {
+	if len(t.build.operations) == 0 {
+		return nil
+	}
+	switch op := t.build.operations[0].(type) {
+	case *txnbuild.InvokeHostFunction:
+		return op.Ext.SorobanData
+	case *txnbuild.RestoreFootprint:
+		return op.Ext.SorobanData
+	case *txnbuild.ExtendFootprintTtl:
+		return op.Ext.SorobanData
+	}
+	return nil
+}
+
+// resourceFee returns the resource fee of the transaction, the one of its
+// Soroban data unless overridden with ResourceFee
+func (t *Transaction) resourceFee() int64 {
+	data := t.sorobanData()
+	switch {
+	case data == nil:
+		return 0
+	case t.build.resourceFee != 0:
+		return t.build.resourceFee
+	}
+	return int64(data.ResourceFee)
+}
@@ -0,0 +1,321 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+	"github.com/stellar/go/xdr"
+)
+
+func TestMaxFee(t *testing.T) {
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}
+	fake := &sorobantest.FakeClient{Passphrase: network.TestNetworkPassphrase}
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(account).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		InclusionFee(1000).
+		MaxFee(500).
+		Send(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorFeeCeilingExceeded) {
+		t.Fatal(err)
+	}
+	if account.Sequence != 1 || len(fake.Calls) != 0 {
+		t.Fatal("transaction built or sent", account.Sequence, fake.Calls)
+	}
+
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase, MaxFee: 100}
+	_, err = soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(account).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}, &txnbuild.BumpSequence{BumpTo: 11}).
+		Send(context.Background())
+	if err == nil || err.Error() != soroban.ErrorFeeCeilingExceeded+": 200 > 100" {
+		t.Fatal(err)
+	}
+}
+
+func TestInclusionAndResourceFee(t *testing.T) {
+	var fees []uint32
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 500})
+			return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 500}, nil
+		},
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			if data := envelope.V1.Tx.Ext.SorobanData; data == nil || data.ResourceFee > xdr.Int64(envelope.Fee()) {
+				t.Fatal("resource fee", envelope.Fee(), data)
+			}
+			fees = append(fees, envelope.Fee())
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	contract := contractAddress(1)
+	ctx := context.Background()
+	send := func(build func(*soroban.Transaction) *soroban.Transaction) {
+		t.Helper()
+		tx := soroban.NewTransctionBuilder().
+			Client(fake).
+			SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+			Signer(pair).
+			Operation(&txnbuild.InvokeHostFunction{HostFunction: xdr.HostFunction{
+				Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+				InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contract, FunctionName: "hello"},
+			}})
+		tx = build(tx)
+		if _, err := tx.Simulate(ctx); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tx.Send(ctx); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// the simulation sets the resource fee, BaseFee the inclusion fee
+	send(func(tx *soroban.Transaction) *soroban.Transaction { return tx })
+	send(func(tx *soroban.Transaction) *soroban.Transaction { return tx.BaseFee(300) })
+	send(func(tx *soroban.Transaction) *soroban.Transaction { return tx.InclusionFee(200).ResourceFee(1000) })
+	if len(fees) != 3 || fees[0] != txnbuild.MinBaseFee+500 || fees[1] != 800 || fees[2] != 1200 {
+		t.Fatal(fees)
+	}
+}
+
+func TestSorobanMaxFee(t *testing.T) {
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionFunc: func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+			data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{ResourceFee: 500})
+			return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 500}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}
+	tx := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(account).
+		Signer(pair).
+		Operation(&txnbuild.InvokeHostFunction{HostFunction: xdr.HostFunction{
+			Type:           xdr.HostFunctionTypeHostFunctionTypeInvokeContract,
+			InvokeContract: &xdr.InvokeContractArgs{ContractAddress: contractAddress(1), FunctionName: "hello"},
+		}}).
+		InclusionFee(100).
+		MaxFee(550)
+	if _, err := tx.Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	// the fee of the built transaction, inclusion plus simulated resource fee
+	_, err := tx.Send(context.Background())
+	if err == nil || err.Error() != soroban.ErrorFeeCeilingExceeded+": 600 > 550" {
+		t.Fatal(err)
+	}
+	if account.Sequence != 1 || countCalls(fake, soroban.SendTransaction) != 0 {
+		t.Fatal("transaction built or sent", account.Sequence, fake.Calls)
+	}
+}
+
+func TestTimeoutRoundedUp(t *testing.T) {
+	var maxTime xdr.TimePoint
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+			var envelope xdr.TransactionEnvelope
+			if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+				t.Fatal(err)
+			}
+			maxTime = envelope.TimeBounds().MaxTime
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	now := time.Now().Unix()
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Timeout(1500 * time.Millisecond).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if int64(maxTime) < now+2 || int64(maxTime) > time.Now().Unix()+2 {
+		t.Fatal(maxTime, now)
+	}
+}
+
+func TestInvokeMaxFee(t *testing.T) {
+	latestLedger := int64(10)
+	contract, fake := livenessContract(&latestLedger)
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+		return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 1_000_000}, nil
+	}
+	_, err := contract.Invoke().Function("hello").MaxFee(10_000).Send(context.Background())
+	if err == nil || !strings.HasPrefix(err.Error(), soroban.ErrorFeeCeilingExceeded) {
+		t.Fatal(err)
+	}
+	if countCalls(fake, soroban.SendTransaction) != 0 {
+		t.Fatal(fake.Calls)
+	}
+}
+
+func TestInvokeResourceFee(t *testing.T) {
+	contract, fake := fakeContract()
+	fake.SimulateTransactionFunc = func(envelopeXdr string) (*soroban.SimulateTransactionResult, error) {
+		data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{
+			Resources:   xdr.SorobanResources{Instructions: 100},
+			ResourceFee: 500,
+		})
+		return &soroban.SimulateTransactionResult{TransactionData: data, MinResourceFee: 500}, nil
+	}
+	var fees []uint32
+	fake.SendTransactionFunc = func(envelopeXdr string) (*soroban.SendTransactionResult, error) {
+		var envelope xdr.TransactionEnvelope
+		if err := xdr.SafeUnmarshalBase64(envelopeXdr, &envelope); err != nil {
+			return nil, err
+		}
+		fees = append(fees, envelope.Fee())
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+	}
+	// the resource fee is scaled with the instructions, or overridden
+	if _, err := contract.Invoke().Function("hello").Instructions(200).Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := contract.Invoke().Function("hello").ResourceFee(300).Send(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(fees) != 2 || fees[0] != txnbuild.MinBaseFee+1000 || fees[1] != txnbuild.MinBaseFee+300 {
+		t.Fatal(fees)
+	}
+}
+
+func TestSendCanceled(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+
+	pair := keypair.MustRandom()
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatal(err)
+	}
+}
+
+func TestInstructionLeeway(t *testing.T) {
+	data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	var params []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64          `json:"id"`
+			Params json.RawMessage `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var p map[string]any
+		json.Unmarshal(req.Params, &p)
+		config, _ := json.Marshal(p["resourceConfig"])
+		params = append(params, string(config))
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"transactionData":%q,"minResourceFee":"100","latestLedger":7}}`, req.ID, data)
+	}))
+	defer server.Close()
+
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	pair := keypair.MustRandom()
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+	if _, err := tx.Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.InstructionLeeway(1000000).Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(params) != 2 || params[0] != "null" || params[1] != `{"instructionLeeway":1000000}` {
+		t.Fatal(params)
+	}
+}
+
+func TestAuthMode(t *testing.T) {
+	data, _ := xdr.MarshalBase64(xdr.SorobanTransactionData{})
+	var modes []any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64         `json:"id"`
+			Params map[string]any `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		modes = append(modes, req.Params["authMode"])
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"transactionData":%q,"minResourceFee":"100","latestLedger":7}}`, req.ID, data)
+	}))
+	defer server.Close()
+
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+	pair := keypair.MustRandom()
+	tx := soroban.NewTransctionBuilder().
+		Client(client).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10})
+	if _, err := tx.Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.AuthMode(soroban.AuthModeRecordAllowNonroot).Simulate(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	var options []soroban.SimulateOptions
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SimulateTransactionWithOptionsFunc: func(envelopeXdr string, opts soroban.SimulateOptions) (*soroban.SimulateTransactionResult, error) {
+			options = append(options, opts)
+			return &soroban.SimulateTransactionResult{TransactionData: data}, nil
+		},
+	}
+	_, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		AuthMode(soroban.AuthModeEnforce).
+		InstructionLeeway(10).
+		Simulate(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(modes) != 2 || modes[0] != nil || modes[1] != "record_allow_nonroot" ||
+		len(options) != 1 || options[0].AuthMode != "enforce" || options[0].ResourceConfig.InstructionLeeway != 10 {
+		t.Fatal(modes, options)
+	}
+}
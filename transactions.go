@@ -0,0 +1,172 @@
+package soroban
+
+import (
+	"context"
+	"strconv"
+)
+
+// TransactionsOptions are the optional params of GetTransactions, the
+// startLedger is ignored with a Cursor
+type TransactionsOptions struct {
+	Cursor string
+	Limit  uint
+}
+
+// GetTransactionsResult as defined in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getTransactions
+type GetTransactionsResult struct {
+	Transactions               []TransactionInfo `json:"transactions"`
+	LatestLedger               int64             `json:"latestLedger"`
+	LatestLedgerCloseTimestamp int64             `json:"latestLedgerCloseTimestamp"`
+	OldestLedger               int64             `json:"oldestLedger"`
+	OldestLedgerCloseTimestamp int64             `json:"oldestLedgerCloseTimestamp"`
+	Cursor                     string            `json:"cursor"`
+}
+
+// TransactionInfo is a transaction of GetTransactionsResult
+type TransactionInfo struct {
+	Status              string   `json:"status"`
+	TxHash              string   `json:"txHash"`
+	ApplicationOrder    int64    `json:"applicationOrder"`
+	FeeBump             bool     `json:"feeBump"`
+	EnvelopeXdr         string   `json:"envelopeXdr"`
+	ResultXdr           string   `json:"resultXdr"`
+	ResultMetaXdr       string   `json:"resultMetaXdr"`
+	DiagnosticEventsXdr []string `json:"diagnosticEventsXdr"`
+	Ledger              int64    `json:"ledger"`
+	CreatedAt           int64    `json:"createdAt"`
+}
+
+// Result returns the transaction as a GetTransactionResult, to decode it
+// with Decode or Meta
+func (t TransactionInfo) Result() GetTransactionResult {
+	return GetTransactionResult{
+		Status:           t.Status,
+		TxHash:           t.TxHash,
+		Ledger:           t.Ledger,
+		CreatedAt:        strconv.FormatInt(t.CreatedAt, 10),
+		ApplicationOrder: t.ApplicationOrder,
+		FeeBump:          t.FeeBump,
+		EnvelopeXdr:      t.EnvelopeXdr,
+		ResultXdr:        t.ResultXdr,
+		ResultMetaXdr:    t.ResultMetaXdr,
+
+		DiagnosticEventsXdr: t.DiagnosticEventsXdr,
+	}
+}
+
+// PagingToken returns the paging token of the transaction, see
+// GetTransactionResult.PagingToken
+func (t TransactionInfo) PagingToken() string {
+	return TOID{Ledger: t.Ledger, ApplicationOrder: t.ApplicationOrder}.PagingToken()
+}
+
+type transactionsPagination struct {
+	Cursor string `json:"cursor,omitempty"`
+	Limit  uint   `json:"limit,omitempty"`
+}
+
+// GetTransactions returns a page of the transactions from startLedger, in
+// execution order, or after opts.Cursor. See Transactions to iterate a range.
+// Returns an error if unmarshal, http call, etc; fail.
+// Result matches the result in the docs https://developers.stellar.org/docs/data/rpc/api-reference/methods/getTransactions
+func (c Client) GetTransactions(ctx context.Context, startLedger int64, opts *TransactionsOptions) (*GetTransactionsResult, error) {
+	params := struct {
+		StartLedger int64                   `json:"startLedger,omitempty"`
+		Pagination  *transactionsPagination `json:"pagination,omitempty"`
+	}{StartLedger: startLedger}
+	if opts != nil && (opts.Cursor != "" || opts.Limit != 0) {
+		params.Pagination = &transactionsPagination{Cursor: opts.Cursor, Limit: opts.Limit}
+		if opts.Cursor != "" {
+			params.StartLedger = 0
+		}
+	}
+	var getTransactionsResult GetTransactionsResult
+	err := c.CallResult(ctx, GetTransactions, &getTransactionsResult, params)
+	if err != nil {
+		return nil, err
+	}
+	return &getTransactionsResult, nil
+}
+
+// TransactionIterator iterates the transactions of a ledger range, following
+// the cursors of GetTransactions
+//
+//	Example:
+//	 it := client.Transactions(1000, 2000)
+//	 for it.Next(ctx) {
+//		tx := it.Transaction()
+//	 }
+//	 if err := it.Err(); err != nil {
+type TransactionIterator struct {
+	client    Client
+	start     int64
+	end       int64
+	limit     uint
+	cursor    string
+	page      []TransactionInfo
+	current   TransactionInfo
+	err       error
+	exhausted bool
+}
+
+// Transactions returns an iterator of the transactions from startLedger to
+// endLedger, both included. With endLedger 0 it stops at the latest ledger.
+func (c Client) Transactions(startLedger, endLedger int64) *TransactionIterator {
+	return &TransactionIterator{client: c, start: startLedger, end: endLedger}
+}
+
+// Limit sets the number of transactions requested per page
+func (it *TransactionIterator) Limit(limit uint) *TransactionIterator {
+	it.limit = limit
+	return it
+}
+
+// Cursor sets the cursor to resume after, the PagingToken of the last
+// transaction processed, instead of the start ledger
+func (it *TransactionIterator) Cursor(cursor string) *TransactionIterator {
+	it.cursor = cursor
+	return it
+}
+
+// Next advances to the next transaction, fetching the next page when
+// needed. It returns false at the end of the range, or on error, see Err.
+func (it *TransactionIterator) Next(ctx context.Context) bool {
+	for len(it.page) == 0 {
+		if it.exhausted || it.err != nil {
+			return false
+		}
+		res, err := it.client.GetTransactions(ctx, it.start, &TransactionsOptions{Cursor: it.cursor, Limit: it.limit})
+		if err != nil {
+			it.err = err
+			return false
+		}
+		it.page = res.Transactions
+		// an empty page, or an unchanged cursor, is the latest ledger
+		it.exhausted = len(res.Transactions) == 0 || res.Cursor == "" || res.Cursor == it.cursor
+		it.cursor = res.Cursor
+	}
+	if it.end != 0 && it.page[0].Ledger > it.end {
+		it.page = nil
+		it.exhausted = true
+		return false
+	}
+	it.current = it.page[0]
+	it.page = it.page[1:]
+	return true
+}
+
+// Transaction returns the current transaction
+func (it *TransactionIterator) Transaction() TransactionInfo {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
+
+// PagingToken returns the paging token of the current transaction, to resume
+// the iteration after it with Cursor
+func (it *TransactionIterator) PagingToken() string {
+	return it.current.PagingToken()
+}
@@ -0,0 +1,76 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/network"
+)
+
+func TestTransactionIterator(t *testing.T) {
+	ledgers := []int64{10, 10, 11, 12, 13}
+	var params []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Method string `json:"method"`
+			Params struct {
+				StartLedger int64 `json:"startLedger"`
+				Pagination  struct {
+					Cursor string `json:"cursor"`
+					Limit  int    `json:"limit"`
+				} `json:"pagination"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		if req.Method != soroban.GetTransactions {
+			t.Error(req.Method)
+		}
+		params = append(params, fmt.Sprintf("%d/%s", req.Params.StartLedger, req.Params.Pagination.Cursor))
+		// the cursor is the index of the last transaction returned
+		from := 0
+		fmt.Sscan(req.Params.Pagination.Cursor, &from)
+		var txs []string
+		cursor := req.Params.Pagination.Cursor
+		for i := from; i < len(ledgers) && len(txs) < req.Params.Pagination.Limit; i++ {
+			txs = append(txs, fmt.Sprintf(`{"status":"SUCCESS","txHash":"%d","ledger":%d,"applicationOrder":%d}`, i, ledgers[i], i+1))
+			cursor = fmt.Sprint(i + 1)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":13,"cursor":"%s","transactions":[%s]}}`,
+			req.ID, cursor, strings.Join(txs, ","))
+	}))
+	defer server.Close()
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+
+	it := client.Transactions(10, 12).Limit(2)
+	var hashes []string
+	for it.Next(context.Background()) {
+		hashes = append(hashes, it.Transaction().TxHash)
+	}
+	if err := it.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Join(hashes, ",") != "0,1,2,3" {
+		t.Fatal(hashes)
+	}
+	if strings.Join(params, ",") != "10/,0/2,0/4" {
+		t.Fatal(params)
+	}
+
+	params = nil
+	it = client.Transactions(10, 0).Limit(2).Cursor("3")
+	hashes = nil
+	for it.Next(context.Background()) {
+		hashes = append(hashes, it.Transaction().TxHash)
+	}
+	if it.Err() != nil || strings.Join(hashes, ",") != "3,4" || strings.Join(params, ",") != "0/3,0/5" {
+		t.Fatal(hashes, params, it.Err())
+	}
+}
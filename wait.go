@@ -0,0 +1,125 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// LedgerPollInitialInterval is the first interval between getLatestLedger
+// polls while waiting for a ledger to close, it doubles up to
+// PendingTransactionPollInterval.
+var LedgerPollInitialInterval = 250 * time.Millisecond
+
+// waitNextLedger polls getLatestLedger, with exponential backoff, until a
+// ledger after the ledger after closes, returning its sequence
+func waitNextLedger(ctx context.Context, client SorobanClient, after int64) (int64, error) {
+	interval := LedgerPollInitialInterval
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, ctx.Err()
+		case <-time.After(interval):
+		}
+		latest, err := client.GetLatestLedger(ctx)
+		if err != nil {
+			return 0, err
+		}
+		if latest.Sequence > after {
+			return latest.Sequence, nil
+		}
+		interval *= 2
+		if interval > PendingTransactionPollInterval {
+			interval = PendingTransactionPollInterval
+		}
+	}
+}
+
+// ErrTimeout is returned by WaitForTransaction, and by PendingTransaction,
+// when the transaction is not found after the attempts
+var ErrTimeout = errors.New(ErrorTransactionNotFound)
+
+// WaitOptions is the polling strategy of WaitForTransaction, the zero value
+// queries getTransaction once per closed ledger, up to
+// PendingTransactionPollAttempts times.
+type WaitOptions struct {
+	// MaxAttempts of getTransaction, PendingTransactionPollAttempts if 0
+	MaxAttempts int
+	// Interval between the attempts, if 0 they wait for the next ledger
+	Interval time.Duration
+	// Backoff multiplies the Interval after every attempt, up to
+	// MaxInterval if set, 0 or 1 keeps it constant
+	Backoff     float64
+	MaxInterval time.Duration
+}
+
+// WaitForTransaction polls getTransaction until the transaction with hash is
+// found, SUCCESS or FAILED, or fails with ErrTimeout after the attempts of
+// opts. It is traced in a span "soroban.wait".
+//
+//	Example:
+//	 res, err := client.WaitForTransaction(ctx, hash, soroban.WaitOptions{MaxAttempts: 30, Interval: time.Second})
+//	 if errors.Is(err, soroban.ErrTimeout) {
+func (c *Client) WaitForTransaction(ctx context.Context, hash string, opts WaitOptions) (*GetTransactionResult, error) {
+	return waitForTransaction(ctx, c, hash, opts)
+}
+
+func waitForTransaction(ctx context.Context, client SorobanClient, hash string, opts WaitOptions) (res *GetTransactionResult, err error) {
+	ctx, span := startSpan(ctx, client, "soroban.wait", TxHashAttribute.String(hash))
+	defer func() {
+		traceResult(span, res)
+		endSpan(span, err)
+	}()
+	res, err = pollTransaction(ctx, client, hash, opts)
+	if err != nil {
+		return nil, err
+	}
+	if res.Status == "NOT_FOUND" {
+		return nil, ErrTimeout
+	}
+	return res, nil
+}
+
+// pollTransaction queries getTransaction, following opts, until the
+// transaction is found. It returns the last NOT_FOUND result if it never is.
+func pollTransaction(ctx context.Context, client SorobanClient, hash string, opts WaitOptions) (*GetTransactionResult, error) {
+	attempts := opts.MaxAttempts
+	if attempts <= 0 {
+		attempts = PendingTransactionPollAttempts
+	}
+	interval := opts.Interval
+	var res *GetTransactionResult
+	for i := 0; i < attempts; i++ {
+		var err error
+		res, err = client.GetTransaction(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		debug(ctx, client, "poll transaction", slog.String("hash", hash), slog.Int("attempt", i+1), slog.String("status", res.Status))
+		if res.Status != "NOT_FOUND" {
+			return res, nil
+		}
+		if i == attempts-1 {
+			break
+		}
+		if interval == 0 {
+			if _, err := waitNextLedger(ctx, client, res.LatestLedger); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		if opts.Backoff > 1 {
+			interval = time.Duration(float64(interval) * opts.Backoff)
+			if opts.MaxInterval > 0 && interval > opts.MaxInterval {
+				interval = opts.MaxInterval
+			}
+		}
+	}
+	return res, nil
+}
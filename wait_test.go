@@ -0,0 +1,138 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestPendingTransactionWaitsLedgers(t *testing.T) {
+	soroban.LedgerPollInitialInterval = time.Millisecond
+	defer func() { soroban.LedgerPollInitialInterval = 250 * time.Millisecond }()
+
+	// a ledger closes every 3 getLatestLedger polls, the transaction is
+	// included 2 ledgers after being sent
+	var polls, queries atomic.Int64
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+		GetLatestLedgerFunc: func() (*soroban.GetLatestLedgerResult, error) {
+			return &soroban.GetLatestLedgerResult{Sequence: 10 + polls.Add(1)/3}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			ledger := 10 + polls.Load()/3
+			queries.Add(1)
+			if ledger < 12 {
+				return &soroban.GetTransactionResult{Status: "NOT_FOUND", LatestLedger: ledger}, nil
+			}
+			return &soroban.GetTransactionResult{Status: "SUCCESS", Ledger: 12, LatestLedger: ledger}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := pending.Wait(context.Background())
+	if err != nil || res.Status != "SUCCESS" {
+		t.Fatal(res, err)
+	}
+	// one getTransaction per ledger, not per poll
+	if queries.Load() != 3 || polls.Load() != 6 {
+		t.Fatal(queries.Load(), polls.Load())
+	}
+}
+
+func TestPendingTransactionConfirmationDepth(t *testing.T) {
+	soroban.LedgerPollInitialInterval = time.Millisecond
+	defer func() { soroban.LedgerPollInitialInterval = 250 * time.Millisecond }()
+
+	// a ledger closes on every getLatestLedger poll, the transaction is in
+	// ledger 10
+	var latest atomic.Int64
+	latest.Store(10)
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetLatestLedgerFunc: func() (*soroban.GetLatestLedgerResult, error) {
+			return &soroban.GetLatestLedgerResult{Sequence: latest.Add(1)}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{Status: "SUCCESS", Ledger: 10, LatestLedger: latest.Load()}, nil
+		},
+	}
+	fake.SendTransactionFunc = func(string) (*soroban.SendTransactionResult, error) {
+		return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+	}
+	pair := keypair.MustRandom()
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := pending.ConfirmationDepth(3).Wait(context.Background())
+	if err != nil || res.LatestLedger != 13 {
+		t.Fatal(res, err)
+	}
+	if provisional := pending.Provisional(); provisional == nil || provisional.LatestLedger != 12 {
+		t.Fatal(provisional)
+	}
+}
+
+func TestWaitForTransaction(t *testing.T) {
+	var queries []time.Time
+	found := 4
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		queries = append(queries, time.Now())
+		status := "NOT_FOUND"
+		if len(queries) == found {
+			status = "SUCCESS"
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"status":%q,"latestLedger":7}}`, req.ID, status)
+	}))
+	defer server.Close()
+	client := &soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = server.URL
+
+	opts := soroban.WaitOptions{MaxAttempts: 4, Interval: 10 * time.Millisecond, Backoff: 2, MaxInterval: 30 * time.Millisecond}
+	res, err := client.WaitForTransaction(context.Background(), "abc", opts)
+	if err != nil || res.Status != "SUCCESS" {
+		t.Fatal(res, err)
+	}
+	// 10ms, 20ms, 30ms
+	if gap := queries[3].Sub(queries[2]); gap < 30*time.Millisecond || queries[2].Sub(queries[1]) < 20*time.Millisecond {
+		t.Fatal(gap)
+	}
+
+	queries, found = nil, 0
+	res, err = client.WaitForTransaction(context.Background(), "abc", opts)
+	if !errors.Is(err, soroban.ErrTimeout) || res != nil || len(queries) != 4 {
+		t.Fatal(res, err, len(queries))
+	}
+}
@@ -0,0 +1,164 @@
+package soroban
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"os"
+	"sync"
+	"time"
+)
+
+// WasmStatus is the last verification of an installed wasm
+type WasmStatus struct {
+	VerifiedLedger  int64     `json:"verified_ledger"`
+	LiveUntilLedger int64     `json:"live_until_ledger"`
+	VerifiedAt      time.Time `json:"verified_at"`
+}
+
+// WasmCache stores the wasm hashes known to be installed on each network,
+// identified by its passphrase, so repeated runs skip reading the code of the
+// binaries verified recently. See FileWasmCache.
+type WasmCache interface {
+	// Get returns the status of hash on the network, false if it is not
+	// cached
+	Get(ctx context.Context, passphrase string, hash [32]byte) (WasmStatus, bool, error)
+	Put(ctx context.Context, passphrase string, hash [32]byte, status WasmStatus) error
+}
+
+// fresh returns if s was verified within maxAge and its code is estimated
+// to be live now, using LedgerCloseTime
+func (s WasmStatus) fresh(maxAge time.Duration, now time.Time) bool {
+	elapsed := now.Sub(s.VerifiedAt)
+	if elapsed < 0 || elapsed > maxAge {
+		return false
+	}
+	return s.VerifiedLedger+int64(elapsed/LedgerCloseTime) < s.LiveUntilLedger
+}
+
+// FileWasmCache is a WasmCache stored as a JSON file, rewritten on each Put,
+// of the hex hashes by network passphrase. With an empty path it is kept in
+// memory.
+type FileWasmCache struct {
+	path    string
+	mu      sync.Mutex
+	entries map[string]map[string]WasmStatus
+}
+
+// NewFileWasmCache returns a cache stored at path, read on the first use
+func NewFileWasmCache(path string) *FileWasmCache {
+	return &FileWasmCache{path: path}
+}
+
+// Get returns the status of hash on the network
+func (f *FileWasmCache) Get(ctx context.Context, passphrase string, hash [32]byte) (WasmStatus, bool, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.load(); err != nil {
+		return WasmStatus{}, false, err
+	}
+	status, ok := f.entries[passphrase][hex.EncodeToString(hash[:])]
+	return status, ok, nil
+}
+
+// Put stores the status of hash on the network and rewrites the file
+func (f *FileWasmCache) Put(ctx context.Context, passphrase string, hash [32]byte, status WasmStatus) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.load(); err != nil {
+		return err
+	}
+	if f.entries[passphrase] == nil {
+		f.entries[passphrase] = map[string]WasmStatus{}
+	}
+	f.entries[passphrase][hex.EncodeToString(hash[:])] = status
+	if f.path == "" {
+		return nil
+	}
+	b, err := json.MarshalIndent(f.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp := f.path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, f.path)
+}
+
+// load reads the file once, a missing one is an empty cache
+func (f *FileWasmCache) load() error {
+	if f.entries != nil {
+		return nil
+	}
+	entries := map[string]map[string]WasmStatus{}
+	if f.path != "" {
+		b, err := os.ReadFile(f.path)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if err == nil {
+			if err := json.Unmarshal(b, &entries); err != nil {
+				return err
+			}
+		}
+	}
+	f.entries = entries
+	return nil
+}
+
+// WasmCache sets the cache of the installed wasm hashes used by Deploy and
+// InstallWasms, a hash verified within maxAge, and estimated to be live, is
+// not read again.
+//
+//	Example:
+//	 cache := soroban.NewFileWasmCache(".soroban/wasm.json")
+//	 contract := soroban.NewContract().Wasm(wasm).WasmCache(cache, 24*time.Hour)
+func (c *Contract) WasmCache(cache WasmCache, maxAge time.Duration) *Contract {
+	c.wasmCache = cache
+	c.wasmCacheMaxAge = maxAge
+	return c
+}
+
+// RefreshWasmCache forces the reads of the code, updating the cache
+func (c *Contract) RefreshWasmCache(refresh bool) *Contract {
+	c.refreshWasmCache = refresh
+	return c
+}
+
+// cachedCodeAlive returns if the code is live according to the cache
+func (c *Contract) cachedCodeAlive(ctx context.Context) (bool, error) {
+	if c.wasmCache == nil || c.refreshWasmCache {
+		return false, nil
+	}
+	status, ok, err := c.wasmCache.Get(ctx, c.client.NetworkPassphrase(), c.wasmHash)
+	if err != nil || !ok {
+		return false, err
+	}
+	return status.fresh(c.wasmCacheMaxAge, time.Now()), nil
+}
+
+// cacheCode stores the code as verified at latestLedger
+func (c *Contract) cacheCode(ctx context.Context, liveUntilLedger, latestLedger int64) error {
+	if c.wasmCache == nil || liveUntilLedger < latestLedger {
+		return nil
+	}
+	return c.wasmCache.Put(ctx, c.client.NetworkPassphrase(), c.wasmHash, WasmStatus{
+		VerifiedLedger:  latestLedger,
+		LiveUntilLedger: liveUntilLedger,
+		VerifiedAt:      time.Now(),
+	})
+}
+
+// codeAlive returns if the code is live, from the cache if it is fresh
+func (c *Contract) codeAlive(ctx context.Context) (bool, error) {
+	if ok, err := c.cachedCodeAlive(ctx); ok || err != nil {
+		return ok, err
+	}
+	alive, res, err := c.IsCodeAlive(ctx)
+	if err != nil || !alive {
+		return false, err
+	}
+	return true, c.cacheCode(ctx, res.Entries[0].LiveUntilLedgerSeq, res.LatestLedger)
+}
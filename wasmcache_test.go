@@ -0,0 +1,77 @@
+package soroban_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+func TestWasmCache(t *testing.T) {
+	liveUntil := int64(100000)
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		GetLedgerEntriesFunc: func(keys ...string) (*soroban.GetLedgerEntriesResult, error) {
+			entries := make([]soroban.GetLedgerEntry, len(keys))
+			for i, key := range keys {
+				entries[i] = soroban.GetLedgerEntry{Key: key, LiveUntilLedgerSeq: liveUntil}
+			}
+			return &soroban.GetLedgerEntriesResult{LatestLedger: 10, Entries: entries}, nil
+		},
+	}
+	path := filepath.Join(t.TempDir(), "wasm.json")
+	pair := keypair.MustRandom()
+	account := &txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}
+	contract := func(cache soroban.WasmCache, wasm string) *soroban.Contract {
+		return soroban.NewContract().Client(fake).SourceAccount(account).KeyPair(pair).Wasm([]byte(wasm)).WasmCache(cache, time.Hour)
+	}
+
+	cache := soroban.NewFileWasmCache(path)
+	if _, err := soroban.InstallWasms(context.Background(), contract(cache, "token"), contract(cache, "pool")); err != nil {
+		t.Fatal(err)
+	}
+	if fake.Count(soroban.GetLedgerEntries) != 1 {
+		t.Fatal(fake.Calls)
+	}
+	status, ok, err := cache.Get(context.Background(), network.TestNetworkPassphrase, sha256.Sum256([]byte("token")))
+	if err != nil || !ok || status.VerifiedLedger != 10 || status.LiveUntilLedger != liveUntil {
+		t.Fatal(status, ok, err)
+	}
+	// the same hash on another network is not installed
+	if _, ok, err := cache.Get(context.Background(), network.PublicNetworkPassphrase, sha256.Sum256([]byte("token"))); err != nil || ok {
+		t.Fatal(ok, err)
+	}
+
+	reloaded := soroban.NewFileWasmCache(path)
+	installs, err := soroban.InstallWasms(context.Background(), contract(reloaded, "token"), contract(reloaded, "pool"))
+	if err != nil || !installs[0].Live || !installs[1].Live || fake.Count(soroban.GetLedgerEntries) != 1 {
+		t.Fatal(installs, err, fake.Calls)
+	}
+	if _, err := soroban.InstallWasms(context.Background(), contract(reloaded, "token").RefreshWasmCache(true)); err != nil {
+		t.Fatal(err)
+	}
+	if fake.Count(soroban.GetLedgerEntries) != 2 {
+		t.Fatal(fake.Calls)
+	}
+
+	// a code close to its ttl is read again
+	expiring := soroban.NewFileWasmCache("")
+	expiring.Put(context.Background(), network.TestNetworkPassphrase, sha256.Sum256([]byte("token")), soroban.WasmStatus{
+		VerifiedLedger:  10,
+		LiveUntilLedger: 11,
+		VerifiedAt:      time.Now().Add(-time.Minute),
+	})
+	if _, err := soroban.InstallWasms(context.Background(), contract(expiring, "token")); err != nil {
+		t.Fatal(err)
+	}
+	if fake.Count(soroban.GetLedgerEntries) != 3 {
+		t.Fatal(fake.Calls)
+	}
+}
@@ -0,0 +1,178 @@
+package soroban
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/stellar/go/xdr"
+)
+
+const (
+	ErrorInvalidWatchInterval = "Watch interval must be positive"
+)
+
+// MaxLedgerEntriesKeys is the maximum number of keys of a getLedgerEntries
+// request, WatchLedgerEntries polls more keys with several requests
+const MaxLedgerEntriesKeys = 200
+
+// LedgerEntryChange is emitted by WatchLedgerEntries when a watched entry is
+// created, modified or removed
+type LedgerEntryChange struct {
+	Key xdr.LedgerKey
+	// Ledger is the last modified ledger of the entry, or the latest ledger
+	// when it was removed
+	Ledger int64
+	// Before is nil if the entry was created
+	Before *xdr.LedgerEntryData
+	// After is nil if the entry was removed, or archived
+	After              *xdr.LedgerEntryData
+	LiveUntilLedgerSeq int64
+	// Provisional is set on changes not yet Client.ConfirmationDepth ledgers
+	// deep, only emitted with Client.DeliverProvisional. The change is emitted
+	// again, not provisional, once confirmed.
+	Provisional bool
+	// Err is set, and the rest empty, when a poll failed, the watch continues
+	Err error
+}
+
+type watchedEntry struct {
+	lastModified int64
+	liveUntil    int64
+	data         *xdr.LedgerEntryData
+}
+
+// WatchLedgerEntries polls getLedgerEntries for keys every interval and
+// emits a LedgerEntryChange each time the lastModifiedLedgerSeq of an entry
+// changes, or the entry appears or disappears. The current state is read
+// before returning, only later changes are emitted. The channel is closed
+// when ctx is done. The interval must be positive.
+// With Client.ConfirmationDepth, changes are emitted once the latest ledger
+// is that many ledgers after them, as data near the tip can change across
+// providers.
+func (c Client) WatchLedgerEntries(ctx context.Context, keys []xdr.LedgerKey, interval time.Duration) (<-chan LedgerEntryChange, error) {
+	if interval <= 0 {
+		return nil, errors.New(ErrorInvalidWatchInterval)
+	}
+	encoded := make([]string, 0, len(keys))
+	byKey := make(map[string]xdr.LedgerKey, len(keys))
+	for _, key := range keys {
+		k, err := key.MarshalBinaryBase64()
+		if err != nil {
+			return nil, err
+		}
+		encoded = append(encoded, k)
+		byKey[k] = key
+	}
+	state, _, err := c.pollLedgerEntries(ctx, encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	changes := make(chan LedgerEntryChange)
+	// provisional is the ledger of the last provisional change of each key
+	provisional := make(map[string]int64)
+	removedAt := make(map[string]int64)
+	go func() {
+		defer close(changes)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			current, latest, err := c.pollLedgerEntries(ctx, encoded)
+			if err != nil {
+				select {
+				case changes <- LedgerEntryChange{Err: err}:
+				case <-ctx.Done():
+					return
+				}
+				continue
+			}
+			for _, k := range encoded {
+				change, ok := diffWatchedEntry(state[k], current[k], latest)
+				if !ok {
+					// a provisional change may have been reverted
+					state[k] = current[k]
+					delete(provisional, k)
+					delete(removedAt, k)
+					continue
+				}
+				change.Key = byKey[k]
+				if change.After == nil {
+					// the ledger of a removal is the first one it was seen at
+					if at, ok := removedAt[k]; ok {
+						change.Ledger = at
+					} else {
+						removedAt[k] = change.Ledger
+					}
+				} else {
+					delete(removedAt, k)
+				}
+				change.Provisional = change.Ledger+int64(c.ConfirmationDepth) > latest
+				if change.Provisional {
+					if !c.DeliverProvisional || provisional[k] == change.Ledger {
+						continue
+					}
+					provisional[k] = change.Ledger
+				} else {
+					state[k] = current[k]
+					delete(provisional, k)
+					delete(removedAt, k)
+				}
+				select {
+				case changes <- change:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// pollLedgerEntries reads the entries of keys, MaxLedgerEntriesKeys per
+// request. The latest ledger is the lowest of the requests, the changes are
+// confirmed in all of them.
+func (c Client) pollLedgerEntries(ctx context.Context, keys []string) (map[string]watchedEntry, int64, error) {
+	state := make(map[string]watchedEntry, len(keys))
+	var latest int64
+	for chunk := range slices.Chunk(keys, MaxLedgerEntriesKeys) {
+		res, err := c.GetLedgerEntries(ctx, chunk...)
+		if err != nil {
+			return nil, 0, err
+		}
+		for _, e := range res.Entries {
+			var data xdr.LedgerEntryData
+			if err := xdr.SafeUnmarshalBase64(e.Xdr, &data); err != nil {
+				return nil, 0, err
+			}
+			state[e.Key] = watchedEntry{lastModified: e.LastModifiedLedgerSeq, liveUntil: e.LiveUntilLedgerSeq, data: &data}
+		}
+		if latest == 0 || res.LatestLedger < latest {
+			latest = res.LatestLedger
+		}
+	}
+	return state, latest, nil
+}
+
+func diffWatchedEntry(before, after watchedEntry, latest int64) (LedgerEntryChange, bool) {
+	switch {
+	case before.data == nil && after.data == nil:
+		return LedgerEntryChange{}, false
+	case after.data == nil:
+		return LedgerEntryChange{Ledger: latest, Before: before.data}, true
+	case before.data != nil && before.lastModified == after.lastModified:
+		return LedgerEntryChange{}, false
+	}
+	return LedgerEntryChange{
+		Ledger:             after.lastModified,
+		Before:             before.data,
+		After:              after.data,
+		LiveUntilLedgerSeq: after.liveUntil,
+	}, true
+}
@@ -0,0 +1,146 @@
+package soroban_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/stellar/go/xdr"
+)
+
+func TestWatchLedgerEntries(t *testing.T) {
+	key := codeKey(1)
+	keyXdr, _ := key.MarshalBinaryBase64()
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// the entry is modified on the second poll and removed on the fourth
+		poll := polls.Add(1)
+		entries := "[]"
+		if poll < 4 {
+			modified := int64(10)
+			if poll >= 2 {
+				modified = 20
+			}
+			code := xdr.LedgerEntryData{
+				Type:         xdr.LedgerEntryTypeContractCode,
+				ContractCode: &xdr.ContractCodeEntry{Hash: xdr.Hash{1}, Code: []byte{byte(modified)}},
+			}
+			codeXdr, _ := xdr.MarshalBase64(code)
+			entries = fmt.Sprintf(`[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":%d,"liveUntilLedgerSeq":100}]`, keyXdr, codeXdr, modified)
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":%d,"entries":%s}}`, req.ID, 30+poll, entries)
+	}))
+	defer server.Close()
+
+	client := soroban.Client{}
+	client.URL = server.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changes, err := client.WatchLedgerEntries(ctx, []xdr.LedgerKey{key}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	modified := <-changes
+	if modified.Err != nil || modified.Ledger != 20 || modified.Before.ContractCode.Code[0] != 10 || modified.After.ContractCode.Code[0] != 20 {
+		t.Fatal(modified)
+	}
+	removed := <-changes
+	if removed.Err != nil || removed.After != nil || removed.Before.ContractCode.Code[0] != 20 {
+		t.Fatal(removed)
+	}
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchLedgerEntriesConfirmationDepth(t *testing.T) {
+	key := codeKey(1)
+	keyXdr, _ := key.MarshalBinaryBase64()
+	var polls atomic.Int64
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID uint64 `json:"id"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		// the entry is modified in ledger 20, seen on the second poll
+		poll := polls.Add(1)
+		modified := int64(10)
+		if poll >= 2 {
+			modified = 20
+		}
+		code := xdr.LedgerEntryData{
+			Type:         xdr.LedgerEntryTypeContractCode,
+			ContractCode: &xdr.ContractCodeEntry{Hash: xdr.Hash{1}, Code: []byte{byte(modified)}},
+		}
+		codeXdr, _ := xdr.MarshalBase64(code)
+		entries := fmt.Sprintf(`[{"key":%q,"xdr":%q,"lastModifiedLedgerSeq":%d,"liveUntilLedgerSeq":100}]`, keyXdr, codeXdr, modified)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":%d,"entries":%s}}`, req.ID, 30+poll, entries)
+	}))
+	defer server.Close()
+
+	client := soroban.Client{ConfirmationDepth: 13, DeliverProvisional: true}
+	client.URL = server.URL
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	changes, err := client.WatchLedgerEntries(ctx, []xdr.LedgerKey{key}, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	provisional := <-changes
+	if provisional.Err != nil || !provisional.Provisional || provisional.Ledger != 20 {
+		t.Fatal(provisional)
+	}
+	confirmed := <-changes
+	if confirmed.Err != nil || confirmed.Provisional || confirmed.Ledger != 20 || polls.Load() < 3 {
+		t.Fatal(confirmed, polls.Load())
+	}
+	cancel()
+	for range changes {
+	}
+}
+
+func TestWatchLedgerEntriesChunks(t *testing.T) {
+	var requests [][]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64 `json:"id"`
+			Params struct {
+				Keys []string `json:"keys"`
+			} `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		requests = append(requests, req.Params.Keys)
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"latestLedger":30,"entries":[]}}`, req.ID)
+	}))
+	defer server.Close()
+
+	client := soroban.Client{}
+	client.URL = server.URL
+	keys := make([]xdr.LedgerKey, soroban.MaxLedgerEntriesKeys+1)
+	for i := range keys {
+		keys[i] = xdr.LedgerKey{Type: xdr.LedgerEntryTypeContractCode, ContractCode: &xdr.LedgerKeyContractCode{Hash: xdr.Hash{byte(i), byte(i >> 8)}}}
+	}
+	if _, err := client.WatchLedgerEntries(context.Background(), keys, 0); err == nil || err.Error() != soroban.ErrorInvalidWatchInterval {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	if _, err := client.WatchLedgerEntries(ctx, keys, time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	if len(requests) != 2 || len(requests[0]) != soroban.MaxLedgerEntriesKeys || len(requests[1]) != 1 {
+		t.Fatal(len(requests))
+	}
+}
@@ -0,0 +1,217 @@
+package soroban
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/sebamiro/soroban/internal/rpc"
+)
+
+const (
+	ErrorWebhookDelivery = "Webhook delivery failed"
+)
+
+// Notification types
+const (
+	NotificationTransaction = "transaction"
+	NotificationEvent       = "event"
+)
+
+// WebhookIDHeader carries the Notification ID, stable across retries so the
+// receiver can drop duplicates
+const WebhookIDHeader = "X-Webhook-Id"
+
+// Notification is the JSON payload POSTed to the webhooks
+type Notification struct {
+	// ID is the transaction hash, or the event id
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	// Transaction is set on NotificationTransaction, nil if it was rejected
+	// or never found
+	Transaction *GetTransactionResult `json:"transaction,omitempty"`
+	// Error is set on NotificationTransaction when the transaction did not
+	// finalize
+	Error string `json:"error,omitempty"`
+	Event *Event `json:"event,omitempty"`
+}
+
+// Webhook is a URL notified by a Notifier
+type Webhook struct {
+	URL string
+	// Signer signs every payload, e.g. HMACRequestSigner, optional
+	Signer RequestSigner
+}
+
+// Notifier POSTs Notifications to webhooks when watched transactions
+// finalize or when events match its filters, so downstream services do not
+// have to poll.
+//
+//	Example:
+//	 notifier := client.Notifier(soroban.Webhook{
+//		URL:    "https://example.com/hook",
+//		Signer: soroban.HMACRequestSigner("X-Signature", secret),
+//	 }).Retries(5, time.Second)
+//	 notifier.WatchTransaction(ctx, pending)
+//	 go notifier.WatchEvents(ctx, latest, filters, 5*time.Second)
+type Notifier struct {
+	client  Client
+	hooks   []Webhook
+	http    rpc.HTTP
+	retries int
+	backoff time.Duration
+	onError func(Notification, error)
+}
+
+// Notifier returns a Notifier of hooks, retrying every delivery 3 times
+// starting at 1 second of backoff
+func (c Client) Notifier(hooks ...Webhook) *Notifier {
+	return &Notifier{client: c, hooks: hooks, retries: 3, backoff: time.Second}
+}
+
+// Retries sets the number of retries of a failed delivery, the backoff
+// doubles after each one. Deliveries answered with a 4xx status, but 429,
+// are not retried.
+func (n *Notifier) Retries(retries int, backoff time.Duration) *Notifier {
+	n.retries = retries
+	n.backoff = backoff
+	return n
+}
+
+// HTTP sets the transport of the deliveries, http.DefaultClient by default
+func (n *Notifier) HTTP(h rpc.HTTP) *Notifier {
+	n.http = h
+	return n
+}
+
+// OnError sets a callback for the deliveries that failed every retry, and
+// the event polls that failed
+func (n *Notifier) OnError(f func(Notification, error)) *Notifier {
+	n.onError = f
+	return n
+}
+
+// WatchTransaction notifies the webhooks once p is finalized, in the
+// background, unless ctx is done before
+func (n *Notifier) WatchTransaction(ctx context.Context, p *PendingTransaction) {
+	go func() {
+		res, err := p.Wait(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		notification := Notification{ID: p.Hash(), Type: NotificationTransaction, Transaction: res}
+		if err != nil {
+			notification.Error = err.Error()
+		}
+		n.notify(ctx, notification)
+	}()
+}
+
+// WatchEvents polls getEvents every interval, from startLedger, and notifies
+// the webhooks of every event matching filters, in order. It returns when
+// ctx is done.
+func (n *Notifier) WatchEvents(ctx context.Context, startLedger int64, filters []EventFilter, interval time.Duration) error {
+	req := GetEventsRequest{StartLedger: startLedger, Filters: filters}
+	for {
+		res, err := n.client.GetEvents(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			n.failed(Notification{Type: NotificationEvent}, err)
+		} else {
+			for i := range res.Events {
+				n.notify(ctx, Notification{ID: res.Events[i].Id, Type: NotificationEvent, Event: &res.Events[i]})
+			}
+			if res.Cursor != "" {
+				req.StartLedger = 0
+				req.Pagination = &EventsPagination{Cursor: res.Cursor}
+			}
+			if len(res.Events) > 0 && res.Cursor != "" {
+				continue
+			}
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(interval):
+		}
+	}
+}
+
+// Notify delivers notification to every webhook, with retries, returning
+// the errors of the ones that failed
+func (n *Notifier) Notify(ctx context.Context, notification Notification) error {
+	body, err := json.Marshal(notification)
+	if err != nil {
+		return err
+	}
+	var errs []error
+	for _, hook := range n.hooks {
+		if err := n.deliver(ctx, hook, notification.ID, body); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s: %w", ErrorWebhookDelivery, hook.URL, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (n *Notifier) notify(ctx context.Context, notification Notification) {
+	if err := n.Notify(ctx, notification); err != nil {
+		n.failed(notification, err)
+	}
+}
+
+func (n *Notifier) failed(notification Notification, err error) {
+	if n.onError != nil {
+		n.onError(notification, err)
+	}
+}
+
+func (n *Notifier) deliver(ctx context.Context, hook Webhook, id string, body []byte) error {
+	backoff := n.backoff
+	for attempt := 0; ; attempt++ {
+		retry, err := n.post(ctx, hook, id, body)
+		if err == nil || !retry || attempt >= n.retries {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// post sends body to hook once, returning if a failure can be retried
+func (n *Notifier) post(ctx context.Context, hook Webhook, id string, body []byte) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, "POST", hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(WebhookIDHeader, id)
+	if hook.Signer != nil {
+		if err := hook.Signer.SignRequest(req, body); err != nil {
+			return false, err
+		}
+	}
+	client := n.http
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return ctx.Err() == nil, err
+	}
+	resp.Body.Close()
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, nil
+	}
+	retry := resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests
+	return retry, fmt.Errorf("bad status %s", resp.Status)
+}
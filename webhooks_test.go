@@ -0,0 +1,181 @@
+package soroban_test
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/sebamiro/soroban"
+	"github.com/sebamiro/soroban/sorobantest"
+	"github.com/stellar/go/keypair"
+	"github.com/stellar/go/network"
+	"github.com/stellar/go/txnbuild"
+)
+
+// webhookServer records the notifications it receives, failing the first
+// fail deliveries with a 503
+type webhookServer struct {
+	mu            sync.Mutex
+	fail          int
+	attempts      int
+	notifications []soroban.Notification
+	received      chan struct{}
+}
+
+func (s *webhookServer) start(t *testing.T, secret []byte) string {
+	s.received = make(chan struct{}, 10)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		mac := hmac.New(sha256.New, secret)
+		mac.Write([]byte(r.Header.Get("X-Timestamp") + "\nPOST\n" + r.URL.Path + "\n"))
+		mac.Write(body)
+		if secret != nil && r.Header.Get("X-Signature") != hex.EncodeToString(mac.Sum(nil)) {
+			t.Error("bad signature")
+		}
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.attempts++
+		if s.fail > 0 {
+			s.fail--
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		var notification soroban.Notification
+		if err := json.Unmarshal(body, &notification); err != nil {
+			t.Error(err)
+		}
+		if r.Header.Get(soroban.WebhookIDHeader) != notification.ID {
+			t.Error("bad id header", r.Header.Get(soroban.WebhookIDHeader))
+		}
+		s.notifications = append(s.notifications, notification)
+		s.received <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+	return server.URL + "/hook"
+}
+
+func TestNotifierTransaction(t *testing.T) {
+	secret := []byte("secret")
+	hook := &webhookServer{fail: 2}
+	url := hook.start(t, secret)
+
+	fake := &sorobantest.FakeClient{
+		Passphrase: network.TestNetworkPassphrase,
+		SendTransactionFunc: func(string) (*soroban.SendTransactionResult, error) {
+			return &soroban.SendTransactionResult{Hash: "abc", Status: "PENDING"}, nil
+		},
+		GetTransactionFunc: func(hash string) (*soroban.GetTransactionResult, error) {
+			return &soroban.GetTransactionResult{Status: "SUCCESS", TxHash: hash, Ledger: 12, LatestLedger: 12}, nil
+		},
+	}
+	pair := keypair.MustRandom()
+	pending, err := soroban.NewTransctionBuilder().
+		Client(fake).
+		SourceAccount(&txnbuild.SimpleAccount{AccountID: pair.Address(), Sequence: 1}).
+		Signer(pair).
+		Operation(&txnbuild.BumpSequence{BumpTo: 10}).
+		Send(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	notifier := client.Notifier(soroban.Webhook{
+		URL:    url,
+		Signer: soroban.HMACRequestSigner("X-Signature", func() ([]byte, error) { return secret, nil }),
+	}).Retries(3, time.Millisecond).OnError(func(n soroban.Notification, err error) {
+		t.Error(err)
+	})
+	notifier.WatchTransaction(context.Background(), pending)
+	select {
+	case <-hook.received:
+	case <-time.After(5 * time.Second):
+		t.Fatal("not notified")
+	}
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if hook.attempts != 3 || len(hook.notifications) != 1 {
+		t.Fatal(hook.attempts, hook.notifications)
+	}
+	n := hook.notifications[0]
+	if n.Type != soroban.NotificationTransaction || n.ID != "abc" || n.Transaction == nil || n.Transaction.Status != "SUCCESS" {
+		t.Fatal(n)
+	}
+}
+
+func TestNotifierRetries(t *testing.T) {
+	hook := &webhookServer{fail: 10}
+	url := hook.start(t, nil)
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	err := client.Notifier(soroban.Webhook{URL: url}).
+		Retries(2, time.Millisecond).
+		Notify(context.Background(), soroban.Notification{ID: "1", Type: soroban.NotificationEvent})
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if err == nil || hook.attempts != 3 {
+		t.Fatal(err, hook.attempts)
+	}
+}
+
+func TestNotifierEvents(t *testing.T) {
+	hook := &webhookServer{}
+	url := hook.start(t, nil)
+
+	var mu sync.Mutex
+	var cursors []string
+	rpcServer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     uint64                   `json:"id"`
+			Params soroban.GetEventsRequest `json:"params"`
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		cursor := ""
+		if req.Params.Pagination != nil {
+			cursor = req.Params.Pagination.Cursor
+		}
+		mu.Lock()
+		cursors = append(cursors, fmt.Sprintf("%d/%s", req.Params.StartLedger, cursor))
+		mu.Unlock()
+		events := ""
+		if cursor == "" {
+			events = `{"type":"contract","ledger":10,"contractId":"C1","id":"0000000042949677056-0000000000"}`
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":{"events":[%s],"cursor":"c1","latestLedger":10}}`, req.ID, events)
+	}))
+	defer rpcServer.Close()
+	client := soroban.Client{PassPhrase: network.TestNetworkPassphrase}
+	client.URL = rpcServer.URL
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error)
+	go func() {
+		done <- client.Notifier(soroban.Webhook{URL: url}).
+			WatchEvents(ctx, 10, []soroban.EventFilter{{ContractIds: []string{"C1"}}}, time.Millisecond)
+	}()
+	<-hook.received
+	time.Sleep(10 * time.Millisecond)
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Fatal(err)
+	}
+	mu.Lock()
+	defer mu.Unlock()
+	hook.mu.Lock()
+	defer hook.mu.Unlock()
+	if len(hook.notifications) != 1 || hook.notifications[0].Event == nil || hook.notifications[0].Event.ContractId != "C1" {
+		t.Fatal(hook.notifications)
+	}
+	if len(cursors) < 2 || cursors[0] != "10/" || cursors[1] != "0/c1" {
+		t.Fatal(cursors)
+	}
+}